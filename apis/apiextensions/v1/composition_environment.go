@@ -137,6 +137,12 @@ type EnvironmentPatch struct {
 	// +optional
 	ToFieldPath *string `json:"toFieldPath,omitempty"`
 
+	// ToFieldPaths are additional paths of fields on the resource whose
+	// values will be changed with the result of transforms. A patch with
+	// ToFieldPaths doesn't default its ToFieldPath to its FromFieldPath.
+	// +optional
+	ToFieldPaths []string `json:"toFieldPaths,omitempty"`
+
 	// Transforms are the list of functions that are used as a FIFO pipe for the
 	// input to be transformed.
 	// +optional
@@ -146,3 +152,20 @@ type EnvironmentPatch struct {
 	// +optional
 	Policy *PatchPolicy `json:"policy,omitempty"`
 }
+
+// Default the EnvironmentPatch object. Patch types that read from a single
+// field path default their ToFieldPath to their FromFieldPath, unless they
+// write to ToFieldPaths.
+func (ep *EnvironmentPatch) Default() {
+	switch ep.Type {
+	case "", PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeToCompositeFieldPath, PatchTypeToEnvironmentFieldPath:
+		if ep.ToFieldPath == nil && len(ep.ToFieldPaths) == 0 && ep.FromFieldPath != nil {
+			to := *ep.FromFieldPath
+			ep.ToFieldPath = &to
+		}
+	case PatchTypePatchSet, PatchTypeCombineFromEnvironment, PatchTypeCombineFromComposite, PatchTypeCombineToComposite, PatchTypeCombineToEnvironment,
+		PatchTypeNoop, PatchTypeFromComposedFieldPath, PatchTypeFromControllerConfig, PatchTypeFromConnectionSecretKey, PatchTypeFromComposedConnectionSecretKey:
+		// These patch types have no defaults, or aren't supported by
+		// environment patches.
+	}
+}
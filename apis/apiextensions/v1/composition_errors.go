@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Sentinel errors that may be matched using errors.Is to determine why a
+// Composition could not be inlined or patched.
+var (
+	// ErrPatchSetType indicates that a patch in a PatchSet is itself of type
+	// PatchSet.
+	ErrPatchSetType = errors.New(errPatchSetType)
+
+	// ErrUndefinedPatchSet indicates that a patch references a PatchSet that
+	// is not defined by the Composition.
+	ErrUndefinedPatchSet = errors.New("undefined PatchSet")
+
+	// ErrTransformSetType indicates that a transform in a TransformSet is
+	// itself of type transformSet.
+	ErrTransformSetType = errors.New(errTransformSetType)
+
+	// ErrUndefinedTransformSet indicates that a transform references a
+	// TransformSet that is not defined by the Composition.
+	ErrUndefinedTransformSet = errors.New("undefined TransformSet")
+
+	// ErrInvalidPatchType indicates that a patch is of an unsupported type.
+	ErrInvalidPatchType = errors.New("invalid patch type")
+
+	// ErrDependencyCycle indicates that the FromComposedFieldPath and
+	// FromComposedConnectionSecretKey patches of a Composition's resource
+	// templates read from each other in a cycle, so they can't be ordered.
+	ErrDependencyCycle = errors.New("cross-resource patches form a dependency cycle")
+)
+
+// A sentinelError is an error with a descriptive message that matches a
+// sentinel error when compared using errors.Is.
+// +k8s:deepcopy-gen=false
+type sentinelError struct {
+	sentinel error
+	message  string
+}
+
+func (e *sentinelError) Error() string {
+	return e.message
+}
+
+func (e *sentinelError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+// A PatchSetReferenceError is an error with a patch of a resource template
+// that references a PatchSet. Use errors.As to determine which patch of which
+// resource template the error applies to, and errors.Is to determine why.
+// +k8s:deepcopy-gen=false
+type PatchSetReferenceError struct {
+	// ResourceIndex is the index of the resource template.
+	ResourceIndex int
+
+	// PatchIndex is the index of the patch within its resource template.
+	PatchIndex int
+
+	// Err is the error with the reference.
+	Err error
+}
+
+func (e *PatchSetReferenceError) Error() string {
+	return fmt.Sprintf(errFmtPatchSetReference, e.PatchIndex, e.ResourceIndex, e.Err)
+}
+
+// Unwrap returns the error with the reference.
+func (e *PatchSetReferenceError) Unwrap() error {
+	return e.Err
+}
+
+// A TransformSetReferenceError is an error with a transform of a patch of a
+// resource template that references a TransformSet. Use errors.As to determine
+// which transform the error applies to, and errors.Is to determine why. The
+// patch index is that of the patch once PatchSets are inlined.
+// +k8s:deepcopy-gen=false
+type TransformSetReferenceError struct {
+	// ResourceIndex is the index of the resource template.
+	ResourceIndex int
+
+	// PatchIndex is the index of the patch within its resource template.
+	PatchIndex int
+
+	// TransformIndex is the index of the transform within its patch.
+	TransformIndex int
+
+	// Err is the error with the reference.
+	Err error
+}
+
+func (e *TransformSetReferenceError) Error() string {
+	return fmt.Sprintf(errFmtTransformSetReference, e.TransformIndex, e.PatchIndex, e.ResourceIndex, e.Err)
+}
+
+// Unwrap returns the error with the reference.
+func (e *TransformSetReferenceError) Unwrap() error {
+	return e.Err
+}
+
+// UndefinedTransformSetError returns an error indicating that the named
+// TransformSet is not defined. The error matches ErrUndefinedTransformSet.
+func UndefinedTransformSetError(name string) error {
+	return &sentinelError{sentinel: ErrUndefinedTransformSet, message: fmt.Sprintf(errFmtUndefinedTransformSet, name)}
+}
+
+// UndefinedPatchSetError returns an error indicating that the named PatchSet
+// is not defined. The error matches ErrUndefinedPatchSet.
+func UndefinedPatchSetError(name string) error {
+	return &sentinelError{sentinel: ErrUndefinedPatchSet, message: fmt.Sprintf(errFmtUndefinedPatchSet, name)}
+}
+
+// InvalidPatchTypeError returns an error indicating that the supplied patch
+// type is not supported. The error matches ErrInvalidPatchType.
+func InvalidPatchTypeError(t PatchType) error {
+	return &sentinelError{sentinel: ErrInvalidPatchType, message: fmt.Sprintf(errFmtInvalidPatchType, t)}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	type want struct {
+		msg                    string
+		undefinedPatchSet      bool
+		invalidPatchType       bool
+		patchSetOfTypePatchSet bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"UndefinedPatchSet": {
+			reason: "An undefined PatchSet error should match only ErrUndefinedPatchSet.",
+			err:    UndefinedPatchSetError("nope"),
+			want: want{
+				msg:               "cannot find PatchSet by name nope",
+				undefinedPatchSet: true,
+			},
+		},
+		"InvalidPatchType": {
+			reason: "An invalid patch type error should match only ErrInvalidPatchType.",
+			err:    InvalidPatchTypeError("Nope"),
+			want: want{
+				msg:              "patch type Nope is unsupported",
+				invalidPatchType: true,
+			},
+		},
+		"WrappedUndefinedPatchSet": {
+			reason: "A wrapped undefined PatchSet error should still match ErrUndefinedPatchSet.",
+			err:    errors.Wrap(UndefinedPatchSetError("nope"), "cannot inline"),
+			want: want{
+				msg:               "cannot inline: cannot find PatchSet by name nope",
+				undefinedPatchSet: true,
+			},
+		},
+		"PatchSetType": {
+			reason: "ErrPatchSetType should match only itself.",
+			err:    ErrPatchSetType,
+			want: want{
+				msg:                    errPatchSetType,
+				patchSetOfTypePatchSet: true,
+			},
+		},
+		"InlinedResources": {
+			reason: "Errors returned by InlinedResources should match their sentinel.",
+			err: func() error {
+				cs := &CompositionSpec{Resources: []ComposedTemplate{{
+					Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("nope")}},
+				}}}
+				_, err := cs.InlinedResources()
+				return err
+			}(),
+			want: want{
+				msg:               "patch 0 of resource 0: cannot find PatchSet by name nope",
+				undefinedPatchSet: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{
+				msg:                    tc.err.Error(),
+				undefinedPatchSet:      errors.Is(tc.err, ErrUndefinedPatchSet),
+				invalidPatchType:       errors.Is(tc.err, ErrInvalidPatchType),
+				patchSetOfTypePatchSet: errors.Is(tc.err, ErrPatchSetType),
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\n-want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPatchSetReferenceErrors(t *testing.T) {
+	type position struct {
+		Resource int
+		Patch    int
+	}
+
+	cs := &CompositionSpec{
+		PatchSets: []PatchSet{{Name: "ps"}},
+		Resources: []ComposedTemplate{
+			{Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("nope")}}},
+			{Patches: []Patch{
+				{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+				{Type: PatchTypePatchSet, PatchSetName: pointer.String("nope")},
+			}},
+		},
+	}
+	want := []position{{Resource: 0, Patch: 0}, {Resource: 1, Patch: 1}}
+
+	_, err := cs.InlinedResources()
+	agg, ok := err.(utilerrors.Aggregate) //nolint:errorlint // We want the aggregate itself, not an error it wraps.
+	if !ok {
+		t.Fatalf("InlinedResources(): want an aggregate error, got %T", err)
+	}
+
+	got := make([]position, 0, len(agg.Errors()))
+	for _, err := range agg.Errors() {
+		pe := &PatchSetReferenceError{}
+		if !errors.As(err, &pe) {
+			t.Fatalf("InlinedResources(): want a PatchSetReferenceError, got %T", err)
+		}
+		if !errors.Is(pe, ErrUndefinedPatchSet) {
+			t.Errorf("InlinedResources(): want an error matching ErrUndefinedPatchSet, got %q", pe)
+		}
+		got = append(got, position{Resource: pe.ResourceIndex, Patch: pe.PatchIndex})
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nInlinedResources() should return the position of each undefined PatchSet reference.\n-want, +got:\n%s", diff)
+	}
+}
@@ -21,6 +21,13 @@ import (
 	"fmt"
 
 	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errInlineResources = "cannot inline resources"
+	errMarshalSpec     = "cannot marshal effective spec"
 )
 
 // Hash of the Composition.
@@ -50,3 +57,30 @@ func (c Composition) Hash() string {
 	_, _ = h.Write(y)
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
+
+// Hash of the effective CompositionSpec, i.e. the spec with references to
+// PatchSets replaced by the patches of those sets, and references to
+// TransformSets by the transforms of those sets. Moving patches into or out
+// of PatchSets, renaming or reordering PatchSets, or adding unused PatchSets
+// doesn't change the hash as long as each resource ends up with the same
+// patches in the same order. The hash is deterministic; it doesn't depend on
+// the process that computes it.
+func (cs *CompositionSpec) Hash() (string, error) {
+	ct, err := cs.InlinedResources()
+	if err != nil {
+		return "", errors.Wrap(err, errInlineResources)
+	}
+
+	cp := cs.DeepCopy()
+	cp.PatchSets = nil
+	cp.TransformSets = nil
+	cp.Resources = ct
+
+	// Struct fields are marshalled in a fixed order and map keys are sorted,
+	// so equal specs always produce the same bytes.
+	s, err := yaml.Marshal(cp)
+	if err != nil {
+		return "", errors.Wrap(err, errMarshalSpec)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(s)), nil
+}
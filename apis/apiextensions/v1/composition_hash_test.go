@@ -0,0 +1,111 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestCompositionSpecHash(t *testing.T) {
+	region := Patch{FromFieldPath: pointer.String("spec.region")}
+	size := Patch{FromFieldPath: pointer.String("spec.size")}
+	ref := func(name string) Patch {
+		return Patch{Type: PatchTypePatchSet, PatchSetName: pointer.String(name)}
+	}
+
+	inline := &CompositionSpec{
+		CompositeTypeRef: TypeReference{APIVersion: "example.org/v1", Kind: "XDatabase"},
+		Resources: []ComposedTemplate{{
+			Name:    pointer.String("db"),
+			Patches: []Patch{region, size},
+		}},
+	}
+
+	cases := map[string]struct {
+		reason string
+		cs     *CompositionSpec
+		same   bool
+	}{
+		"PatchSets": {
+			reason: "Moving patches into PatchSets shouldn't change the hash.",
+			cs: &CompositionSpec{
+				CompositeTypeRef: TypeReference{APIVersion: "example.org/v1", Kind: "XDatabase"},
+				PatchSets: []PatchSet{
+					{Name: "unused", Patches: []Patch{size}},
+					{Name: "sizing", Patches: []Patch{size}},
+					{Name: "common", Patches: []Patch{region}},
+				},
+				Resources: []ComposedTemplate{{
+					Name:    pointer.String("db"),
+					Patches: []Patch{ref("common"), ref("sizing")},
+				}},
+			},
+			same: true,
+		},
+		"PatchOrder": {
+			reason: "Changing the order in which patches are applied should change the hash.",
+			cs: &CompositionSpec{
+				CompositeTypeRef: TypeReference{APIVersion: "example.org/v1", Kind: "XDatabase"},
+				Resources: []ComposedTemplate{{
+					Name:    pointer.String("db"),
+					Patches: []Patch{size, region},
+				}},
+			},
+			same: false,
+		},
+		"CompositeTypeRef": {
+			reason: "Changing a field other than the resources should change the hash.",
+			cs: &CompositionSpec{
+				CompositeTypeRef: TypeReference{APIVersion: "example.org/v2", Kind: "XDatabase"},
+				Resources: []ComposedTemplate{{
+					Name:    pointer.String("db"),
+					Patches: []Patch{region, size},
+				}},
+			},
+			same: false,
+		},
+	}
+
+	want, err := inline.Hash()
+	if err != nil {
+		t.Fatalf("Hash(): %v", err)
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.cs.Hash()
+			if err != nil {
+				t.Fatalf("\n%s\nHash(): %v", tc.reason, err)
+			}
+			if (got == want) != tc.same {
+				t.Errorf("\n%s\nHash(): want same hash %t, got %s and %s", tc.reason, tc.same, want, got)
+			}
+		})
+	}
+}
+
+func TestCompositionSpecHashError(t *testing.T) {
+	cs := &CompositionSpec{
+		Resources: []ComposedTemplate{{
+			Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("nope")}},
+		}},
+	}
+	if _, err := cs.Hash(); err == nil {
+		t.Errorf("Hash(): want an error for an undefined PatchSet, got nil")
+	}
+}
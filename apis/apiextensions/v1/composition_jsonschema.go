@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// JSONSchemaDraft is the JSON Schema draft the schemas returned by
+// PatchJSONSchema and TransformJSONSchema conform to.
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// The values accepted by the enumerated string types used by patches and
+// transforms.
+var jsonSchemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(FromFieldPathPolicy("")):        {string(FromFieldPathPolicyOptional), string(FromFieldPathPolicyRequired)},
+	reflect.TypeOf(CombineStrategy("")):            {string(CombineStrategyString), string(CombineStrategyCoalesce), string(CombineStrategyPercentDiff), string(CombineStrategyMath), string(CombineStrategyArray), string(CombineStrategySet)},
+	reflect.TypeOf(MathCombineOperation("")):       {string(MathCombineOperationAdd), string(MathCombineOperationMultiply)},
+	reflect.TypeOf(SetCombineOperation("")):        {string(SetCombineOperationDifference), string(SetCombineOperationIntersection), string(SetCombineOperationUnion)},
+	reflect.TypeOf(PatchConditionSource("")):       {string(PatchConditionSourceComposite), string(PatchConditionSourceEnvironment)},
+	reflect.TypeOf(TransformOnErrorPolicy("")):     {string(TransformOnErrorPolicyFail), string(TransformOnErrorPolicySkip)},
+	reflect.TypeOf(TransformConditionOperator("")): {string(TransformConditionOperatorEqual), string(TransformConditionOperatorNotEqual), string(TransformConditionOperatorContains), string(TransformConditionOperatorRegexp)},
+	reflect.TypeOf(MathTransformType("")):          {string(MathTransformTypeMultiply), string(MathTransformTypeClampMin), string(MathTransformTypeClampMax), string(MathTransformTypeIdentity), string(MathTransformTypeModulo), string(MathTransformTypeAdd)},
+	reflect.TypeOf(AggregateTransformType("")):     {string(AggregateTransformTypeSum), string(AggregateTransformTypeMax), string(AggregateTransformTypeMin), string(AggregateTransformTypeCount)},
+	reflect.TypeOf(MatchFallbackTo("")):            {string(MatchFallbackToTypeValue), string(MatchFallbackToTypeInput)},
+	reflect.TypeOf(MatchTransformPatternType("")):  {string(MatchTransformPatternTypeLiteral), string(MatchTransformPatternTypeRegexp)},
+	reflect.TypeOf(StringTransformType("")): {
+		string(StringTransformTypeFormat), string(StringTransformTypeConvert), string(StringTransformTypeTrimPrefix),
+		string(StringTransformTypeTrimSuffix), string(StringTransformTypeRegexp), string(StringTransformTypeReplace),
+		string(StringTransformTypeSlice), string(StringTransformTypeEscape),
+	},
+	reflect.TypeOf(StringEscapeContext("")): {string(StringEscapeContextJSON), string(StringEscapeContextShell), string(StringEscapeContextURL)},
+	reflect.TypeOf(StringConversionType("")): {
+		string(StringConversionTypeToUpper), string(StringConversionTypeToLower), string(StringConversionTypeToJSON),
+		string(StringConversionTypeToBase64), string(StringConversionTypeFromBase64), string(StringConversionTypeToSHA1),
+		string(StringConversionTypeToSHA256), string(StringConversionTypeToSHA512), string(StringConversionTypeURLEncode),
+		string(StringConversionTypeURLDecode), string(StringConversionTypeDNS1123),
+	},
+	reflect.TypeOf(TransformIOType("")): {
+		string(TransformIOTypeString), string(TransformIOTypeBool), string(TransformIOTypeInt),
+		string(TransformIOTypeInt64), string(TransformIOTypeInt32), string(TransformIOTypeInt16), string(TransformIOTypeFloat64),
+	},
+	reflect.TypeOf(ConvertTransformFormat("")):     {string(ConvertTransformFormatNone), string(ConvertTransformFormatQuantity), string(ConvertTransformFormatIPv4)},
+	reflect.TypeOf(ValidateFormat("")):             {string(ValidateFormatEmail), string(ValidateFormatHostname)},
+	reflect.TypeOf(SemverTransformOperation("")):   {string(SemverTransformOperationNormalize), string(SemverTransformOperationMajor), string(SemverTransformOperationMinor), string(SemverTransformOperationPatch)},
+	reflect.TypeOf(CaseTransformType("")):          {string(CaseTransformTypeKebab), string(CaseTransformTypeSnake), string(CaseTransformTypeCamel)},
+	reflect.TypeOf(LabelSelectorTransformMode("")): {string(LabelSelectorTransformModeToSelector), string(LabelSelectorTransformModeFromSelector)},
+	reflect.TypeOf(PEMTransformAttribute("")): {
+		string(PEMTransformAttributeCommonName), string(PEMTransformAttributeIssuerCommonName), string(PEMTransformAttributeSerialNumber),
+		string(PEMTransformAttributeNotBefore), string(PEMTransformAttributeNotAfter), string(PEMTransformAttributeDNSNames),
+	},
+}
+
+// PatchJSONSchema returns a JSON Schema describing a Patch. The schema is
+// derived from the Patch type, and requires the fields each patch type
+// requires, including a toFieldPath or toFieldPaths where one is required.
+func PatchJSONSchema() *extv1.JSONSchemaProps {
+	s := jsonSchemaFor(reflect.TypeOf(Patch{}))
+	s.Schema = JSONSchemaDraft
+
+	types := ValidPatchTypes()
+	s.Properties["type"] = withEnum(s.Properties["type"], patchTypeStrings(types))
+	for _, t := range types {
+		v := variant(string(t), t == PatchTypeFromCompositeFieldPath, patchTypeRequiredFields[t])
+		if patchTypeRequiresToFieldPaths[t] {
+			v.AnyOf = []extv1.JSONSchemaProps{{Required: []string{"toFieldPath"}}, {Required: []string{"toFieldPaths"}}}
+		}
+		s.OneOf = append(s.OneOf, v)
+	}
+	return s
+}
+
+// TransformJSONSchema returns a JSON Schema describing a Transform. The schema
+// is derived from the Transform type, and requires the configuration each
+// transform type requires.
+func TransformJSONSchema() *extv1.JSONSchemaProps {
+	s := jsonSchemaFor(reflect.TypeOf(Transform{}))
+	s.Schema = JSONSchemaDraft
+
+	types := ValidTransformTypes()
+	ts := make([]string, len(types))
+	for i, t := range types {
+		ts[i] = string(t)
+	}
+	s.Properties["type"] = withEnum(s.Properties["type"], ts)
+	for _, t := range types {
+		// Each transform type is configured by the field of the same name.
+		s.OneOf = append(s.OneOf, variant(string(t), false, []string{string(t)}))
+	}
+	return s
+}
+
+func patchTypeStrings(types []PatchType) []string {
+	out := make([]string, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
+
+// variant returns a schema that matches objects of the supplied type that
+// specify the supplied required fields. A default type matches objects that
+// omit their type.
+func variant(t string, isDefault bool, required []string) extv1.JSONSchemaProps {
+	v := extv1.JSONSchemaProps{
+		Properties: map[string]extv1.JSONSchemaProps{
+			"type": withEnum(extv1.JSONSchemaProps{}, []string{t}),
+		},
+		Required: append([]string{}, required...),
+	}
+	if !isDefault {
+		v.Required = append([]string{"type"}, v.Required...)
+	}
+	return v
+}
+
+func withEnum(s extv1.JSONSchemaProps, values []string) extv1.JSONSchemaProps {
+	s.Enum = make([]extv1.JSON, len(values))
+	for i, v := range values {
+		raw, _ := json.Marshal(v)
+		s.Enum[i] = extv1.JSON{Raw: raw}
+	}
+	return s
+}
+
+// jsonSchemaFor returns a JSON Schema for the supplied type by reflecting on
+// its kind and JSON struct tags. Fields that are neither pointers nor tagged
+// omitempty are required.
+func jsonSchemaFor(t reflect.Type) *extv1.JSONSchemaProps { //nolint:gocyclo // This is a long but simple/same-y switch.
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Arbitrary JSON may be of any type.
+	if t == reflect.TypeOf(extv1.JSON{}) {
+		return &extv1.JSONSchemaProps{}
+	}
+
+	s := &extv1.JSONSchemaProps{}
+	switch t.Kind() { //nolint:exhaustive // We only handle the kinds used by our API types.
+	case reflect.String:
+		s.Type = "string"
+		if e, ok := jsonSchemaEnums[t]; ok {
+			*s = withEnum(*s, e)
+		}
+	case reflect.Bool:
+		s.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		s.Type = "number"
+	case reflect.Slice:
+		s.Type = "array"
+		s.Items = &extv1.JSONSchemaPropsOrArray{Schema: jsonSchemaFor(t.Elem())}
+	case reflect.Map:
+		s.Type = "object"
+		s.AdditionalProperties = &extv1.JSONSchemaPropsOrBool{Allows: true, Schema: jsonSchemaFor(t.Elem())}
+	case reflect.Struct:
+		s.Type = "object"
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			// An inlined map (e.g. a MapTransform's pairs) makes the struct
+			// itself an arbitrary map.
+			if opts == "inline" && f.Type.Kind() == reflect.Map {
+				return jsonSchemaFor(f.Type)
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if s.Properties == nil {
+				s.Properties = map[string]extv1.JSONSchemaProps{}
+			}
+			s.Properties[name] = *jsonSchemaFor(f.Type)
+			if f.Type.Kind() != reflect.Pointer && !strings.Contains(opts, "omitempty") {
+				s.Required = append(s.Required, name)
+			}
+		}
+		sort.Strings(s.Required)
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestPatchJSONSchema(t *testing.T) {
+	s := PatchJSONSchema()
+
+	if diff := cmp.Diff(len(ValidPatchTypes()), len(s.OneOf)); diff != "" {
+		t.Errorf("PatchJSONSchema(): OneOf should have a variant per patch type: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(len(ValidPatchTypes()), len(s.Properties["type"].Enum)); diff != "" {
+		t.Errorf("PatchJSONSchema(): type should enumerate every patch type: -want, +got:\n%s", diff)
+	}
+
+	cases := map[string]struct {
+		reason string
+		t      PatchType
+		want   []string
+		anyOf  []extv1.JSONSchemaProps
+	}{
+		"DefaultType": {
+			reason: "The default patch type should not require a type",
+			t:      PatchTypeFromCompositeFieldPath,
+			want:   []string{"fromFieldPath"},
+		},
+		"Combine": {
+			reason: "Combine patches should require combine configuration and a toFieldPath or toFieldPaths",
+			t:      PatchTypeCombineToComposite,
+			want:   []string{"type", "combine"},
+			anyOf:  []extv1.JSONSchemaProps{{Required: []string{"toFieldPath"}}, {Required: []string{"toFieldPaths"}}},
+		},
+		"Noop": {
+			reason: "Noop patches should require only a type",
+			t:      PatchTypeNoop,
+			want:   []string{"type"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			for _, v := range s.OneOf {
+				if string(v.Properties["type"].Enum[0].Raw) != `"`+string(tc.t)+`"` {
+					continue
+				}
+				if diff := cmp.Diff(tc.want, v.Required); diff != "" {
+					t.Errorf("%s\nPatchJSONSchema(): -want required, +got required:\n%s", tc.reason, diff)
+				}
+				if diff := cmp.Diff(tc.anyOf, v.AnyOf); diff != "" {
+					t.Errorf("%s\nPatchJSONSchema(): -want anyOf, +got anyOf:\n%s", tc.reason, diff)
+				}
+				return
+			}
+			t.Errorf("%s\nPatchJSONSchema(): no variant for patch type %s", tc.reason, tc.t)
+		})
+	}
+}
+
+func TestTransformJSONSchema(t *testing.T) {
+	s := TransformJSONSchema()
+
+	cases := map[string]struct {
+		reason string
+		got    any
+		want   any
+	}{
+		"Variants": {
+			reason: "OneOf should have a variant per transform type",
+			got:    len(s.OneOf),
+			want:   len(ValidTransformTypes()),
+		},
+		"RequiredType": {
+			reason: "A transform should require a type",
+			got:    s.Required,
+			want:   []string{"type"},
+		},
+		"MapIsArbitraryObject": {
+			reason: "A map transform should be an object with arbitrary properties",
+			got:    s.Properties["map"],
+			want: extv1.JSONSchemaProps{
+				Type:                 "object",
+				AdditionalProperties: &extv1.JSONSchemaPropsOrBool{Allows: true, Schema: &extv1.JSONSchemaProps{}},
+			},
+		},
+		"ConvertRequiresToType": {
+			reason: "A convert transform should require a toType",
+			got:    s.Properties["convert"].Required,
+			want:   []string{"toType"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.got); diff != "" {
+				t.Errorf("%s\nTransformJSONSchema(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	verrors "github.com/crossplane/crossplane/internal/validation/errors"
+)
+
+// A FindingSeverity indicates how severe a Finding is.
+type FindingSeverity string
+
+// Finding severities.
+const (
+	// FindingSeverityError indicates a problem that prevents the
+	// Composition from being rendered as intended.
+	FindingSeverityError FindingSeverity = "Error"
+
+	// FindingSeverityWarning indicates a likely mistake that doesn't
+	// prevent the Composition from being rendered.
+	FindingSeverityWarning FindingSeverity = "Warning"
+)
+
+// A FindingCode is a machine-readable identifier for a kind of Finding.
+type FindingCode string
+
+// Finding codes.
+const (
+	FindingCodePatchSetType           FindingCode = "PatchSetType"
+	FindingCodeUndefinedPatchSet      FindingCode = "UndefinedPatchSet"
+	FindingCodeUnusedPatchSet         FindingCode = "UnusedPatchSet"
+	FindingCodeTransformSetType       FindingCode = "TransformSetType"
+	FindingCodeUndefinedTransformSet  FindingCode = "UndefinedTransformSet"
+	FindingCodeUnusedTransformSet     FindingCode = "UnusedTransformSet"
+	FindingCodeInvalidPatch           FindingCode = "InvalidPatch"
+	FindingCodeInvalidTransform       FindingCode = "InvalidTransform"
+	FindingCodeIncompatibleTransforms FindingCode = "IncompatibleTransforms"
+	FindingCodeConflictingToFieldPath FindingCode = "ConflictingToFieldPath"
+	FindingCodeInvalidComposedSource  FindingCode = "InvalidComposedSource"
+	FindingCodeDependencyCycle        FindingCode = "DependencyCycle"
+	FindingCodePatchPhaseConflict     FindingCode = "PatchPhaseConflict"
+)
+
+// A Finding is a problem found by linting a CompositionSpec.
+// +k8s:deepcopy-gen=false
+type Finding struct {
+	// Severity of the finding.
+	Severity FindingSeverity
+
+	// Code identifies the kind of finding.
+	Code FindingCode
+
+	// Path of the offending field, e.g. spec.resources[0].patches[1].
+	Path string
+
+	// ResourceIndex is the index of the resource template the finding
+	// applies to, or -1 if it doesn't apply to a resource template.
+	ResourceIndex int
+
+	// PatchIndex is the index of the patch the finding applies to within
+	// its resource template, PatchSet, or environment, or -1 if it doesn't
+	// apply to a patch.
+	PatchIndex int
+
+	// Message is a human-readable description of the finding.
+	Message string
+
+	// PatchDescription is the description of the patch the finding applies
+	// to, if any, to help identify it.
+	PatchDescription string
+}
+
+// String returns a human-readable representation of the finding.
+func (f Finding) String() string {
+	if f.PatchDescription != "" {
+		return fmt.Sprintf("%s: %s (%q): %s (%s)", f.Severity, f.Path, f.PatchDescription, f.Message, f.Code)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Severity, f.Path, f.Message, f.Code)
+}
+
+// +k8s:deepcopy-gen=false
+type lintOptions struct {
+	allowOverrides bool
+}
+
+// A LintOption configures how a CompositionSpec is linted.
+// +k8s:deepcopy-gen=false
+type LintOption func(o *lintOptions)
+
+// AllowToFieldPathOverrides disables the warning that is otherwise reported
+// when more than one patch of a resource writes to the same field path. Use it
+// when patches intentionally override each other.
+func AllowToFieldPathOverrides() LintOption {
+	return func(o *lintOptions) {
+		o.allowOverrides = true
+	}
+}
+
+// Lint the CompositionSpec, returning all findings. Lint resolves references
+// to PatchSets and TransformSets, checks that each patch has the fields its
+// type requires and reads from another of the resource templates if it reads
+// from a composed resource, checks that cross-resource patches don't form a
+// cycle and that no patch is overridden by a patch of a later phase despite
+// its priority, and validates each transform and that the output of each
+// transform may be input to the next. Unlike Validate it doesn't stop at the
+// first problem with a patch, and it reports likely mistakes as warnings.
+func (cs *CompositionSpec) Lint(o ...LintOption) []Finding {
+	lo := &lintOptions{}
+	for _, fn := range o {
+		fn(lo)
+	}
+
+	defined := make(map[string][]Patch, len(cs.PatchSets))
+	for _, s := range cs.PatchSets {
+		defined[s.Name] = s.Patches
+	}
+	used := make(map[string]bool, len(cs.PatchSets))
+
+	fs := cs.lintPatchSets()
+	fs = append(fs, cs.lintTransformSets()...)
+	fs = append(fs, cs.lintResources(lo, defined, used)...)
+	fs = append(fs, cs.lintEnvironment()...)
+	fs = append(fs, cs.lintUnusedPatchSets(used)...)
+	fs = append(fs, cs.lintTransformSetReferences()...)
+	fs = append(fs, cs.lintApplyOrder()...)
+	return append(fs, cs.lintPatchPhases()...)
+}
+
+// lintPatchSets returns findings for the patches of each PatchSet.
+func (cs *CompositionSpec) lintPatchSets() []Finding {
+	var fs []Finding
+	for i, s := range cs.PatchSets {
+		for j, p := range s.Patches {
+			path := field.NewPath("spec", "patchSets").Index(i).Child("patches").Index(j)
+			if p.Type == PatchTypePatchSet {
+				fs = append(fs, Finding{
+					Severity:         FindingSeverityError,
+					Code:             FindingCodePatchSetType,
+					Path:             path.Child("type").String(),
+					ResourceIndex:    -1,
+					PatchIndex:       j,
+					Message:          errPatchSetType,
+					PatchDescription: p.GetDescription(),
+				})
+				continue
+			}
+			fs = append(fs, lintPatch(p, path, -1, j)...)
+			fs = append(fs, cs.lintComposedSource(p, path, -1, j)...)
+		}
+	}
+	return fs
+}
+
+// lintTransformSets returns findings for the transforms of each TransformSet.
+func (cs *CompositionSpec) lintTransformSets() []Finding {
+	finding := func(code FindingCode, err *field.Error, path *field.Path) Finding {
+		err = verrors.WrapFieldError(err, path)
+		return Finding{
+			Severity:      FindingSeverityError,
+			Code:          code,
+			Path:          err.Field,
+			ResourceIndex: -1,
+			PatchIndex:    -1,
+			Message:       err.ErrorBody(),
+		}
+	}
+
+	var fs []Finding
+	for i, s := range cs.TransformSets {
+		path := field.NewPath("spec", "transformSets").Index(i)
+		nested := false
+		for k, t := range s.Transforms {
+			if t.Type != TransformTypeTransformSet {
+				continue
+			}
+			nested = true
+			fs = append(fs, Finding{
+				Severity:      FindingSeverityError,
+				Code:          FindingCodeTransformSetType,
+				Path:          path.Child("transforms").Index(k).Child("type").String(),
+				ResourceIndex: -1,
+				PatchIndex:    -1,
+				Message:       errTransformSetType,
+			})
+		}
+		if nested {
+			// A TransformSet that references another can't be inlined,
+			// so its transforms are never applied.
+			continue
+		}
+		fs = append(fs, lintTransforms(s.Transforms, path, finding)...)
+	}
+	return fs
+}
+
+// lintResources returns findings for the patches of each resource. It records
+// the names of the PatchSets that resources reference in the supplied used
+// map.
+func (cs *CompositionSpec) lintResources(lo *lintOptions, defined map[string][]Patch, used map[string]bool) []Finding {
+	var fs []Finding
+	for i, r := range cs.Resources {
+		for j, p := range r.Patches {
+			path := field.NewPath("spec", "resources").Index(i).Child("patches").Index(j)
+			if p.Type == PatchTypePatchSet && p.PatchSetName != nil {
+				used[*p.PatchSetName] = true
+				if _, ok := defined[*p.PatchSetName]; !ok {
+					fs = append(fs, Finding{
+						Severity:         FindingSeverityError,
+						Code:             FindingCodeUndefinedPatchSet,
+						Path:             path.Child("patchSetName").String(),
+						ResourceIndex:    i,
+						PatchIndex:       j,
+						Message:          fmt.Sprintf(errFmtUndefinedPatchSet, *p.PatchSetName),
+						PatchDescription: p.GetDescription(),
+					})
+				}
+			}
+			fs = append(fs, lintPatch(p, path, i, j)...)
+			fs = append(fs, cs.lintComposedSource(p, path, i, j)...)
+		}
+		if !lo.allowOverrides {
+			fs = append(fs, lintToFieldPaths(r.Patches, defined, i)...)
+		}
+	}
+	return fs
+}
+
+// lintEnvironment returns findings for the environment patches.
+func (cs *CompositionSpec) lintEnvironment() []Finding {
+	if cs.Environment == nil {
+		return nil
+	}
+	var fs []Finding
+	for j, ep := range cs.Environment.Patches {
+		// Environment patches are linted like regular patches.
+		p := Patch{
+			Type:          ep.Type,
+			FromFieldPath: ep.FromFieldPath,
+			Combine:       ep.Combine,
+			ToFieldPath:   ep.ToFieldPath,
+			ToFieldPaths:  ep.ToFieldPaths,
+			Transforms:    ep.Transforms,
+			Policy:        ep.Policy,
+		}
+		fs = append(fs, lintPatch(p, field.NewPath("spec", "environment", "patches").Index(j), -1, j)...)
+	}
+	return fs
+}
+
+// lintUnusedPatchSets returns a warning for each PatchSet that isn't in the
+// supplied used map.
+func (cs *CompositionSpec) lintUnusedPatchSets(used map[string]bool) []Finding {
+	var fs []Finding
+	for i, s := range cs.PatchSets {
+		if used[s.Name] {
+			continue
+		}
+		fs = append(fs, Finding{
+			Severity:      FindingSeverityWarning,
+			Code:          FindingCodeUnusedPatchSet,
+			Path:          field.NewPath("spec", "patchSets").Index(i).String(),
+			ResourceIndex: -1,
+			PatchIndex:    -1,
+			Message:       fmt.Sprintf("PatchSet %s is not used by any resource", s.Name),
+		})
+	}
+	return fs
+}
+
+// lintTransformSetReferences returns an error for each reference to a
+// TransformSet that isn't defined, and a warning for each TransformSet that no
+// patch references.
+func (cs *CompositionSpec) lintTransformSetReferences() []Finding {
+	defined := make(map[string]bool, len(cs.TransformSets))
+	for _, s := range cs.TransformSets {
+		defined[s.Name] = true
+	}
+	used := make(map[string]bool, len(cs.TransformSets))
+
+	var fs []Finding
+	cs.walkTransformSetReferences(func(p Patch, name string, path *field.Path, resource, patch int) {
+		used[name] = true
+		if defined[name] {
+			return
+		}
+		fs = append(fs, Finding{
+			Severity:         FindingSeverityError,
+			Code:             FindingCodeUndefinedTransformSet,
+			Path:             path.Child("transformSetName").String(),
+			ResourceIndex:    resource,
+			PatchIndex:       patch,
+			Message:          fmt.Sprintf(errFmtUndefinedTransformSet, name),
+			PatchDescription: p.GetDescription(),
+		})
+	})
+	for i, s := range cs.TransformSets {
+		if used[s.Name] {
+			continue
+		}
+		fs = append(fs, Finding{
+			Severity:      FindingSeverityWarning,
+			Code:          FindingCodeUnusedTransformSet,
+			Path:          field.NewPath("spec", "transformSets").Index(i).String(),
+			ResourceIndex: -1,
+			PatchIndex:    -1,
+			Message:       fmt.Sprintf("TransformSet %s is not used by any patch", s.Name),
+		})
+	}
+	return fs
+}
+
+// lintComposedSource returns an error if the supplied patch of the resource
+// template at the supplied index reads from a composed resource that isn't
+// composed from another of the CompositionSpec's resource templates. Invalid
+// selectors are reported by lintPatch.
+func (cs *CompositionSpec) lintComposedSource(p Patch, path *field.Path, resource, patch int) []Finding {
+	if p.FromComposedResource == nil || p.FromComposedResource.Validate() != nil {
+		return nil
+	}
+	err := cs.validateFromComposedResource(p, resource)
+	if err == nil {
+		return nil
+	}
+	err = verrors.WrapFieldError(err, path)
+	return []Finding{{
+		Severity:         FindingSeverityError,
+		Code:             FindingCodeInvalidComposedSource,
+		Path:             err.Field,
+		ResourceIndex:    resource,
+		PatchIndex:       patch,
+		Message:          err.ErrorBody(),
+		PatchDescription: p.GetDescription(),
+	}}
+}
+
+// lintApplyOrder returns an error if the resource templates can't be ordered
+// such that each is applied after the templates its patches read from.
+// References to PatchSets are inlined regardless of their conditions. Invalid
+// references are reported by other findings.
+func (cs *CompositionSpec) lintApplyOrder() []Finding {
+	ct, err := cs.InlinedResources()
+	if err != nil {
+		return nil
+	}
+	if _, err := ApplyOrder(ct); err != nil {
+		return []Finding{{
+			Severity:      FindingSeverityError,
+			Code:          FindingCodeDependencyCycle,
+			Path:          field.NewPath("spec", "resources").String(),
+			ResourceIndex: -1,
+			PatchIndex:    -1,
+			Message:       err.Error(),
+		}}
+	}
+	return nil
+}
+
+// lintPatchPhases returns an error for each patch of a resource template that
+// is overridden by a patch that Priority would apply before it, because the
+// overriding patch belongs to a later phase.
+func (cs *CompositionSpec) lintPatchPhases() []Finding {
+	var fs []Finding
+	cs.walkPatchPhaseConflicts(func(resource int, p, o inlinedPatch, path string) {
+		fs = append(fs, Finding{
+			Severity:         FindingSeverityError,
+			Code:             FindingCodePatchPhaseConflict,
+			Path:             field.NewPath("spec", "resources").Index(resource).Child("patches").Index(p.index).String(),
+			ResourceIndex:    resource,
+			PatchIndex:       p.index,
+			Message:          patchPhaseConflict(p, o, path),
+			PatchDescription: p.patch.GetDescription(),
+		})
+	})
+	return fs
+}
+
+// lintToFieldPaths returns a warning for each field path that more than one of
+// the supplied patches of a resource writes to. Only the patch that is applied
+// last takes effect, which is usually a mistake. Patches of referenced
+// PatchSets are attributed to the patch that references them. Patches of
+// PatchSets whose references have mutually exclusive conditions don't
+// conflict, because they are never applied together.
+func lintToFieldPaths(ps []Patch, sets map[string][]Patch, resource int) []Finding {
+	targets := make([]string, 0)
+	writers := make(map[string][]inlinedPatch)
+	for _, ip := range inlinePatchesOf(ps, sets) {
+		for _, k := range ip.patch.targets() {
+			if _, ok := writers[k]; !ok {
+				targets = append(targets, k)
+			}
+			writers[k] = append(writers[k], ip)
+		}
+	}
+
+	var fs []Finding
+	for _, k := range targets {
+		conflicting := conflictingWriters(writers[k])
+		if len(conflicting) < 2 {
+			continue
+		}
+		descs := make([]string, len(conflicting))
+		last := conflicting[0]
+		for i, ip := range conflicting {
+			descs[i] = ip.desc
+			if ip.appliedAfter(last) {
+				last = ip
+			}
+		}
+		r, path, _ := strings.Cut(k, ":")
+		fs = append(fs, Finding{
+			Severity:      FindingSeverityWarning,
+			Code:          FindingCodeConflictingToFieldPath,
+			Path:          field.NewPath("spec", "resources").Index(resource).Child("patches").String(),
+			ResourceIndex: resource,
+			PatchIndex:    -1,
+			Message:       fmt.Sprintf("%s all write to field path %s of the %s resource; only %s takes effect", strings.Join(descs, ", "), path, r, last.desc),
+		})
+	}
+	return fs
+}
+
+// conflictingWriters returns the supplied patches that may be applied together
+// with at least one of the others.
+func conflictingWriters(ps []inlinedPatch) []inlinedPatch {
+	var out []inlinedPatch
+	for i := range ps {
+		for j := range ps {
+			if i != j && !exclusive(ps[i].when, ps[j].when) {
+				out = append(out, ps[i])
+				break
+			}
+		}
+	}
+	return out
+}
+
+// lintPatch returns the findings for the supplied patch at the supplied path.
+func lintPatch(p Patch, path *field.Path, resource, patch int) []Finding {
+	var fs []Finding
+	finding := func(code FindingCode, err *field.Error, path *field.Path) Finding {
+		err = verrors.WrapFieldError(err, path)
+		return Finding{
+			Severity:         FindingSeverityError,
+			Code:             code,
+			Path:             err.Field,
+			ResourceIndex:    resource,
+			PatchIndex:       patch,
+			Message:          err.ErrorBody(),
+			PatchDescription: p.GetDescription(),
+		}
+	}
+
+	for _, err := range p.fieldErrors() {
+		fs = append(fs, finding(FindingCodeInvalidPatch, err, path))
+	}
+
+	if p.Combine != nil {
+		for k, v := range p.Combine.Variables {
+			fs = append(fs, lintTransforms(v.Transforms, path.Child("combine", "variables").Index(k), finding)...)
+		}
+	}
+	return append(fs, lintTransforms(p.Transforms, path, finding)...)
+}
+
+// lintTransforms returns the findings for the supplied chain of transforms,
+// whose parent is at the supplied path.
+func lintTransforms(ts []Transform, path *field.Path, finding func(FindingCode, *field.Error, *field.Path) Finding) []Finding {
+	var fs []Finding
+	for k, t := range ts {
+		if err := t.Validate(false); err != nil {
+			fs = append(fs, finding(FindingCodeInvalidTransform, err, path.Child("transforms").Index(k)))
+		}
+	}
+	if len(fs) > 0 {
+		// Checking the compatibility of invalid transforms would only
+		// produce noise.
+		return fs
+	}
+
+	for k := 1; k < len(ts); k++ {
+		out, err := ts[k-1].GetOutputType()
+		if err != nil || out == nil {
+			continue
+		}
+		if err := ts[k].ValidateInput(*out); err != nil {
+			fs = append(fs, finding(FindingCodeIncompatibleTransforms, field.Invalid(field.NewPath("type"), ts[k].Type, err.Error()), path.Child("transforms").Index(k)))
+			// Subsequent transforms receive input of an unknown type.
+			break
+		}
+	}
+	return fs
+}
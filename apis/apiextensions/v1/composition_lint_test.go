@@ -0,0 +1,480 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestCompositionSpecLint(t *testing.T) {
+	truncate := Transform{Type: TransformTypeTruncate, Truncate: &TruncateTransform{MaxLength: 5}}
+	multiply := Transform{Type: TransformTypeMath, Math: &MathTransform{Multiply: pointer.Int64(2)}}
+	whenEnv := func(v string) *PatchCondition {
+		return &PatchCondition{FieldPath: "spec.env", Equals: &extv1.JSON{Raw: []byte(v)}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		cs     *CompositionSpec
+		o      []LintOption
+		want   []Finding
+	}{
+		"NoFindings": {
+			reason: "A valid CompositionSpec should have no findings.",
+			cs: &CompositionSpec{
+				PatchSets: []PatchSet{{Name: "ps", Patches: []Patch{{FromFieldPath: pointer.String("spec.a")}}}},
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+						{FromFieldPath: pointer.String("spec.b"), Transforms: []Transform{multiply, multiply}},
+					},
+				}},
+			},
+		},
+		"PatchSetType": {
+			reason: "A PatchSet that contains a PatchSet patch should be an error.",
+			cs: &CompositionSpec{
+				PatchSets: []PatchSet{{Name: "ps", Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("other")}}}},
+				Resources: []ComposedTemplate{{Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")}}}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityError,
+				Code:          FindingCodePatchSetType,
+				Path:          "spec.patchSets[0].patches[0].type",
+				ResourceIndex: -1,
+				PatchIndex:    0,
+				Message:       errPatchSetType,
+			}},
+		},
+		"UndefinedPatchSet": {
+			reason: "A reference to an undefined PatchSet should be an error.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{}, {Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("nope")}}}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityError,
+				Code:          FindingCodeUndefinedPatchSet,
+				Path:          "spec.resources[1].patches[0].patchSetName",
+				ResourceIndex: 1,
+				PatchIndex:    0,
+				Message:       "cannot find PatchSet by name nope",
+			}},
+		},
+		"UnusedPatchSet": {
+			reason: "A PatchSet that no resource uses should be a warning.",
+			cs: &CompositionSpec{
+				PatchSets: []PatchSet{{Name: "ps"}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityWarning,
+				Code:          FindingCodeUnusedPatchSet,
+				Path:          "spec.patchSets[0]",
+				ResourceIndex: -1,
+				PatchIndex:    -1,
+				Message:       "PatchSet ps is not used by any resource",
+			}},
+		},
+		"TransformSetType": {
+			reason: "A TransformSet that contains a transformSet transform should be an error.",
+			cs: &CompositionSpec{
+				TransformSets: []TransformSet{{Name: "ts", Transforms: []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}}}},
+				Resources: []ComposedTemplate{{Patches: []Patch{{
+					FromFieldPath: pointer.String("spec.a"),
+					Transforms:    []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+				}}}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityError,
+				Code:          FindingCodeTransformSetType,
+				Path:          "spec.transformSets[0].transforms[0].type",
+				ResourceIndex: -1,
+				PatchIndex:    -1,
+				Message:       errTransformSetType,
+			}},
+		},
+		"InvalidTransformSetTransform": {
+			reason: "An invalid transform of a TransformSet should be an error.",
+			cs: &CompositionSpec{
+				TransformSets: []TransformSet{{Name: "ts", Transforms: []Transform{{Type: TransformTypeMath}}}},
+				Resources: []ComposedTemplate{{Patches: []Patch{{
+					FromFieldPath: pointer.String("spec.a"),
+					Transforms:    []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+				}}}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityError,
+				Code:          FindingCodeInvalidTransform,
+				Path:          "spec.transformSets[0].transforms[0].math",
+				ResourceIndex: -1,
+				PatchIndex:    -1,
+				Message:       "Required value: given transform type math requires configuration",
+			}},
+		},
+		"UndefinedTransformSet": {
+			reason: "A reference to an undefined TransformSet should be an error.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{}, {Patches: []Patch{{
+					FromFieldPath: pointer.String("spec.a"),
+					Transforms:    []Transform{multiply, {Type: TransformTypeTransformSet, TransformSetName: pointer.String("nope")}},
+					Description:   pointer.String("Double the size"),
+				}}}},
+			},
+			want: []Finding{{
+				Severity:         FindingSeverityError,
+				Code:             FindingCodeUndefinedTransformSet,
+				Path:             "spec.resources[1].patches[0].transforms[1].transformSetName",
+				ResourceIndex:    1,
+				PatchIndex:       0,
+				Message:          "cannot find TransformSet by name nope",
+				PatchDescription: "Double the size",
+			}},
+		},
+		"UnusedTransformSet": {
+			reason: "A TransformSet that no patch uses should be a warning.",
+			cs: &CompositionSpec{
+				TransformSets: []TransformSet{{Name: "ts", Transforms: []Transform{multiply}}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityWarning,
+				Code:          FindingCodeUnusedTransformSet,
+				Path:          "spec.transformSets[0]",
+				ResourceIndex: -1,
+				PatchIndex:    -1,
+				Message:       "TransformSet ts is not used by any patch",
+			}},
+		},
+		"AllFindingsOfAPatch": {
+			reason: "A patch that is missing a required field and has an invalid transform should have a finding for each.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{Patches: []Patch{{
+					Type:       PatchTypeFromCompositeFieldPath,
+					Transforms: []Transform{multiply, {Type: TransformTypeMath}},
+				}}}},
+			},
+			want: []Finding{
+				{
+					Severity:      FindingSeverityError,
+					Code:          FindingCodeInvalidPatch,
+					Path:          "spec.resources[0].patches[0].fromFieldPath",
+					ResourceIndex: 0,
+					PatchIndex:    0,
+					Message:       "Required value: fromFieldPath must be set for patch type FromCompositeFieldPath",
+				},
+				{
+					Severity:      FindingSeverityError,
+					Code:          FindingCodeInvalidTransform,
+					Path:          "spec.resources[0].patches[0].transforms[1].math",
+					ResourceIndex: 0,
+					PatchIndex:    0,
+					Message:       "Required value: given transform type math requires configuration",
+				},
+			},
+		},
+		"AllRequiredFieldsOfAPatch": {
+			reason: "A patch that is missing more than one required field should have a finding for each.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{Patches: []Patch{{
+					Type: PatchTypeFromComposedConnectionSecretKey,
+				}}}},
+			},
+			want: []Finding{
+				{
+					Severity:      FindingSeverityError,
+					Code:          FindingCodeInvalidPatch,
+					Path:          "spec.resources[0].patches[0].fromComposedResource",
+					ResourceIndex: 0,
+					PatchIndex:    0,
+					Message:       "Required value: fromComposedResource must be set for patch type FromComposedConnectionSecretKey",
+				},
+				{
+					Severity:      FindingSeverityError,
+					Code:          FindingCodeInvalidPatch,
+					Path:          "spec.resources[0].patches[0].connectionSecretKey",
+					ResourceIndex: 0,
+					PatchIndex:    0,
+					Message:       "Required value: connectionSecretKey must be set for patch type FromComposedConnectionSecretKey",
+				},
+				{
+					Severity:      FindingSeverityError,
+					Code:          FindingCodeInvalidPatch,
+					Path:          "spec.resources[0].patches[0].toFieldPath",
+					ResourceIndex: 0,
+					PatchIndex:    0,
+					Message:       "Required value: toFieldPath or toFieldPaths must be set for patch type FromComposedConnectionSecretKey",
+				},
+			},
+		},
+		"InvalidComposedSource": {
+			reason: "A patch that reads from a resource template that doesn't exist, or from its own, should be an error.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{
+					Name: pointer.String("a"),
+					Patches: []Patch{
+						{
+							Type:                 PatchTypeFromComposedFieldPath,
+							FromComposedResource: &ComposedResourceSelector{Name: pointer.String("b")},
+							FromFieldPath:        pointer.String("status.id"),
+						},
+						{
+							Type:                 PatchTypeFromComposedFieldPath,
+							FromComposedResource: &ComposedResourceSelector{Index: pointer.Int(0)},
+							FromFieldPath:        pointer.String("status.id"),
+							ToFieldPath:          pointer.String("spec.id"),
+						},
+					},
+				}},
+			},
+			want: []Finding{
+				{
+					Severity:      FindingSeverityError,
+					Code:          FindingCodeInvalidComposedSource,
+					Path:          "spec.resources[0].patches[0].fromComposedResource.name",
+					ResourceIndex: 0,
+					PatchIndex:    0,
+					Message:       `Invalid value: "b": no resource template exists with this name`,
+				},
+				{
+					Severity:      FindingSeverityError,
+					Code:          FindingCodeInvalidComposedSource,
+					Path:          "spec.resources[0].patches[1].fromComposedResource.index",
+					ResourceIndex: 0,
+					PatchIndex:    1,
+					Message:       "Invalid value: 0: a composed resource cannot patch from itself",
+				},
+			},
+		},
+		"DependencyCycle": {
+			reason: "Resource templates whose patches read from each other should be an error.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{
+					{
+						Name: pointer.String("a"),
+						Patches: []Patch{{
+							Type:                 PatchTypeFromComposedFieldPath,
+							FromComposedResource: &ComposedResourceSelector{Name: pointer.String("b")},
+							FromFieldPath:        pointer.String("status.id"),
+						}},
+					},
+					{
+						Name: pointer.String("b"),
+						Patches: []Patch{{
+							Type:                 PatchTypeFromComposedFieldPath,
+							FromComposedResource: &ComposedResourceSelector{Name: pointer.String("a")},
+							FromFieldPath:        pointer.String("status.id"),
+						}},
+					},
+				},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityError,
+				Code:          FindingCodeDependencyCycle,
+				Path:          "spec.resources",
+				ResourceIndex: -1,
+				PatchIndex:    -1,
+				Message:       "cannot order resources a, b: cross-resource patches form a dependency cycle",
+			}},
+		},
+		"PatchDescription": {
+			reason: "A finding for a patch should include its description.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{Patches: []Patch{{
+					Type:        PatchTypeToCompositeFieldPath,
+					Description: pointer.String("Report the instance's endpoint"),
+				}}}},
+			},
+			want: []Finding{{
+				Severity:         FindingSeverityError,
+				Code:             FindingCodeInvalidPatch,
+				Path:             "spec.resources[0].patches[0].fromFieldPath",
+				ResourceIndex:    0,
+				PatchIndex:       0,
+				Message:          "Required value: fromFieldPath must be set for patch type ToCompositeFieldPath",
+				PatchDescription: "Report the instance's endpoint",
+			}},
+		},
+		"IncompatibleTransforms": {
+			reason: "A transform that can't accept the output of the previous transform should be an error.",
+			cs: &CompositionSpec{
+				Environment: &EnvironmentConfiguration{Patches: []EnvironmentPatch{{
+					FromFieldPath: pointer.String("spec.a"),
+					Transforms:    []Transform{truncate, multiply, multiply},
+				}}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityError,
+				Code:          FindingCodeIncompatibleTransforms,
+				Path:          "spec.environment.patches[0].transforms[1].type",
+				ResourceIndex: -1,
+				PatchIndex:    0,
+				Message:       `Invalid value: "math": math transform can only be used with numeric types, got string`,
+			}},
+		},
+		"ConflictingToFieldPath": {
+			reason: "More than one patch writing to the same field path of a resource should be a warning.",
+			cs: &CompositionSpec{
+				PatchSets: []PatchSet{{Name: "ps", Patches: []Patch{{FromFieldPath: pointer.String("spec.region")}}}},
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a"), ToFieldPath: pointer.String("spec.region")},
+						{Type: PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String("spec.region")},
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+					},
+				}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityWarning,
+				Code:          FindingCodeConflictingToFieldPath,
+				Path:          "spec.resources[0].patches",
+				ResourceIndex: 0,
+				PatchIndex:    -1,
+				Message:       "patches[0], patches[2] (PatchSet ps) all write to field path spec.region of the composed resource; only patches[2] (PatchSet ps) takes effect",
+			}},
+		},
+		"ConflictingToFieldPaths": {
+			reason: "A patch writing to one of the ToFieldPaths of another patch should be a warning.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a"), ToFieldPaths: []string{"spec.x", "spec.region"}},
+						{FromFieldPath: pointer.String("spec.region")},
+					},
+				}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityWarning,
+				Code:          FindingCodeConflictingToFieldPath,
+				Path:          "spec.resources[0].patches",
+				ResourceIndex: 0,
+				PatchIndex:    -1,
+				Message:       "patches[0], patches[1] all write to field path spec.region of the composed resource; only patches[1] takes effect",
+			}},
+		},
+		"ExclusivePatchSetConditions": {
+			reason: "Patches of PatchSets whose references have mutually exclusive conditions should not conflict.",
+			cs: &CompositionSpec{
+				PatchSets: []PatchSet{
+					{Name: "dev", Patches: []Patch{{FromFieldPath: pointer.String("spec.dev.size"), ToFieldPath: pointer.String("spec.size")}}},
+					{Name: "prod", Patches: []Patch{{FromFieldPath: pointer.String("spec.prod.size"), ToFieldPath: pointer.String("spec.size")}}},
+				},
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("dev"), When: whenEnv(`"dev"`)},
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("prod"), When: whenEnv(`"prod"`)},
+					},
+				}},
+			},
+		},
+		"OverlappingPatchSetConditions": {
+			reason: "Patches that may be applied together with a conditional PatchSet's patches should conflict with them.",
+			cs: &CompositionSpec{
+				PatchSets: []PatchSet{
+					{Name: "dev", Patches: []Patch{{FromFieldPath: pointer.String("spec.dev.size"), ToFieldPath: pointer.String("spec.size")}}},
+					{Name: "prod", Patches: []Patch{{FromFieldPath: pointer.String("spec.prod.size"), ToFieldPath: pointer.String("spec.size")}}},
+				},
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("dev"), When: whenEnv(`"dev"`)},
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("prod"), When: whenEnv(`"prod"`)},
+						{FromFieldPath: pointer.String("spec.size")},
+					},
+				}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityWarning,
+				Code:          FindingCodeConflictingToFieldPath,
+				Path:          "spec.resources[0].patches",
+				ResourceIndex: 0,
+				PatchIndex:    -1,
+				Message:       "patches[0] (PatchSet dev), patches[1] (PatchSet prod), patches[2] all write to field path spec.size of the composed resource; only patches[2] takes effect",
+			}},
+		},
+		"ConflictingToFieldPathPriority": {
+			reason: "The warning for patches writing to the same field path should name the patch with the highest priority.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a"), ToFieldPath: pointer.String("spec.region"), Priority: pointer.Int(1)},
+						{FromFieldPath: pointer.String("spec.region")},
+					},
+				}},
+			},
+			want: []Finding{{
+				Severity:      FindingSeverityWarning,
+				Code:          FindingCodeConflictingToFieldPath,
+				Path:          "spec.resources[0].patches",
+				ResourceIndex: 0,
+				PatchIndex:    -1,
+				Message:       "patches[0], patches[1] all write to field path spec.region of the composed resource; only patches[0] takes effect",
+			}},
+		},
+		"PatchPhaseConflict": {
+			reason: "A patch that is overridden by a patch of a later phase despite its priority should be an error.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{
+					{Name: pointer.String("a")},
+					{
+						Name: pointer.String("b"),
+						Patches: []Patch{
+							{
+								Type:                 PatchTypeFromComposedFieldPath,
+								FromComposedResource: &ComposedResourceSelector{Name: pointer.String("a")},
+								FromFieldPath:        pointer.String("status.id"),
+								ToFieldPath:          pointer.String("spec.id"),
+							},
+							{FromFieldPath: pointer.String("spec.id"), Priority: pointer.Int(1), Description: pointer.String("Override the ID")},
+						},
+					},
+				},
+			},
+			o: []LintOption{AllowToFieldPathOverrides()},
+			want: []Finding{{
+				Severity:         FindingSeverityError,
+				Code:             FindingCodePatchPhaseConflict,
+				Path:             "spec.resources[1].patches[1]",
+				ResourceIndex:    1,
+				PatchIndex:       1,
+				Message:          "patches[1] should override patches[0], which writes to the same field path spec.id, but FromComposedFieldPath patches are applied after FromCompositeFieldPath patches regardless of priority",
+				PatchDescription: "Override the ID",
+			}},
+		},
+		"AllowToFieldPathOverrides": {
+			reason: "More than one patch writing to the same field path of a resource should be allowed when overrides are allowed.",
+			cs: &CompositionSpec{
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a"), ToFieldPath: pointer.String("spec.region")},
+						{FromFieldPath: pointer.String("spec.b"), ToFieldPath: pointer.String("spec.region")},
+					},
+				}},
+			},
+			o: []LintOption{AllowToFieldPathOverrides()},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.cs.Lint(tc.o...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLint(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
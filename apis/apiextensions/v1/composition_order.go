@@ -0,0 +1,298 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtOrderResources     = "cannot order resources %s"
+	errFmtPatchPhaseConflict = "%s should override %s, which writes to the same field path %s, but %s patches are applied after %s patches regardless of priority"
+)
+
+// ApplyOrder returns the indices of the supplied composed resource templates
+// in an order in which they may be applied, such that every template is
+// applied after the templates its FromComposedFieldPath and
+// FromComposedConnectionSecretKey patches read from.
+// Templates are otherwise kept in the order they are supplied. PatchSets must
+// be inlined before the order is determined. References to templates that
+// don't exist and references of a template to itself are ignored. The
+// returned error matches ErrDependencyCycle if the references form a cycle.
+func ApplyOrder(cts []ComposedTemplate) ([]int, error) {
+	dependents, blocked := dependencies(cts)
+
+	order := make([]int, 0, len(cts))
+	ordered := make([]bool, len(cts))
+	for len(order) < len(cts) {
+		next := nextUnblocked(ordered, blocked)
+		if next < 0 {
+			return nil, errors.Wrapf(ErrDependencyCycle, errFmtOrderResources, strings.Join(unordered(cts, ordered), ", "))
+		}
+		ordered[next] = true
+		order = append(order, next)
+		for _, d := range dependents[next] {
+			blocked[d]--
+		}
+	}
+	return order, nil
+}
+
+// dependencies returns the dependencies between the supplied templates.
+// dependents[j] are the templates that read from template j, and blocked[i] is
+// the number of templates template i reads from.
+func dependencies(cts []ComposedTemplate) (dependents [][]int, blocked []int) {
+	names := make(map[string]int, len(cts))
+	for i, t := range cts {
+		if t.Name != nil {
+			names[*t.Name] = i
+		}
+	}
+
+	dependents = make([][]int, len(cts))
+	blocked = make([]int, len(cts))
+	for i, t := range cts {
+		seen := map[int]bool{}
+		for _, p := range t.Patches {
+			if !p.readsFromComposedResource() || p.FromComposedResource == nil {
+				continue
+			}
+			j, ok := templateIndex(*p.FromComposedResource, names, len(cts))
+			if !ok || j == i || seen[j] {
+				continue
+			}
+			seen[j] = true
+			dependents[j] = append(dependents[j], i)
+			blocked[i]++
+		}
+	}
+	return dependents, blocked
+}
+
+// nextUnblocked returns the first template that isn't ordered and doesn't read
+// from any unordered template, in order to keep templates in their supplied
+// order where possible. It returns -1 if there is no such template.
+func nextUnblocked(ordered []bool, blocked []int) int {
+	for i := range ordered {
+		if !ordered[i] && blocked[i] == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// templateIndex returns the index of the template selected by the supplied
+// selector, and whether it exists.
+func templateIndex(s ComposedResourceSelector, names map[string]int, n int) (int, bool) {
+	if s.Index != nil {
+		return *s.Index, *s.Index >= 0 && *s.Index < n
+	}
+	i, ok := names[pointer.StringDeref(s.Name, "")]
+	return i, ok
+}
+
+// unordered returns the names of the templates that haven't been ordered. An
+// anonymous template is named by its index.
+func unordered(cts []ComposedTemplate, ordered []bool) []string {
+	out := make([]string, 0)
+	for i, t := range cts {
+		if !ordered[i] {
+			out = append(out, pointer.StringDeref(t.Name, strconv.Itoa(i)))
+		}
+	}
+	return out
+}
+
+// Phases in which the patches of a composed resource template are applied to
+// the composed resource. Patches are applied in ascending order of priority
+// within each phase.
+const (
+	// phaseNone is the phase of patches that don't write to the composed
+	// resource.
+	phaseNone = iota - 1
+
+	// phaseComposite is the phase of patches that read from the composite
+	// resource, the environment, or the controller's configuration.
+	phaseComposite
+
+	// phaseConnectionSecret is the phase of patches that read from the
+	// composite resource's connection secret.
+	phaseConnectionSecret
+
+	// phaseComposed is the phase of patches that read from other composed
+	// resources, which must be applied first.
+	phaseComposed
+)
+
+// composedPhase returns the phase in which the patch is applied to the
+// composed resource, or phaseNone if it doesn't write to it.
+func (p *Patch) composedPhase() int {
+	switch p.GetType() {
+	case PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeFromControllerConfig, PatchTypeCombineFromComposite,
+		PatchTypeCombineFromEnvironment:
+		return phaseComposite
+	case PatchTypeFromConnectionSecretKey:
+		return phaseConnectionSecret
+	case PatchTypeFromComposedFieldPath, PatchTypeFromComposedConnectionSecretKey:
+		return phaseComposed
+	case PatchTypePatchSet, PatchTypeNoop, PatchTypeToCompositeFieldPath, PatchTypeToEnvironmentFieldPath, PatchTypeCombineToComposite,
+		PatchTypeCombineToEnvironment:
+		return phaseNone
+	}
+	return phaseNone
+}
+
+// An inlinedPatch is a patch of a resource template, or a patch of a PatchSet
+// that a patch of the resource template references.
+// +k8s:deepcopy-gen=false
+type inlinedPatch struct {
+	// patch is the patch. A patch of a PatchSet inherits the priority of the
+	// reference to its PatchSet.
+	patch Patch
+
+	// index is the index of the resource template's patch that is, or
+	// references, the patch.
+	index int
+
+	// position is the position of the patch in the resource template's
+	// inlined patches.
+	position int
+
+	// desc describes the patch, e.g. patches[1] (PatchSet ps).
+	desc string
+
+	// when is the condition of the reference to the PatchSet the patch
+	// belongs to, if any.
+	when *PatchCondition
+}
+
+// inlinePatchesOf returns the supplied patches of a resource template in the
+// order in which they are specified, inlining the patches of the supplied
+// PatchSets they reference. References to PatchSets that don't exist are
+// ignored.
+func inlinePatchesOf(ps []Patch, sets map[string][]Patch) []inlinedPatch {
+	out := make([]inlinedPatch, 0, len(ps))
+	for j, p := range ps {
+		desc := field.NewPath("patches").Index(j).String()
+		if p.Type != PatchTypePatchSet {
+			out = append(out, inlinedPatch{patch: p, index: j, position: len(out), desc: desc})
+			continue
+		}
+		if p.PatchSetName == nil {
+			continue
+		}
+		for _, sp := range InheritPriority(sets[*p.PatchSetName], p.Priority) {
+			out = append(out, inlinedPatch{patch: sp, index: j, position: len(out), desc: fmt.Sprintf("%s (PatchSet %s)", desc, *p.PatchSetName), when: p.When})
+		}
+	}
+	return out
+}
+
+// prioritizedAfter returns true if Priority, and the order in which patches
+// are specified, would apply the patch after the supplied patch.
+func (ip inlinedPatch) prioritizedAfter(o inlinedPatch) bool {
+	if ip.patch.GetPriority() != o.patch.GetPriority() {
+		return ip.patch.GetPriority() > o.patch.GetPriority()
+	}
+	return ip.position > o.position
+}
+
+// appliedAfter returns true if the patch is applied after the supplied patch.
+// Patches are applied in phases, and by priority within each phase.
+func (ip inlinedPatch) appliedAfter(o inlinedPatch) bool {
+	if ip.patch.composedPhase() != o.patch.composedPhase() {
+		return ip.patch.composedPhase() > o.patch.composedPhase()
+	}
+	return ip.prioritizedAfter(o)
+}
+
+// walkPatchPhaseConflicts calls the supplied function for each pair of patches
+// of a resource template that write to the same field path of the composed
+// resource, where Priority would apply the first patch after the second, but
+// the second is applied after it because it belongs to a later phase. The
+// function is called with the index of the resource template, the patches,
+// and the field path. Patches of PatchSets whose references have mutually
+// exclusive conditions don't conflict.
+func (cs *CompositionSpec) walkPatchPhaseConflicts(fn func(resource int, p, overriding inlinedPatch, path string)) {
+	sets := make(map[string][]Patch, len(cs.PatchSets))
+	for _, s := range cs.PatchSets {
+		sets[s.Name] = s.Patches
+	}
+	for i, r := range cs.Resources {
+		ps := inlinePatchesOf(r.Patches, sets)
+		for _, p := range ps {
+			if p.patch.composedPhase() == phaseNone {
+				continue
+			}
+			for _, o := range ps {
+				if o.patch.composedPhase() == phaseNone || !p.prioritizedAfter(o) || !o.appliedAfter(p) || exclusive(p.when, o.when) {
+					continue
+				}
+				if path, ok := sharedTarget(p.patch, o.patch); ok {
+					fn(i, p, o, path)
+				}
+			}
+		}
+	}
+}
+
+// patchPhaseConflict describes a patch that is overridden by a patch of a later
+// phase, which both write to the supplied field path.
+func patchPhaseConflict(p, overriding inlinedPatch, path string) string {
+	return fmt.Sprintf(errFmtPatchPhaseConflict, p.desc, overriding.desc, path, overriding.patch.GetType(), p.patch.GetType())
+}
+
+// sharedTarget returns the first field path both patches write to, and
+// whether there is one.
+func sharedTarget(a, b Patch) (string, bool) {
+	bt := make(map[string]bool)
+	for _, k := range b.targets() {
+		bt[k] = true
+	}
+	for _, k := range a.targets() {
+		if bt[k] {
+			_, path, _ := strings.Cut(k, ":")
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// exclusive returns true if the supplied conditions can't both be met, because
+// they require the same field of the same source to equal different values.
+func exclusive(a, b *PatchCondition) bool {
+	if a == nil || b == nil || a.Equals == nil || b.Equals == nil {
+		return false
+	}
+	if a.GetSource() != b.GetSource() || a.FieldPath != b.FieldPath {
+		return false
+	}
+	var av, bv any
+	if json.Unmarshal(a.Equals.Raw, &av) != nil || json.Unmarshal(b.Equals.Raw, &bv) != nil {
+		return false
+	}
+	return !reflect.DeepEqual(av, bv)
+}
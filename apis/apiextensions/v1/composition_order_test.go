@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestApplyOrder(t *testing.T) {
+	// template returns a template with the supplied name that reads from
+	// each of the supplied templates.
+	template := func(name string, from ...ComposedResourceSelector) ComposedTemplate {
+		t := ComposedTemplate{Name: pointer.String(name)}
+		for i := range from {
+			t.Patches = append(t.Patches, Patch{
+				Type:                 PatchTypeFromComposedFieldPath,
+				FromComposedResource: &from[i],
+				FromFieldPath:        pointer.String("status.atProvider.id"),
+				ToFieldPath:          pointer.String("spec.forProvider.id"),
+			})
+		}
+		return t
+	}
+	byName := func(name string) ComposedResourceSelector {
+		return ComposedResourceSelector{Name: pointer.String(name)}
+	}
+	byIndex := func(i int) ComposedResourceSelector {
+		return ComposedResourceSelector{Index: pointer.Int(i)}
+	}
+
+	type want struct {
+		order []int
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		cts    []ComposedTemplate
+		want   want
+	}{
+		"NoDependencies": {
+			reason: "Templates that don't read from each other should keep their order.",
+			cts:    []ComposedTemplate{template("a"), template("b"), template("c")},
+			want: want{
+				order: []int{0, 1, 2},
+			},
+		},
+		"MultiLevel": {
+			reason: "Each template should be ordered after every template it reads from, directly or indirectly.",
+			cts: []ComposedTemplate{
+				template("subnet", byName("vpc")),
+				template("instance", byName("subnet"), byName("sg")),
+				template("sg", byIndex(3)),
+				template("vpc"),
+				template("dns"),
+			},
+			want: want{
+				order: []int{3, 0, 2, 1, 4},
+			},
+		},
+		"IgnoredReferences": {
+			reason: "References to missing templates and to a template itself should be ignored.",
+			cts: []ComposedTemplate{
+				template("a", byName("a"), byName("nope"), byIndex(42)),
+				template("b", byName("a")),
+			},
+			want: want{
+				order: []int{0, 1},
+			},
+		},
+		"Cycle": {
+			reason: "References that form a cycle should return an error naming the templates that can't be ordered.",
+			cts: []ComposedTemplate{
+				template("a", byName("c")),
+				template("b", byName("a")),
+				template("c", byName("b")),
+				template("d"),
+				{Patches: []Patch{{Type: PatchTypeFromComposedFieldPath, FromComposedResource: &ComposedResourceSelector{Name: pointer.String("a")}}}},
+			},
+			want: want{
+				err: errors.Wrapf(ErrDependencyCycle, errFmtOrderResources, "a, b, c, 4"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			order, err := ApplyOrder(tc.cts)
+			if diff := cmp.Diff(tc.want.order, order); diff != "" {
+				t.Errorf("\n%s\nApplyOrder(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApplyOrder(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil && !errors.Is(err, ErrDependencyCycle) {
+				t.Errorf("\n%s\nApplyOrder(...): want an error matching ErrDependencyCycle, got %q", tc.reason, err)
+			}
+		})
+	}
+}
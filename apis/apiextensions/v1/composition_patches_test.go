@@ -21,11 +21,16 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
 func TestPatchValidate(t *testing.T) {
+	required := FromFieldPathPolicyRequired
+
 	type args struct {
 		patch *Patch
 	}
@@ -48,6 +53,221 @@ func TestPatchValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidCombineToFieldPaths": {
+			reason: "Combine patch with ToFieldPaths but no ToFieldPath should be valid",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{{FromFieldPath: "spec.a"}},
+						Strategy:  CombineStrategyString,
+						String:    &StringCombine{Format: "%s"},
+					},
+					ToFieldPaths: []string{"spec.b", "spec.c"},
+				},
+			},
+		},
+		"InvalidEmptyToFieldPaths": {
+			reason: "ToFieldPaths containing an empty field path should be invalid",
+			args: args{
+				patch: &Patch{
+					FromFieldPath: pointer.String("spec.a"),
+					ToFieldPaths:  []string{"spec.b", ""},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "toFieldPaths[1]",
+				},
+			},
+		},
+		"InvalidPatchSetToFieldPaths": {
+			reason: "PatchSet patch with ToFieldPaths should be invalid",
+			args: args{
+				patch: &Patch{
+					Type:         PatchTypePatchSet,
+					PatchSetName: pointer.String("ps"),
+					ToFieldPaths: []string{"spec.b"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "toFieldPaths",
+				},
+			},
+		},
+		"ValidNoop": {
+			reason: "Noop patch with only a description should be valid",
+			args: args{
+				patch: &Patch{
+					Type:        PatchTypeNoop,
+					Description: pointer.String("Networking patches follow."),
+				},
+			},
+		},
+		"ValidFromComposedFieldPathByName": {
+			reason: "FromComposedFieldPath patch selecting its source by name should be valid",
+			args: args{
+				patch: &Patch{
+					Type:                 PatchTypeFromComposedFieldPath,
+					FromFieldPath:        pointer.String("status.atProvider.arn"),
+					FromComposedResource: &ComposedResourceSelector{Name: pointer.String("bucket")},
+				},
+			},
+		},
+		"ValidFromComposedFieldPathByIndex": {
+			reason: "FromComposedFieldPath patch selecting its source by index should be valid",
+			args: args{
+				patch: &Patch{
+					Type:                 PatchTypeFromComposedFieldPath,
+					FromFieldPath:        pointer.String("status.atProvider.arn"),
+					FromComposedResource: &ComposedResourceSelector{Index: pointer.Int(0)},
+				},
+			},
+		},
+		"ValidFromControllerConfig": {
+			reason: "FromControllerConfig patch with a FromFieldPath should be valid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromControllerConfig,
+					FromFieldPath: pointer.String("defaults.region"),
+					ToFieldPath:   pointer.String("spec.forProvider.region"),
+				},
+			},
+		},
+		"InvalidFromControllerConfigMissingFromFieldPath": {
+			reason: "FromControllerConfig patch missing FromFieldPath should return error",
+			args: args{
+				patch: &Patch{
+					Type:        PatchTypeFromControllerConfig,
+					ToFieldPath: pointer.String("spec.forProvider.region"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "fromFieldPath",
+				},
+			},
+		},
+		"ValidFromConnectionSecretKey": {
+			reason: "FromConnectionSecretKey patch with a ConnectionSecretKey and ToFieldPath should be valid",
+			args: args{
+				patch: &Patch{
+					Type:                PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+					ToFieldPath:         pointer.String("spec.forProvider.certificate"),
+				},
+			},
+		},
+		"InvalidFromConnectionSecretKeyMissingToFieldPath": {
+			reason: "FromConnectionSecretKey patch missing ToFieldPath should return error",
+			args: args{
+				patch: &Patch{
+					Type:                PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "toFieldPath",
+				},
+			},
+		},
+		"ValidFromComposedConnectionSecretKey": {
+			reason: "FromComposedConnectionSecretKey patch selecting its source by name should be valid",
+			args: args{
+				patch: &Patch{
+					Type:                 PatchTypeFromComposedConnectionSecretKey,
+					FromComposedResource: &ComposedResourceSelector{Name: pointer.String("database")},
+					ConnectionSecretKey:  pointer.String("endpoint"),
+					ToFieldPath:          pointer.String("spec.forProvider.databaseEndpoint"),
+				},
+			},
+		},
+		"InvalidFromComposedConnectionSecretKeyByIndex": {
+			reason: "FromComposedConnectionSecretKey patch selecting its source by index should return error",
+			args: args{
+				patch: &Patch{
+					Type:                 PatchTypeFromComposedConnectionSecretKey,
+					FromComposedResource: &ComposedResourceSelector{Index: pointer.Int(0)},
+					ConnectionSecretKey:  pointer.String("endpoint"),
+					ToFieldPath:          pointer.String("spec.forProvider.databaseEndpoint"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "fromComposedResource.name",
+				},
+			},
+		},
+		"InvalidFromComposedConnectionSecretKeyMissingConnectionSecretKey": {
+			reason: "FromComposedConnectionSecretKey patch missing ConnectionSecretKey should return error",
+			args: args{
+				patch: &Patch{
+					Type:                 PatchTypeFromComposedConnectionSecretKey,
+					FromComposedResource: &ComposedResourceSelector{Name: pointer.String("database")},
+					ToFieldPath:          pointer.String("spec.forProvider.databaseEndpoint"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "connectionSecretKey",
+				},
+			},
+		},
+		"InvalidFromComposedFieldPathMissingFromComposedResource": {
+			reason: "FromComposedFieldPath patch missing FromComposedResource should return error",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromComposedFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.arn"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "fromComposedResource",
+				},
+			},
+		},
+		"InvalidFromComposedFieldPathEmptySelector": {
+			reason: "FromComposedFieldPath patch selecting neither a name nor an index should return error",
+			args: args{
+				patch: &Patch{
+					Type:                 PatchTypeFromComposedFieldPath,
+					FromFieldPath:        pointer.String("status.atProvider.arn"),
+					FromComposedResource: &ComposedResourceSelector{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "fromComposedResource.name",
+				},
+			},
+		},
+		"InvalidFromComposedFieldPathNameAndIndex": {
+			reason: "FromComposedFieldPath patch selecting both a name and an index should return error",
+			args: args{
+				patch: &Patch{
+					Type:                 PatchTypeFromComposedFieldPath,
+					FromFieldPath:        pointer.String("status.atProvider.arn"),
+					FromComposedResource: &ComposedResourceSelector{Name: pointer.String("bucket"), Index: pointer.Int(0)},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "fromComposedResource.index",
+				},
+			},
+		},
 		"FromCompositeFieldPathWithInvalidTransforms": {
 			reason: "FromCompositeFieldPath with invalid transforms should return error",
 			args: args{
@@ -113,6 +333,474 @@ func TestPatchValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidConditionalPatchSet": {
+			reason: "PatchSet with a valid condition should be valid",
+			args: args{
+				patch: &Patch{
+					Type:         PatchTypePatchSet,
+					PatchSetName: pointer.String("prod"),
+					When: &PatchCondition{
+						Source:    PatchConditionSourceEnvironment,
+						FieldPath: "stage",
+					},
+				},
+			},
+		},
+		"InvalidConditionalPatchSetMissingFieldPath": {
+			reason: "PatchSet with a condition missing its field path should return error",
+			args: args{
+				patch: &Patch{
+					Type:         PatchTypePatchSet,
+					PatchSetName: pointer.String("prod"),
+					When:         &PatchCondition{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "when.fieldPath",
+				},
+			},
+		},
+		"InvalidConditionalPatch": {
+			reason: "Conditions are only supported on PatchSet patches",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.stage"),
+					When:          &PatchCondition{FieldPath: "spec.stage"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "when",
+				},
+			},
+		},
+		"ValidSkipWhenValue": {
+			reason: "FromCompositeFieldPath patch with a SkipWhenValue should be valid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.region"),
+					SkipWhenValue: &extv1.JSON{Raw: []byte(`""`)},
+				},
+			},
+		},
+		"InvalidNoopSkipWhenValue": {
+			reason: "SkipWhenValue is not supported on Noop patches",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeNoop,
+					SkipWhenValue: &extv1.JSON{Raw: []byte(`""`)},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "skipWhenValue",
+				},
+			},
+		},
+		"InvalidPercentDiffVariables": {
+			reason: "A percentDiff combine requires exactly two variables",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineToComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{{FromFieldPath: "spec.forProvider.size"}},
+						Strategy:  CombineStrategyPercentDiff,
+					},
+					ToFieldPath: pointer.String("status.drift"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.variables",
+				},
+			},
+		},
+		"InvalidFromFieldPathDefaultRequired": {
+			reason: "A default for a missing fromFieldPath should be invalid if the fromFieldPath is required",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.endpoint"),
+					Policy: &PatchPolicy{
+						FromFieldPath:        &required,
+						FromFieldPathDefault: &extv1.JSON{Raw: []byte(`"pending"`)},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "policy.fromFieldPathDefault",
+				},
+			},
+		},
+		"InvalidRelativeTimeNotImmutable": {
+			reason: "A relativeTime transform should be invalid for a patch that is applied every reconcile",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.ttlSeconds"),
+					ToFieldPath:   pointer.String("spec.forProvider.expiresAt"),
+					Transforms:    []Transform{{Type: TransformTypeRelativeTime}},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "policy.immutableAfterCreate",
+				},
+			},
+		},
+		"ValidRelativeTimeImmutable": {
+			reason: "A relativeTime transform should be valid for a patch that is only applied until the composed resource is created",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.ttlSeconds"),
+					ToFieldPath:   pointer.String("spec.forProvider.expiresAt"),
+					Transforms:    []Transform{{Type: TransformTypeRelativeTime}},
+					Policy:        &PatchPolicy{ImmutableAfterCreate: pointer.Bool(true)},
+				},
+			},
+		},
+		"InvalidExpectedTypePatchType": {
+			reason: "An expected type should be invalid for a patch type that doesn't write a value",
+			args: args{
+				patch: &Patch{
+					Type:         PatchTypeNoop,
+					ExpectedType: &[]TransformIOType{TransformIOTypeInt}[0],
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "expectedType",
+				},
+			},
+		},
+		"InvalidExpectedType": {
+			reason: "An unknown expected type should be invalid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.replicas"),
+					ExpectedType:  &[]TransformIOType{"integer"}[0],
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeNotSupported,
+					Field: "expectedType",
+				},
+			},
+		},
+		"InvalidTransformFromFieldPathDefaultMissing": {
+			reason: "Transforming the default for a missing fromFieldPath should be invalid if there is no default",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("status.counter"),
+					Policy:        &PatchPolicy{TransformFromFieldPathDefault: pointer.Bool(true)},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "policy.fromFieldPathDefault",
+				},
+			},
+		},
+		"InvalidFromFieldPathDefaultPatchType": {
+			reason: "A default for a missing fromFieldPath should be invalid for a patch type that doesn't read a fromFieldPath",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineToComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{{FromFieldPath: "status.a"}},
+						Strategy:  CombineStrategyString,
+						String:    &StringCombine{Format: "%s"},
+					},
+					ToFieldPath: pointer.String("status.b"),
+					Policy:      &PatchPolicy{FromFieldPathDefault: &extv1.JSON{Raw: []byte(`"pending"`)}},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "policy.fromFieldPathDefault",
+				},
+			},
+		},
+		"ValidMergeConditions": {
+			reason: "A policy that merges conditions should be valid for a patch type that reads a fromFieldPath",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.conditions"),
+					ToFieldPath:   pointer.String("status.resourceConditions"),
+					Policy:        &PatchPolicy{MergeConditions: pointer.Bool(true)},
+				},
+			},
+		},
+		"InvalidMergeConditionsWithMergeOptions": {
+			reason: "A policy that merges conditions should be invalid if it also has merge options",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.conditions"),
+					Policy: &PatchPolicy{
+						MergeConditions: pointer.Bool(true),
+						MergeOptions:    &xpv1.MergeOptions{AppendSlice: pointer.Bool(true)},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "policy.mergeConditions",
+				},
+			},
+		},
+		"InvalidMergeConditionsPatchType": {
+			reason: "A policy that merges conditions should be invalid for a patch type that doesn't read a fromFieldPath",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineToComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{{FromFieldPath: "status.a"}},
+						Strategy:  CombineStrategyString,
+						String:    &StringCombine{Format: "%s"},
+					},
+					ToFieldPath: pointer.String("status.b"),
+					Policy:      &PatchPolicy{MergeConditions: pointer.Bool(true)},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "policy.mergeConditions",
+				},
+			},
+		},
+		"InvalidStrategicMergeWithMergeConditions": {
+			reason: "A policy that strategic merges should be invalid if it also merges conditions",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.manifest"),
+					Policy: &PatchPolicy{
+						StrategicMerge:  pointer.Bool(true),
+						MergeConditions: pointer.Bool(true),
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "policy.strategicMerge",
+				},
+			},
+		},
+		"InvalidStrategicMergePatchType": {
+			reason: "A policy that strategic merges should be invalid for a patch type that doesn't read a fromFieldPath",
+			args: args{
+				patch: &Patch{
+					Type:                PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("kubeconfig"),
+					ToFieldPath:         pointer.String("spec.manifest"),
+					Policy:              &PatchPolicy{StrategicMerge: pointer.Bool(true)},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "policy.strategicMerge",
+				},
+			},
+		},
+		"InvalidCombineVariablePolicy": {
+			reason: "A combine variable with an unknown policy should be invalid",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{
+							{FromFieldPath: "spec.officeCIDR"},
+							{FromFieldPath: "spec.vpnCIDR", Policy: func() *FromFieldPathPolicy { p := FromFieldPathPolicy("Sometimes"); return &p }()},
+						},
+						Strategy: CombineStrategyArray,
+					},
+					ToFieldPath: pointer.String("spec.allowedRanges"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.variables[1].policy",
+				},
+			},
+		},
+		"ValidToEmbeddedJSON": {
+			reason: "A policy that patches embedded JSON should be valid for a patch type that writes a toFieldPath",
+			args: args{
+				patch: &Patch{
+					Type:                PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("endpoint"),
+					ToFieldPath:         pointer.String("spec.forProvider.config"),
+					Policy:              &PatchPolicy{ToEmbeddedJSON: &EmbeddedJSONPolicy{FieldPath: "database.endpoint"}},
+				},
+			},
+		},
+		"InvalidToEmbeddedJSONFieldPath": {
+			reason: "A policy that patches embedded JSON requires a fieldPath",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.bucketArn"),
+					ToFieldPath:   pointer.String("spec.forProvider.policy"),
+					Policy:        &PatchPolicy{ToEmbeddedJSON: &EmbeddedJSONPolicy{}},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "policy.toEmbeddedJSON.fieldPath",
+				},
+			},
+		},
+		"InvalidToEmbeddedJSONWithMergeConditions": {
+			reason: "A policy that patches embedded JSON should be invalid if it also merges conditions",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("status.conditions"),
+					ToFieldPath:   pointer.String("spec.forProvider.policy"),
+					Policy: &PatchPolicy{
+						ToEmbeddedJSON:  &EmbeddedJSONPolicy{FieldPath: "conditions"},
+						MergeConditions: pointer.Bool(true),
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "policy.toEmbeddedJSON",
+				},
+			},
+		},
+		"InvalidMathCombineOperation": {
+			reason: "A math combine requires a known operation",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{{FromFieldPath: "spec.nodes"}, {FromFieldPath: "spec.cpusPerNode"}},
+						Strategy:  CombineStrategyMath,
+						Math:      &MathCombine{Operation: "Divide"},
+					},
+					ToFieldPath: pointer.String("spec.forProvider.cpus"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.math.operation",
+				},
+			},
+		},
+		"InvalidSetCombineVariables": {
+			reason: "A set combine requires exactly two variables",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{{FromFieldPath: "spec.desiredRules"}},
+						Strategy:  CombineStrategySet,
+						Set:       &SetCombine{Operation: SetCombineOperationDifference},
+					},
+					ToFieldPath: pointer.String("spec.forProvider.rules"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.variables",
+				},
+			},
+		},
+		"InvalidSetCombineOperation": {
+			reason: "A set combine requires a known operation",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{{FromFieldPath: "spec.desiredRules"}, {FromFieldPath: "status.currentRules"}},
+						Strategy:  CombineStrategySet,
+						Set:       &SetCombine{Operation: "SymmetricDifference"},
+					},
+					ToFieldPath: pointer.String("spec.forProvider.rules"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.set.operation",
+				},
+			},
+		},
+		"InvalidCombineVariableTransform": {
+			reason: "An invalid transform of a combine variable should return error",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{
+							{FromFieldPath: "spec.a"},
+							{FromFieldPath: "spec.b", Transforms: []Transform{{Type: TransformTypeMath}}},
+						},
+						Strategy: CombineStrategyString,
+						String:   &StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("metadata.name"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "combine.variables[1].transforms[0].math",
+				},
+			},
+		},
+		"InvalidCombineVariableTransformSet": {
+			reason: "A combine variable transform that references a TransformSet should return error",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{
+							{FromFieldPath: "spec.a"},
+							{FromFieldPath: "spec.b", Transforms: []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("lowercase")}}},
+						},
+						Strategy: CombineStrategyString,
+						String:   &StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("metadata.name"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.variables[1].transforms[0].type",
+				},
+			},
+		},
 		"InvalidCombineMissingCombine": {
 			reason: "Invalid Combine missing Combine should return error",
 			args: args{
@@ -159,3 +847,201 @@ func TestPatchValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestMergePatches(t *testing.T) {
+	type args struct {
+		pss [][]Patch
+	}
+	type want struct {
+		out []Patch
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Empty": {
+			reason: "Merging no patches should return an empty list",
+			args:   args{},
+			want: want{
+				out: []Patch{},
+			},
+		},
+		"NoOverlap": {
+			reason: "Patches that write to different field paths should all be kept, in order",
+			args: args{
+				pss: [][]Patch{
+					{{FromFieldPath: pointer.String("spec.a")}},
+					{{FromFieldPath: pointer.String("spec.b")}},
+				},
+			},
+			want: want{
+				out: []Patch{
+					{FromFieldPath: pointer.String("spec.a")},
+					{FromFieldPath: pointer.String("spec.b")},
+				},
+			},
+		},
+		"LastWins": {
+			reason: "A later patch that writes to the same field path should replace an earlier one in place",
+			args: args{
+				pss: [][]Patch{
+					{
+						{FromFieldPath: pointer.String("spec.a"), ToFieldPath: pointer.String("spec.forProvider.a")},
+						{FromFieldPath: pointer.String("spec.b")},
+					},
+					{
+						{FromFieldPath: pointer.String("spec.override"), ToFieldPath: pointer.String("spec.forProvider.a")},
+					},
+				},
+			},
+			want: want{
+				out: []Patch{
+					{FromFieldPath: pointer.String("spec.override"), ToFieldPath: pointer.String("spec.forProvider.a")},
+					{FromFieldPath: pointer.String("spec.b")},
+				},
+			},
+		},
+		"DifferentToFieldPaths": {
+			reason: "Patches should only replace each other if they write to exactly the same field paths",
+			args: args{
+				pss: [][]Patch{
+					{{FromFieldPath: pointer.String("spec.a"), ToFieldPaths: []string{"spec.x", "spec.y"}}},
+					{{FromFieldPath: pointer.String("spec.b"), ToFieldPaths: []string{"spec.x"}}},
+					{{FromFieldPath: pointer.String("spec.c"), ToFieldPath: pointer.String("spec.x"), ToFieldPaths: []string{"spec.y"}}},
+				},
+			},
+			want: want{
+				out: []Patch{
+					{FromFieldPath: pointer.String("spec.c"), ToFieldPath: pointer.String("spec.x"), ToFieldPaths: []string{"spec.y"}},
+					{FromFieldPath: pointer.String("spec.b"), ToFieldPaths: []string{"spec.x"}},
+				},
+			},
+		},
+		"DefaultedToFieldPath": {
+			reason: "A patch without a ToFieldPath should be considered to write to its FromFieldPath",
+			args: args{
+				pss: [][]Patch{
+					{{FromFieldPath: pointer.String("spec.a")}},
+					{{FromFieldPath: pointer.String("spec.b"), ToFieldPath: pointer.String("spec.a")}},
+				},
+			},
+			want: want{
+				out: []Patch{
+					{FromFieldPath: pointer.String("spec.b"), ToFieldPath: pointer.String("spec.a")},
+				},
+			},
+		},
+		"DifferentResources": {
+			reason: "Patches that write to the same field path of different resources should all be kept",
+			args: args{
+				pss: [][]Patch{
+					{
+						{FromFieldPath: pointer.String("status.id")},
+						{Type: PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String("status.id")},
+					},
+				},
+			},
+			want: want{
+				out: []Patch{
+					{FromFieldPath: pointer.String("status.id")},
+					{Type: PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String("status.id")},
+				},
+			},
+		},
+		"NoTarget": {
+			reason: "Patches that don't write to a field path should never be merged",
+			args: args{
+				pss: [][]Patch{
+					{{Type: PatchTypePatchSet, PatchSetName: pointer.String("a")}},
+					{{Type: PatchTypePatchSet, PatchSetName: pointer.String("a")}},
+				},
+			},
+			want: want{
+				out: []Patch{
+					{Type: PatchTypePatchSet, PatchSetName: pointer.String("a")},
+					{Type: PatchTypePatchSet, PatchSetName: pointer.String("a")},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MergePatches(tc.args.pss...)
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("%s\nMergePatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPatchFields(t *testing.T) {
+	type want struct {
+		required []string
+		optional []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		patch  *Patch
+		want   want
+	}{
+		"DefaultType": {
+			reason: "A patch without a type should return the fields of a FromCompositeFieldPath patch.",
+			patch:  &Patch{},
+			want: want{
+				required: []string{"fromFieldPath"},
+				optional: []string{"description", "tags", "priority", "toFieldPath", "toFieldPaths", "transforms", "policy", "skipWhenValue", "expectedType"},
+			},
+		},
+		"FromComposedFieldPath": {
+			reason: "A FromComposedFieldPath patch should require the resource it reads from.",
+			patch:  &Patch{Type: PatchTypeFromComposedFieldPath},
+			want: want{
+				required: []string{"fromComposedResource", "fromFieldPath"},
+				optional: []string{"description", "tags", "priority", "toFieldPath", "toFieldPaths", "transforms", "policy", "skipWhenValue", "expectedType"},
+			},
+		},
+		"CombineToComposite": {
+			reason: "A combine patch should require a combine and a toFieldPath or toFieldPaths.",
+			patch:  &Patch{Type: PatchTypeCombineToComposite},
+			want: want{
+				required: []string{"combine", "toFieldPath or toFieldPaths"},
+				optional: []string{"description", "tags", "priority", "transforms", "policy", "skipWhenValue", "expectedType"},
+			},
+		},
+		"PatchSet": {
+			reason: "A PatchSet patch should require a PatchSet name, and may have a priority and a condition.",
+			patch:  &Patch{Type: PatchTypePatchSet},
+			want: want{
+				required: []string{"patchSetName"},
+				optional: []string{"description", "tags", "priority", "when"},
+			},
+		},
+		"Noop": {
+			reason: "A Noop patch should require no fields.",
+			patch:  &Patch{Type: PatchTypeNoop},
+			want: want{
+				required: []string{},
+				optional: []string{"description", "tags"},
+			},
+		},
+		"UnknownType": {
+			reason: "A patch of an unknown type should return no fields.",
+			patch:  &Patch{Type: "Nope"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			required, optional := tc.patch.Fields()
+			if diff := cmp.Diff(tc.want.required, required); diff != "" {
+				t.Errorf("%s\nFields(...): -want required, +got required:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.optional, optional); diff != "" {
+				t.Errorf("%s\nFields(...): -want optional, +got optional:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
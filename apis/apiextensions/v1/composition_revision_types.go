@@ -47,6 +47,13 @@ type CompositionRevisionSpec struct {
 	// +optional
 	PatchSets []PatchSet `json:"patchSets,omitempty"`
 
+	// TransformSets define a named chain of transforms that may be included
+	// by any patch of any resource in this Composition, using a transform of
+	// type transformSet. TransformSets cannot themselves refer to other
+	// TransformSets.
+	// +optional
+	TransformSets []TransformSet `json:"transformSets,omitempty"`
+
 	// Environment configures the environment in which resources are rendered.
 	// +optional
 	Environment *EnvironmentConfiguration `json:"environment,omitempty"`
@@ -56,6 +63,18 @@ type CompositionRevisionSpec struct {
 	// +optional
 	Resources []ComposedTemplate `json:"resources"`
 
+	// PatchErrorPolicy determines what happens when a composed resource can't
+	// be rendered because one of its patches returned an error. The default,
+	// continue, skips that resource and composes the others. With abort, no
+	// composed resource is applied if any resource can't be rendered from the
+	// composite resource or environment, or requires a composed resource that
+	// can't be rendered. A resource that can't be rendered from the observed
+	// state of the composed resources it reads from stops composition, but
+	// only after those resources have been applied.
+	// +optional
+	// +kubebuilder:validation:Enum=continue;abort
+	PatchErrorPolicy *PatchErrorPolicy `json:"patchErrorPolicy,omitempty"`
+
 	// Functions is list of Composition Functions that will be used when a
 	// composite resource referring to this composition is created. At least one
 	// of resources and functions must be specified. If both are specified the
@@ -87,6 +106,15 @@ type CompositionRevisionSpec struct {
 	Revision int64 `json:"revision"`
 }
 
+// GetPatchErrorPolicy returns the patch error policy of the revision, which
+// defaults to continue.
+func (s *CompositionRevisionSpec) GetPatchErrorPolicy() PatchErrorPolicy {
+	if s.PatchErrorPolicy == nil {
+		return PatchErrorPolicyContinue
+	}
+	return *s.PatchErrorPolicy
+}
+
 // CompositionRevisionStatus shows the observed state of the composition
 // revision.
 type CompositionRevisionStatus struct {
@@ -18,38 +18,113 @@ package v1
 
 import (
 	"encoding/json"
+	"fmt"
+	"net"
 	"regexp"
+	"sort"
+	"strings"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 
 	verrors "github.com/crossplane/crossplane/internal/validation/errors"
 )
 
+// Error strings.
+const (
+	ErrFmtConvertFormatPairNotSupported = "conversion from %s to %s is not supported with format %s"
+
+	errTransformConfigMismatch = "transform configuration does not match the transform type"
+)
+
 // TransformType is type of the transform function to be chosen.
 type TransformType string
 
 // Accepted TransformTypes.
 const (
-	ErrFmtConvertFormatPairNotSupported = "conversion from %s to %s is not supported with format %s"
-
-	TransformTypeMap     TransformType = "map"
-	TransformTypeMatch   TransformType = "match"
-	TransformTypeMath    TransformType = "math"
-	TransformTypeString  TransformType = "string"
-	TransformTypeConvert TransformType = "convert"
+	TransformTypeMap             TransformType = "map"
+	TransformTypeMatch           TransformType = "match"
+	TransformTypeMath            TransformType = "math"
+	TransformTypeString          TransformType = "string"
+	TransformTypeConvert         TransformType = "convert"
+	TransformTypeRange           TransformType = "range"
+	TransformTypeAggregate       TransformType = "aggregate"
+	TransformTypeTernary         TransformType = "ternary"
+	TransformTypeTruncate        TransformType = "truncate"
+	TransformTypeLength          TransformType = "length"
+	TransformTypeNumberFormat    TransformType = "numberFormat"
+	TransformTypeJSONParse       TransformType = "jsonParse"
+	TransformTypeCIDRMatch       TransformType = "cidrMatch"
+	TransformTypePEM             TransformType = "pem"
+	TransformTypeAllowlist       TransformType = "allowlist"
+	TransformTypeConditionStatus TransformType = "conditionStatus"
+	TransformTypeFieldSelect     TransformType = "fieldSelect"
+	TransformTypeValidateFormat  TransformType = "validateFormat"
+	TransformTypeStableSuffix    TransformType = "stableSuffix"
+	TransformTypeSelectMatch     TransformType = "selectMatch"
+	TransformTypeLabelSelector   TransformType = "labelSelector"
+	TransformTypeTransformSet    TransformType = "transformSet"
+	TransformTypeRelativeTime    TransformType = "relativeTime"
+	TransformTypeCase            TransformType = "case"
+	TransformTypeDedup           TransformType = "dedup"
+	TransformTypeSemver          TransformType = "semver"
 )
 
+// ValidTransformTypes returns the list of valid transform types.
+func ValidTransformTypes() []TransformType {
+	return []TransformType{
+		TransformTypeMap,
+		TransformTypeMatch,
+		TransformTypeMath,
+		TransformTypeString,
+		TransformTypeConvert,
+		TransformTypeRange,
+		TransformTypeAggregate,
+		TransformTypeTernary,
+		TransformTypeTruncate,
+		TransformTypeLength,
+		TransformTypeNumberFormat,
+		TransformTypeJSONParse,
+		TransformTypeCIDRMatch,
+		TransformTypePEM,
+		TransformTypeAllowlist,
+		TransformTypeConditionStatus,
+		TransformTypeFieldSelect,
+		TransformTypeValidateFormat,
+		TransformTypeStableSuffix,
+		TransformTypeSelectMatch,
+		TransformTypeLabelSelector,
+		TransformTypeTransformSet,
+		TransformTypeRelativeTime,
+		TransformTypeCase,
+		TransformTypeDedup,
+		TransformTypeSemver,
+	}
+}
+
 // Transform is a unit of process whose input is transformed into an output with
 // the supplied configuration.
 type Transform struct {
 
-	// Type of the transform to be run.
-	// +kubebuilder:validation:Enum=map;match;math;string;convert
+	// Type of the transform to be run. The length transform, which returns
+	// the number of elements in an array input, the jsonParse transform,
+	// which parses a JSON string input into the value it encodes, and the
+	// relativeTime transform, which returns the current time plus a numeric
+	// input number of seconds as an RFC3339 timestamp, take no
+	// configuration. The output of a relativeTime transform changes every
+	// time it is resolved, so it may only be used by patches whose policy is
+	// immutableAfterCreate.
+	// +kubebuilder:validation:Enum=map;match;math;string;convert;range;aggregate;ternary;truncate;length;numberFormat;jsonParse;cidrMatch;pem;allowlist;conditionStatus;fieldSelect;validateFormat;stableSuffix;selectMatch;labelSelector;transformSet;relativeTime;case;dedup;semver
 	Type TransformType `json:"type"`
 
+	// TransformSetName is the name of the TransformSet whose transforms
+	// replace this transform. Required when type is transformSet.
+	// +optional
+	TransformSetName *string `json:"transformSetName,omitempty"`
+
 	// Math is used to transform the input via mathematical operations such as
 	// multiplication.
 	// +optional
@@ -59,6 +134,11 @@ type Transform struct {
 	// +optional
 	Map *MapTransform `json:"map,omitempty"`
 
+	// MapOptions configures how a map transform looks up its input. It may
+	// only be set for map transforms.
+	// +optional
+	MapOptions *MapTransformOptions `json:"mapOptions,omitempty"`
+
 	// Match is a more complex version of Map that matches a list of patterns.
 	// +optional
 	Match *MatchTransform `json:"match,omitempty"`
@@ -71,12 +151,218 @@ type Transform struct {
 	// Convert is used to cast the input into the given output type.
 	// +optional
 	Convert *ConvertTransform `json:"convert,omitempty"`
+
+	// Range maps a numeric input to the value of the first of an ordered list
+	// of buckets whose max it does not exceed.
+	// +optional
+	Range *RangeTransform `json:"range,omitempty"`
+
+	// Aggregate reduces an array of numbers to a single number, for example
+	// their sum.
+	// +optional
+	Aggregate *AggregateTransform `json:"aggregate,omitempty"`
+
+	// Ternary returns one of two values depending on whether a boolean input
+	// is true or false.
+	// +optional
+	Ternary *TernaryTransform `json:"ternary,omitempty"`
+
+	// Truncate shortens a string input to a maximum length, optionally
+	// appending a hash of the input to keep truncated values unique.
+	// +optional
+	Truncate *TruncateTransform `json:"truncate,omitempty"`
+
+	// NumberFormat formats a numeric input as a human-readable string, for
+	// example with thousands separators.
+	// +optional
+	NumberFormat *NumberFormatTransform `json:"numberFormat,omitempty"`
+
+	// CIDRMatch maps an IP address input to the value of the first of an
+	// ordered list of CIDR blocks that contains it.
+	// +optional
+	CIDRMatch *CIDRMatchTransform `json:"cidrMatch,omitempty"`
+
+	// PEM parses a PEM-encoded certificate input and returns one of its
+	// attributes, for example its common name or expiry.
+	// +optional
+	PEM *PEMTransform `json:"pem,omitempty"`
+
+	// Allowlist passes its input through unchanged if it is one of a set of
+	// permitted values, and returns an error otherwise.
+	// +optional
+	Allowlist *AllowlistTransform `json:"allowlist,omitempty"`
+
+	// ConditionStatus maps the status of a condition, i.e. True, False, or
+	// Unknown, to a value.
+	// +optional
+	ConditionStatus *ConditionStatusTransform `json:"conditionStatus,omitempty"`
+
+	// FieldSelect returns the value at a field path of an object input, for
+	// example an object produced by a jsonParse transform.
+	// +optional
+	FieldSelect *FieldSelectTransform `json:"fieldSelect,omitempty"`
+
+	// ValidateFormat checks that a string input is of a format, for example
+	// an email address, and returns it normalized. It returns an error if
+	// the input is malformed.
+	// +optional
+	ValidateFormat *ValidateFormatTransform `json:"validateFormat,omitempty"`
+
+	// StableSuffix returns a short pseudo-random string that is derived
+	// from a string input, for example to make a name unique but stable
+	// across reconciles.
+	// +optional
+	StableSuffix *StableSuffixTransform `json:"stableSuffix,omitempty"`
+
+	// SelectMatch returns the first element of an array input whose value
+	// at a field path equals a value, for example to select an address of
+	// a particular type from an array of addresses.
+	// +optional
+	SelectMatch *SelectMatchTransform `json:"selectMatch,omitempty"`
+
+	// LabelSelector converts a map of labels to a label selector that
+	// matches them, or a label selector to the map of labels it matches.
+	// +optional
+	LabelSelector *LabelSelectorTransform `json:"labelSelector,omitempty"`
+
+	// Case converts a string input of words delimited by whitespace,
+	// underscores, or hyphens to a naming convention, for example kebab-case.
+	// +optional
+	Case *CaseTransform `json:"case,omitempty"`
+
+	// Dedup removes duplicate elements from an array input, keeping the
+	// first of each. It may be configured to dedup objects by a key.
+	// +optional
+	Dedup *DedupTransform `json:"dedup,omitempty"`
+
+	// Semver parses a semantic version string input and returns it
+	// normalized, or one of its components. It returns an error if the
+	// input is not a semantic version.
+	// +optional
+	Semver *SemverTransform `json:"semver,omitempty"`
+
+	// OnError determines what happens if this transform returns an error.
+	// The default, 'fail', fails the patch the transform belongs to. Use
+	// 'skip' to instead pass the input of this transform, unchanged, to the
+	// next transform in the chain.
+	// +optional
+	// +kubebuilder:validation:Enum=fail;skip
+	OnError *TransformOnErrorPolicy `json:"onError,omitempty"`
+
+	// When, if set, runs this transform only if its input meets the
+	// condition. Otherwise the input is passed through unchanged to the next
+	// transform in the chain.
+	// +optional
+	When *TransformCondition `json:"when,omitempty"`
 }
 
-// Validate this Transform is valid.
+// A TransformOnErrorPolicy determines what happens when a transform returns an
+// error.
+type TransformOnErrorPolicy string
+
+// Accepted TransformOnErrorPolicies.
+const (
+	TransformOnErrorPolicyFail TransformOnErrorPolicy = "fail" // Default
+	TransformOnErrorPolicySkip TransformOnErrorPolicy = "skip"
+)
+
+// GetOnError returns the OnError policy of the transform, returning the
+// default if not specified.
+func (t *Transform) GetOnError() TransformOnErrorPolicy {
+	if t.OnError == nil {
+		return TransformOnErrorPolicyFail
+	}
+	return *t.OnError
+}
+
+// A TransformConditionOperator determines how a TransformCondition compares
+// the input of a transform to its value.
+type TransformConditionOperator string
+
+// Accepted TransformConditionOperators.
+const (
+	TransformConditionOperatorEqual    TransformConditionOperator = "Equal"
+	TransformConditionOperatorNotEqual TransformConditionOperator = "NotEqual"
+	TransformConditionOperatorContains TransformConditionOperator = "Contains"
+	TransformConditionOperatorRegexp   TransformConditionOperator = "Regexp"
+)
+
+// A TransformCondition is a predicate on the input of a transform.
+type TransformCondition struct {
+	// Operator determines how the input is compared to the value.
+	// 'Equal' and 'NotEqual' compare the input to the value. 'Contains' is
+	// met if a string input contains a string value, or if an array input
+	// has an element equal to the value. 'Regexp' is met if a string input
+	// matches the regular expression.
+	// +kubebuilder:validation:Enum=Equal;NotEqual;Contains;Regexp
+	Operator TransformConditionOperator `json:"operator"`
+
+	// Value the input is compared to. Required by all operators except
+	// 'Regexp'.
+	// +optional
+	Value *extv1.JSON `json:"value,omitempty"`
+
+	// Regexp the input must match. Required by the 'Regexp' operator. See
+	// https://github.com/google/re2/wiki/Syntax for the syntax.
+	// +optional
+	Regexp *string `json:"regexp,omitempty"`
+}
+
+// Validate the TransformCondition object.
+func (c *TransformCondition) Validate() *field.Error {
+	switch c.Operator {
+	case TransformConditionOperatorEqual, TransformConditionOperatorNotEqual, TransformConditionOperatorContains:
+		if c.Value == nil {
+			return field.Required(field.NewPath("value"), fmt.Sprintf("value is required by operator %s", c.Operator))
+		}
+		if !json.Valid(c.Value.Raw) {
+			return field.Invalid(field.NewPath("value"), string(c.Value.Raw), "value must be valid JSON")
+		}
+	case TransformConditionOperatorRegexp:
+		if c.Regexp == nil {
+			return field.Required(field.NewPath("regexp"), fmt.Sprintf("regexp is required by operator %s", c.Operator))
+		}
+		if _, err := regexp.Compile(*c.Regexp); err != nil {
+			return field.Invalid(field.NewPath("regexp"), *c.Regexp, err.Error())
+		}
+	default:
+		return field.Invalid(field.NewPath("operator"), c.Operator, "unknown transform condition operator")
+	}
+	return nil
+}
+
+// Validate this Transform is valid. In strict mode configuration for any type
+// other than the transform's type is considered invalid, in order to catch
+// transforms whose type and configuration disagree.
 //
 //nolint:gocyclo // This is a long but simple/same-y switch.
-func (t *Transform) Validate() *field.Error {
+func (t *Transform) Validate(strict bool) *field.Error {
+	switch t.GetOnError() {
+	case TransformOnErrorPolicyFail, TransformOnErrorPolicySkip:
+	default:
+		return field.Invalid(field.NewPath("onError"), t.OnError, "unknown on error policy")
+	}
+	if t.When != nil {
+		if err := t.When.Validate(); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("when"))
+		}
+	}
+
+	if strict {
+		// Each transform type is configured by the field of the same name.
+		for _, c := range t.configured() {
+			if c != string(t.Type) {
+				return field.Invalid(field.NewPath(c), t.Type, errTransformConfigMismatch)
+			}
+		}
+		if t.MapOptions != nil && t.Type != TransformTypeMap {
+			return field.Invalid(field.NewPath("mapOptions"), t.Type, errTransformConfigMismatch)
+		}
+		if t.TransformSetName != nil && t.Type != TransformTypeTransformSet {
+			return field.Invalid(field.NewPath("transformSetName"), t.Type, errTransformConfigMismatch)
+		}
+	}
+
 	switch t.Type {
 	case TransformTypeMath:
 		if t.Math == nil {
@@ -87,7 +373,12 @@ func (t *Transform) Validate() *field.Error {
 		if t.Map == nil {
 			return field.Required(field.NewPath("map"), "given transform type map requires configuration")
 		}
-		return verrors.WrapFieldError(t.Map.Validate(), field.NewPath("map"))
+		if err := t.Map.Validate(); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("map"))
+		}
+		if t.MapOptions.IsCaseInsensitive() {
+			return verrors.WrapFieldError(t.Map.ValidateCaseInsensitive(), field.NewPath("map"))
+		}
 	case TransformTypeMatch:
 		if t.Match == nil {
 			return field.Required(field.NewPath("match"), "given transform type match requires configuration")
@@ -105,6 +396,97 @@ func (t *Transform) Validate() *field.Error {
 		if err := t.Convert.Validate(); err != nil {
 			return verrors.WrapFieldError(err, field.NewPath("convert"))
 		}
+	case TransformTypeRange:
+		if t.Range == nil {
+			return field.Required(field.NewPath("range"), "given transform type range requires configuration")
+		}
+		return verrors.WrapFieldError(t.Range.Validate(), field.NewPath("range"))
+	case TransformTypeAggregate:
+		if t.Aggregate == nil {
+			return field.Required(field.NewPath("aggregate"), "given transform type aggregate requires configuration")
+		}
+		return verrors.WrapFieldError(t.Aggregate.Validate(), field.NewPath("aggregate"))
+	case TransformTypeTernary:
+		if t.Ternary == nil {
+			return field.Required(field.NewPath("ternary"), "given transform type ternary requires configuration")
+		}
+		return verrors.WrapFieldError(t.Ternary.Validate(), field.NewPath("ternary"))
+	case TransformTypeTruncate:
+		if t.Truncate == nil {
+			return field.Required(field.NewPath("truncate"), "given transform type truncate requires configuration")
+		}
+		return verrors.WrapFieldError(t.Truncate.Validate(), field.NewPath("truncate"))
+	case TransformTypeLength, TransformTypeJSONParse, TransformTypeRelativeTime:
+		// These transforms have no configuration.
+	case TransformTypeNumberFormat:
+		if t.NumberFormat == nil {
+			return field.Required(field.NewPath("numberFormat"), "given transform type numberFormat requires configuration")
+		}
+		return verrors.WrapFieldError(t.NumberFormat.Validate(), field.NewPath("numberFormat"))
+	case TransformTypeCIDRMatch:
+		if t.CIDRMatch == nil {
+			return field.Required(field.NewPath("cidrMatch"), "given transform type cidrMatch requires configuration")
+		}
+		return verrors.WrapFieldError(t.CIDRMatch.Validate(), field.NewPath("cidrMatch"))
+	case TransformTypePEM:
+		if t.PEM == nil {
+			return field.Required(field.NewPath("pem"), "given transform type pem requires configuration")
+		}
+		return verrors.WrapFieldError(t.PEM.Validate(), field.NewPath("pem"))
+	case TransformTypeAllowlist:
+		if t.Allowlist == nil {
+			return field.Required(field.NewPath("allowlist"), "given transform type allowlist requires configuration")
+		}
+		return verrors.WrapFieldError(t.Allowlist.Validate(), field.NewPath("allowlist"))
+	case TransformTypeConditionStatus:
+		if t.ConditionStatus == nil {
+			return field.Required(field.NewPath("conditionStatus"), "given transform type conditionStatus requires configuration")
+		}
+		return verrors.WrapFieldError(t.ConditionStatus.Validate(), field.NewPath("conditionStatus"))
+	case TransformTypeFieldSelect:
+		if t.FieldSelect == nil {
+			return field.Required(field.NewPath("fieldSelect"), "given transform type fieldSelect requires configuration")
+		}
+		return verrors.WrapFieldError(t.FieldSelect.Validate(), field.NewPath("fieldSelect"))
+	case TransformTypeValidateFormat:
+		if t.ValidateFormat == nil {
+			return field.Required(field.NewPath("validateFormat"), "given transform type validateFormat requires configuration")
+		}
+		return verrors.WrapFieldError(t.ValidateFormat.Validate(), field.NewPath("validateFormat"))
+	case TransformTypeStableSuffix:
+		if t.StableSuffix == nil {
+			return field.Required(field.NewPath("stableSuffix"), "given transform type stableSuffix requires configuration")
+		}
+		return verrors.WrapFieldError(t.StableSuffix.Validate(), field.NewPath("stableSuffix"))
+	case TransformTypeSelectMatch:
+		if t.SelectMatch == nil {
+			return field.Required(field.NewPath("selectMatch"), "given transform type selectMatch requires configuration")
+		}
+		return verrors.WrapFieldError(t.SelectMatch.Validate(), field.NewPath("selectMatch"))
+	case TransformTypeLabelSelector:
+		if t.LabelSelector == nil {
+			return field.Required(field.NewPath("labelSelector"), "given transform type labelSelector requires configuration")
+		}
+		return verrors.WrapFieldError(t.LabelSelector.Validate(), field.NewPath("labelSelector"))
+	case TransformTypeCase:
+		if t.Case == nil {
+			return field.Required(field.NewPath("case"), "given transform type case requires configuration")
+		}
+		return verrors.WrapFieldError(t.Case.Validate(), field.NewPath("case"))
+	case TransformTypeDedup:
+		// A dedup transform's configuration is optional.
+		if t.Dedup != nil {
+			return verrors.WrapFieldError(t.Dedup.Validate(), field.NewPath("dedup"))
+		}
+	case TransformTypeSemver:
+		if t.Semver == nil {
+			return field.Required(field.NewPath("semver"), "given transform type semver requires configuration")
+		}
+		return verrors.WrapFieldError(t.Semver.Validate(), field.NewPath("semver"))
+	case TransformTypeTransformSet:
+		if t.TransformSetName == nil || *t.TransformSetName == "" {
+			return field.Required(field.NewPath("transformSetName"), "given transform type transformSet requires a transformSetName")
+		}
 	default:
 		// Should never happen
 		return field.Invalid(field.NewPath("type"), t.Type, "unknown transform type")
@@ -113,6 +495,45 @@ func (t *Transform) Validate() *field.Error {
 	return nil
 }
 
+// configured returns the names of the transform configuration fields that are
+// set.
+func (t *Transform) configured() []string {
+	fields := []struct {
+		typ TransformType
+		set bool
+	}{
+		{TransformTypeMath, t.Math != nil},
+		{TransformTypeMap, t.Map != nil},
+		{TransformTypeMatch, t.Match != nil},
+		{TransformTypeString, t.String != nil},
+		{TransformTypeConvert, t.Convert != nil},
+		{TransformTypeRange, t.Range != nil},
+		{TransformTypeAggregate, t.Aggregate != nil},
+		{TransformTypeTernary, t.Ternary != nil},
+		{TransformTypeTruncate, t.Truncate != nil},
+		{TransformTypeNumberFormat, t.NumberFormat != nil},
+		{TransformTypeCIDRMatch, t.CIDRMatch != nil},
+		{TransformTypePEM, t.PEM != nil},
+		{TransformTypeAllowlist, t.Allowlist != nil},
+		{TransformTypeConditionStatus, t.ConditionStatus != nil},
+		{TransformTypeFieldSelect, t.FieldSelect != nil},
+		{TransformTypeValidateFormat, t.ValidateFormat != nil},
+		{TransformTypeStableSuffix, t.StableSuffix != nil},
+		{TransformTypeSelectMatch, t.SelectMatch != nil},
+		{TransformTypeLabelSelector, t.LabelSelector != nil},
+		{TransformTypeCase, t.Case != nil},
+		{TransformTypeDedup, t.Dedup != nil},
+		{TransformTypeSemver, t.Semver != nil},
+	}
+	var c []string
+	for _, f := range fields {
+		if f.set {
+			c = append(c, string(f.typ))
+		}
+	}
+	return c
+}
+
 // GetFormat returns the format of the transform.
 func (t *ConvertTransform) GetFormat() ConvertTransformFormat {
 	if t.Format != nil {
@@ -125,22 +546,199 @@ func (t *ConvertTransform) GetFormat() ConvertTransformFormat {
 // It returns an error if the transform type is unknown.
 // It returns nil if the output type is not known.
 func (t *Transform) GetOutputType() (*TransformIOType, error) {
+	if t.mayOutputInput() {
+		return nil, nil
+	}
 	var out TransformIOType
 	switch t.Type {
-	case TransformTypeMap, TransformTypeMatch:
+	case TransformTypeMap, TransformTypeMatch, TransformTypeRange, TransformTypeTernary, TransformTypeJSONParse, TransformTypeCIDRMatch, TransformTypeAllowlist, TransformTypeConditionStatus, TransformTypeFieldSelect, TransformTypeSelectMatch, TransformTypeLabelSelector,
+		TransformTypeTransformSet, TransformTypeDedup:
 		return nil, nil
 	case TransformTypeMath:
 		out = TransformIOTypeFloat64
-	case TransformTypeString:
+	case TransformTypeString, TransformTypeTruncate, TransformTypeNumberFormat, TransformTypeValidateFormat, TransformTypeStableSuffix, TransformTypeCase,
+		TransformTypeRelativeTime:
 		out = TransformIOTypeString
+	case TransformTypeLength:
+		out = TransformIOTypeInt64
 	case TransformTypeConvert:
-		out = t.Convert.ToType
+		return t.Convert.outputType(), nil
+	case TransformTypeAggregate:
+		return t.Aggregate.outputType(), nil
+	case TransformTypeSemver:
+		return t.Semver.outputType(), nil
+	case TransformTypePEM:
+		return t.PEM.outputType(), nil
 	default:
 		return nil, errors.Errorf("unable to get output type, unknown transform type: %s", t.Type)
 	}
 	return &out, nil
 }
 
+// mayOutputInput returns true if the transform may output its input, whose
+// type we don't know. This is the case for skipped or conditional transforms.
+func (t *Transform) mayOutputInput() bool {
+	return t.GetOnError() == TransformOnErrorPolicySkip || t.When != nil
+}
+
+// outputType returns the output type of the convert transform.
+func (t *ConvertTransform) outputType() *TransformIOType {
+	out := t.ToType
+	// Narrower integer types are output as int64 values.
+	if out == TransformIOTypeInt32 || out == TransformIOTypeInt16 {
+		out = TransformIOTypeInt64
+	}
+	return &out
+}
+
+// outputType returns the output type of the aggregate transform, or nil if it
+// isn't known. Only a count is known to be an integer; the other aggregations
+// output an integer or a float depending on their input.
+func (t *AggregateTransform) outputType() *TransformIOType {
+	if t == nil || t.GetType() != AggregateTransformTypeCount {
+		return nil
+	}
+	out := TransformIOTypeInt64
+	return &out
+}
+
+// outputType returns the output type of the semver transform, or nil if it
+// isn't known.
+func (t *SemverTransform) outputType() *TransformIOType {
+	if t == nil {
+		return nil
+	}
+	out := TransformIOTypeInt64
+	if t.Operation == SemverTransformOperationNormalize {
+		out = TransformIOTypeString
+	}
+	return &out
+}
+
+// outputType returns the output type of the PEM transform, or nil if it isn't
+// known. Only the DNS names of a certificate are an array.
+func (t *PEMTransform) outputType() *TransformIOType {
+	if t == nil || t.Attribute == PEMTransformAttributeDNSNames {
+		return nil
+	}
+	out := TransformIOTypeString
+	return &out
+}
+
+// ValidateInput returns an error if the transform can't accept input of the
+// supplied type. Arrays have no TransformIOType, so transforms that require an
+// array reject any known input type. Convert transforms accept any input type;
+// whether a conversion between two types is supported depends on the
+// conversions implemented by the Composition engine.
+//
+//nolint:gocyclo // This is a long but simple/same-y switch.
+func (t *Transform) ValidateInput(fromType TransformIOType) error {
+	switch t.Type {
+	case TransformTypeMath:
+		if fromType != TransformIOTypeInt && fromType != TransformIOTypeInt64 && fromType != TransformIOTypeFloat64 {
+			return errors.Errorf("math transform can only be used with numeric types, got %s", fromType)
+		}
+	case TransformTypeRange:
+		if fromType != TransformIOTypeInt && fromType != TransformIOTypeInt64 && fromType != TransformIOTypeFloat64 {
+			return errors.Errorf("range transform can only be used with numeric types, got %s", fromType)
+		}
+	case TransformTypeAggregate:
+		if fromType != "" {
+			return errors.Errorf("aggregate transform can only be used with array types, got %s", fromType)
+		}
+	case TransformTypeNumberFormat:
+		if fromType != TransformIOTypeInt && fromType != TransformIOTypeInt64 && fromType != TransformIOTypeFloat64 {
+			return errors.Errorf("numberFormat transform can only be used with numeric types, got %s", fromType)
+		}
+	case TransformTypeRelativeTime:
+		if fromType != TransformIOTypeInt && fromType != TransformIOTypeInt64 && fromType != TransformIOTypeFloat64 {
+			return errors.Errorf("relativeTime transform can only be used with numeric types, got %s", fromType)
+		}
+	case TransformTypeLength:
+		if fromType != "" {
+			return errors.Errorf("length transform can only be used with array types, got %s", fromType)
+		}
+	case TransformTypeFieldSelect:
+		// Objects have no TransformIOType either.
+		if fromType != "" {
+			return errors.Errorf("fieldSelect transform can only be used with object types, got %s", fromType)
+		}
+	case TransformTypeSelectMatch:
+		if fromType != "" {
+			return errors.Errorf("selectMatch transform can only be used with array types, got %s", fromType)
+		}
+	case TransformTypeDedup:
+		if fromType != "" {
+			return errors.Errorf("dedup transform can only be used with array types, got %s", fromType)
+		}
+	case TransformTypeLabelSelector:
+		if fromType != "" {
+			return errors.Errorf("labelSelector transform can only be used with object types, got %s", fromType)
+		}
+	case TransformTypeTernary:
+		if fromType != TransformIOTypeBool {
+			return errors.Errorf("ternary transform can only be used with bool input types, got %s", fromType)
+		}
+	case TransformTypeJSONParse:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("jsonParse transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeTruncate:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("truncate transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeMap:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("map transform can only be used with string types, got %s", fromType)
+		}
+	case TransformTypeMatch:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("match transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeString:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("string transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeCase:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("case transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeSemver:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("semver transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeCIDRMatch:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("cidrMatch transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypePEM:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("pem transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeConditionStatus:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("conditionStatus transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeValidateFormat:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("validateFormat transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeStableSuffix:
+		if fromType != TransformIOTypeString {
+			return errors.Errorf("stableSuffix transform can only be used with string input types, got %s", fromType)
+		}
+	case TransformTypeConvert:
+		// Supported conversions are checked by the Composition engine.
+	case TransformTypeAllowlist:
+		// Any input may be compared to the permitted values.
+	case TransformTypeTransformSet:
+		// The transforms of the set are validated once inlined.
+	default:
+		return errors.Errorf("unknown transform type %s", t.Type)
+	}
+	return nil
+}
+
 // MathTransformType conducts mathematical operations.
 type MathTransformType string
 
@@ -149,14 +747,19 @@ const (
 	MathTransformTypeMultiply MathTransformType = "Multiply" // Default
 	MathTransformTypeClampMin MathTransformType = "ClampMin"
 	MathTransformTypeClampMax MathTransformType = "ClampMax"
+	MathTransformTypeIdentity MathTransformType = "Identity"
+	MathTransformTypeModulo   MathTransformType = "Modulo"
+	MathTransformTypeAdd      MathTransformType = "Add"
 )
 
 // MathTransform conducts mathematical operations on the input with the given
 // configuration in its properties.
 type MathTransform struct {
-	// Type of the math transform to be run.
+	// Type of the math transform to be run. An Identity math transform
+	// returns its numeric input unchanged, and requires no other
+	// configuration.
 	// +optional
-	// +kubebuilder:validation:Enum=Multiply;ClampMin;ClampMax
+	// +kubebuilder:validation:Enum=Multiply;ClampMin;ClampMax;Identity;Modulo;Add
 	// +kubebuilder:default=Multiply
 	Type MathTransformType `json:"type,omitempty"`
 
@@ -169,6 +772,16 @@ type MathTransform struct {
 	// ClampMax makes sure that the value is not bigger than the given value.
 	// +optional
 	ClampMax *int64 `json:"clampMax,omitempty"`
+	// Modulo returns the remainder of dividing the value by the given
+	// value. The remainder follows Go's % operator: it has the sign of the
+	// value, so -7 modulo 3 is -1 and 7 modulo -3 is 1. Modulo by zero is
+	// an error.
+	// +optional
+	Modulo *int64 `json:"modulo,omitempty"`
+	// Add the given value to the value. A negative value subtracts from
+	// the value.
+	// +optional
+	Add *int64 `json:"add,omitempty"`
 }
 
 // GetType returns the type of the math transform, returning the default if not specified.
@@ -183,20 +796,29 @@ func (m *MathTransform) GetType() MathTransformType {
 func (m *MathTransform) Validate() *field.Error {
 	switch m.GetType() {
 	case MathTransformTypeMultiply:
-		if m.Multiply == nil {
-			return field.Required(field.NewPath("multiply"), "must specify a value if a multiply math transform is specified")
-		}
+		return requireMathValue(m.Multiply, "multiply", "must specify a value if a multiply math transform is specified")
 	case MathTransformTypeClampMin:
-		if m.ClampMin == nil {
-			return field.Required(field.NewPath("clampMin"), "must specify a value if a clamp min math transform is specified")
-		}
+		return requireMathValue(m.ClampMin, "clampMin", "must specify a value if a clamp min math transform is specified")
 	case MathTransformTypeClampMax:
-		if m.ClampMax == nil {
-			return field.Required(field.NewPath("clampMax"), "must specify a value if a clamp max math transform is specified")
-		}
+		return requireMathValue(m.ClampMax, "clampMax", "must specify a value if a clamp max math transform is specified")
+	case MathTransformTypeModulo:
+		return requireMathValue(m.Modulo, "modulo", "must specify a value if a modulo math transform is specified")
+	case MathTransformTypeAdd:
+		return requireMathValue(m.Add, "add", "must specify a value if an add math transform is specified")
+	case MathTransformTypeIdentity:
+		// An identity math transform has no configuration.
+		return nil
 	default:
 		return field.Invalid(field.NewPath("type"), m.Type, "unknown math transform type")
 	}
+}
+
+// requireMathValue returns a required field error for the supplied field if
+// its value is nil.
+func requireMathValue(v *int64, name, detail string) *field.Error {
+	if v == nil {
+		return field.Required(field.NewPath(name), detail)
+	}
 	return nil
 }
 
@@ -215,6 +837,42 @@ func (m *MapTransform) Validate() *field.Error {
 	return nil
 }
 
+// ValidateCaseInsensitive checks this MapTransform is valid when its keys are
+// matched case-insensitively, i.e. that no two keys differ only by case.
+func (m *MapTransform) ValidateCaseInsensitive() *field.Error {
+	keys := make([]string, 0, len(m.Pairs))
+	for k := range m.Pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	seen := make(map[string]string, len(keys))
+	for _, k := range keys {
+		l := strings.ToLower(k)
+		if d, ok := seen[l]; ok {
+			return field.Duplicate(field.NewPath(k), fmt.Sprintf("key differs only by case from key %q", d))
+		}
+		seen[l] = k
+	}
+	return nil
+}
+
+// MapTransformOptions configures how a map transform looks up its input. They
+// are kept separate from the MapTransform because its pairs are inlined.
+type MapTransformOptions struct {
+	// CaseInsensitive matches the input against the keys of the map
+	// regardless of case. Keys that differ only by case are invalid when
+	// matching case-insensitively.
+	// +optional
+	CaseInsensitive bool `json:"caseInsensitive,omitempty"`
+}
+
+// IsCaseInsensitive returns true if map keys should be matched regardless of
+// case.
+func (o *MapTransformOptions) IsCaseInsensitive() bool {
+	return o != nil && o.CaseInsensitive
+}
+
 // NOTE(negz): The Kubernetes JSON decoder doesn't seem to like inlining a map
 // into a struct - doing so results in a seemingly successful unmarshal of the
 // data, but an empty map. We must keep the ,inline tag nevertheless in order to
@@ -329,6 +987,641 @@ func (m *MatchTransformPattern) Validate() *field.Error {
 	return nil
 }
 
+// A RangeTransform maps a numeric input to the value of the first of an ordered
+// list of buckets whose max it does not exceed.
+type RangeTransform struct {
+	// Buckets are tested in order. The value of the first bucket whose max is
+	// greater than or equal to the input is used as the result of this
+	// transform.
+	// +kubebuilder:validation:MinItems=1
+	Buckets []RangeTransformBucket `json:"buckets"`
+
+	// FallbackValue is the result of this transform if the input exceeds the
+	// max of every bucket. The transform returns an error if the input exceeds
+	// the max of every bucket and no fallback value is specified.
+	// +optional
+	FallbackValue extv1.JSON `json:"fallbackValue,omitempty"`
+}
+
+// A RangeTransformBucket is a bucket of a RangeTransform.
+type RangeTransformBucket struct {
+	// Max is the inclusive upper bound of this bucket.
+	Max int64 `json:"max"`
+
+	// Value is the result of the transform if the input falls in this bucket.
+	Value extv1.JSON `json:"value"`
+}
+
+// Validate checks this RangeTransform is valid.
+func (r *RangeTransform) Validate() *field.Error {
+	if len(r.Buckets) == 0 {
+		return field.Required(field.NewPath("buckets"), "at least one bucket must be specified if a range transform is specified")
+	}
+	return nil
+}
+
+// A CIDRMatchTransform maps an IP address input to the value of the first of
+// an ordered list of CIDR blocks that contains it.
+type CIDRMatchTransform struct {
+	// Blocks are tested in order. The value of the first block that contains
+	// the input IP address is used as the result of this transform.
+	// +kubebuilder:validation:MinItems=1
+	Blocks []CIDRMatchTransformBlock `json:"blocks"`
+
+	// FallbackValue is the result of this transform if no block contains the
+	// input IP address. The transform returns an error if no block contains
+	// the input and no fallback value is specified.
+	// +optional
+	FallbackValue extv1.JSON `json:"fallbackValue,omitempty"`
+}
+
+// A CIDRMatchTransformBlock is a block of a CIDRMatchTransform.
+type CIDRMatchTransformBlock struct {
+	// CIDR is the block of IP addresses, e.g. 10.0.0.0/16 or fd00::/8.
+	CIDR string `json:"cidr"`
+
+	// Value is the result of the transform if the input is in this block.
+	Value extv1.JSON `json:"value"`
+}
+
+// Validate checks this CIDRMatchTransform is valid.
+func (c *CIDRMatchTransform) Validate() *field.Error {
+	if len(c.Blocks) == 0 {
+		return field.Required(field.NewPath("blocks"), "at least one block must be specified if a cidrMatch transform is specified")
+	}
+	for i, b := range c.Blocks {
+		if _, _, err := net.ParseCIDR(b.CIDR); err != nil {
+			return field.Invalid(field.NewPath("blocks").Index(i).Child("cidr"), b.CIDR, err.Error())
+		}
+	}
+	return nil
+}
+
+// A PEMTransformAttribute is an attribute of a certificate.
+type PEMTransformAttribute string
+
+// Accepted PEMTransformAttributes.
+const (
+	PEMTransformAttributeCommonName       PEMTransformAttribute = "CommonName"
+	PEMTransformAttributeIssuerCommonName PEMTransformAttribute = "IssuerCommonName"
+	PEMTransformAttributeSerialNumber     PEMTransformAttribute = "SerialNumber"
+	PEMTransformAttributeNotBefore        PEMTransformAttribute = "NotBefore"
+	PEMTransformAttributeNotAfter         PEMTransformAttribute = "NotAfter"
+	PEMTransformAttributeDNSNames         PEMTransformAttribute = "DNSNames"
+)
+
+// A PEMTransform parses the first PEM-encoded certificate of its string input
+// and returns one of its attributes.
+type PEMTransform struct {
+	// Attribute of the certificate to return. `CommonName` and
+	// `IssuerCommonName` return the common name of the certificate's subject
+	// and issuer. `SerialNumber` returns the serial number as a decimal
+	// string. `NotBefore` and `NotAfter` return the bounds of the
+	// certificate's validity period as RFC 3339 timestamps. `DNSNames`
+	// returns the certificate's DNS subject alternative names as an array.
+	// +kubebuilder:validation:Enum=CommonName;IssuerCommonName;SerialNumber;NotBefore;NotAfter;DNSNames
+	Attribute PEMTransformAttribute `json:"attribute"`
+}
+
+// Validate checks this PEMTransform is valid.
+func (p *PEMTransform) Validate() *field.Error {
+	switch p.Attribute {
+	case PEMTransformAttributeCommonName, PEMTransformAttributeIssuerCommonName, PEMTransformAttributeSerialNumber,
+		PEMTransformAttributeNotBefore, PEMTransformAttributeNotAfter, PEMTransformAttributeDNSNames:
+		return nil
+	case "":
+		return field.Required(field.NewPath("attribute"), "pem transform requires an attribute")
+	}
+	return field.Invalid(field.NewPath("attribute"), p.Attribute, "unknown pem transform attribute")
+}
+
+// An AllowlistTransform passes its input through unchanged if it is one of a
+// set of permitted values, and returns an error otherwise. Unlike a map
+// transform it never changes its input; it is used to reject unexpected values
+// before they are patched.
+type AllowlistTransform struct {
+	// Values the input is permitted to be. An input is permitted if it is
+	// equal to one of the values when both are encoded as JSON, so for
+	// example the number 3 doesn't permit the string "3".
+	// +kubebuilder:validation:MinItems=1
+	Values []extv1.JSON `json:"values"`
+}
+
+// A ConditionStatusTransform maps the status of a condition, e.g. a
+// composed resource's Ready condition, to a value. It is a more convenient
+// form of a map transform with the keys True, False, and Unknown.
+type ConditionStatusTransform struct {
+	// True is the value to return if the status is True.
+	// +optional
+	True *extv1.JSON `json:"true,omitempty"`
+
+	// False is the value to return if the status is False.
+	// +optional
+	False *extv1.JSON `json:"false,omitempty"`
+
+	// Unknown is the value to return if the status is Unknown.
+	// +optional
+	Unknown *extv1.JSON `json:"unknown,omitempty"`
+
+	// Default is the value to return if no value is specified for the
+	// status, or if the input is not a known status. The transform returns
+	// an error in these cases if no default is specified.
+	// +optional
+	Default *extv1.JSON `json:"default,omitempty"`
+}
+
+// Validate checks this ConditionStatusTransform is valid.
+func (c *ConditionStatusTransform) Validate() *field.Error {
+	if c.True == nil && c.False == nil && c.Unknown == nil && c.Default == nil {
+		return field.Required(field.NewPath("default"), "at least one value or a default must be specified if a conditionStatus transform is specified")
+	}
+	return nil
+}
+
+// A ValidateFormat is a format of string that a validateFormat transform checks
+// its input is of.
+type ValidateFormat string
+
+// Accepted ValidateFormats.
+const (
+	ValidateFormatEmail    ValidateFormat = "email"
+	ValidateFormatHostname ValidateFormat = "hostname"
+)
+
+// A ValidateFormatTransform checks that its string input is of a format, and
+// returns it normalized. It returns an error if the input is malformed, in
+// order to surface bad input before it is patched to a composed resource.
+type ValidateFormatTransform struct {
+	// Format the input must be of. Surrounding whitespace is trimmed from
+	// the input before it is checked.
+	//
+	// * `email` - an email address without a display name, e.g.
+	// ops@example.org. The domain is returned in lower case.
+	// * `hostname` - an RFC 1123 hostname, e.g. db.example.org. It is
+	// returned in lower case, without a trailing dot.
+	//
+	// +kubebuilder:validation:Enum=email;hostname
+	Format ValidateFormat `json:"format"`
+}
+
+// Validate checks this ValidateFormatTransform is valid.
+func (v *ValidateFormatTransform) Validate() *field.Error {
+	switch v.Format {
+	case ValidateFormatEmail, ValidateFormatHostname:
+		return nil
+	case "":
+		return field.Required(field.NewPath("format"), "validateFormat transform requires a format")
+	}
+	return field.Invalid(field.NewPath("format"), v.Format, "unknown validateFormat transform format")
+}
+
+// A LabelSelectorTransformMode determines the direction in which a
+// labelSelector transform converts its input.
+type LabelSelectorTransformMode string
+
+// Accepted LabelSelectorTransformModes.
+const (
+	// LabelSelectorTransformModeToSelector converts a map of labels to a
+	// label selector whose matchLabels are the labels.
+	LabelSelectorTransformModeToSelector LabelSelectorTransformMode = "ToSelector"
+
+	// LabelSelectorTransformModeFromSelector converts a label selector to
+	// the map of labels it matches. A matchExpressions requirement is only
+	// supported if it uses the In operator with a single value.
+	LabelSelectorTransformModeFromSelector LabelSelectorTransformMode = "FromSelector"
+)
+
+// A LabelSelectorTransform converts between a map of labels and a label
+// selector that matches them, e.g. to select the resources labelled by a
+// field of another resource.
+type LabelSelectorTransform struct {
+	// Mode determines whether a map of labels is converted to a label
+	// selector ('ToSelector') or a label selector is converted to a map of
+	// labels ('FromSelector').
+	// +kubebuilder:validation:Enum=ToSelector;FromSelector
+	Mode LabelSelectorTransformMode `json:"mode"`
+}
+
+// Validate checks this LabelSelectorTransform is valid.
+func (l *LabelSelectorTransform) Validate() *field.Error {
+	switch l.Mode {
+	case LabelSelectorTransformModeToSelector, LabelSelectorTransformModeFromSelector:
+		return nil
+	case "":
+		return field.Required(field.NewPath("mode"), "labelSelector transform requires a mode")
+	}
+	return field.Invalid(field.NewPath("mode"), l.Mode, "unknown labelSelector transform mode")
+}
+
+// A FieldSelectTransform returns the value at a field path of an object
+// input. It is typically used after a jsonParse transform, to extract a value
+// from the parsed object without an intermediate patch.
+type FieldSelectTransform struct {
+	// FieldPath of the value to return, relative to the input object, e.g.
+	// spec.endpoints[0].address.
+	FieldPath string `json:"fieldPath"`
+
+	// Policy determines what happens if the field path does not exist. The
+	// default, 'Optional', skips the patch the transform belongs to if that
+	// patch is optional, just like a patch whose optional fromFieldPath does
+	// not exist. Use 'Required' to instead fail the patch.
+	// +kubebuilder:validation:Enum=Optional;Required
+	// +optional
+	Policy *FromFieldPathPolicy `json:"policy,omitempty"`
+}
+
+// GetPolicy returns the policy of the field select transform, returning the
+// default if not specified.
+func (f *FieldSelectTransform) GetPolicy() FromFieldPathPolicy {
+	if f.Policy == nil {
+		return FromFieldPathPolicyOptional
+	}
+	return *f.Policy
+}
+
+// Validate checks this FieldSelectTransform is valid.
+func (f *FieldSelectTransform) Validate() *field.Error {
+	if f.FieldPath == "" {
+		return field.Required(field.NewPath("fieldPath"), "a field path must be specified if a fieldSelect transform is specified")
+	}
+	if _, err := fieldpath.Parse(f.FieldPath); err != nil {
+		return field.Invalid(field.NewPath("fieldPath"), f.FieldPath, err.Error())
+	}
+	switch f.GetPolicy() {
+	case FromFieldPathPolicyOptional, FromFieldPathPolicyRequired:
+	default:
+		return field.Invalid(field.NewPath("policy"), f.GetPolicy(), "unknown fromFieldPath policy")
+	}
+	return nil
+}
+
+// A SelectMatchTransform returns the first element of an array input whose
+// value at a field path equals a value, or the value at a field path of that
+// element. It is typically used to select an element of an array of objects
+// returned by a provider without relying on its index.
+type SelectMatchTransform struct {
+	// FieldPath of the value to match, relative to each element of the
+	// input, e.g. type.
+	FieldPath string `json:"fieldPath"`
+
+	// Value the field must be equal to for an element to match. Values are
+	// equal when both are encoded as JSON, so for example the number 3
+	// doesn't match the string "3".
+	Value extv1.JSON `json:"value"`
+
+	// ReturnField is the field path of the value to return, relative to
+	// the matching element, e.g. address. The matching element itself is
+	// returned if omitted.
+	// +optional
+	ReturnField *string `json:"returnField,omitempty"`
+
+	// Policy determines what happens if no element matches, or if the
+	// returnField of the matching element does not exist. The default,
+	// 'Optional', skips the patch the transform belongs to if that patch is
+	// optional, just like a patch whose optional fromFieldPath does not
+	// exist. Use 'Required' to instead fail the patch.
+	// +kubebuilder:validation:Enum=Optional;Required
+	// +optional
+	Policy *FromFieldPathPolicy `json:"policy,omitempty"`
+}
+
+// GetPolicy returns the policy of the select match transform, returning the
+// default if not specified.
+func (s *SelectMatchTransform) GetPolicy() FromFieldPathPolicy {
+	if s.Policy == nil {
+		return FromFieldPathPolicyOptional
+	}
+	return *s.Policy
+}
+
+// A DedupTransform removes duplicate elements from an array input, preserving
+// the order in which elements are first seen. Scalar elements are duplicates
+// when they are equal when encoded as JSON, so for example the number 3 is not
+// a duplicate of the string "3". Object and array elements can't be compared
+// unless a KeyFieldPath is specified.
+type DedupTransform struct {
+	// KeyFieldPath of the value that identifies an element, relative to each
+	// element of the input, e.g. name. If specified every element must be an
+	// object with a value at this field path, and elements are duplicates
+	// when their values are equal when encoded as JSON. If omitted every
+	// element must be a scalar.
+	// +optional
+	KeyFieldPath *string `json:"keyFieldPath,omitempty"`
+}
+
+// Validate checks this DedupTransform is valid.
+func (d *DedupTransform) Validate() *field.Error {
+	if d.KeyFieldPath == nil {
+		return nil
+	}
+	if *d.KeyFieldPath == "" {
+		return field.Required(field.NewPath("keyFieldPath"), "keyFieldPath must not be empty if specified")
+	}
+	if _, err := fieldpath.Parse(*d.KeyFieldPath); err != nil {
+		return field.Invalid(field.NewPath("keyFieldPath"), *d.KeyFieldPath, err.Error())
+	}
+	return nil
+}
+
+// Validate checks this SelectMatchTransform is valid.
+func (s *SelectMatchTransform) Validate() *field.Error {
+	if s.FieldPath == "" {
+		return field.Required(field.NewPath("fieldPath"), "a field path must be specified if a selectMatch transform is specified")
+	}
+	if _, err := fieldpath.Parse(s.FieldPath); err != nil {
+		return field.Invalid(field.NewPath("fieldPath"), s.FieldPath, err.Error())
+	}
+	if !json.Valid(s.Value.Raw) {
+		return field.Invalid(field.NewPath("value"), string(s.Value.Raw), "value must be valid JSON")
+	}
+	if s.ReturnField != nil {
+		if _, err := fieldpath.Parse(*s.ReturnField); err != nil {
+			return field.Invalid(field.NewPath("returnField"), *s.ReturnField, err.Error())
+		}
+	}
+	switch s.GetPolicy() {
+	case FromFieldPathPolicyOptional, FromFieldPathPolicyRequired:
+	default:
+		return field.Invalid(field.NewPath("policy"), s.GetPolicy(), "unknown fromFieldPath policy")
+	}
+	return nil
+}
+
+// Validate checks this AllowlistTransform is valid.
+func (a *AllowlistTransform) Validate() *field.Error {
+	if len(a.Values) == 0 {
+		return field.Required(field.NewPath("values"), "at least one value must be specified if an allowlist transform is specified")
+	}
+	for i, v := range a.Values {
+		if !json.Valid(v.Raw) {
+			return field.Invalid(field.NewPath("values").Index(i), string(v.Raw), "value is not valid JSON")
+		}
+	}
+	return nil
+}
+
+// AggregateTransformType is the type of an aggregate transform.
+type AggregateTransformType string
+
+// Accepted AggregateTransformTypes.
+const (
+	AggregateTransformTypeSum   AggregateTransformType = "Sum" // Default
+	AggregateTransformTypeMax   AggregateTransformType = "Max"
+	AggregateTransformTypeMin   AggregateTransformType = "Min"
+	AggregateTransformTypeCount AggregateTransformType = "Count"
+)
+
+// An AggregateTransform reduces an array of numbers to a single number. The
+// result is an integer if every element of the array is an integer, and a
+// float otherwise. A count is always an integer.
+type AggregateTransform struct {
+	// Type of the aggregation to be run.
+	// +optional
+	// +kubebuilder:validation:Enum=Sum;Max;Min;Count
+	// +kubebuilder:default=Sum
+	Type AggregateTransformType `json:"type,omitempty"`
+}
+
+// GetType returns the type of the aggregate transform, returning the default
+// if not specified.
+func (a *AggregateTransform) GetType() AggregateTransformType {
+	if a.Type == "" {
+		return AggregateTransformTypeSum
+	}
+	return a.Type
+}
+
+// Validate checks this AggregateTransform is valid.
+func (a *AggregateTransform) Validate() *field.Error {
+	switch a.GetType() {
+	case AggregateTransformTypeSum, AggregateTransformTypeMax, AggregateTransformTypeMin, AggregateTransformTypeCount:
+		return nil
+	default:
+		return field.Invalid(field.NewPath("type"), a.Type, "unknown aggregate transform type")
+	}
+}
+
+// A TernaryTransform returns one of two values depending on whether its
+// boolean input is true or false.
+type TernaryTransform struct {
+	// True is the result of this transform if its input is true.
+	True extv1.JSON `json:"true"`
+
+	// False is the result of this transform if its input is false.
+	False extv1.JSON `json:"false"`
+}
+
+// Validate checks this TernaryTransform is valid.
+func (t *TernaryTransform) Validate() *field.Error {
+	if len(t.True.Raw) == 0 {
+		return field.Required(field.NewPath("true"), "a value must be specified for a true input")
+	}
+	if len(t.False.Raw) == 0 {
+		return field.Required(field.NewPath("false"), "a value must be specified for a false input")
+	}
+	return nil
+}
+
+// TruncateHashLength is the number of hexadecimal characters of the hash a
+// TruncateTransform appends to a truncated input. The hash is separated from
+// the input by a hyphen.
+const TruncateHashLength = 8
+
+// A TruncateTransform shortens its string input to a maximum length. Inputs
+// that are already within the maximum length are not changed.
+type TruncateTransform struct {
+	// MaxLength is the maximum length, in characters, of the output.
+	// +kubebuilder:validation:Minimum=1
+	MaxLength int `json:"maxLength"`
+
+	// HashSuffix appends a hyphen and a short hash of the entire input to a
+	// truncated input, such that different inputs with the same prefix are
+	// truncated to different outputs. The input is truncated further to make
+	// space for the suffix, so that the output is never longer than
+	// MaxLength.
+	// +optional
+	HashSuffix bool `json:"hashSuffix,omitempty"`
+}
+
+// Validate checks this TruncateTransform is valid.
+func (t *TruncateTransform) Validate() *field.Error {
+	if t.MaxLength < 1 {
+		return field.Invalid(field.NewPath("maxLength"), t.MaxLength, "maxLength must be positive")
+	}
+	// At least one character of the input must fit before the suffix.
+	if t.HashSuffix && t.MaxLength < TruncateHashLength+2 {
+		return field.Invalid(field.NewPath("maxLength"), t.MaxLength, fmt.Sprintf("maxLength must be at least %d to fit a hash suffix", TruncateHashLength+2))
+	}
+	return nil
+}
+
+// CaseTransformType is the naming convention a case transform converts to.
+type CaseTransformType string
+
+// Accepted CaseTransformTypes.
+const (
+	CaseTransformTypeKebab CaseTransformType = "kebab"
+	CaseTransformTypeSnake CaseTransformType = "snake"
+	CaseTransformTypeCamel CaseTransformType = "camel"
+)
+
+// A CaseTransform converts a string input of words to a naming convention.
+// The words of the input are the maximal runs of characters other than
+// whitespace, underscores, and hyphens, so consecutive, leading, and trailing
+// delimiters are ignored, and delimiters of different kinds may be mixed.
+// Letters within a word are not split by case, i.e. an input of "fooBar" is a
+// single word. The kebab and snake conventions join the lowercased words with
+// hyphens and underscores respectively, e.g. "my-bucket" and "my_bucket". The
+// camel convention lowercases the first word, and uppercases the first letter
+// of each subsequent word and lowercases the rest, e.g. "myBucket".
+type CaseTransform struct {
+	// To is the naming convention to convert the input to.
+	// +kubebuilder:validation:Enum=kebab;snake;camel
+	To CaseTransformType `json:"to"`
+}
+
+// Validate checks this CaseTransform is valid.
+func (t *CaseTransform) Validate() *field.Error {
+	switch t.To {
+	case CaseTransformTypeKebab, CaseTransformTypeSnake, CaseTransformTypeCamel:
+		return nil
+	case "":
+		return field.Required(field.NewPath("to"), "case transform requires a naming convention")
+	default:
+		return field.Invalid(field.NewPath("to"), t.To, "unknown case transform type")
+	}
+}
+
+// SemverTransformOperation is the operation a semver transform performs.
+type SemverTransformOperation string
+
+// Accepted SemverTransformOperations.
+const (
+	SemverTransformOperationNormalize SemverTransformOperation = "normalize"
+	SemverTransformOperationMajor     SemverTransformOperation = "major"
+	SemverTransformOperationMinor     SemverTransformOperation = "minor"
+	SemverTransformOperationPatch     SemverTransformOperation = "patch"
+)
+
+// A SemverTransform parses a semantic version string input, such as v1.2.3 or
+// 1.2.3-rc.1+build.5. A leading v is optional, and a missing minor or patch
+// version is taken to be 0, so v1.2 is parsed as 1.2.0. Any other input that
+// isn't a semantic version returns an error.
+type SemverTransform struct {
+	// Operation to perform on the parsed version. 'normalize' returns the
+	// version as a string without a leading v, with all three of its
+	// numeric components, and with its prerelease and build metadata if
+	// any, e.g. 1.2.0. 'major', 'minor', and 'patch' return the respective
+	// numeric component as an integer.
+	// +kubebuilder:validation:Enum=normalize;major;minor;patch
+	Operation SemverTransformOperation `json:"operation"`
+}
+
+// Validate checks this SemverTransform is valid.
+func (t *SemverTransform) Validate() *field.Error {
+	switch t.Operation {
+	case SemverTransformOperationNormalize, SemverTransformOperationMajor, SemverTransformOperationMinor, SemverTransformOperationPatch:
+		return nil
+	case "":
+		return field.Required(field.NewPath("operation"), "semver transform requires an operation")
+	default:
+		return field.Invalid(field.NewPath("operation"), t.Operation, "unknown semver transform operation")
+	}
+}
+
+// DefaultStableSuffixCharset is the charset a StableSuffixTransform draws from
+// if none is specified. It contains only characters that are valid in a
+// Kubernetes resource name.
+const DefaultStableSuffixCharset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// MaxStableSuffixLength is the maximum length of the suffix returned by a
+// StableSuffixTransform.
+const MaxStableSuffixLength = 64
+
+// A StableSuffixTransform returns a pseudo-random string derived from its
+// string input. The same input always returns the same string, so the output
+// is stable across reconciles. The output is deterministic and predictable by
+// anyone who knows the input; it is not suitable for secrets.
+type StableSuffixTransform struct {
+	// Length of the output, in characters.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	Length int `json:"length"`
+
+	// Charset is the set of characters the output is drawn from. The
+	// default is lower case letters and digits.
+	// +optional
+	Charset *string `json:"charset,omitempty"`
+}
+
+// GetCharset returns the charset of the transform, returning the default if
+// not specified.
+func (s *StableSuffixTransform) GetCharset() string {
+	if s.Charset == nil {
+		return DefaultStableSuffixCharset
+	}
+	return *s.Charset
+}
+
+// Validate checks this StableSuffixTransform is valid.
+func (s *StableSuffixTransform) Validate() *field.Error {
+	if s.Length < 1 || s.Length > MaxStableSuffixLength {
+		return field.Invalid(field.NewPath("length"), s.Length, fmt.Sprintf("length must be between 1 and %d", MaxStableSuffixLength))
+	}
+	if len([]rune(s.GetCharset())) < 2 {
+		return field.Invalid(field.NewPath("charset"), s.GetCharset(), "charset must contain at least two characters")
+	}
+	return nil
+}
+
+// MaxNumberFormatDecimalPlaces is the maximum number of decimal places a
+// NumberFormatTransform may format a number with.
+const MaxNumberFormatDecimalPlaces = 15
+
+// A NumberFormatTransform formats a numeric input as a string.
+type NumberFormatTransform struct {
+	// ThousandsSeparator is inserted between each group of three digits of
+	// the integer part of the number, for example "," to format 1234567 as
+	// "1,234,567". Digits are not grouped if it is omitted.
+	// +optional
+	ThousandsSeparator *string `json:"thousandsSeparator,omitempty"`
+
+	// DecimalSeparator separates the integer part of the number from its
+	// fractional part. Defaults to ".".
+	// +optional
+	DecimalSeparator *string `json:"decimalSeparator,omitempty"`
+
+	// DecimalPlaces is the fixed number of decimal places to format the
+	// number with. The number is rounded if it has more decimal places, and
+	// padded with zeros if it has fewer. If it is omitted integers are
+	// formatted without decimal places, and floats with as many as are
+	// needed to represent them exactly.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	DecimalPlaces *int `json:"decimalPlaces,omitempty"`
+}
+
+// GetDecimalSeparator returns the decimal separator of the transform,
+// returning the default if not specified.
+func (t *NumberFormatTransform) GetDecimalSeparator() string {
+	if t.DecimalSeparator == nil {
+		return "."
+	}
+	return *t.DecimalSeparator
+}
+
+// Validate checks this NumberFormatTransform is valid.
+func (t *NumberFormatTransform) Validate() *field.Error {
+	if t.DecimalPlaces != nil && (*t.DecimalPlaces < 0 || *t.DecimalPlaces > MaxNumberFormatDecimalPlaces) {
+		return field.Invalid(field.NewPath("decimalPlaces"), *t.DecimalPlaces, fmt.Sprintf("decimalPlaces must be between 0 and %d", MaxNumberFormatDecimalPlaces))
+	}
+	if t.ThousandsSeparator != nil && *t.ThousandsSeparator == t.GetDecimalSeparator() {
+		return field.Invalid(field.NewPath("thousandsSeparator"), *t.ThousandsSeparator, "thousandsSeparator must differ from decimalSeparator")
+	}
+	return nil
+}
+
 // StringTransformType transforms a string.
 type StringTransformType string
 
@@ -339,6 +1632,9 @@ const (
 	StringTransformTypeTrimPrefix StringTransformType = "TrimPrefix"
 	StringTransformTypeTrimSuffix StringTransformType = "TrimSuffix"
 	StringTransformTypeRegexp     StringTransformType = "Regexp"
+	StringTransformTypeReplace    StringTransformType = "Replace"
+	StringTransformTypeSlice      StringTransformType = "Slice"
+	StringTransformTypeEscape     StringTransformType = "Escape"
 )
 
 // StringConversionType converts a string.
@@ -354,6 +1650,9 @@ const (
 	StringConversionTypeToSHA1     StringConversionType = "ToSha1"
 	StringConversionTypeToSHA256   StringConversionType = "ToSha256"
 	StringConversionTypeToSHA512   StringConversionType = "ToSha512"
+	StringConversionTypeURLEncode  StringConversionType = "URLEncode"
+	StringConversionTypeURLDecode  StringConversionType = "URLDecode"
+	StringConversionTypeDNS1123    StringConversionType = "DNS1123"
 )
 
 // A StringTransform returns a string given the supplied input.
@@ -361,7 +1660,7 @@ type StringTransform struct {
 
 	// Type of the string transform to be run.
 	// +optional
-	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp
+	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp;Replace;Slice;Escape
 	// +kubebuilder:default=Format
 	Type StringTransformType `json:"type,omitempty"`
 
@@ -373,11 +1672,20 @@ type StringTransform struct {
 	// Optional conversion method to be specified.
 	// `ToUpper` and `ToLower` change the letter case of the input string.
 	// `ToBase64` and `FromBase64` perform a base64 conversion based on the input string.
-	// `ToJson` converts any input value into its raw JSON representation.
+	// `ToJson` converts any input value, including an object or an array,
+	// into its raw JSON representation. It is the inverse of the jsonParse
+	// transform.
 	// `ToSha1`, `ToSha256` and `ToSha512` generate a hash value based on the input
 	// converted to JSON.
+	// `URLEncode` and `URLDecode` perform URL query escaping based on the input
+	// string.
+	// `DNS1123` normalizes the input to a valid DNS-1123 label, which may be
+	// used as the name of a resource or as a label value. The input is
+	// lowercased, each character other than a-z, 0-9, and '-' is replaced
+	// with '-', the result is truncated to 63 characters, and any leading or
+	// trailing '-' are removed.
 	// +optional
-	// +kubebuilder:validation:Enum=ToUpper;ToLower;ToBase64;FromBase64;ToJson;ToSha1;ToSha256;ToSha512
+	// +kubebuilder:validation:Enum=ToUpper;ToLower;ToBase64;FromBase64;ToJson;ToSha1;ToSha256;ToSha512;URLEncode;URLDecode;DNS1123
 	Convert *StringConversionType `json:"convert,omitempty"`
 
 	// Trim the prefix or suffix from the input
@@ -387,6 +1695,30 @@ type StringTransform struct {
 	// Extract a match from the input using a regular expression.
 	// +optional
 	Regexp *StringTransformRegexp `json:"regexp,omitempty"`
+
+	// Replace occurrences of a string in the input with another string.
+	// +optional
+	Replace *StringTransformReplace `json:"replace,omitempty"`
+
+	// Slice returns the characters of the input between two indices.
+	// +optional
+	Slice *StringTransformSlice `json:"slice,omitempty"`
+
+	// Escape the input so that it may be safely embedded in a particular
+	// context, such as a JSON string or a shell command.
+	// +optional
+	Escape *StringTransformEscape `json:"escape,omitempty"`
+
+	// Prefix is prepended to the result of the transform, regardless of its
+	// type. Use it to wrap a value in fixed text without embedding that text
+	// in a format string.
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+
+	// Suffix is appended to the result of the transform, regardless of its
+	// type.
+	// +optional
+	Suffix *string `json:"suffix,omitempty"`
 }
 
 // Validate checks this StringTransform is valid.
@@ -416,6 +1748,29 @@ func (s *StringTransform) Validate() *field.Error {
 		if _, err := regexp.Compile(s.Regexp.Match); err != nil {
 			return field.Invalid(field.NewPath("regexp", "match"), s.Regexp.Match, "invalid regexp")
 		}
+	case StringTransformTypeReplace:
+		if s.Replace == nil {
+			return field.Required(field.NewPath("replace"), "replace transform requires a replace configuration")
+		}
+		if s.Replace.Old == "" {
+			return field.Required(field.NewPath("replace", "old"), "replace transform requires a string to replace")
+		}
+		if s.Replace.Count != nil && *s.Replace.Count < 1 {
+			return field.Invalid(field.NewPath("replace", "count"), *s.Replace.Count, "count must be positive")
+		}
+	case StringTransformTypeSlice:
+		if s.Slice == nil {
+			return field.Required(field.NewPath("slice"), "slice transform requires a slice configuration")
+		}
+	case StringTransformTypeEscape:
+		if s.Escape == nil {
+			return field.Required(field.NewPath("escape"), "escape transform requires an escape configuration")
+		}
+		switch s.Escape.Context {
+		case StringEscapeContextJSON, StringEscapeContextShell, StringEscapeContextURL:
+		default:
+			return field.Invalid(field.NewPath("escape", "context"), s.Escape.Context, "unknown escape context")
+		}
 	default:
 		return field.Invalid(field.NewPath("type"), s.Type, "unknown string transform type")
 	}
@@ -435,6 +1790,61 @@ type StringTransformRegexp struct {
 	Group *int `json:"group,omitempty"`
 }
 
+// A StringTransformReplace replaces occurrences of a string in the input with
+// another string.
+type StringTransformReplace struct {
+	// Old is the string to replace. It must not be empty.
+	Old string `json:"old"`
+
+	// New is the string to replace Old with. Use an empty string to delete
+	// occurrences of Old.
+	// +optional
+	New string `json:"new,omitempty"`
+
+	// Count is the maximum number of occurrences of Old to replace, starting
+	// from the beginning of the input. All occurrences are replaced by default.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Count *int `json:"count,omitempty"`
+}
+
+// A StringTransformSlice returns the characters of the input between two
+// indices. A negative index counts back from the end of the input, so -1 is
+// the index of its last character. Indices beyond either end of the input are
+// clamped to it.
+type StringTransformSlice struct {
+	// Start is the index of the first character to return. Defaults to 0,
+	// the start of the input.
+	// +optional
+	Start int `json:"start,omitempty"`
+
+	// End is the index after the last character to return. Defaults to the
+	// end of the input.
+	// +optional
+	End *int `json:"end,omitempty"`
+}
+
+// A StringEscapeContext is a context in which a string may be embedded.
+type StringEscapeContext string
+
+// Accepted StringEscapeContexts.
+const (
+	StringEscapeContextJSON  StringEscapeContext = "json"
+	StringEscapeContextShell StringEscapeContext = "shell"
+	StringEscapeContextURL   StringEscapeContext = "url"
+)
+
+// A StringTransformEscape escapes the input so that it may be safely embedded
+// in a particular context.
+type StringTransformEscape struct {
+	// Context in which the output will be embedded. `json` escapes the input
+	// for use between the quotes of a JSON string, without adding the quotes.
+	// `shell` quotes the input as a single POSIX shell word. `url` escapes
+	// the input for use as a URL query parameter.
+	// +kubebuilder:validation:Enum=json;shell;url
+	Context StringEscapeContext `json:"context"`
+}
+
 // TransformIOType defines the type of a ConvertTransform.
 type TransformIOType string
 
@@ -445,12 +1855,19 @@ const (
 	TransformIOTypeInt     TransformIOType = "int"
 	TransformIOTypeInt64   TransformIOType = "int64"
 	TransformIOTypeFloat64 TransformIOType = "float64"
+
+	// TransformIOTypeInt32 and TransformIOTypeInt16 are only supported as
+	// the output type of a ConvertTransform. Their values are represented as
+	// int64, but must fit within the narrower type.
+	TransformIOTypeInt32 TransformIOType = "int32"
+	TransformIOTypeInt16 TransformIOType = "int16"
 )
 
 // IsValid checks if the given TransformIOType is valid.
 func (c TransformIOType) IsValid() bool {
 	switch c {
-	case TransformIOTypeString, TransformIOTypeBool, TransformIOTypeInt, TransformIOTypeInt64, TransformIOTypeFloat64:
+	case TransformIOTypeString, TransformIOTypeBool, TransformIOTypeInt, TransformIOTypeInt64, TransformIOTypeFloat64,
+		TransformIOTypeInt32, TransformIOTypeInt16:
 		return true
 	}
 	return false
@@ -464,12 +1881,13 @@ type ConvertTransformFormat string
 const (
 	ConvertTransformFormatNone     ConvertTransformFormat = "none"
 	ConvertTransformFormatQuantity ConvertTransformFormat = "quantity"
+	ConvertTransformFormatIPv4     ConvertTransformFormat = "ipv4"
 )
 
 // IsValid returns true if the format is valid.
 func (c ConvertTransformFormat) IsValid() bool {
 	switch c {
-	case ConvertTransformFormatNone, ConvertTransformFormatQuantity:
+	case ConvertTransformFormatNone, ConvertTransformFormatQuantity, ConvertTransformFormatIPv4:
 		return true
 	}
 	return false
@@ -477,20 +1895,31 @@ func (c ConvertTransformFormat) IsValid() bool {
 
 // A ConvertTransform converts the input into a new object whose type is supplied.
 type ConvertTransform struct {
-	// ToType is the type of the output of this transform.
-	// +kubebuilder:validation:Enum=string;int;int64;bool;float64
+	// ToType is the type of the output of this transform. Conversions to
+	// int32 and int16 fail if the value does not fit within the type.
+	// Conversions to an integer type accept numbers of any kind, truncating
+	// floats, and strings containing either an integer or a float.
+	// +kubebuilder:validation:Enum=string;int;int64;int32;int16;bool;float64
 	ToType TransformIOType `json:"toType"`
 
 	// The expected input format.
 	//
 	// * `quantity` - parses the input as a K8s [`resource.Quantity`](https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#Quantity).
 	// Only used during `string -> float64` conversions.
+	// * `ipv4` - converts between a dotted decimal IPv4 address, e.g.
+	// `10.0.0.1`, and its 32-bit unsigned integer representation. Only used
+	// during `string -> int64` and `int64 -> string` conversions.
 	//
 	// If this property is null, the default conversion is applied.
 	//
-	// +kubebuilder:validation:Enum=none;quantity
+	// +kubebuilder:validation:Enum=none;quantity;ipv4
 	// +kubebuilder:validation:Default=none
 	Format *ConvertTransformFormat `json:"format,omitempty"`
+
+	// EmptyAsZero converts an empty string input to the zero value of ToType,
+	// i.e. 0, false, or an empty string, rather than returning an error.
+	// +optional
+	EmptyAsZero bool `json:"emptyAsZero,omitempty"`
 }
 
 // Validate returns an error if the ConvertTransform is invalid.
@@ -32,6 +32,7 @@ import (
 func TestTransformValidate(t *testing.T) {
 	type args struct {
 		transform *Transform
+		strict    bool
 	}
 	type want struct {
 		err *field.Error
@@ -77,6 +78,35 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidMathModulo": {
+			reason: "Math transform with valid MathTransform Modulo set should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{
+						Type:   MathTransformTypeModulo,
+						Modulo: pointer.Int64(3),
+					},
+				},
+			},
+		},
+		"InvalidMathModuloMissing": {
+			reason: "Math transform of type Modulo without a modulo should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{
+						Type: MathTransformTypeModulo,
+					},
+				},
+			},
+			want: want{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "math.modulo",
+				},
+			},
+		},
 		"InvalidMathWrongSpec": {
 			reason: "Math transform with invalid MathTransform set should be invalid",
 			args: args{
@@ -153,6 +183,59 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidMapCaseInsensitive": {
+			reason: "Map transform matched case-insensitively with keys that differ by more than case should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMap,
+					Map: &MapTransform{
+						Pairs: map[string]extv1.JSON{
+							"foo": {Raw: []byte(`"bar"`)},
+							"fop": {Raw: []byte(`"baz"`)},
+						},
+					},
+					MapOptions: &MapTransformOptions{CaseInsensitive: true},
+				},
+			},
+		},
+		"InvalidMapCaseInsensitiveDuplicateKeys": {
+			reason: "Map transform matched case-insensitively with keys that differ only by case should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMap,
+					Map: &MapTransform{
+						Pairs: map[string]extv1.JSON{
+							"Foo": {Raw: []byte(`"bar"`)},
+							"foo": {Raw: []byte(`"baz"`)},
+						},
+					},
+					MapOptions: &MapTransformOptions{CaseInsensitive: true},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeDuplicate,
+					Field: "map.foo",
+				},
+			},
+		},
+		"InvalidMapOptionsTypeMismatch": {
+			reason: "Map options set for a transform that isn't a map transform should be invalid in strict mode",
+			args: args{
+				transform: &Transform{
+					Type:       TransformTypeMath,
+					Math:       &MathTransform{Multiply: pointer.Int64(2)},
+					MapOptions: &MapTransformOptions{CaseInsensitive: true},
+				},
+				strict: true,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "mapOptions",
+				},
+			},
+		},
 		"InvalidMatchNoMatch": {
 			reason: "Match transform with no match set should be invalid",
 			args: args{
@@ -266,6 +349,87 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidStringReplace": {
+			reason: "String transform of type replace with a string to replace should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeString,
+					String: &StringTransform{
+						Type:    StringTransformTypeReplace,
+						Replace: &StringTransformReplace{Old: "-"},
+					},
+				},
+			},
+		},
+		"InvalidStringReplaceEmptyOld": {
+			reason: "String transform of type replace with an empty string to replace should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeString,
+					String: &StringTransform{
+						Type:    StringTransformTypeReplace,
+						Replace: &StringTransformReplace{New: "_"},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "string.replace.old",
+				},
+			},
+		},
+		"InvalidStringReplaceCount": {
+			reason: "String transform of type replace with a non-positive count should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeString,
+					String: &StringTransform{
+						Type:    StringTransformTypeReplace,
+						Replace: &StringTransformReplace{Old: "-", Count: pointer.Int(0)},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "string.replace.count",
+				},
+			},
+		},
+		"InvalidStringSliceMissingSlice": {
+			reason: "String transform of type slice without a slice configuration should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:   TransformTypeString,
+					String: &StringTransform{Type: StringTransformTypeSlice},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "string.slice",
+				},
+			},
+		},
+		"InvalidStringEscapeContext": {
+			reason: "String transform of type escape with an unknown context should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeString,
+					String: &StringTransform{
+						Type:   StringTransformTypeEscape,
+						Escape: &StringTransformEscape{Context: "sql"},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "string.escape.context",
+				},
+			},
+		},
 		"InvalidConvertMissingConvert": {
 			reason: "Convert transform missing Convert should be invalid",
 			args: args{
@@ -315,81 +479,815 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
-		"ValidConvert": {
-			reason: "Convert transform with valid format and toType should be valid",
+		"InvalidOnError": {
+			reason: "Unknown on error policy should be invalid",
 			args: args{
 				transform: &Transform{
-					Type: TransformTypeConvert,
-					Convert: &ConvertTransform{
-						Format: &[]ConvertTransformFormat{ConvertTransformFormatNone}[0],
-						ToType: TransformIOTypeInt,
+					Type: TransformTypeString,
+					String: &StringTransform{
+						Format: pointer.String("foo"),
 					},
+					OnError: &[]TransformOnErrorPolicy{"ignore"}[0],
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "onError",
 				},
 			},
 		},
-	}
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			err := tc.args.transform.Validate()
-			if diff := cmp.Diff(tc.want.err, err, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
-				t.Errorf("%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
-			}
-		})
-	}
-}
-
-func TestTransformGetOutputType(t *testing.T) {
-	type args struct {
-		transform *Transform
-	}
-	type want struct {
-		output *TransformIOType
-		err    error
-	}
-	cases := map[string]struct {
-		reason string
-		args   args
-		want   want
-	}{
-		"MapTransform": {
-			reason: "Output of Math transform should be float64",
+		"InvalidWhenMissingValue": {
+			reason: "A condition with an operator that requires a value but no value should be invalid",
 			args: args{
 				transform: &Transform{
-					Type: TransformTypeMath,
+					Type: TransformTypeLength,
+					When: &TransformCondition{Operator: TransformConditionOperatorContains},
 				},
 			},
 			want: want{
-				output: &[]TransformIOType{TransformIOTypeFloat64}[0],
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "when.value",
+				},
 			},
 		},
-		"ConvertTransform": {
-			reason: "Output of Convert transform, no validation, should be the type specified",
+		"InvalidWhenRegexp": {
+			reason: "A condition with an invalid regexp should be invalid",
 			args: args{
 				transform: &Transform{
-					Type:    TransformTypeConvert,
-					Convert: &ConvertTransform{ToType: "fakeType"},
+					Type: TransformTypeLength,
+					When: &TransformCondition{Operator: TransformConditionOperatorRegexp, Regexp: pointer.String("(")},
 				},
 			},
 			want: want{
-				output: &[]TransformIOType{"fakeType"}[0],
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "when.regexp",
+				},
 			},
 		},
-		"ErrorUnknownType": {
-			reason: "Output of Unknown transform type returns an error",
+		"InvalidWhenOperator": {
+			reason: "A condition with an unknown operator should be invalid",
 			args: args{
 				transform: &Transform{
-					Type: "fakeType",
+					Type: TransformTypeLength,
+					When: &TransformCondition{Operator: "GreaterThan"},
 				},
 			},
 			want: want{
-				err: fmt.Errorf("unable to get output type, unknown transform type: fakeType"),
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "when.operator",
+				},
 			},
 		},
-		"MapTransformNil": {
-			reason: "Output of Map transform is nil",
+		"ValidWhen": {
+			reason: "A transform with a valid condition should be valid",
 			args: args{
 				transform: &Transform{
-					Type: TransformTypeMap,
+					Type: TransformTypeLength,
+					When: &TransformCondition{Operator: TransformConditionOperatorNotEqual, Value: &extv1.JSON{Raw: []byte(`""`)}},
+				},
+			},
+		},
+		"InvalidRangeNoBuckets": {
+			reason: "Range transform with no buckets should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:  TransformTypeRange,
+					Range: &RangeTransform{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "range.buckets",
+				},
+			},
+		},
+		"ValidCIDRMatch": {
+			reason: "CIDRMatch transform with valid blocks should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCIDRMatch,
+					CIDRMatch: &CIDRMatchTransform{Blocks: []CIDRMatchTransformBlock{
+						{CIDR: "10.0.0.0/16"},
+						{CIDR: "fd00::/8"},
+					}},
+				},
+			},
+		},
+		"ValidPEM": {
+			reason: "PEM transform with a known attribute should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypePEM,
+					PEM:  &PEMTransform{Attribute: PEMTransformAttributeNotAfter},
+				},
+			},
+		},
+		"InvalidPEMAttribute": {
+			reason: "PEM transform with an unknown attribute should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypePEM,
+					PEM:  &PEMTransform{Attribute: "PrivateKey"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "pem.attribute",
+				},
+			},
+		},
+		"ValidAllowlist": {
+			reason: "Allowlist transform with valid values should be valid",
+			args: args{
+				transform: &Transform{
+					Type:      TransformTypeAllowlist,
+					Allowlist: &AllowlistTransform{Values: []extv1.JSON{{Raw: []byte(`"prod"`)}, {Raw: []byte(`3`)}}},
+				},
+			},
+		},
+		"ValidConditionStatusDefaultOnly": {
+			reason: "ConditionStatus transform with only a default should be valid",
+			args: args{
+				transform: &Transform{
+					Type:            TransformTypeConditionStatus,
+					ConditionStatus: &ConditionStatusTransform{Default: &extv1.JSON{Raw: []byte(`"unhealthy"`)}},
+				},
+			},
+		},
+		"InvalidConditionStatusNoValues": {
+			reason: "ConditionStatus transform with no values and no default should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:            TransformTypeConditionStatus,
+					ConditionStatus: &ConditionStatusTransform{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "conditionStatus.default",
+				},
+			},
+		},
+		"ValidFieldSelect": {
+			reason: "FieldSelect transform with a field path should be valid",
+			args: args{
+				transform: &Transform{
+					Type:        TransformTypeFieldSelect,
+					FieldSelect: &FieldSelectTransform{FieldPath: "endpoints[0].address"},
+				},
+			},
+		},
+		"InvalidFieldSelectNoFieldPath": {
+			reason: "FieldSelect transform without a field path should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:        TransformTypeFieldSelect,
+					FieldSelect: &FieldSelectTransform{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "fieldSelect.fieldPath",
+				},
+			},
+		},
+		"InvalidFieldSelectFieldPath": {
+			reason: "FieldSelect transform with a field path that can't be parsed should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:        TransformTypeFieldSelect,
+					FieldSelect: &FieldSelectTransform{FieldPath: "endpoints[0"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "fieldSelect.fieldPath",
+				},
+			},
+		},
+		"ValidSelectMatch": {
+			reason: "SelectMatch transform with a field path and a value should be valid",
+			args: args{
+				transform: &Transform{
+					Type:        TransformTypeSelectMatch,
+					SelectMatch: &SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"ExternalIP"`)}, ReturnField: pointer.String("address")},
+				},
+			},
+		},
+		"InvalidSelectMatchNoFieldPath": {
+			reason: "SelectMatch transform without a field path should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:        TransformTypeSelectMatch,
+					SelectMatch: &SelectMatchTransform{Value: extv1.JSON{Raw: []byte(`"ExternalIP"`)}},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "selectMatch.fieldPath",
+				},
+			},
+		},
+		"InvalidSelectMatchValue": {
+			reason: "SelectMatch transform without a value should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:        TransformTypeSelectMatch,
+					SelectMatch: &SelectMatchTransform{FieldPath: "type"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "selectMatch.value",
+				},
+			},
+		},
+		"InvalidSelectMatchReturnField": {
+			reason: "SelectMatch transform with a returnField that can't be parsed should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:        TransformTypeSelectMatch,
+					SelectMatch: &SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"ExternalIP"`)}, ReturnField: pointer.String("addresses[0")},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "selectMatch.returnField",
+				},
+			},
+		},
+		"ValidLabelSelector": {
+			reason: "LabelSelector transform with a known mode should be valid",
+			args: args{
+				transform: &Transform{
+					Type:          TransformTypeLabelSelector,
+					LabelSelector: &LabelSelectorTransform{Mode: LabelSelectorTransformModeFromSelector},
+				},
+			},
+		},
+		"InvalidLabelSelectorNoMode": {
+			reason: "LabelSelector transform without a mode should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:          TransformTypeLabelSelector,
+					LabelSelector: &LabelSelectorTransform{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "labelSelector.mode",
+				},
+			},
+		},
+		"InvalidLabelSelectorMode": {
+			reason: "LabelSelector transform with an unknown mode should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:          TransformTypeLabelSelector,
+					LabelSelector: &LabelSelectorTransform{Mode: "ToString"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "labelSelector.mode",
+				},
+			},
+		},
+		"ValidCase": {
+			reason: "Case transform with a known naming convention should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCase,
+					Case: &CaseTransform{To: CaseTransformTypeKebab},
+				},
+			},
+		},
+		"InvalidCaseMissingConfig": {
+			reason: "Case transform without configuration should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCase,
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "case",
+				},
+			},
+		},
+		"InvalidCaseNoTo": {
+			reason: "Case transform without a naming convention should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCase,
+					Case: &CaseTransform{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "case.to",
+				},
+			},
+		},
+		"InvalidCaseTo": {
+			reason: "Case transform with an unknown naming convention should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCase,
+					Case: &CaseTransform{To: "pascal"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "case.to",
+				},
+			},
+		},
+		"ValidSemver": {
+			reason: "Semver transform with a known operation should be valid",
+			args: args{
+				transform: &Transform{
+					Type:   TransformTypeSemver,
+					Semver: &SemverTransform{Operation: SemverTransformOperationMajor},
+				},
+			},
+		},
+		"InvalidSemverMissingConfig": {
+			reason: "Semver transform without configuration should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeSemver,
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "semver",
+				},
+			},
+		},
+		"InvalidSemverOperation": {
+			reason: "Semver transform with an unknown operation should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:   TransformTypeSemver,
+					Semver: &SemverTransform{Operation: "bump"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "semver.operation",
+				},
+			},
+		},
+		"ValidDedupWithoutConfig": {
+			reason: "Dedup transform without configuration should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeDedup,
+				},
+			},
+		},
+		"ValidDedupKeyFieldPath": {
+			reason: "Dedup transform with a valid key field path should be valid",
+			args: args{
+				transform: &Transform{
+					Type:  TransformTypeDedup,
+					Dedup: &DedupTransform{KeyFieldPath: pointer.String("metadata.name")},
+				},
+			},
+		},
+		"InvalidDedupKeyFieldPath": {
+			reason: "Dedup transform with an unparseable key field path should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:  TransformTypeDedup,
+					Dedup: &DedupTransform{KeyFieldPath: pointer.String("spec[0")},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "dedup.keyFieldPath",
+				},
+			},
+		},
+		"ValidValidateFormat": {
+			reason: "ValidateFormat transform with a known format should be valid",
+			args: args{
+				transform: &Transform{
+					Type:           TransformTypeValidateFormat,
+					ValidateFormat: &ValidateFormatTransform{Format: ValidateFormatEmail},
+				},
+			},
+		},
+		"InvalidValidateFormatUnknownFormat": {
+			reason: "ValidateFormat transform with an unknown format should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:           TransformTypeValidateFormat,
+					ValidateFormat: &ValidateFormatTransform{Format: "uuid"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "validateFormat.format",
+				},
+			},
+		},
+		"ValidStableSuffix": {
+			reason: "StableSuffix transform with a length and the default charset should be valid",
+			args: args{
+				transform: &Transform{
+					Type:         TransformTypeStableSuffix,
+					StableSuffix: &StableSuffixTransform{Length: 5},
+				},
+			},
+		},
+		"InvalidStableSuffixLength": {
+			reason: "StableSuffix transform with a length above the maximum should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:         TransformTypeStableSuffix,
+					StableSuffix: &StableSuffixTransform{Length: MaxStableSuffixLength + 1},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "stableSuffix.length",
+				},
+			},
+		},
+		"InvalidStableSuffixCharset": {
+			reason: "StableSuffix transform with a charset of a single character should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:         TransformTypeStableSuffix,
+					StableSuffix: &StableSuffixTransform{Length: 5, Charset: pointer.String("a")},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "stableSuffix.charset",
+				},
+			},
+		},
+		"InvalidAllowlistNoValues": {
+			reason: "Allowlist transform with no values should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:      TransformTypeAllowlist,
+					Allowlist: &AllowlistTransform{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "allowlist.values",
+				},
+			},
+		},
+		"InvalidAllowlistValue": {
+			reason: "Allowlist transform with a value that isn't valid JSON should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:      TransformTypeAllowlist,
+					Allowlist: &AllowlistTransform{Values: []extv1.JSON{{Raw: []byte(`"prod"`)}, {Raw: []byte(`prod`)}}},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "allowlist.values[1]",
+				},
+			},
+		},
+		"InvalidCIDRMatchCIDR": {
+			reason: "CIDRMatch transform with an invalid CIDR should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCIDRMatch,
+					CIDRMatch: &CIDRMatchTransform{Blocks: []CIDRMatchTransformBlock{
+						{CIDR: "10.0.0.0/16"},
+						{CIDR: "10.0.0.0"},
+					}},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "cidrMatch.blocks[1].cidr",
+				},
+			},
+		},
+		"ValidAggregateDefault": {
+			reason: "Aggregate transform with no type should default to a valid sum",
+			args: args{
+				transform: &Transform{
+					Type:      TransformTypeAggregate,
+					Aggregate: &AggregateTransform{},
+				},
+			},
+		},
+		"InvalidAggregateType": {
+			reason: "Aggregate transform with an unknown type should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:      TransformTypeAggregate,
+					Aggregate: &AggregateTransform{Type: "Median"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "aggregate.type",
+				},
+			},
+		},
+		"InvalidTernaryMissingFalse": {
+			reason: "Ternary transform without a false value should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeTernary,
+					Ternary: &TernaryTransform{
+						True: extv1.JSON{Raw: []byte(`"yes"`)},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "ternary.false",
+				},
+			},
+		},
+		"ValidTruncate": {
+			reason: "Truncate transform with a positive max length should be valid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTruncate,
+					Truncate: &TruncateTransform{MaxLength: 10, HashSuffix: true},
+				},
+			},
+		},
+		"InvalidTruncateTooShortForHash": {
+			reason: "Truncate transform with a hash suffix and a max length that can't fit it should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTruncate,
+					Truncate: &TruncateTransform{MaxLength: 9, HashSuffix: true},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "truncate.maxLength",
+				},
+			},
+		},
+		"ValidLength": {
+			reason: "Length transform requires no configuration",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeLength,
+				},
+			},
+		},
+		"ValidNumberFormat": {
+			reason: "NumberFormat transform with distinct separators should be valid",
+			args: args{
+				transform: &Transform{
+					Type:         TransformTypeNumberFormat,
+					NumberFormat: &NumberFormatTransform{ThousandsSeparator: pointer.String(","), DecimalPlaces: pointer.Int(2)},
+				},
+			},
+		},
+		"InvalidNumberFormatDecimalPlaces": {
+			reason: "NumberFormat transform with too many decimal places should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:         TransformTypeNumberFormat,
+					NumberFormat: &NumberFormatTransform{DecimalPlaces: pointer.Int(16)},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "numberFormat.decimalPlaces",
+				},
+			},
+		},
+		"ValidJSONParse": {
+			reason: "JSONParse transform requires no configuration",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeJSONParse,
+				},
+			},
+		},
+		"ValidMismatchedConfig": {
+			reason: "Configuration for another transform type should be ignored when not strict",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{
+						Multiply: pointer.Int64(2),
+					},
+					String: &StringTransform{
+						Format: pointer.String("%d"),
+					},
+				},
+			},
+		},
+		"InvalidStrictMismatchedConfig": {
+			reason: "Configuration for another transform type should be invalid when strict",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{
+						Multiply: pointer.Int64(2),
+					},
+					String: &StringTransform{
+						Format: pointer.String("%d"),
+					},
+				},
+				strict: true,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "string",
+				},
+			},
+		},
+		"InvalidStrictMissingConfig": {
+			reason: "Configuration for only another transform type should be a mismatch when strict",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					String: &StringTransform{
+						Format: pointer.String("%d"),
+					},
+				},
+				strict: true,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "string",
+				},
+			},
+		},
+		"ValidStrict": {
+			reason: "Configuration matching the transform type should be valid when strict",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{
+						Multiply: pointer.Int64(2),
+					},
+				},
+				strict: true,
+			},
+		},
+		"ValidConvert": {
+			reason: "Convert transform with valid format and toType should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeConvert,
+					Convert: &ConvertTransform{
+						Format: &[]ConvertTransformFormat{ConvertTransformFormatNone}[0],
+						ToType: TransformIOTypeInt,
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.args.transform.Validate(tc.args.strict)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTransformGetOutputType(t *testing.T) {
+	type args struct {
+		transform *Transform
+	}
+	type want struct {
+		output *TransformIOType
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"MapTransform": {
+			reason: "Output of Math transform should be float64",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeFloat64}[0],
+			},
+		},
+		"ConvertTransform": {
+			reason: "Output of Convert transform, no validation, should be the type specified",
+			args: args{
+				transform: &Transform{
+					Type:    TransformTypeConvert,
+					Convert: &ConvertTransform{ToType: "fakeType"},
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{"fakeType"}[0],
+			},
+		},
+		"ConvertTransformInt32": {
+			reason: "Output of Convert transform to int32 should be int64, which is how its values are represented",
+			args: args{
+				transform: &Transform{
+					Type:    TransformTypeConvert,
+					Convert: &ConvertTransform{ToType: TransformIOTypeInt32},
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeInt64}[0],
+			},
+		},
+		"LengthTransform": {
+			reason: "Output of Length transform should be int64",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeLength,
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeInt64}[0],
+			},
+		},
+		"ErrorUnknownType": {
+			reason: "Output of Unknown transform type returns an error",
+			args: args{
+				transform: &Transform{
+					Type: "fakeType",
+				},
+			},
+			want: want{
+				err: fmt.Errorf("unable to get output type, unknown transform type: fakeType"),
+			},
+		},
+		"MapTransformNil": {
+			reason: "Output of Map transform is nil",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMap,
+				},
+			},
+		},
+		"SkipOnErrorNil": {
+			reason: "Output of a transform that may be skipped is nil",
+			args: args{
+				transform: &Transform{
+					Type:    TransformTypeConvert,
+					Convert: &ConvertTransform{ToType: TransformIOTypeInt64},
+					OnError: &[]TransformOnErrorPolicy{TransformOnErrorPolicySkip}[0],
+				},
+			},
+		},
+		"ConditionalNil": {
+			reason: "Output of a transform that may not run is nil",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeLength,
+					When: &TransformCondition{Operator: TransformConditionOperatorRegexp, Regexp: pointer.String(".")},
 				},
 			},
 		},
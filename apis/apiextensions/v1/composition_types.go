@@ -17,7 +17,25 @@ limitations under the License.
 package v1
 
 import (
+	"sort"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errPatchSetType         = "a patch in a PatchSet cannot be of type PatchSet"
+	errFmtUndefinedPatchSet = "cannot find PatchSet by name %s"
+	errPatchSetName         = "patchSetName is required"
+	errFmtPatchSetReference = "patch %d of resource %d: %v"
+	errFmtInvalidPatchType  = "patch type %s is unsupported"
+	errFmtPatchSetCondition = "cannot evaluate condition of reference to PatchSet %s"
+
+	errTransformSetType         = "a transform in a TransformSet cannot be of type transformSet"
+	errFmtUndefinedTransformSet = "cannot find TransformSet by name %s"
+	errFmtTransformSetReference = "transform %d of patch %d of resource %d: %v"
 )
 
 // CompositionSpec specifies desired state of a composition.
@@ -33,6 +51,13 @@ type CompositionSpec struct {
 	// +optional
 	PatchSets []PatchSet `json:"patchSets,omitempty"`
 
+	// TransformSets define a named chain of transforms that may be included
+	// by any patch of any resource in this Composition, using a transform of
+	// type transformSet. TransformSets cannot themselves refer to other
+	// TransformSets.
+	// +optional
+	TransformSets []TransformSet `json:"transformSets,omitempty"`
+
 	// Environment configures the environment in which resources are rendered.
 	// THIS IS AN ALPHA FIELD. Do not use it in production. It is not honored
 	// unless the relevant Crossplane feature flag is enabled, and may be
@@ -48,6 +73,18 @@ type CompositionSpec struct {
 	// +optional
 	Resources []ComposedTemplate `json:"resources,omitempty"`
 
+	// PatchErrorPolicy determines what happens when a composed resource can't
+	// be rendered because one of its patches returned an error. The default,
+	// continue, skips that resource and composes the others. With abort, no
+	// composed resource is applied if any resource can't be rendered from the
+	// composite resource or environment, or requires a composed resource that
+	// can't be rendered. A resource that can't be rendered from the observed
+	// state of the composed resources it reads from stops composition, but
+	// only after those resources have been applied.
+	// +optional
+	// +kubebuilder:validation:Enum=continue;abort
+	PatchErrorPolicy *PatchErrorPolicy `json:"patchErrorPolicy,omitempty"`
+
 	// Functions is list of Composition Functions that will be used when a
 	// composite resource referring to this composition is created. At least one
 	// of resources and functions must be specified. If both are specified the
@@ -83,6 +120,304 @@ type CompositionSpec struct {
 	PublishConnectionDetailsWithStoreConfigRef *StoreConfigReference `json:"publishConnectionDetailsWithStoreConfigRef,omitempty"`
 }
 
+// Default the CompositionSpec. Defaults are applied to all patches, including
+// those in patch sets and the environment, so that the Composition reflects
+// the effective field paths each patch reads from and writes to. Patches are
+// applied identically whether or not they have been defaulted.
+func (cs *CompositionSpec) Default() {
+	for i := range cs.PatchSets {
+		for j := range cs.PatchSets[i].Patches {
+			cs.PatchSets[i].Patches[j].Default()
+		}
+	}
+	for i := range cs.Resources {
+		for j := range cs.Resources[i].Patches {
+			cs.Resources[i].Patches[j].Default()
+		}
+	}
+	if cs.Environment != nil {
+		for i := range cs.Environment.Patches {
+			cs.Environment.Patches[i].Default()
+		}
+	}
+}
+
+// InlinedResources returns the effective resources of the CompositionSpec, per
+// InlineResources. The CompositionSpec is not modified. References to patch
+// sets are inlined regardless of their conditions, which can only be evaluated
+// against a composite resource.
+func (cs *CompositionSpec) InlinedResources() ([]ComposedTemplate, error) {
+	cp := cs.DeepCopy()
+	return InlineResources(cp.PatchSets, cp.TransformSets, cp.Resources, nil)
+}
+
+// A PatchConditionEvaluator returns true if the supplied condition of a
+// reference to a patch set is met.
+type PatchConditionEvaluator func(c PatchCondition) (bool, error)
+
+// InlineResources returns the supplied resource templates with each reference
+// to a patch set replaced by the patches of that set, and each reference to a
+// transform set replaced by the transforms of that set. The patches of each
+// resource are returned in the order in which they are applied, per
+// SortPatches. A reference to a patch set that has a condition is inlined only
+// if the supplied evaluator returns true, or regardless of its condition if the
+// evaluator is nil. Patches inlined from a patch set are copies, but the
+// returned templates otherwise share memory with the supplied templates.
+//
+// ErrPatchSetType is returned if any patch set references another patch set,
+// and an error returned by the evaluator is returned wrapped with the name of
+// the referenced patch set. Otherwise the
+// returned error aggregates a PatchSetReferenceError or
+// TransformSetReferenceError for each invalid reference, so that all of them
+// may be fixed at once.
+func InlineResources(pss []PatchSet, tss []TransformSet, cts []ComposedTemplate, met PatchConditionEvaluator) ([]ComposedTemplate, error) {
+	pn, err := patchSetsByName(pss)
+	if err != nil {
+		return nil, err
+	}
+	tn := transformSetsByName(tss)
+
+	ct := make([]ComposedTemplate, len(cts))
+	var errs []error
+	for i, r := range cts {
+		ps, rerrs, err := inlinePatches(pn, tn, r.Patches, i, met)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, rerrs...)
+		ct[i] = r
+		ct[i].Patches = ps
+	}
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+	return ct, nil
+}
+
+// inlinePatches returns the supplied patches of the resource template at the
+// supplied index, with references to patch sets and transform sets inlined and
+// sorted per SortPatches. References to transform sets are inlined after
+// references to patch sets, so that errors refer to the index of the patch
+// once patch sets are inlined. It returns a PatchSetReferenceError or
+// TransformSetReferenceError for each invalid reference, and any error
+// returned by the supplied evaluator, wrapped with the name of the referenced
+// patch set.
+func inlinePatches(pn map[string][]Patch, tn map[string][]Transform, ps []Patch, resource int, met PatchConditionEvaluator) ([]Patch, []error, error) {
+	po, errs, err := inlinePatchSets(pn, ps, resource, met)
+	if err != nil {
+		return nil, nil, err
+	}
+	for j, p := range po {
+		var terrs []error
+		po[j], terrs = inlineTransformSets(tn, p, resource, j)
+		errs = append(errs, terrs...)
+	}
+	return SortPatches(po), errs, nil
+}
+
+// patchSetsByName returns the patches of the supplied patch sets, keyed by the
+// name of their set. It returns ErrPatchSetType if a patch set references
+// another patch set.
+func patchSetsByName(pss []PatchSet) (map[string][]Patch, error) {
+	pn := make(map[string][]Patch, len(pss))
+	for _, s := range pss {
+		for _, p := range s.Patches {
+			if p.Type == PatchTypePatchSet {
+				return nil, ErrPatchSetType
+			}
+		}
+		pn[s.Name] = s.Patches
+	}
+	return pn, nil
+}
+
+// inlinePatchSets returns the supplied patches of the resource template at the
+// supplied index, with each reference to a patch set replaced by a copy of the
+// patches of that set. References whose condition the supplied evaluator
+// reports as unmet are dropped. It returns a PatchSetReferenceError for each
+// invalid reference, which is omitted from the returned patches, and any error
+// returned by the evaluator. Patches of a set that don't specify a priority
+// inherit the priority of the reference.
+func inlinePatchSets(pn map[string][]Patch, ps []Patch, resource int, met PatchConditionEvaluator) ([]Patch, []error, error) {
+	po := make([]Patch, 0, len(ps))
+	var errs []error
+	for j, p := range ps {
+		if p.Type != PatchTypePatchSet {
+			po = append(po, p)
+			continue
+		}
+		if p.PatchSetName == nil {
+			errs = append(errs, &PatchSetReferenceError{ResourceIndex: resource, PatchIndex: j, Err: errors.New(errPatchSetName)})
+			continue
+		}
+		sp, ok := pn[*p.PatchSetName]
+		if !ok {
+			errs = append(errs, &PatchSetReferenceError{ResourceIndex: resource, PatchIndex: j, Err: UndefinedPatchSetError(*p.PatchSetName)})
+			continue
+		}
+		if p.When != nil && met != nil {
+			ok, err := met(*p.When)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, errFmtPatchSetCondition, *p.PatchSetName)
+			}
+			if !ok {
+				continue
+			}
+		}
+		for _, p := range InheritPriority(sp, p.Priority) {
+			po = append(po, *p.DeepCopy())
+		}
+	}
+	return po, errs, nil
+}
+
+// InheritPriority returns the supplied patches of a patch set with the supplied
+// priority, of the patch that references the set, set on each patch that
+// doesn't specify its own priority. The supplied patches are not modified.
+func InheritPriority(ps []Patch, priority *int) []Patch {
+	if priority == nil {
+		return ps
+	}
+	out := make([]Patch, len(ps))
+	for i, p := range ps {
+		if p.Priority == nil {
+			p.Priority = priority
+		}
+		out[i] = p
+	}
+	return out
+}
+
+// SortPatches returns the supplied patches in the order in which they should
+// be applied, which is ascending order of priority. Patches with the same
+// priority keep the order in which they are supplied, so patches are returned
+// unchanged if none of them specify a priority. Patch sets must be inlined
+// before patches are sorted.
+func SortPatches(ps []Patch) []Patch {
+	prioritized := false
+	for _, p := range ps {
+		if p.Priority != nil {
+			prioritized = true
+			break
+		}
+	}
+	if !prioritized {
+		return ps
+	}
+
+	out := make([]Patch, len(ps))
+	copy(out, ps)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].GetPriority() < out[j].GetPriority()
+	})
+	return out
+}
+
+// transformSetsByName returns the transforms of the supplied transform sets,
+// keyed by the name of their set.
+func transformSetsByName(tss []TransformSet) map[string][]Transform {
+	tn := make(map[string][]Transform, len(tss))
+	for _, s := range tss {
+		tn[s.Name] = s.Transforms
+	}
+	return tn
+}
+
+// inlineTransformSets returns the supplied patch, at the supplied index of the
+// resource template at the supplied index, with each transform that references
+// a transform set replaced by the transforms of that set. Only the transforms
+// of the patch itself are inlined; the transforms of combine variables cannot
+// reference a transform set. It returns a TransformSetReferenceError for each
+// invalid reference.
+func inlineTransformSets(tn map[string][]Transform, p Patch, resource, patch int) (Patch, []error) {
+	if !referencesTransformSet(p.Transforms) {
+		return p, nil
+	}
+	var errs []error
+	to := make([]Transform, 0, len(p.Transforms))
+	for k, t := range p.Transforms {
+		if t.Type != TransformTypeTransformSet {
+			to = append(to, t)
+			continue
+		}
+		name := ""
+		if t.TransformSetName != nil {
+			name = *t.TransformSetName
+		}
+		ts, ok := tn[name]
+		if !ok {
+			errs = append(errs, &TransformSetReferenceError{ResourceIndex: resource, PatchIndex: patch, TransformIndex: k, Err: UndefinedTransformSetError(name)})
+			continue
+		}
+		if referencesTransformSet(ts) {
+			errs = append(errs, &TransformSetReferenceError{ResourceIndex: resource, PatchIndex: patch, TransformIndex: k, Err: ErrTransformSetType})
+			continue
+		}
+		for _, t := range ts {
+			to = append(to, *t.DeepCopy())
+		}
+	}
+	p.Transforms = to
+	return p, errs
+}
+
+// referencesTransformSet returns true if any of the supplied transforms is a
+// reference to a transform set.
+func referencesTransformSet(ts []Transform) bool {
+	for _, t := range ts {
+		if t.Type == TransformTypeTransformSet {
+			return true
+		}
+	}
+	return false
+}
+
+// InlinePatchSets replaces each reference to a patch set in the resources of
+// the CompositionSpec with the patches of that set. The CompositionSpec is not
+// modified if an error is returned.
+func (cs *CompositionSpec) InlinePatchSets() error {
+	ct, err := cs.InlinedResources()
+	if err != nil {
+		return err
+	}
+	cs.Resources = ct
+	return nil
+}
+
+// WalkPatches calls the supplied function for each patch of each resource of
+// the CompositionSpec, with the index of the resource the patch belongs to.
+// Like InlinedResources it visits the patches of each referenced patch set in
+// place of the reference, with references to transform sets inlined, in the
+// order in which they are applied, but it inlines these references one
+// resource at a time rather than returning a copy of all resources. Patches may
+// share memory with the CompositionSpec and must not be modified. The walk
+// returns ErrPatchSetType before visiting any patch if a patch set references
+// another patch set. It otherwise stops at the first invalid reference, which
+// is returned as a PatchSetReferenceError or TransformSetReferenceError before
+// any patch of its resource is visited, or at the first error returned by the
+// supplied function, which is returned unchanged.
+func (cs *CompositionSpec) WalkPatches(fn func(resource int, p Patch) error) error {
+	pn, err := patchSetsByName(cs.PatchSets)
+	if err != nil {
+		return err
+	}
+	tn := transformSetsByName(cs.TransformSets)
+
+	for i, r := range cs.Resources {
+		// Conditions are never evaluated, so no error is returned.
+		ps, errs, _ := inlinePatches(pn, tn, r.Patches, i, nil)
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		for _, p := range ps {
+			if err := fn(i, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +genclient
@@ -0,0 +1,559 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCompositionSpecDefault(t *testing.T) {
+	type args struct {
+		spec *CompositionSpec
+	}
+	type want struct {
+		spec *CompositionSpec
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Empty": {
+			reason: "Defaulting an empty spec should be a no-op",
+			args: args{
+				spec: &CompositionSpec{},
+			},
+			want: want{
+				spec: &CompositionSpec{},
+			},
+		},
+		"DefaultToFieldPath": {
+			reason: "ToFieldPath should default to FromFieldPath for single field path patches everywhere in the spec",
+			args: args{
+				spec: &CompositionSpec{
+					PatchSets: []PatchSet{{
+						Name: "ps",
+						Patches: []Patch{
+							{FromFieldPath: pointer.String("spec.a")},
+						},
+					}},
+					Resources: []ComposedTemplate{{
+						Patches: []Patch{
+							{Type: PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String("status.b")},
+							{Type: PatchTypeFromEnvironmentFieldPath, FromFieldPath: pointer.String("c"), ToFieldPath: pointer.String("spec.c")},
+							{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+							{Type: PatchTypeCombineFromComposite, Combine: &Combine{}},
+						},
+					}},
+					Environment: &EnvironmentConfiguration{
+						Patches: []EnvironmentPatch{
+							{FromFieldPath: pointer.String("spec.d")},
+						},
+					},
+				},
+			},
+			want: want{
+				spec: &CompositionSpec{
+					PatchSets: []PatchSet{{
+						Name: "ps",
+						Patches: []Patch{
+							{FromFieldPath: pointer.String("spec.a"), ToFieldPath: pointer.String("spec.a")},
+						},
+					}},
+					Resources: []ComposedTemplate{{
+						Patches: []Patch{
+							{Type: PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String("status.b"), ToFieldPath: pointer.String("status.b")},
+							{Type: PatchTypeFromEnvironmentFieldPath, FromFieldPath: pointer.String("c"), ToFieldPath: pointer.String("spec.c")},
+							{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+							{Type: PatchTypeCombineFromComposite, Combine: &Combine{}},
+						},
+					}},
+					Environment: &EnvironmentConfiguration{
+						Patches: []EnvironmentPatch{
+							{FromFieldPath: pointer.String("spec.d"), ToFieldPath: pointer.String("spec.d")},
+						},
+					},
+				},
+			},
+		},
+		"ToFieldPaths": {
+			reason: "ToFieldPath should not be defaulted for patches that write to ToFieldPaths",
+			args: args{
+				spec: &CompositionSpec{
+					Environment: &EnvironmentConfiguration{
+						Patches: []EnvironmentPatch{
+							{FromFieldPath: pointer.String("spec.d"), ToFieldPaths: []string{"d", "e"}},
+						},
+					},
+				},
+			},
+			want: want{
+				spec: &CompositionSpec{
+					Environment: &EnvironmentConfiguration{
+						Patches: []EnvironmentPatch{
+							{FromFieldPath: pointer.String("spec.d"), ToFieldPaths: []string{"d", "e"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.args.spec.Default()
+			if diff := cmp.Diff(tc.want.spec, tc.args.spec); diff != "" {
+				t.Errorf("%s\nDefault(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionSpecInlinedResources(t *testing.T) {
+	type want struct {
+		ct  []ComposedTemplate
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   *CompositionSpec
+		want   want
+	}{
+		"NoPatchSets": {
+			reason: "Resources without references to patch sets should be returned unchanged",
+			spec: &CompositionSpec{
+				Resources: []ComposedTemplate{{
+					Name:    pointer.String("a"),
+					Patches: []Patch{{FromFieldPath: pointer.String("spec.a")}},
+				}},
+			},
+			want: want{
+				ct: []ComposedTemplate{{
+					Name:    pointer.String("a"),
+					Patches: []Patch{{FromFieldPath: pointer.String("spec.a")}},
+				}},
+			},
+		},
+		"InlinePatchSets": {
+			reason: "References to patch sets should be replaced by the patches of those sets, in order, including their descriptions",
+			spec: &CompositionSpec{
+				PatchSets: []PatchSet{{
+					Name: "ps",
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.b"), Description: pointer.String("Patch b")},
+						{FromFieldPath: pointer.String("spec.c")},
+					},
+				}},
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a")},
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+						{FromFieldPath: pointer.String("spec.d")},
+					},
+				}},
+			},
+			want: want{
+				ct: []ComposedTemplate{{
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a")},
+						{FromFieldPath: pointer.String("spec.b"), Description: pointer.String("Patch b")},
+						{FromFieldPath: pointer.String("spec.c")},
+						{FromFieldPath: pointer.String("spec.d")},
+					},
+				}},
+			},
+		},
+		"PrioritizedPatches": {
+			reason: "Patches should be sorted by priority, with the patches of a patch set inheriting the priority of the reference unless they specify their own",
+			spec: &CompositionSpec{
+				PatchSets: []PatchSet{{
+					Name: "ps",
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a")},
+						{FromFieldPath: pointer.String("spec.b"), Priority: pointer.Int(-1)},
+					},
+				}},
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.c"), Priority: pointer.Int(2)},
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps"), Priority: pointer.Int(1)},
+						{FromFieldPath: pointer.String("spec.d")},
+					},
+				}},
+			},
+			want: want{
+				ct: []ComposedTemplate{{
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.b"), Priority: pointer.Int(-1)},
+						{FromFieldPath: pointer.String("spec.d")},
+						{FromFieldPath: pointer.String("spec.a"), Priority: pointer.Int(1)},
+						{FromFieldPath: pointer.String("spec.c"), Priority: pointer.Int(2)},
+					},
+				}},
+			},
+		},
+		"UndefinedPatchSet": {
+			reason: "A reference to a patch set that doesn't exist should return an error",
+			spec: &CompositionSpec{
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("nope")}},
+				}},
+			},
+			want: want{
+				err: utilerrors.NewAggregate([]error{&PatchSetReferenceError{ResourceIndex: 0, PatchIndex: 0, Err: UndefinedPatchSetError("nope")}}),
+			},
+		},
+		"MissingPatchSetName": {
+			reason: "A reference to a patch set without a name should return an error",
+			spec: &CompositionSpec{
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{{Type: PatchTypePatchSet}},
+				}},
+			},
+			want: want{
+				err: utilerrors.NewAggregate([]error{&PatchSetReferenceError{ResourceIndex: 0, PatchIndex: 0, Err: errors.New(errPatchSetName)}}),
+			},
+		},
+		"InlineTransformSets": {
+			reason: "References to transform sets should be replaced by the transforms of those sets, in order, including in patches of patch sets",
+			spec: &CompositionSpec{
+				PatchSets: []PatchSet{{
+					Name: "ps",
+					Patches: []Patch{{
+						FromFieldPath: pointer.String("spec.b"),
+						Transforms:    []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+					}},
+				}},
+				TransformSets: []TransformSet{{
+					Name:       "ts",
+					Transforms: []Transform{{Type: TransformTypeLength}, {Type: TransformTypeMath, Math: &MathTransform{Multiply: pointer.Int64(2)}}},
+				}},
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{
+						{
+							FromFieldPath: pointer.String("spec.a"),
+							Transforms: []Transform{
+								{Type: TransformTypeJSONParse},
+								{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")},
+							},
+						},
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+					},
+				}},
+			},
+			want: want{
+				ct: []ComposedTemplate{{
+					Patches: []Patch{
+						{
+							FromFieldPath: pointer.String("spec.a"),
+							Transforms: []Transform{
+								{Type: TransformTypeJSONParse},
+								{Type: TransformTypeLength},
+								{Type: TransformTypeMath, Math: &MathTransform{Multiply: pointer.Int64(2)}},
+							},
+						},
+						{
+							FromFieldPath: pointer.String("spec.b"),
+							Transforms: []Transform{
+								{Type: TransformTypeLength},
+								{Type: TransformTypeMath, Math: &MathTransform{Multiply: pointer.Int64(2)}},
+							},
+						},
+					},
+				}},
+			},
+		},
+		"UndefinedTransformSet": {
+			reason: "A reference to a transform set that doesn't exist should return an error",
+			spec: &CompositionSpec{
+				Resources: []ComposedTemplate{{}, {
+					Patches: []Patch{{
+						FromFieldPath: pointer.String("spec.a"),
+						Transforms:    []Transform{{Type: TransformTypeLength}, {Type: TransformTypeTransformSet, TransformSetName: pointer.String("nope")}},
+					}},
+				}},
+			},
+			want: want{
+				err: utilerrors.NewAggregate([]error{
+					utilerrors.NewAggregate([]error{&TransformSetReferenceError{ResourceIndex: 1, PatchIndex: 0, TransformIndex: 1, Err: UndefinedTransformSetError("nope")}}),
+				}),
+			},
+		},
+		"NestedTransformSet": {
+			reason: "A reference to a transform set that references another transform set should return an error",
+			spec: &CompositionSpec{
+				TransformSets: []TransformSet{{
+					Name:       "ts",
+					Transforms: []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+				}},
+				Resources: []ComposedTemplate{{
+					Patches: []Patch{{
+						FromFieldPath: pointer.String("spec.a"),
+						Transforms:    []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+					}},
+				}},
+			},
+			want: want{
+				err: utilerrors.NewAggregate([]error{
+					utilerrors.NewAggregate([]error{&TransformSetReferenceError{ResourceIndex: 0, PatchIndex: 0, TransformIndex: 0, Err: ErrTransformSetType}}),
+				}),
+			},
+		},
+		"NestedPatchSet": {
+			reason: "A patch set that references another patch set should return an error",
+			spec: &CompositionSpec{
+				PatchSets: []PatchSet{{
+					Name:    "ps",
+					Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")}},
+				}},
+			},
+			want: want{
+				err: ErrPatchSetType,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			original := tc.spec.DeepCopy()
+			ct, err := tc.spec.InlinedResources()
+			if diff := cmp.Diff(tc.want.ct, ct); diff != "" {
+				t.Errorf("\n%s\nInlinedResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInlinedResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(original, tc.spec); diff != "" {
+				t.Errorf("\n%s\nInlinedResources(...): spec should not be modified: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionSpecInlinePatchSets(t *testing.T) {
+	cs := &CompositionSpec{
+		PatchSets: []PatchSet{{
+			Name:    "ps",
+			Patches: []Patch{{FromFieldPath: pointer.String("spec.a")}},
+		}},
+		Resources: []ComposedTemplate{{
+			Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")}},
+		}},
+	}
+	if err := cs.InlinePatchSets(); err != nil {
+		t.Fatalf("InlinePatchSets(): %v", err)
+	}
+	want := []ComposedTemplate{{
+		Patches: []Patch{{FromFieldPath: pointer.String("spec.a")}},
+	}}
+	if diff := cmp.Diff(want, cs.Resources); diff != "" {
+		t.Errorf("InlinePatchSets(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestCompositionSpecWalkPatches(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type visit struct {
+		Resource int
+		Patch    Patch
+	}
+	type want struct {
+		visits []visit
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   *CompositionSpec
+		fn     func(resource int, p Patch) error
+		want   want
+	}{
+		"InlinedPatches": {
+			reason: "The patches of each resource should be visited in order, with references to patch and transform sets inlined.",
+			spec: &CompositionSpec{
+				PatchSets: []PatchSet{{
+					Name: "ps",
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.b")},
+						{FromFieldPath: pointer.String("spec.c"), Transforms: []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}}},
+					},
+				}},
+				TransformSets: []TransformSet{{Name: "ts", Transforms: []Transform{{Type: TransformTypeLength}}}},
+				Resources: []ComposedTemplate{
+					{Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a")},
+						{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+					}},
+					{},
+					{Patches: []Patch{{FromFieldPath: pointer.String("spec.d")}}},
+				},
+			},
+			want: want{
+				visits: []visit{
+					{Resource: 0, Patch: Patch{FromFieldPath: pointer.String("spec.a")}},
+					{Resource: 0, Patch: Patch{FromFieldPath: pointer.String("spec.b")}},
+					{Resource: 0, Patch: Patch{FromFieldPath: pointer.String("spec.c"), Transforms: []Transform{{Type: TransformTypeLength}}}},
+					{Resource: 2, Patch: Patch{FromFieldPath: pointer.String("spec.d")}},
+				},
+			},
+		},
+		"PrioritizedPatches": {
+			reason: "The patches of each resource should be visited in ascending order of priority, with the patches of a patch set inheriting the priority of the reference unless they specify their own.",
+			spec: &CompositionSpec{
+				PatchSets: []PatchSet{{
+					Name: "ps",
+					Patches: []Patch{
+						{FromFieldPath: pointer.String("spec.a")},
+						{FromFieldPath: pointer.String("spec.b"), Priority: pointer.Int(-1)},
+					},
+				}},
+				Resources: []ComposedTemplate{{Patches: []Patch{
+					{FromFieldPath: pointer.String("spec.c"), Priority: pointer.Int(2)},
+					{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps"), Priority: pointer.Int(1)},
+					{FromFieldPath: pointer.String("spec.d")},
+				}}},
+			},
+			want: want{
+				visits: []visit{
+					{Resource: 0, Patch: Patch{FromFieldPath: pointer.String("spec.b"), Priority: pointer.Int(-1)}},
+					{Resource: 0, Patch: Patch{FromFieldPath: pointer.String("spec.d")}},
+					{Resource: 0, Patch: Patch{FromFieldPath: pointer.String("spec.a"), Priority: pointer.Int(1)}},
+					{Resource: 0, Patch: Patch{FromFieldPath: pointer.String("spec.c"), Priority: pointer.Int(2)}},
+				},
+			},
+		},
+		"VisitorError": {
+			reason: "An error returned by the visitor should stop the walk and be returned unchanged.",
+			spec: &CompositionSpec{
+				Resources: []ComposedTemplate{{Patches: []Patch{
+					{FromFieldPath: pointer.String("spec.a")},
+					{FromFieldPath: pointer.String("spec.b")},
+				}}},
+			},
+			fn: func(_ int, _ Patch) error { return errBoom },
+			want: want{
+				visits: []visit{{Resource: 0, Patch: Patch{FromFieldPath: pointer.String("spec.a")}}},
+				err:    errBoom,
+			},
+		},
+		"UndefinedPatchSet": {
+			reason: "A reference to a patch set that doesn't exist should stop the walk and return an error before any patch of its resource is visited.",
+			spec: &CompositionSpec{
+				Resources: []ComposedTemplate{{Patches: []Patch{
+					{FromFieldPath: pointer.String("spec.a")},
+					{Type: PatchTypePatchSet, PatchSetName: pointer.String("nope")},
+					{FromFieldPath: pointer.String("spec.b")},
+				}}},
+			},
+			want: want{
+				err: &PatchSetReferenceError{ResourceIndex: 0, PatchIndex: 1, Err: UndefinedPatchSetError("nope")},
+			},
+		},
+		"PatchSetType": {
+			reason: "A patch set that references a patch set should return an error before any patch is visited, whether or not it is referenced.",
+			spec: &CompositionSpec{
+				PatchSets: []PatchSet{{Name: "ps", Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")}}}},
+				Resources: []ComposedTemplate{{Patches: []Patch{{FromFieldPath: pointer.String("spec.a")}}}},
+			},
+			want: want{
+				err: ErrPatchSetType,
+			},
+		},
+		"UndefinedTransformSet": {
+			reason: "A reference to a transform set that doesn't exist should return an error with the index of the patch once patch sets are inlined, before any patch of its resource is visited.",
+			spec: &CompositionSpec{
+				PatchSets: []PatchSet{{Name: "ps", Patches: []Patch{
+					{FromFieldPath: pointer.String("spec.a")},
+					{FromFieldPath: pointer.String("spec.b")},
+				}}},
+				Resources: []ComposedTemplate{{Patches: []Patch{
+					{Type: PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+					{FromFieldPath: pointer.String("spec.c"), Transforms: []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("nope")}}},
+				}}},
+			},
+			want: want{
+				err: &TransformSetReferenceError{ResourceIndex: 0, PatchIndex: 2, TransformIndex: 0, Err: UndefinedTransformSetError("nope")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var visits []visit
+			err := tc.spec.WalkPatches(func(resource int, p Patch) error {
+				visits = append(visits, visit{Resource: resource, Patch: p})
+				if tc.fn != nil {
+					return tc.fn(resource, p)
+				}
+				return nil
+			})
+			if diff := cmp.Diff(tc.want.visits, visits); diff != "" {
+				t.Errorf("\n%s\nWalkPatches(...): -want visits, +got visits:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWalkPatches(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSortPatches(t *testing.T) {
+	patch := func(from string, priority *int) Patch {
+		return Patch{FromFieldPath: pointer.String(from), Priority: priority}
+	}
+
+	cases := map[string]struct {
+		reason string
+		ps     []Patch
+		want   []Patch
+	}{
+		"NoPriorities": {
+			reason: "Patches that don't specify a priority should keep their order.",
+			ps:     []Patch{patch("a", nil), patch("b", nil), patch("c", nil)},
+			want:   []Patch{patch("a", nil), patch("b", nil), patch("c", nil)},
+		},
+		"Priorities": {
+			reason: "Patches should be sorted in ascending order of priority, with patches that don't specify a priority having priority 0.",
+			ps:     []Patch{patch("a", pointer.Int(10)), patch("b", nil), patch("c", pointer.Int(-5))},
+			want:   []Patch{patch("c", pointer.Int(-5)), patch("b", nil), patch("a", pointer.Int(10))},
+		},
+		"Ties": {
+			reason: "Patches with the same priority should keep their order.",
+			ps:     []Patch{patch("a", pointer.Int(1)), patch("b", nil), patch("c", pointer.Int(1)), patch("d", pointer.Int(0))},
+			want:   []Patch{patch("b", nil), patch("d", pointer.Int(0)), patch("a", pointer.Int(1)), patch("c", pointer.Int(1))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SortPatches(tc.ps)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSortPatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
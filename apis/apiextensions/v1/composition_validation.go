@@ -29,8 +29,12 @@ func (c *Composition) Validate() (warns []string, errs field.ErrorList) {
 	type validationFunc func() field.ErrorList
 	validations := []validationFunc{
 		c.validatePatchSets,
+		c.validateTransformSets,
 		c.validateResources,
+		c.validateApplyOrder,
+		c.validatePatchPhases,
 		c.validateFunctions,
+		c.validateTransforms,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
@@ -59,21 +63,75 @@ func (c *Composition) validatePatchSets() (errs field.ErrorList) {
 				errs = append(errs, field.Invalid(field.NewPath("spec", "patchSets").Index(i).Child("patches").Index(j).Child("type"), p.Type, errors.New("cannot use patches within patches").Error()))
 				continue
 			}
-			if err := p.Validate(); err != nil {
+			// Transforms are validated by validateTransforms.
+			if err := p.validateFields(); err != nil {
 				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "patchSets").Index(i).Child("patches").Index(j)))
+				continue
+			}
+			if err := c.Spec.validateFromComposedResource(p, -1); err != nil {
+				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "patchSets").Index(i).Child("patches").Index(j)))
+			}
+		}
+	}
+	return errs
+}
+
+func (c *Composition) validateTransformSets() (errs field.ErrorList) {
+	defined := make(map[string]bool, len(c.Spec.TransformSets))
+	for i, s := range c.Spec.TransformSets {
+		defined[s.Name] = true
+		for k, t := range s.Transforms {
+			if t.Type == TransformTypeTransformSet {
+				errs = append(errs, field.Invalid(field.NewPath("spec", "transformSets").Index(i).Child("transforms").Index(k).Child("type"), t.Type, errTransformSetType))
 			}
 		}
+		// Transforms are validated by validateTransforms.
 	}
+	c.Spec.walkTransformSetReferences(func(_ Patch, name string, path *field.Path, _, _ int) {
+		if !defined[name] {
+			errs = append(errs, field.Invalid(path.Child("transformSetName"), name, "no TransformSet exists with this name"))
+		}
+	})
 	return errs
 }
 
+// walkTransformSetReferences calls the supplied function for each transform of
+// type transformSet of each patch of the CompositionSpec's PatchSets and
+// resources, with the patch, the name of the referenced TransformSet, the path
+// of the transform, and the indices of the resource and patch the transform
+// belongs to. The resource index of a patch of a PatchSet is -1. Transforms
+// that don't name a TransformSet are invalid, and are skipped.
+func (cs *CompositionSpec) walkTransformSetReferences(fn func(p Patch, name string, path *field.Path, resource, patch int)) {
+	walk := func(ps []Patch, path *field.Path, resource int) {
+		for j, p := range ps {
+			for k, t := range p.Transforms {
+				if t.Type != TransformTypeTransformSet || t.TransformSetName == nil || *t.TransformSetName == "" {
+					continue
+				}
+				fn(p, *t.TransformSetName, path.Index(j).Child("transforms").Index(k), resource, j)
+			}
+		}
+	}
+	for i, s := range cs.PatchSets {
+		walk(s.Patches, field.NewPath("spec", "patchSets").Index(i).Child("patches"), -1)
+	}
+	for i, r := range cs.Resources {
+		walk(r.Patches, field.NewPath("spec", "resources").Index(i).Child("patches"), i)
+	}
+}
+
 func (c *Composition) validateResources() (errs field.ErrorList) {
 	if err := c.validateResourceNames(); err != nil {
 		errs = append(errs, err...)
 	}
 	for i, res := range c.Spec.Resources {
 		for j, patch := range res.Patches {
-			if err := patch.Validate(); err != nil {
+			// Transforms are validated by validateTransforms.
+			if err := patch.validateFields(); err != nil {
+				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("patches").Index(j)))
+				continue
+			}
+			if err := c.Spec.validateFromComposedResource(patch, i); err != nil {
 				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("patches").Index(j)))
 			}
 		}
@@ -87,6 +145,120 @@ func (c *Composition) validateResources() (errs field.ErrorList) {
 	return errs
 }
 
+// validateApplyOrder checks that the resource templates can be ordered such
+// that each is applied after the templates its patches read from, per
+// ApplyOrder. References to patch sets are inlined regardless of their
+// conditions. Invalid references are reported by other validations.
+func (c *Composition) validateApplyOrder() field.ErrorList {
+	ct, err := c.Spec.InlinedResources()
+	if err != nil {
+		return nil
+	}
+	if _, err := ApplyOrder(ct); err != nil {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "resources"), err.Error())}
+	}
+	return nil
+}
+
+// validatePatchPhases checks that no patch of a resource template is
+// overridden by a patch that Priority would apply before it, because the
+// overriding patch belongs to a later phase.
+func (c *Composition) validatePatchPhases() (errs field.ErrorList) {
+	c.Spec.walkPatchPhaseConflicts(func(resource int, p, o inlinedPatch, path string) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "resources").Index(resource).Child("patches").Index(p.index), patchPhaseConflict(p, o, path)))
+	})
+	return errs
+}
+
+// validateTransforms validates every transform of every patch of the
+// Composition, including its environment patches and the variables of combine
+// patches. Unlike validating each
+// patch, it reports all invalid transforms rather than only the first.
+func (c *Composition) validateTransforms() (errs field.ErrorList) {
+	for i, s := range c.Spec.TransformSets {
+		errs = append(errs, validateTransforms(s.Transforms, field.NewPath("spec", "transformSets").Index(i))...)
+	}
+	for i, s := range c.Spec.PatchSets {
+		for j, p := range s.Patches {
+			errs = append(errs, validatePatchTransforms(p.Transforms, p.Combine, field.NewPath("spec", "patchSets").Index(i).Child("patches").Index(j))...)
+		}
+	}
+	for i, res := range c.Spec.Resources {
+		for j, p := range res.Patches {
+			errs = append(errs, validatePatchTransforms(p.Transforms, p.Combine, field.NewPath("spec", "resources").Index(i).Child("patches").Index(j))...)
+		}
+	}
+	if c.Spec.Environment != nil {
+		for j, p := range c.Spec.Environment.Patches {
+			errs = append(errs, validatePatchTransforms(p.Transforms, p.Combine, field.NewPath("spec", "environment", "patches").Index(j))...)
+		}
+	}
+	return errs
+}
+
+// validatePatchTransforms validates the supplied transforms of the patch at the
+// supplied path, and the transforms of each variable of its supplied combine.
+func validatePatchTransforms(ts []Transform, c *Combine, path *field.Path) field.ErrorList {
+	errs := validateTransforms(ts, path)
+	if c == nil {
+		return errs
+	}
+	for k, v := range c.Variables {
+		errs = append(errs, validateTransforms(v.Transforms, path.Child("combine", "variables").Index(k))...)
+	}
+	return errs
+}
+
+// validateTransforms validates the supplied transforms of the patch, or of the
+// combine variable, at the supplied path.
+func validateTransforms(ts []Transform, path *field.Path) (errs field.ErrorList) {
+	for k, t := range ts {
+		if err := t.Validate(false); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, path.Child("transforms").Index(k)))
+		}
+	}
+	return errs
+}
+
+// validateFromComposedResource checks that the composed resource a
+// FromComposedFieldPath or FromComposedConnectionSecretKey patch of the
+// resource template at the supplied index reads from is composed from another
+// of the CompositionSpec's resource templates. Patches that don't belong to a
+// resource template have an index of -1.
+func (cs *CompositionSpec) validateFromComposedResource(p Patch, index int) *field.Error {
+	if !p.readsFromComposedResource() || p.FromComposedResource == nil {
+		return nil
+	}
+	s := p.FromComposedResource
+	path := field.NewPath("fromComposedResource")
+
+	var selected int
+	var value any
+	switch {
+	case s.Index != nil:
+		selected, value, path = *s.Index, *s.Index, path.Child("index")
+		if selected >= len(cs.Resources) {
+			return field.Invalid(path, value, "no resource template exists at this index")
+		}
+	case s.Name != nil:
+		selected, value, path = -1, *s.Name, path.Child("name")
+		for i := range cs.Resources {
+			if cs.Resources[i].GetName() == *s.Name {
+				selected = i
+				break
+			}
+		}
+		if selected < 0 {
+			return field.Invalid(path, value, "no resource template exists with this name")
+		}
+	}
+
+	if selected == index {
+		return field.Invalid(path, value, "a composed resource cannot patch from itself")
+	}
+	return nil
+}
+
 // validateResourceNames checks that:
 //  1. Either all resources have a name or they are all anonymous: because if some but not all templates are named it's
 //     safest to refuse to operate. We don't have enough information to use the named composer, but using the anonymous
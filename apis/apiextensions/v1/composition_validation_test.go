@@ -295,6 +295,262 @@ func TestCompositionValidatePatchSets(t *testing.T) {
 	}
 }
 
+func TestCompositionValidateTransformSets(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		comp   *Composition
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A transform set that doesn't reference other transform sets should be valid",
+			comp: &Composition{Spec: CompositionSpec{TransformSets: []TransformSet{{
+				Name:       "ts",
+				Transforms: []Transform{{Type: TransformTypeLength}},
+			}}}},
+		},
+		"NestedTransformSet": {
+			reason: "A transform set that references another transform set should be invalid",
+			comp: &Composition{Spec: CompositionSpec{TransformSets: []TransformSet{{
+				Name:       "ts",
+				Transforms: []Transform{{Type: TransformTypeLength}, {Type: TransformTypeTransformSet, TransformSetName: pointer.String("other")}},
+			}}}},
+			want: field.ErrorList{{
+				Type:  field.ErrorTypeInvalid,
+				Field: "spec.transformSets[0].transforms[1].type",
+			}},
+		},
+		"DefinedTransformSetReferences": {
+			reason: "Patches of PatchSets and resources that reference a defined transform set should be valid",
+			comp: &Composition{Spec: CompositionSpec{
+				TransformSets: []TransformSet{{Name: "ts", Transforms: []Transform{{Type: TransformTypeLength}}}},
+				PatchSets: []PatchSet{{Name: "ps", Patches: []Patch{{
+					FromFieldPath: pointer.String("spec.a"),
+					Transforms:    []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+				}}}},
+				Resources: []ComposedTemplate{{Patches: []Patch{{
+					FromFieldPath: pointer.String("spec.b"),
+					Transforms:    []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+				}}}},
+			}},
+		},
+		"UndefinedTransformSetReferences": {
+			reason: "Patches of PatchSets and resources that reference an undefined transform set should be invalid",
+			comp: &Composition{Spec: CompositionSpec{
+				PatchSets: []PatchSet{{Name: "ps", Patches: []Patch{{
+					FromFieldPath: pointer.String("spec.a"),
+					Transforms:    []Transform{{Type: TransformTypeTransformSet, TransformSetName: pointer.String("nope")}},
+				}}}},
+				Resources: []ComposedTemplate{{Patches: []Patch{{
+					FromFieldPath: pointer.String("spec.b"),
+					Transforms:    []Transform{{Type: TransformTypeLength}, {Type: TransformTypeTransformSet, TransformSetName: pointer.String("nope")}},
+				}}}},
+			}},
+			want: field.ErrorList{
+				{
+					Type:  field.ErrorTypeInvalid,
+					Field: "spec.patchSets[0].patches[0].transforms[0].transformSetName",
+				},
+				{
+					Type:  field.ErrorTypeInvalid,
+					Field: "spec.resources[0].patches[0].transforms[1].transformSetName",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.comp.validateTransformSets()
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateTransformSets(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidateTransforms(t *testing.T) {
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidTransforms": {
+			reason: "patches with valid transforms should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{{
+							Patches: []Patch{{
+								FromFieldPath: pointer.String("spec.foo"),
+								Transforms: []Transform{{
+									Type: TransformTypeMath,
+									Math: &MathTransform{Multiply: pointer.Int64(2)},
+								}},
+							}},
+						}},
+					},
+				},
+			},
+		},
+		"InvalidTransforms": {
+			reason: "all invalid transforms should be reported, wherever they are",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						PatchSets: []PatchSet{{
+							Name: "foo",
+							Patches: []Patch{{
+								FromFieldPath: pointer.String("spec.foo"),
+								Transforms: []Transform{{
+									Type:    TransformTypeConvert,
+									Convert: &ConvertTransform{ToType: "nope"},
+								}},
+							}},
+						}},
+						Resources: []ComposedTemplate{{
+							Patches: []Patch{
+								{
+									FromFieldPath: pointer.String("spec.foo"),
+								},
+								{
+									FromFieldPath: pointer.String("spec.foo"),
+									Transforms: []Transform{
+										{
+											Type: TransformTypeMath,
+											Math: &MathTransform{},
+										},
+										{
+											Type: TransformTypeMath,
+											Math: &MathTransform{Multiply: pointer.Int64(2)},
+										},
+										{
+											Type: TransformTypeString,
+										},
+									},
+								},
+							},
+						}},
+						Environment: &EnvironmentConfiguration{
+							Patches: []EnvironmentPatch{{
+								FromFieldPath: pointer.String("spec.foo"),
+								Transforms: []Transform{{
+									Type: TransformTypeMap,
+									Map:  &MapTransform{},
+								}},
+							}},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.patchSets[0].patches[0].transforms[0].convert.toType",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].patches[1].transforms[0].math.multiply",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].patches[1].transforms[2].string",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.environment.patches[0].transforms[0].map.pairs",
+					},
+				},
+			},
+		},
+		"InvalidVariableTransforms": {
+			reason: "invalid transforms of combine variables should be reported",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{{
+							Patches: []Patch{{
+								Type: PatchTypeCombineFromComposite,
+								Combine: &Combine{
+									Strategy: CombineStrategyString,
+									Variables: []CombineVariable{
+										{FromFieldPath: "spec.foo"},
+										{
+											FromFieldPath: "spec.bar",
+											Transforms: []Transform{
+												{Type: TransformTypeLength},
+												{Type: TransformTypeMath, Math: &MathTransform{}},
+											},
+										},
+									},
+									String: &StringCombine{Format: "%s-%s"},
+								},
+								ToFieldPath: pointer.String("spec.baz"),
+							}},
+						}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].patches[0].combine.variables[1].transforms[1].math.multiply",
+					},
+				},
+			},
+		},
+		"InvalidTransformSets": {
+			reason: "invalid transforms of transform sets should be reported",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						TransformSets: []TransformSet{{
+							Name: "ts",
+							Transforms: []Transform{
+								{Type: TransformTypeLength},
+								{Type: TransformTypeMath, Math: &MathTransform{}},
+							},
+						}},
+						Resources: []ComposedTemplate{{
+							Patches: []Patch{{
+								FromFieldPath: pointer.String("spec.foo"),
+								Transforms:    []Transform{{Type: TransformTypeTransformSet}},
+							}},
+						}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.transformSets[0].transforms[1].math.multiply",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].patches[0].transforms[0].transformSetName",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.comp.validateTransforms()
+			if diff := cmp.Diff(tc.want.output, got, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateTransforms(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositionValidateFunctions(t *testing.T) {
 	type args struct {
 		comp *Composition
@@ -470,6 +726,117 @@ func TestCompositionValidateResources(t *testing.T) {
 				},
 			},
 		},
+		"ValidFromComposedResourceByName": {
+			reason: "a patch from another named composed resource should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: pointer.String("foo"),
+							},
+							{
+								Name: pointer.String("bar"),
+								Patches: []Patch{
+									{
+										Type:                 PatchTypeFromComposedFieldPath,
+										FromComposedResource: &ComposedResourceSelector{Name: pointer.String("foo")},
+										FromFieldPath:        pointer.String("status.id"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidFromComposedResourceUnknownName": {
+			reason: "a patch from a composed resource name that no template has should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: pointer.String("foo"),
+								Patches: []Patch{
+									{
+										Type:                 PatchTypeFromComposedFieldPath,
+										FromComposedResource: &ComposedResourceSelector{Name: pointer.String("bar")},
+										FromFieldPath:        pointer.String("status.id"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0].fromComposedResource.name",
+					},
+				},
+			},
+		},
+		"InvalidFromComposedResourceIndexOutOfRange": {
+			reason: "a patch from a composed resource index that no template has should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{},
+							{
+								Patches: []Patch{
+									{
+										Type:                 PatchTypeFromComposedFieldPath,
+										FromComposedResource: &ComposedResourceSelector{Index: pointer.Int(2)},
+										FromFieldPath:        pointer.String("status.id"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[1].patches[0].fromComposedResource.index",
+					},
+				},
+			},
+		},
+		"InvalidFromComposedResourceSelf": {
+			reason: "a patch from the composed resource the patch belongs to should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: pointer.String("foo"),
+								Patches: []Patch{
+									{
+										Type:                 PatchTypeFromComposedFieldPath,
+										FromComposedResource: &ComposedResourceSelector{Name: pointer.String("foo")},
+										FromFieldPath:        pointer.String("status.id"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0].fromComposedResource.name",
+					},
+				},
+			},
+		},
 		"InvalidComplexNamedResourcesDueToDuplicateNames": {
 			reason: "complex named resources with duplicate names should be invalid",
 			args: args{
@@ -593,3 +960,165 @@ func TestCompositionValidateResources(t *testing.T) {
 		})
 	}
 }
+
+func TestCompositionValidateApplyOrder(t *testing.T) {
+	// template returns a template with the supplied name that reads from the
+	// template with the other supplied name.
+	template := func(name, from string) ComposedTemplate {
+		return ComposedTemplate{
+			Name: pointer.String(name),
+			Patches: []Patch{{
+				Type:                 PatchTypeFromComposedFieldPath,
+				FromComposedResource: &ComposedResourceSelector{Name: pointer.String(from)},
+				FromFieldPath:        pointer.String("status.id"),
+				ToFieldPath:          pointer.String("spec.id"),
+			}},
+		}
+	}
+
+	type want struct {
+		output field.ErrorList
+	}
+
+	cases := map[string]struct {
+		reason string
+		comp   *Composition
+		want   want
+	}{
+		"Chain": {
+			reason: "Resources that read from each other without a cycle should be valid.",
+			comp: &Composition{
+				Spec: CompositionSpec{
+					Resources: []ComposedTemplate{template("a", "b"), template("b", "c"), {Name: pointer.String("c")}},
+				},
+			},
+		},
+		"Cycle": {
+			reason: "Resources that read from each other in a cycle should be rejected.",
+			comp: &Composition{
+				Spec: CompositionSpec{
+					Resources: []ComposedTemplate{template("a", "b"), template("b", "a")},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.resources",
+					},
+				},
+			},
+		},
+		"CycleThroughPatchSet": {
+			reason: "Resources that read from each other in a cycle through a patch set should be rejected.",
+			comp: &Composition{
+				Spec: CompositionSpec{
+					PatchSets: []PatchSet{{Name: "from-b", Patches: template("", "b").Patches}},
+					Resources: []ComposedTemplate{
+						{Name: pointer.String("a"), Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: pointer.String("from-b")}}},
+						template("b", "a"),
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.resources",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, got := tc.comp.Validate()
+			if diff := cmp.Diff(tc.want.output, got, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidatePatchPhases(t *testing.T) {
+	// fromA returns a patch that reads from the template named a and writes
+	// to spec.id.
+	fromA := func(priority *int) Patch {
+		return Patch{
+			Type:                 PatchTypeFromComposedFieldPath,
+			FromComposedResource: &ComposedResourceSelector{Name: pointer.String("a")},
+			FromFieldPath:        pointer.String("status.id"),
+			ToFieldPath:          pointer.String("spec.id"),
+			Priority:             priority,
+		}
+	}
+
+	type want struct {
+		output field.ErrorList
+	}
+
+	cases := map[string]struct {
+		reason string
+		comp   *Composition
+		want   want
+	}{
+		"LaterPhaseOverrides": {
+			reason: "A patch of a later phase that Priority applies after a patch of an earlier phase should be valid.",
+			comp: &Composition{
+				Spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: pointer.String("a")},
+						{Name: pointer.String("b"), Patches: []Patch{fromA(nil), {FromFieldPath: pointer.String("spec.id"), Priority: pointer.Int(-1)}}},
+					},
+				},
+			},
+		},
+		"EarlierPhaseOverrides": {
+			reason: "A patch of an earlier phase that Priority applies after a patch of a later phase should be rejected.",
+			comp: &Composition{
+				Spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: pointer.String("a")},
+						{Name: pointer.String("b"), Patches: []Patch{fromA(nil), {FromFieldPath: pointer.String("spec.id"), Priority: pointer.Int(1)}}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.resources[1].patches[1]",
+					},
+				},
+			},
+		},
+		"EarlierPhaseOverridesThroughPatchSet": {
+			reason: "A patch of a PatchSet that inherits a priority that applies it after a patch of a later phase should be rejected.",
+			comp: &Composition{
+				Spec: CompositionSpec{
+					PatchSets: []PatchSet{{Name: "id", Patches: []Patch{{FromFieldPath: pointer.String("spec.id")}}}},
+					Resources: []ComposedTemplate{
+						{Name: pointer.String("a")},
+						{Name: pointer.String("b"), Patches: []Patch{fromA(pointer.Int(1)), {Type: PatchTypePatchSet, PatchSetName: pointer.String("id"), Priority: pointer.Int(2)}}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.resources[1].patches[1]",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, got := tc.comp.Validate()
+			if diff := cmp.Diff(tc.want.output, got, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
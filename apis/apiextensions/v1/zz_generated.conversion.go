@@ -4,9 +4,9 @@ package v1
 
 import (
 	v13 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	v1 "k8s.io/api/core/v1"
-	v12 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	v11 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v11 "k8s.io/api/core/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"time"
 )
@@ -21,6 +21,11 @@ func (c *GeneratedRevisionSpecConverter) FromRevisionSpec(source CompositionRevi
 		v1PatchSetList[i] = c.v1PatchSetToV1PatchSet(source.PatchSets[i])
 	}
 	v1CompositionSpec.PatchSets = v1PatchSetList
+	v1TransformSetList := make([]TransformSet, len(source.TransformSets))
+	for j := 0; j < len(source.TransformSets); j++ {
+		v1TransformSetList[j] = c.v1TransformSetToV1TransformSet(source.TransformSets[j])
+	}
+	v1CompositionSpec.TransformSets = v1TransformSetList
 	var pV1EnvironmentConfiguration *EnvironmentConfiguration
 	if source.Environment != nil {
 		v1EnvironmentConfiguration := c.v1EnvironmentConfigurationToV1EnvironmentConfiguration(*source.Environment)
@@ -28,13 +33,19 @@ func (c *GeneratedRevisionSpecConverter) FromRevisionSpec(source CompositionRevi
 	}
 	v1CompositionSpec.Environment = pV1EnvironmentConfiguration
 	v1ComposedTemplateList := make([]ComposedTemplate, len(source.Resources))
-	for j := 0; j < len(source.Resources); j++ {
-		v1ComposedTemplateList[j] = c.v1ComposedTemplateToV1ComposedTemplate(source.Resources[j])
+	for k := 0; k < len(source.Resources); k++ {
+		v1ComposedTemplateList[k] = c.v1ComposedTemplateToV1ComposedTemplate(source.Resources[k])
 	}
 	v1CompositionSpec.Resources = v1ComposedTemplateList
+	var pV1PatchErrorPolicy *PatchErrorPolicy
+	if source.PatchErrorPolicy != nil {
+		v1PatchErrorPolicy := PatchErrorPolicy(*source.PatchErrorPolicy)
+		pV1PatchErrorPolicy = &v1PatchErrorPolicy
+	}
+	v1CompositionSpec.PatchErrorPolicy = pV1PatchErrorPolicy
 	v1FunctionList := make([]Function, len(source.Functions))
-	for k := 0; k < len(source.Functions); k++ {
-		v1FunctionList[k] = c.v1FunctionToV1Function(source.Functions[k])
+	for l := 0; l < len(source.Functions); l++ {
+		v1FunctionList[l] = c.v1FunctionToV1Function(source.Functions[l])
 	}
 	v1CompositionSpec.Functions = v1FunctionList
 	var pString *string
@@ -59,6 +70,11 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 		v1PatchSetList[i] = c.v1PatchSetToV1PatchSet(source.PatchSets[i])
 	}
 	v1CompositionRevisionSpec.PatchSets = v1PatchSetList
+	v1TransformSetList := make([]TransformSet, len(source.TransformSets))
+	for j := 0; j < len(source.TransformSets); j++ {
+		v1TransformSetList[j] = c.v1TransformSetToV1TransformSet(source.TransformSets[j])
+	}
+	v1CompositionRevisionSpec.TransformSets = v1TransformSetList
 	var pV1EnvironmentConfiguration *EnvironmentConfiguration
 	if source.Environment != nil {
 		v1EnvironmentConfiguration := c.v1EnvironmentConfigurationToV1EnvironmentConfiguration(*source.Environment)
@@ -66,13 +82,19 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 	}
 	v1CompositionRevisionSpec.Environment = pV1EnvironmentConfiguration
 	v1ComposedTemplateList := make([]ComposedTemplate, len(source.Resources))
-	for j := 0; j < len(source.Resources); j++ {
-		v1ComposedTemplateList[j] = c.v1ComposedTemplateToV1ComposedTemplate(source.Resources[j])
+	for k := 0; k < len(source.Resources); k++ {
+		v1ComposedTemplateList[k] = c.v1ComposedTemplateToV1ComposedTemplate(source.Resources[k])
 	}
 	v1CompositionRevisionSpec.Resources = v1ComposedTemplateList
+	var pV1PatchErrorPolicy *PatchErrorPolicy
+	if source.PatchErrorPolicy != nil {
+		v1PatchErrorPolicy := PatchErrorPolicy(*source.PatchErrorPolicy)
+		pV1PatchErrorPolicy = &v1PatchErrorPolicy
+	}
+	v1CompositionRevisionSpec.PatchErrorPolicy = pV1PatchErrorPolicy
 	v1FunctionList := make([]Function, len(source.Functions))
-	for k := 0; k < len(source.Functions); k++ {
-		v1FunctionList[k] = c.v1FunctionToV1Function(source.Functions[k])
+	for l := 0; l < len(source.Functions); l++ {
+		v1FunctionList[l] = c.v1FunctionToV1Function(source.Functions[l])
 	}
 	v1CompositionRevisionSpec.Functions = v1FunctionList
 	var pString *string
@@ -89,6 +111,51 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 	v1CompositionRevisionSpec.PublishConnectionDetailsWithStoreConfigRef = pV1StoreConfigReference
 	return v1CompositionRevisionSpec
 }
+func (c *GeneratedRevisionSpecConverter) v1AggregateTransformToV1AggregateTransform(source AggregateTransform) AggregateTransform {
+	var v1AggregateTransform AggregateTransform
+	v1AggregateTransform.Type = AggregateTransformType(source.Type)
+	return v1AggregateTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1AllowlistTransformToV1AllowlistTransform(source AllowlistTransform) AllowlistTransform {
+	var v1AllowlistTransform AllowlistTransform
+	v1JSONList := make([]v1.JSON, len(source.Values))
+	for i := 0; i < len(source.Values); i++ {
+		v1JSONList[i] = c.v1JSONToV1JSON(source.Values[i])
+	}
+	v1AllowlistTransform.Values = v1JSONList
+	return v1AllowlistTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1CIDRMatchTransformBlockToV1CIDRMatchTransformBlock(source CIDRMatchTransformBlock) CIDRMatchTransformBlock {
+	var v1CIDRMatchTransformBlock CIDRMatchTransformBlock
+	v1CIDRMatchTransformBlock.CIDR = source.CIDR
+	v1CIDRMatchTransformBlock.Value = c.v1JSONToV1JSON(source.Value)
+	return v1CIDRMatchTransformBlock
+}
+func (c *GeneratedRevisionSpecConverter) v1CIDRMatchTransformToV1CIDRMatchTransform(source CIDRMatchTransform) CIDRMatchTransform {
+	var v1CIDRMatchTransform CIDRMatchTransform
+	v1CIDRMatchTransformBlockList := make([]CIDRMatchTransformBlock, len(source.Blocks))
+	for i := 0; i < len(source.Blocks); i++ {
+		v1CIDRMatchTransformBlockList[i] = c.v1CIDRMatchTransformBlockToV1CIDRMatchTransformBlock(source.Blocks[i])
+	}
+	v1CIDRMatchTransform.Blocks = v1CIDRMatchTransformBlockList
+	v1CIDRMatchTransform.FallbackValue = c.v1JSONToV1JSON(source.FallbackValue)
+	return v1CIDRMatchTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1CaseTransformToV1CaseTransform(source CaseTransform) CaseTransform {
+	var v1CaseTransform CaseTransform
+	v1CaseTransform.To = CaseTransformType(source.To)
+	return v1CaseTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1CoalesceCombineToV1CoalesceCombine(source CoalesceCombine) CoalesceCombine {
+	var v1CoalesceCombine CoalesceCombine
+	var pV1JSON *v1.JSON
+	if source.Default != nil {
+		v1JSON := c.v1JSONToV1JSON(*source.Default)
+		pV1JSON = &v1JSON
+	}
+	v1CoalesceCombine.Default = pV1JSON
+	return v1CoalesceCombine
+}
 func (c *GeneratedRevisionSpecConverter) v1CombineToV1Combine(source Combine) Combine {
 	var v1Combine Combine
 	v1CombineVariableList := make([]CombineVariable, len(source.Variables))
@@ -103,13 +170,58 @@ func (c *GeneratedRevisionSpecConverter) v1CombineToV1Combine(source Combine) Co
 		pV1StringCombine = &v1StringCombine
 	}
 	v1Combine.String = pV1StringCombine
+	var pV1CoalesceCombine *CoalesceCombine
+	if source.Coalesce != nil {
+		v1CoalesceCombine := c.v1CoalesceCombineToV1CoalesceCombine(*source.Coalesce)
+		pV1CoalesceCombine = &v1CoalesceCombine
+	}
+	v1Combine.Coalesce = pV1CoalesceCombine
+	var pV1MathCombine *MathCombine
+	if source.Math != nil {
+		v1MathCombine := c.v1MathCombineToV1MathCombine(*source.Math)
+		pV1MathCombine = &v1MathCombine
+	}
+	v1Combine.Math = pV1MathCombine
+	var pV1SetCombine *SetCombine
+	if source.Set != nil {
+		v1SetCombine := c.v1SetCombineToV1SetCombine(*source.Set)
+		pV1SetCombine = &v1SetCombine
+	}
+	v1Combine.Set = pV1SetCombine
 	return v1Combine
 }
 func (c *GeneratedRevisionSpecConverter) v1CombineVariableToV1CombineVariable(source CombineVariable) CombineVariable {
 	var v1CombineVariable CombineVariable
 	v1CombineVariable.FromFieldPath = source.FromFieldPath
+	v1TransformList := make([]Transform, len(source.Transforms))
+	for i := 0; i < len(source.Transforms); i++ {
+		v1TransformList[i] = c.v1TransformToV1Transform(source.Transforms[i])
+	}
+	v1CombineVariable.Transforms = v1TransformList
+	var pV1FromFieldPathPolicy *FromFieldPathPolicy
+	if source.Policy != nil {
+		v1FromFieldPathPolicy := FromFieldPathPolicy(*source.Policy)
+		pV1FromFieldPathPolicy = &v1FromFieldPathPolicy
+	}
+	v1CombineVariable.Policy = pV1FromFieldPathPolicy
 	return v1CombineVariable
 }
+func (c *GeneratedRevisionSpecConverter) v1ComposedResourceSelectorToV1ComposedResourceSelector(source ComposedResourceSelector) ComposedResourceSelector {
+	var v1ComposedResourceSelector ComposedResourceSelector
+	var pString *string
+	if source.Name != nil {
+		xstring := *source.Name
+		pString = &xstring
+	}
+	v1ComposedResourceSelector.Name = pString
+	var pInt *int
+	if source.Index != nil {
+		xint := *source.Index
+		pInt = &xint
+	}
+	v1ComposedResourceSelector.Index = pInt
+	return v1ComposedResourceSelector
+}
 func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(source ComposedTemplate) ComposedTemplate {
 	var v1ComposedTemplate ComposedTemplate
 	var pString *string
@@ -136,6 +248,34 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 	v1ComposedTemplate.ReadinessChecks = v1ReadinessCheckList
 	return v1ComposedTemplate
 }
+func (c *GeneratedRevisionSpecConverter) v1ConditionStatusTransformToV1ConditionStatusTransform(source ConditionStatusTransform) ConditionStatusTransform {
+	var v1ConditionStatusTransform ConditionStatusTransform
+	var pV1JSON *v1.JSON
+	if source.True != nil {
+		v1JSON := c.v1JSONToV1JSON(*source.True)
+		pV1JSON = &v1JSON
+	}
+	v1ConditionStatusTransform.True = pV1JSON
+	var pV1JSON2 *v1.JSON
+	if source.False != nil {
+		v1JSON2 := c.v1JSONToV1JSON(*source.False)
+		pV1JSON2 = &v1JSON2
+	}
+	v1ConditionStatusTransform.False = pV1JSON2
+	var pV1JSON3 *v1.JSON
+	if source.Unknown != nil {
+		v1JSON3 := c.v1JSONToV1JSON(*source.Unknown)
+		pV1JSON3 = &v1JSON3
+	}
+	v1ConditionStatusTransform.Unknown = pV1JSON3
+	var pV1JSON4 *v1.JSON
+	if source.Default != nil {
+		v1JSON4 := c.v1JSONToV1JSON(*source.Default)
+		pV1JSON4 = &v1JSON4
+	}
+	v1ConditionStatusTransform.Default = pV1JSON4
+	return v1ConditionStatusTransform
+}
 func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(source ConnectionDetail) ConnectionDetail {
 	var v1ConnectionDetail ConnectionDetail
 	var pString *string
@@ -209,13 +349,13 @@ func (c *GeneratedRevisionSpecConverter) v1ContainerFunctionRunnerToV1ContainerF
 func (c *GeneratedRevisionSpecConverter) v1ContainerFunctionToV1ContainerFunction(source ContainerFunction) ContainerFunction {
 	var v1ContainerFunction ContainerFunction
 	v1ContainerFunction.Image = source.Image
-	var pV1PullPolicy *v1.PullPolicy
+	var pV1PullPolicy *v11.PullPolicy
 	if source.ImagePullPolicy != nil {
-		v1PullPolicy := v1.PullPolicy(*source.ImagePullPolicy)
+		v1PullPolicy := v11.PullPolicy(*source.ImagePullPolicy)
 		pV1PullPolicy = &v1PullPolicy
 	}
 	v1ContainerFunction.ImagePullPolicy = pV1PullPolicy
-	var pV1Duration *v11.Duration
+	var pV1Duration *v12.Duration
 	if source.Timeout != nil {
 		v1Duration := c.v1DurationToV1Duration(*source.Timeout)
 		pV1Duration = &v1Duration
@@ -250,13 +390,35 @@ func (c *GeneratedRevisionSpecConverter) v1ConvertTransformToV1ConvertTransform(
 		pV1ConvertTransformFormat = &v1ConvertTransformFormat
 	}
 	v1ConvertTransform.Format = pV1ConvertTransformFormat
+	v1ConvertTransform.EmptyAsZero = source.EmptyAsZero
 	return v1ConvertTransform
 }
-func (c *GeneratedRevisionSpecConverter) v1DurationToV1Duration(source v11.Duration) v11.Duration {
-	var v1Duration v11.Duration
+func (c *GeneratedRevisionSpecConverter) v1DedupTransformToV1DedupTransform(source DedupTransform) DedupTransform {
+	var v1DedupTransform DedupTransform
+	var pString *string
+	if source.KeyFieldPath != nil {
+		xstring := *source.KeyFieldPath
+		pString = &xstring
+	}
+	v1DedupTransform.KeyFieldPath = pString
+	return v1DedupTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1DurationToV1Duration(source v12.Duration) v12.Duration {
+	var v1Duration v12.Duration
 	v1Duration.Duration = time.Duration(source.Duration)
 	return v1Duration
 }
+func (c *GeneratedRevisionSpecConverter) v1EmbeddedJSONPolicyToV1EmbeddedJSONPolicy(source EmbeddedJSONPolicy) EmbeddedJSONPolicy {
+	var v1EmbeddedJSONPolicy EmbeddedJSONPolicy
+	v1EmbeddedJSONPolicy.FieldPath = source.FieldPath
+	var pBool *bool
+	if source.InitializeInvalid != nil {
+		xbool := *source.InitializeInvalid
+		pBool = &xbool
+	}
+	v1EmbeddedJSONPolicy.InitializeInvalid = pBool
+	return v1EmbeddedJSONPolicy
+}
 func (c *GeneratedRevisionSpecConverter) v1EnvironmentConfigurationToV1EnvironmentConfiguration(source EnvironmentConfiguration) EnvironmentConfiguration {
 	var v1EnvironmentConfiguration EnvironmentConfiguration
 	v1EnvironmentSourceList := make([]EnvironmentSource, len(source.EnvironmentConfigs))
@@ -292,9 +454,14 @@ func (c *GeneratedRevisionSpecConverter) v1EnvironmentPatchToV1EnvironmentPatch(
 		pString2 = &xstring2
 	}
 	v1EnvironmentPatch.ToFieldPath = pString2
+	stringList := make([]string, len(source.ToFieldPaths))
+	for i := 0; i < len(source.ToFieldPaths); i++ {
+		stringList[i] = source.ToFieldPaths[i]
+	}
+	v1EnvironmentPatch.ToFieldPaths = stringList
 	v1TransformList := make([]Transform, len(source.Transforms))
-	for i := 0; i < len(source.Transforms); i++ {
-		v1TransformList[i] = c.v1TransformToV1Transform(source.Transforms[i])
+	for j := 0; j < len(source.Transforms); j++ {
+		v1TransformList[j] = c.v1TransformToV1Transform(source.Transforms[j])
 	}
 	v1EnvironmentPatch.Transforms = v1TransformList
 	var pV1PatchPolicy *PatchPolicy
@@ -354,6 +521,17 @@ func (c *GeneratedRevisionSpecConverter) v1EnvironmentSourceToV1EnvironmentSourc
 	v1EnvironmentSource.Selector = pV1EnvironmentSourceSelector
 	return v1EnvironmentSource
 }
+func (c *GeneratedRevisionSpecConverter) v1FieldSelectTransformToV1FieldSelectTransform(source FieldSelectTransform) FieldSelectTransform {
+	var v1FieldSelectTransform FieldSelectTransform
+	v1FieldSelectTransform.FieldPath = source.FieldPath
+	var pV1FromFieldPathPolicy *FromFieldPathPolicy
+	if source.Policy != nil {
+		v1FromFieldPathPolicy := FromFieldPathPolicy(*source.Policy)
+		pV1FromFieldPathPolicy = &v1FromFieldPathPolicy
+	}
+	v1FieldSelectTransform.Policy = pV1FromFieldPathPolicy
+	return v1FieldSelectTransform
+}
 func (c *GeneratedRevisionSpecConverter) v1FunctionToV1Function(source Function) Function {
 	var v1Function Function
 	v1Function.Name = source.Name
@@ -372,8 +550,8 @@ func (c *GeneratedRevisionSpecConverter) v1FunctionToV1Function(source Function)
 	v1Function.Container = pV1ContainerFunction
 	return v1Function
 }
-func (c *GeneratedRevisionSpecConverter) v1JSONToV1JSON(source v12.JSON) v12.JSON {
-	var v1JSON v12.JSON
+func (c *GeneratedRevisionSpecConverter) v1JSONToV1JSON(source v1.JSON) v1.JSON {
+	var v1JSON v1.JSON
 	byteList := make([]uint8, len(source.Raw))
 	for i := 0; i < len(source.Raw); i++ {
 		byteList[i] = source.Raw[i]
@@ -381,9 +559,19 @@ func (c *GeneratedRevisionSpecConverter) v1JSONToV1JSON(source v12.JSON) v12.JSO
 	v1JSON.Raw = byteList
 	return v1JSON
 }
+func (c *GeneratedRevisionSpecConverter) v1LabelSelectorTransformToV1LabelSelectorTransform(source LabelSelectorTransform) LabelSelectorTransform {
+	var v1LabelSelectorTransform LabelSelectorTransform
+	v1LabelSelectorTransform.Mode = LabelSelectorTransformMode(source.Mode)
+	return v1LabelSelectorTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1MapTransformOptionsToV1MapTransformOptions(source MapTransformOptions) MapTransformOptions {
+	var v1MapTransformOptions MapTransformOptions
+	v1MapTransformOptions.CaseInsensitive = source.CaseInsensitive
+	return v1MapTransformOptions
+}
 func (c *GeneratedRevisionSpecConverter) v1MapTransformToV1MapTransform(source MapTransform) MapTransform {
 	var v1MapTransform MapTransform
-	mapStringV1JSON := make(map[string]v12.JSON, len(source.Pairs))
+	mapStringV1JSON := make(map[string]v1.JSON, len(source.Pairs))
 	for key, value := range source.Pairs {
 		mapStringV1JSON[key] = c.v1JSONToV1JSON(value)
 	}
@@ -419,6 +607,11 @@ func (c *GeneratedRevisionSpecConverter) v1MatchTransformToV1MatchTransform(sour
 	v1MatchTransform.FallbackTo = MatchFallbackTo(source.FallbackTo)
 	return v1MatchTransform
 }
+func (c *GeneratedRevisionSpecConverter) v1MathCombineToV1MathCombine(source MathCombine) MathCombine {
+	var v1MathCombine MathCombine
+	v1MathCombine.Operation = MathCombineOperation(source.Operation)
+	return v1MathCombine
+}
 func (c *GeneratedRevisionSpecConverter) v1MathTransformToV1MathTransform(source MathTransform) MathTransform {
 	var v1MathTransform MathTransform
 	v1MathTransform.Type = MathTransformType(source.Type)
@@ -440,6 +633,18 @@ func (c *GeneratedRevisionSpecConverter) v1MathTransformToV1MathTransform(source
 		pInt643 = &xint643
 	}
 	v1MathTransform.ClampMax = pInt643
+	var pInt644 *int64
+	if source.Modulo != nil {
+		xint644 := *source.Modulo
+		pInt644 = &xint644
+	}
+	v1MathTransform.Modulo = pInt644
+	var pInt645 *int64
+	if source.Add != nil {
+		xint645 := *source.Add
+		pInt645 = &xint645
+	}
+	v1MathTransform.Add = pInt645
 	return v1MathTransform
 }
 func (c *GeneratedRevisionSpecConverter) v1MergeOptionsToV1MergeOptions(source v13.MergeOptions) v13.MergeOptions {
@@ -458,6 +663,45 @@ func (c *GeneratedRevisionSpecConverter) v1MergeOptionsToV1MergeOptions(source v
 	v1MergeOptions.AppendSlice = pBool2
 	return v1MergeOptions
 }
+func (c *GeneratedRevisionSpecConverter) v1NumberFormatTransformToV1NumberFormatTransform(source NumberFormatTransform) NumberFormatTransform {
+	var v1NumberFormatTransform NumberFormatTransform
+	var pString *string
+	if source.ThousandsSeparator != nil {
+		xstring := *source.ThousandsSeparator
+		pString = &xstring
+	}
+	v1NumberFormatTransform.ThousandsSeparator = pString
+	var pString2 *string
+	if source.DecimalSeparator != nil {
+		xstring2 := *source.DecimalSeparator
+		pString2 = &xstring2
+	}
+	v1NumberFormatTransform.DecimalSeparator = pString2
+	var pInt *int
+	if source.DecimalPlaces != nil {
+		xint := *source.DecimalPlaces
+		pInt = &xint
+	}
+	v1NumberFormatTransform.DecimalPlaces = pInt
+	return v1NumberFormatTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1PEMTransformToV1PEMTransform(source PEMTransform) PEMTransform {
+	var v1PEMTransform PEMTransform
+	v1PEMTransform.Attribute = PEMTransformAttribute(source.Attribute)
+	return v1PEMTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1PatchConditionToV1PatchCondition(source PatchCondition) PatchCondition {
+	var v1PatchCondition PatchCondition
+	v1PatchCondition.Source = PatchConditionSource(source.Source)
+	v1PatchCondition.FieldPath = source.FieldPath
+	var pV1JSON *v1.JSON
+	if source.Equals != nil {
+		v1JSON := c.v1JSONToV1JSON(*source.Equals)
+		pV1JSON = &v1JSON
+	}
+	v1PatchCondition.Equals = pV1JSON
+	return v1PatchCondition
+}
 func (c *GeneratedRevisionSpecConverter) v1PatchPolicyToV1PatchPolicy(source PatchPolicy) PatchPolicy {
 	var v1PatchPolicy PatchPolicy
 	var pV1FromFieldPathPolicy *FromFieldPathPolicy
@@ -472,6 +716,48 @@ func (c *GeneratedRevisionSpecConverter) v1PatchPolicyToV1PatchPolicy(source Pat
 		pV1MergeOptions = &v1MergeOptions
 	}
 	v1PatchPolicy.MergeOptions = pV1MergeOptions
+	var pV1JSON *v1.JSON
+	if source.FromFieldPathDefault != nil {
+		v1JSON := c.v1JSONToV1JSON(*source.FromFieldPathDefault)
+		pV1JSON = &v1JSON
+	}
+	v1PatchPolicy.FromFieldPathDefault = pV1JSON
+	var pBool *bool
+	if source.TransformFromFieldPathDefault != nil {
+		xbool := *source.TransformFromFieldPathDefault
+		pBool = &xbool
+	}
+	v1PatchPolicy.TransformFromFieldPathDefault = pBool
+	var pBool2 *bool
+	if source.ImmutableAfterCreate != nil {
+		xbool2 := *source.ImmutableAfterCreate
+		pBool2 = &xbool2
+	}
+	v1PatchPolicy.ImmutableAfterCreate = pBool2
+	var pBool3 *bool
+	if source.SkipWhenDeleting != nil {
+		xbool3 := *source.SkipWhenDeleting
+		pBool3 = &xbool3
+	}
+	v1PatchPolicy.SkipWhenDeleting = pBool3
+	var pBool4 *bool
+	if source.MergeConditions != nil {
+		xbool4 := *source.MergeConditions
+		pBool4 = &xbool4
+	}
+	v1PatchPolicy.MergeConditions = pBool4
+	var pBool5 *bool
+	if source.StrategicMerge != nil {
+		xbool5 := *source.StrategicMerge
+		pBool5 = &xbool5
+	}
+	v1PatchPolicy.StrategicMerge = pBool5
+	var pV1EmbeddedJSONPolicy *EmbeddedJSONPolicy
+	if source.ToEmbeddedJSON != nil {
+		v1EmbeddedJSONPolicy := c.v1EmbeddedJSONPolicyToV1EmbeddedJSONPolicy(*source.ToEmbeddedJSON)
+		pV1EmbeddedJSONPolicy = &v1EmbeddedJSONPolicy
+	}
+	v1PatchPolicy.ToEmbeddedJSON = pV1EmbeddedJSONPolicy
 	return v1PatchPolicy
 }
 func (c *GeneratedRevisionSpecConverter) v1PatchSetToV1PatchSet(source PatchSet) PatchSet {
@@ -488,32 +774,79 @@ func (c *GeneratedRevisionSpecConverter) v1PatchToV1Patch(source Patch) Patch {
 	var v1Patch Patch
 	v1Patch.Type = PatchType(source.Type)
 	var pString *string
-	if source.FromFieldPath != nil {
-		xstring := *source.FromFieldPath
+	if source.Description != nil {
+		xstring := *source.Description
 		pString = &xstring
 	}
-	v1Patch.FromFieldPath = pString
+	v1Patch.Description = pString
+	var pInt *int
+	if source.Priority != nil {
+		xint := *source.Priority
+		pInt = &xint
+	}
+	v1Patch.Priority = pInt
+	var pString2 *string
+	if source.FromFieldPath != nil {
+		xstring2 := *source.FromFieldPath
+		pString2 = &xstring2
+	}
+	v1Patch.FromFieldPath = pString2
+	var pV1ComposedResourceSelector *ComposedResourceSelector
+	if source.FromComposedResource != nil {
+		v1ComposedResourceSelector := c.v1ComposedResourceSelectorToV1ComposedResourceSelector(*source.FromComposedResource)
+		pV1ComposedResourceSelector = &v1ComposedResourceSelector
+	}
+	v1Patch.FromComposedResource = pV1ComposedResourceSelector
+	var pString3 *string
+	if source.ConnectionSecretKey != nil {
+		xstring3 := *source.ConnectionSecretKey
+		pString3 = &xstring3
+	}
+	v1Patch.ConnectionSecretKey = pString3
 	var pV1Combine *Combine
 	if source.Combine != nil {
 		v1Combine := c.v1CombineToV1Combine(*source.Combine)
 		pV1Combine = &v1Combine
 	}
 	v1Patch.Combine = pV1Combine
-	var pString2 *string
+	var pString4 *string
 	if source.ToFieldPath != nil {
-		xstring2 := *source.ToFieldPath
-		pString2 = &xstring2
+		xstring4 := *source.ToFieldPath
+		pString4 = &xstring4
 	}
-	v1Patch.ToFieldPath = pString2
-	var pString3 *string
+	v1Patch.ToFieldPath = pString4
+	stringList := make([]string, len(source.ToFieldPaths))
+	for i := 0; i < len(source.ToFieldPaths); i++ {
+		stringList[i] = source.ToFieldPaths[i]
+	}
+	v1Patch.ToFieldPaths = stringList
+	var pV1JSON *v1.JSON
+	if source.SkipWhenValue != nil {
+		v1JSON := c.v1JSONToV1JSON(*source.SkipWhenValue)
+		pV1JSON = &v1JSON
+	}
+	v1Patch.SkipWhenValue = pV1JSON
+	var pV1TransformIOType *TransformIOType
+	if source.ExpectedType != nil {
+		v1TransformIOType := TransformIOType(*source.ExpectedType)
+		pV1TransformIOType = &v1TransformIOType
+	}
+	v1Patch.ExpectedType = pV1TransformIOType
+	var pString5 *string
 	if source.PatchSetName != nil {
-		xstring3 := *source.PatchSetName
-		pString3 = &xstring3
+		xstring5 := *source.PatchSetName
+		pString5 = &xstring5
 	}
-	v1Patch.PatchSetName = pString3
+	v1Patch.PatchSetName = pString5
+	var pV1PatchCondition *PatchCondition
+	if source.When != nil {
+		v1PatchCondition := c.v1PatchConditionToV1PatchCondition(*source.When)
+		pV1PatchCondition = &v1PatchCondition
+	}
+	v1Patch.When = pV1PatchCondition
 	v1TransformList := make([]Transform, len(source.Transforms))
-	for i := 0; i < len(source.Transforms); i++ {
-		v1TransformList[i] = c.v1TransformToV1Transform(source.Transforms[i])
+	for j := 0; j < len(source.Transforms); j++ {
+		v1TransformList[j] = c.v1TransformToV1Transform(source.Transforms[j])
 	}
 	v1Patch.Transforms = v1TransformList
 	var pV1PatchPolicy *PatchPolicy
@@ -522,8 +855,29 @@ func (c *GeneratedRevisionSpecConverter) v1PatchToV1Patch(source Patch) Patch {
 		pV1PatchPolicy = &v1PatchPolicy
 	}
 	v1Patch.Policy = pV1PatchPolicy
+	stringList2 := make([]string, len(source.Tags))
+	for k := 0; k < len(source.Tags); k++ {
+		stringList2[k] = source.Tags[k]
+	}
+	v1Patch.Tags = stringList2
 	return v1Patch
 }
+func (c *GeneratedRevisionSpecConverter) v1RangeTransformBucketToV1RangeTransformBucket(source RangeTransformBucket) RangeTransformBucket {
+	var v1RangeTransformBucket RangeTransformBucket
+	v1RangeTransformBucket.Max = source.Max
+	v1RangeTransformBucket.Value = c.v1JSONToV1JSON(source.Value)
+	return v1RangeTransformBucket
+}
+func (c *GeneratedRevisionSpecConverter) v1RangeTransformToV1RangeTransform(source RangeTransform) RangeTransform {
+	var v1RangeTransform RangeTransform
+	v1RangeTransformBucketList := make([]RangeTransformBucket, len(source.Buckets))
+	for i := 0; i < len(source.Buckets); i++ {
+		v1RangeTransformBucketList[i] = c.v1RangeTransformBucketToV1RangeTransformBucket(source.Buckets[i])
+	}
+	v1RangeTransform.Buckets = v1RangeTransformBucketList
+	v1RangeTransform.FallbackValue = c.v1JSONToV1JSON(source.FallbackValue)
+	return v1RangeTransform
+}
 func (c *GeneratedRevisionSpecConverter) v1ReadinessCheckToV1ReadinessCheck(source ReadinessCheck) ReadinessCheck {
 	var v1ReadinessCheck ReadinessCheck
 	v1ReadinessCheck.Type = ReadinessCheckType(source.Type)
@@ -532,6 +886,45 @@ func (c *GeneratedRevisionSpecConverter) v1ReadinessCheckToV1ReadinessCheck(sour
 	v1ReadinessCheck.MatchInteger = source.MatchInteger
 	return v1ReadinessCheck
 }
+func (c *GeneratedRevisionSpecConverter) v1SelectMatchTransformToV1SelectMatchTransform(source SelectMatchTransform) SelectMatchTransform {
+	var v1SelectMatchTransform SelectMatchTransform
+	v1SelectMatchTransform.FieldPath = source.FieldPath
+	v1SelectMatchTransform.Value = c.v1JSONToV1JSON(source.Value)
+	var pString *string
+	if source.ReturnField != nil {
+		xstring := *source.ReturnField
+		pString = &xstring
+	}
+	v1SelectMatchTransform.ReturnField = pString
+	var pV1FromFieldPathPolicy *FromFieldPathPolicy
+	if source.Policy != nil {
+		v1FromFieldPathPolicy := FromFieldPathPolicy(*source.Policy)
+		pV1FromFieldPathPolicy = &v1FromFieldPathPolicy
+	}
+	v1SelectMatchTransform.Policy = pV1FromFieldPathPolicy
+	return v1SelectMatchTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1SemverTransformToV1SemverTransform(source SemverTransform) SemverTransform {
+	var v1SemverTransform SemverTransform
+	v1SemverTransform.Operation = SemverTransformOperation(source.Operation)
+	return v1SemverTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1SetCombineToV1SetCombine(source SetCombine) SetCombine {
+	var v1SetCombine SetCombine
+	v1SetCombine.Operation = SetCombineOperation(source.Operation)
+	return v1SetCombine
+}
+func (c *GeneratedRevisionSpecConverter) v1StableSuffixTransformToV1StableSuffixTransform(source StableSuffixTransform) StableSuffixTransform {
+	var v1StableSuffixTransform StableSuffixTransform
+	v1StableSuffixTransform.Length = source.Length
+	var pString *string
+	if source.Charset != nil {
+		xstring := *source.Charset
+		pString = &xstring
+	}
+	v1StableSuffixTransform.Charset = pString
+	return v1StableSuffixTransform
+}
 func (c *GeneratedRevisionSpecConverter) v1StoreConfigReferenceToV1StoreConfigReference(source StoreConfigReference) StoreConfigReference {
 	var v1StoreConfigReference StoreConfigReference
 	v1StoreConfigReference.Name = source.Name
@@ -542,6 +935,11 @@ func (c *GeneratedRevisionSpecConverter) v1StringCombineToV1StringCombine(source
 	v1StringCombine.Format = source.Format
 	return v1StringCombine
 }
+func (c *GeneratedRevisionSpecConverter) v1StringTransformEscapeToV1StringTransformEscape(source StringTransformEscape) StringTransformEscape {
+	var v1StringTransformEscape StringTransformEscape
+	v1StringTransformEscape.Context = StringEscapeContext(source.Context)
+	return v1StringTransformEscape
+}
 func (c *GeneratedRevisionSpecConverter) v1StringTransformRegexpToV1StringTransformRegexp(source StringTransformRegexp) StringTransformRegexp {
 	var v1StringTransformRegexp StringTransformRegexp
 	v1StringTransformRegexp.Match = source.Match
@@ -553,6 +951,29 @@ func (c *GeneratedRevisionSpecConverter) v1StringTransformRegexpToV1StringTransf
 	v1StringTransformRegexp.Group = pInt
 	return v1StringTransformRegexp
 }
+func (c *GeneratedRevisionSpecConverter) v1StringTransformReplaceToV1StringTransformReplace(source StringTransformReplace) StringTransformReplace {
+	var v1StringTransformReplace StringTransformReplace
+	v1StringTransformReplace.Old = source.Old
+	v1StringTransformReplace.New = source.New
+	var pInt *int
+	if source.Count != nil {
+		xint := *source.Count
+		pInt = &xint
+	}
+	v1StringTransformReplace.Count = pInt
+	return v1StringTransformReplace
+}
+func (c *GeneratedRevisionSpecConverter) v1StringTransformSliceToV1StringTransformSlice(source StringTransformSlice) StringTransformSlice {
+	var v1StringTransformSlice StringTransformSlice
+	v1StringTransformSlice.Start = source.Start
+	var pInt *int
+	if source.End != nil {
+		xint := *source.End
+		pInt = &xint
+	}
+	v1StringTransformSlice.End = pInt
+	return v1StringTransformSlice
+}
 func (c *GeneratedRevisionSpecConverter) v1StringTransformToV1StringTransform(source StringTransform) StringTransform {
 	var v1StringTransform StringTransform
 	v1StringTransform.Type = StringTransformType(source.Type)
@@ -580,11 +1001,80 @@ func (c *GeneratedRevisionSpecConverter) v1StringTransformToV1StringTransform(so
 		pV1StringTransformRegexp = &v1StringTransformRegexp
 	}
 	v1StringTransform.Regexp = pV1StringTransformRegexp
+	var pV1StringTransformReplace *StringTransformReplace
+	if source.Replace != nil {
+		v1StringTransformReplace := c.v1StringTransformReplaceToV1StringTransformReplace(*source.Replace)
+		pV1StringTransformReplace = &v1StringTransformReplace
+	}
+	v1StringTransform.Replace = pV1StringTransformReplace
+	var pV1StringTransformSlice *StringTransformSlice
+	if source.Slice != nil {
+		v1StringTransformSlice := c.v1StringTransformSliceToV1StringTransformSlice(*source.Slice)
+		pV1StringTransformSlice = &v1StringTransformSlice
+	}
+	v1StringTransform.Slice = pV1StringTransformSlice
+	var pV1StringTransformEscape *StringTransformEscape
+	if source.Escape != nil {
+		v1StringTransformEscape := c.v1StringTransformEscapeToV1StringTransformEscape(*source.Escape)
+		pV1StringTransformEscape = &v1StringTransformEscape
+	}
+	v1StringTransform.Escape = pV1StringTransformEscape
+	var pString3 *string
+	if source.Prefix != nil {
+		xstring3 := *source.Prefix
+		pString3 = &xstring3
+	}
+	v1StringTransform.Prefix = pString3
+	var pString4 *string
+	if source.Suffix != nil {
+		xstring4 := *source.Suffix
+		pString4 = &xstring4
+	}
+	v1StringTransform.Suffix = pString4
 	return v1StringTransform
 }
+func (c *GeneratedRevisionSpecConverter) v1TernaryTransformToV1TernaryTransform(source TernaryTransform) TernaryTransform {
+	var v1TernaryTransform TernaryTransform
+	v1TernaryTransform.True = c.v1JSONToV1JSON(source.True)
+	v1TernaryTransform.False = c.v1JSONToV1JSON(source.False)
+	return v1TernaryTransform
+}
+func (c *GeneratedRevisionSpecConverter) v1TransformConditionToV1TransformCondition(source TransformCondition) TransformCondition {
+	var v1TransformCondition TransformCondition
+	v1TransformCondition.Operator = TransformConditionOperator(source.Operator)
+	var pV1JSON *v1.JSON
+	if source.Value != nil {
+		v1JSON := c.v1JSONToV1JSON(*source.Value)
+		pV1JSON = &v1JSON
+	}
+	v1TransformCondition.Value = pV1JSON
+	var pString *string
+	if source.Regexp != nil {
+		xstring := *source.Regexp
+		pString = &xstring
+	}
+	v1TransformCondition.Regexp = pString
+	return v1TransformCondition
+}
+func (c *GeneratedRevisionSpecConverter) v1TransformSetToV1TransformSet(source TransformSet) TransformSet {
+	var v1TransformSet TransformSet
+	v1TransformSet.Name = source.Name
+	v1TransformList := make([]Transform, len(source.Transforms))
+	for i := 0; i < len(source.Transforms); i++ {
+		v1TransformList[i] = c.v1TransformToV1Transform(source.Transforms[i])
+	}
+	v1TransformSet.Transforms = v1TransformList
+	return v1TransformSet
+}
 func (c *GeneratedRevisionSpecConverter) v1TransformToV1Transform(source Transform) Transform {
 	var v1Transform Transform
 	v1Transform.Type = TransformType(source.Type)
+	var pString *string
+	if source.TransformSetName != nil {
+		xstring := *source.TransformSetName
+		pString = &xstring
+	}
+	v1Transform.TransformSetName = pString
 	var pV1MathTransform *MathTransform
 	if source.Math != nil {
 		v1MathTransform := c.v1MathTransformToV1MathTransform(*source.Math)
@@ -597,6 +1087,12 @@ func (c *GeneratedRevisionSpecConverter) v1TransformToV1Transform(source Transfo
 		pV1MapTransform = &v1MapTransform
 	}
 	v1Transform.Map = pV1MapTransform
+	var pV1MapTransformOptions *MapTransformOptions
+	if source.MapOptions != nil {
+		v1MapTransformOptions := c.v1MapTransformOptionsToV1MapTransformOptions(*source.MapOptions)
+		pV1MapTransformOptions = &v1MapTransformOptions
+	}
+	v1Transform.MapOptions = pV1MapTransformOptions
 	var pV1MatchTransform *MatchTransform
 	if source.Match != nil {
 		v1MatchTransform := c.v1MatchTransformToV1MatchTransform(*source.Match)
@@ -615,11 +1111,136 @@ func (c *GeneratedRevisionSpecConverter) v1TransformToV1Transform(source Transfo
 		pV1ConvertTransform = &v1ConvertTransform
 	}
 	v1Transform.Convert = pV1ConvertTransform
+	var pV1RangeTransform *RangeTransform
+	if source.Range != nil {
+		v1RangeTransform := c.v1RangeTransformToV1RangeTransform(*source.Range)
+		pV1RangeTransform = &v1RangeTransform
+	}
+	v1Transform.Range = pV1RangeTransform
+	var pV1AggregateTransform *AggregateTransform
+	if source.Aggregate != nil {
+		v1AggregateTransform := c.v1AggregateTransformToV1AggregateTransform(*source.Aggregate)
+		pV1AggregateTransform = &v1AggregateTransform
+	}
+	v1Transform.Aggregate = pV1AggregateTransform
+	var pV1TernaryTransform *TernaryTransform
+	if source.Ternary != nil {
+		v1TernaryTransform := c.v1TernaryTransformToV1TernaryTransform(*source.Ternary)
+		pV1TernaryTransform = &v1TernaryTransform
+	}
+	v1Transform.Ternary = pV1TernaryTransform
+	var pV1TruncateTransform *TruncateTransform
+	if source.Truncate != nil {
+		v1TruncateTransform := c.v1TruncateTransformToV1TruncateTransform(*source.Truncate)
+		pV1TruncateTransform = &v1TruncateTransform
+	}
+	v1Transform.Truncate = pV1TruncateTransform
+	var pV1NumberFormatTransform *NumberFormatTransform
+	if source.NumberFormat != nil {
+		v1NumberFormatTransform := c.v1NumberFormatTransformToV1NumberFormatTransform(*source.NumberFormat)
+		pV1NumberFormatTransform = &v1NumberFormatTransform
+	}
+	v1Transform.NumberFormat = pV1NumberFormatTransform
+	var pV1CIDRMatchTransform *CIDRMatchTransform
+	if source.CIDRMatch != nil {
+		v1CIDRMatchTransform := c.v1CIDRMatchTransformToV1CIDRMatchTransform(*source.CIDRMatch)
+		pV1CIDRMatchTransform = &v1CIDRMatchTransform
+	}
+	v1Transform.CIDRMatch = pV1CIDRMatchTransform
+	var pV1PEMTransform *PEMTransform
+	if source.PEM != nil {
+		v1PEMTransform := c.v1PEMTransformToV1PEMTransform(*source.PEM)
+		pV1PEMTransform = &v1PEMTransform
+	}
+	v1Transform.PEM = pV1PEMTransform
+	var pV1AllowlistTransform *AllowlistTransform
+	if source.Allowlist != nil {
+		v1AllowlistTransform := c.v1AllowlistTransformToV1AllowlistTransform(*source.Allowlist)
+		pV1AllowlistTransform = &v1AllowlistTransform
+	}
+	v1Transform.Allowlist = pV1AllowlistTransform
+	var pV1ConditionStatusTransform *ConditionStatusTransform
+	if source.ConditionStatus != nil {
+		v1ConditionStatusTransform := c.v1ConditionStatusTransformToV1ConditionStatusTransform(*source.ConditionStatus)
+		pV1ConditionStatusTransform = &v1ConditionStatusTransform
+	}
+	v1Transform.ConditionStatus = pV1ConditionStatusTransform
+	var pV1FieldSelectTransform *FieldSelectTransform
+	if source.FieldSelect != nil {
+		v1FieldSelectTransform := c.v1FieldSelectTransformToV1FieldSelectTransform(*source.FieldSelect)
+		pV1FieldSelectTransform = &v1FieldSelectTransform
+	}
+	v1Transform.FieldSelect = pV1FieldSelectTransform
+	var pV1ValidateFormatTransform *ValidateFormatTransform
+	if source.ValidateFormat != nil {
+		v1ValidateFormatTransform := c.v1ValidateFormatTransformToV1ValidateFormatTransform(*source.ValidateFormat)
+		pV1ValidateFormatTransform = &v1ValidateFormatTransform
+	}
+	v1Transform.ValidateFormat = pV1ValidateFormatTransform
+	var pV1StableSuffixTransform *StableSuffixTransform
+	if source.StableSuffix != nil {
+		v1StableSuffixTransform := c.v1StableSuffixTransformToV1StableSuffixTransform(*source.StableSuffix)
+		pV1StableSuffixTransform = &v1StableSuffixTransform
+	}
+	v1Transform.StableSuffix = pV1StableSuffixTransform
+	var pV1SelectMatchTransform *SelectMatchTransform
+	if source.SelectMatch != nil {
+		v1SelectMatchTransform := c.v1SelectMatchTransformToV1SelectMatchTransform(*source.SelectMatch)
+		pV1SelectMatchTransform = &v1SelectMatchTransform
+	}
+	v1Transform.SelectMatch = pV1SelectMatchTransform
+	var pV1LabelSelectorTransform *LabelSelectorTransform
+	if source.LabelSelector != nil {
+		v1LabelSelectorTransform := c.v1LabelSelectorTransformToV1LabelSelectorTransform(*source.LabelSelector)
+		pV1LabelSelectorTransform = &v1LabelSelectorTransform
+	}
+	v1Transform.LabelSelector = pV1LabelSelectorTransform
+	var pV1CaseTransform *CaseTransform
+	if source.Case != nil {
+		v1CaseTransform := c.v1CaseTransformToV1CaseTransform(*source.Case)
+		pV1CaseTransform = &v1CaseTransform
+	}
+	v1Transform.Case = pV1CaseTransform
+	var pV1DedupTransform *DedupTransform
+	if source.Dedup != nil {
+		v1DedupTransform := c.v1DedupTransformToV1DedupTransform(*source.Dedup)
+		pV1DedupTransform = &v1DedupTransform
+	}
+	v1Transform.Dedup = pV1DedupTransform
+	var pV1SemverTransform *SemverTransform
+	if source.Semver != nil {
+		v1SemverTransform := c.v1SemverTransformToV1SemverTransform(*source.Semver)
+		pV1SemverTransform = &v1SemverTransform
+	}
+	v1Transform.Semver = pV1SemverTransform
+	var pV1TransformOnErrorPolicy *TransformOnErrorPolicy
+	if source.OnError != nil {
+		v1TransformOnErrorPolicy := TransformOnErrorPolicy(*source.OnError)
+		pV1TransformOnErrorPolicy = &v1TransformOnErrorPolicy
+	}
+	v1Transform.OnError = pV1TransformOnErrorPolicy
+	var pV1TransformCondition *TransformCondition
+	if source.When != nil {
+		v1TransformCondition := c.v1TransformConditionToV1TransformCondition(*source.When)
+		pV1TransformCondition = &v1TransformCondition
+	}
+	v1Transform.When = pV1TransformCondition
 	return v1Transform
 }
+func (c *GeneratedRevisionSpecConverter) v1TruncateTransformToV1TruncateTransform(source TruncateTransform) TruncateTransform {
+	var v1TruncateTransform TruncateTransform
+	v1TruncateTransform.MaxLength = source.MaxLength
+	v1TruncateTransform.HashSuffix = source.HashSuffix
+	return v1TruncateTransform
+}
 func (c *GeneratedRevisionSpecConverter) v1TypeReferenceToV1TypeReference(source TypeReference) TypeReference {
 	var v1TypeReference TypeReference
 	v1TypeReference.APIVersion = source.APIVersion
 	v1TypeReference.Kind = source.Kind
 	return v1TypeReference
 }
+func (c *GeneratedRevisionSpecConverter) v1ValidateFormatTransformToV1ValidateFormatTransform(source ValidateFormatTransform) ValidateFormatTransform {
+	var v1ValidateFormatTransform ValidateFormatTransform
+	v1ValidateFormatTransform.Format = ValidateFormat(source.Format)
+	return v1ValidateFormatTransform
+}
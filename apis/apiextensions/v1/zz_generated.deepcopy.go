@@ -29,19 +29,147 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AggregateTransform) DeepCopyInto(out *AggregateTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregateTransform.
+func (in *AggregateTransform) DeepCopy() *AggregateTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(AggregateTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowlistTransform) DeepCopyInto(out *AllowlistTransform) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowlistTransform.
+func (in *AllowlistTransform) DeepCopy() *AllowlistTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowlistTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRMatchTransform) DeepCopyInto(out *CIDRMatchTransform) {
+	*out = *in
+	if in.Blocks != nil {
+		in, out := &in.Blocks, &out.Blocks
+		*out = make([]CIDRMatchTransformBlock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.FallbackValue.DeepCopyInto(&out.FallbackValue)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRMatchTransform.
+func (in *CIDRMatchTransform) DeepCopy() *CIDRMatchTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRMatchTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CIDRMatchTransformBlock) DeepCopyInto(out *CIDRMatchTransformBlock) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CIDRMatchTransformBlock.
+func (in *CIDRMatchTransformBlock) DeepCopy() *CIDRMatchTransformBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(CIDRMatchTransformBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CaseTransform) DeepCopyInto(out *CaseTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CaseTransform.
+func (in *CaseTransform) DeepCopy() *CaseTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(CaseTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoalesceCombine) DeepCopyInto(out *CoalesceCombine) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoalesceCombine.
+func (in *CoalesceCombine) DeepCopy() *CoalesceCombine {
+	if in == nil {
+		return nil
+	}
+	out := new(CoalesceCombine)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Combine) DeepCopyInto(out *Combine) {
 	*out = *in
 	if in.Variables != nil {
 		in, out := &in.Variables, &out.Variables
 		*out = make([]CombineVariable, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.String != nil {
 		in, out := &in.String, &out.String
 		*out = new(StringCombine)
 		**out = **in
 	}
+	if in.Coalesce != nil {
+		in, out := &in.Coalesce, &out.Coalesce
+		*out = new(CoalesceCombine)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Math != nil {
+		in, out := &in.Math, &out.Math
+		*out = new(MathCombine)
+		**out = **in
+	}
+	if in.Set != nil {
+		in, out := &in.Set, &out.Set
+		*out = new(SetCombine)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Combine.
@@ -57,6 +185,18 @@ func (in *Combine) DeepCopy() *Combine {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CombineVariable) DeepCopyInto(out *CombineVariable) {
 	*out = *in
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]Transform, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(FromFieldPathPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CombineVariable.
@@ -69,6 +209,31 @@ func (in *CombineVariable) DeepCopy() *CombineVariable {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedResourceSelector) DeepCopyInto(out *ComposedResourceSelector) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedResourceSelector.
+func (in *ComposedResourceSelector) DeepCopy() *ComposedResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 	*out = *in
@@ -454,6 +619,13 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TransformSets != nil {
+		in, out := &in.TransformSets, &out.TransformSets
+		*out = make([]TransformSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Environment != nil {
 		in, out := &in.Environment, &out.Environment
 		*out = new(EnvironmentConfiguration)
@@ -466,6 +638,11 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PatchErrorPolicy != nil {
+		in, out := &in.PatchErrorPolicy, &out.PatchErrorPolicy
+		*out = new(PatchErrorPolicy)
+		**out = **in
+	}
 	if in.Functions != nil {
 		in, out := &in.Functions, &out.Functions
 		*out = make([]Function, len(*in))
@@ -522,6 +699,13 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TransformSets != nil {
+		in, out := &in.TransformSets, &out.TransformSets
+		*out = make([]TransformSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Environment != nil {
 		in, out := &in.Environment, &out.Environment
 		*out = new(EnvironmentConfiguration)
@@ -534,6 +718,11 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PatchErrorPolicy != nil {
+		in, out := &in.PatchErrorPolicy, &out.PatchErrorPolicy
+		*out = new(PatchErrorPolicy)
+		**out = **in
+	}
 	if in.Functions != nil {
 		in, out := &in.Functions, &out.Functions
 		*out = make([]Function, len(*in))
@@ -563,6 +752,41 @@ func (in *CompositionSpec) DeepCopy() *CompositionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionStatusTransform) DeepCopyInto(out *ConditionStatusTransform) {
+	*out = *in
+	if in.True != nil {
+		in, out := &in.True, &out.True
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.False != nil {
+		in, out := &in.False, &out.False
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Unknown != nil {
+		in, out := &in.Unknown, &out.Unknown
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionStatusTransform.
+func (in *ConditionStatusTransform) DeepCopy() *ConditionStatusTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionStatusTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
 	*out = *in
@@ -748,6 +972,46 @@ func (in *ConvertTransform) DeepCopy() *ConvertTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DedupTransform) DeepCopyInto(out *DedupTransform) {
+	*out = *in
+	if in.KeyFieldPath != nil {
+		in, out := &in.KeyFieldPath, &out.KeyFieldPath
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DedupTransform.
+func (in *DedupTransform) DeepCopy() *DedupTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(DedupTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedJSONPolicy) DeepCopyInto(out *EmbeddedJSONPolicy) {
+	*out = *in
+	if in.InitializeInvalid != nil {
+		in, out := &in.InitializeInvalid, &out.InitializeInvalid
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedJSONPolicy.
+func (in *EmbeddedJSONPolicy) DeepCopy() *EmbeddedJSONPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddedJSONPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentConfiguration) DeepCopyInto(out *EnvironmentConfiguration) {
 	*out = *in
@@ -795,6 +1059,11 @@ func (in *EnvironmentPatch) DeepCopyInto(out *EnvironmentPatch) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ToFieldPaths != nil {
+		in, out := &in.ToFieldPaths, &out.ToFieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Transforms != nil {
 		in, out := &in.Transforms, &out.Transforms
 		*out = make([]Transform, len(*in))
@@ -906,6 +1175,26 @@ func (in *EnvironmentSourceSelectorLabelMatcher) DeepCopy() *EnvironmentSourceSe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldSelectTransform) DeepCopyInto(out *FieldSelectTransform) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(FromFieldPathPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldSelectTransform.
+func (in *FieldSelectTransform) DeepCopy() *FieldSelectTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldSelectTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Function) DeepCopyInto(out *Function) {
 	*out = *in
@@ -946,6 +1235,21 @@ func (in *GeneratedRevisionSpecConverter) DeepCopy() *GeneratedRevisionSpecConve
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelSelectorTransform) DeepCopyInto(out *LabelSelectorTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelSelectorTransform.
+func (in *LabelSelectorTransform) DeepCopy() *LabelSelectorTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelSelectorTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MapTransform) DeepCopyInto(out *MapTransform) {
 	*out = *in
@@ -968,6 +1272,21 @@ func (in *MapTransform) DeepCopy() *MapTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MapTransformOptions) DeepCopyInto(out *MapTransformOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapTransformOptions.
+func (in *MapTransformOptions) DeepCopy() *MapTransformOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MapTransformOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchTransform) DeepCopyInto(out *MatchTransform) {
 	*out = *in
@@ -1017,6 +1336,21 @@ func (in *MatchTransformPattern) DeepCopy() *MatchTransformPattern {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MathCombine) DeepCopyInto(out *MathCombine) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MathCombine.
+func (in *MathCombine) DeepCopy() *MathCombine {
+	if in == nil {
+		return nil
+	}
+	out := new(MathCombine)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MathTransform) DeepCopyInto(out *MathTransform) {
 	*out = *in
@@ -1035,6 +1369,16 @@ func (in *MathTransform) DeepCopyInto(out *MathTransform) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Modulo != nil {
+		in, out := &in.Modulo, &out.Modulo
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MathTransform.
@@ -1048,33 +1392,118 @@ func (in *MathTransform) DeepCopy() *MathTransform {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Patch) DeepCopyInto(out *Patch) {
+func (in *NumberFormatTransform) DeepCopyInto(out *NumberFormatTransform) {
 	*out = *in
-	if in.FromFieldPath != nil {
-		in, out := &in.FromFieldPath, &out.FromFieldPath
+	if in.ThousandsSeparator != nil {
+		in, out := &in.ThousandsSeparator, &out.ThousandsSeparator
 		*out = new(string)
 		**out = **in
 	}
-	if in.Combine != nil {
-		in, out := &in.Combine, &out.Combine
-		*out = new(Combine)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ToFieldPath != nil {
-		in, out := &in.ToFieldPath, &out.ToFieldPath
+	if in.DecimalSeparator != nil {
+		in, out := &in.DecimalSeparator, &out.DecimalSeparator
 		*out = new(string)
 		**out = **in
 	}
-	if in.PatchSetName != nil {
-		in, out := &in.PatchSetName, &out.PatchSetName
-		*out = new(string)
+	if in.DecimalPlaces != nil {
+		in, out := &in.DecimalPlaces, &out.DecimalPlaces
+		*out = new(int)
 		**out = **in
 	}
-	if in.Transforms != nil {
-		in, out := &in.Transforms, &out.Transforms
-		*out = make([]Transform, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NumberFormatTransform.
+func (in *NumberFormatTransform) DeepCopy() *NumberFormatTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(NumberFormatTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PEMTransform) DeepCopyInto(out *PEMTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PEMTransform.
+func (in *PEMTransform) DeepCopy() *PEMTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(PEMTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Patch) DeepCopyInto(out *Patch) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int)
+		**out = **in
+	}
+	if in.FromFieldPath != nil {
+		in, out := &in.FromFieldPath, &out.FromFieldPath
+		*out = new(string)
+		**out = **in
+	}
+	if in.FromComposedResource != nil {
+		in, out := &in.FromComposedResource, &out.FromComposedResource
+		*out = new(ComposedResourceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionSecretKey != nil {
+		in, out := &in.ConnectionSecretKey, &out.ConnectionSecretKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.Combine != nil {
+		in, out := &in.Combine, &out.Combine
+		*out = new(Combine)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ToFieldPath != nil {
+		in, out := &in.ToFieldPath, &out.ToFieldPath
+		*out = new(string)
+		**out = **in
+	}
+	if in.ToFieldPaths != nil {
+		in, out := &in.ToFieldPaths, &out.ToFieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkipWhenValue != nil {
+		in, out := &in.SkipWhenValue, &out.SkipWhenValue
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpectedType != nil {
+		in, out := &in.ExpectedType, &out.ExpectedType
+		*out = new(TransformIOType)
+		**out = **in
+	}
+	if in.PatchSetName != nil {
+		in, out := &in.PatchSetName, &out.PatchSetName
+		*out = new(string)
+		**out = **in
+	}
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(PatchCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]Transform, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Policy != nil {
@@ -1082,6 +1511,11 @@ func (in *Patch) DeepCopyInto(out *Patch) {
 		*out = new(PatchPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Patch.
@@ -1094,6 +1528,26 @@ func (in *Patch) DeepCopy() *Patch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchCondition) DeepCopyInto(out *PatchCondition) {
+	*out = *in
+	if in.Equals != nil {
+		in, out := &in.Equals, &out.Equals
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchCondition.
+func (in *PatchCondition) DeepCopy() *PatchCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatchPolicy) DeepCopyInto(out *PatchPolicy) {
 	*out = *in
@@ -1107,6 +1561,41 @@ func (in *PatchPolicy) DeepCopyInto(out *PatchPolicy) {
 		*out = new(commonv1.MergeOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FromFieldPathDefault != nil {
+		in, out := &in.FromFieldPathDefault, &out.FromFieldPathDefault
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TransformFromFieldPathDefault != nil {
+		in, out := &in.TransformFromFieldPathDefault, &out.TransformFromFieldPathDefault
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImmutableAfterCreate != nil {
+		in, out := &in.ImmutableAfterCreate, &out.ImmutableAfterCreate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipWhenDeleting != nil {
+		in, out := &in.SkipWhenDeleting, &out.SkipWhenDeleting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MergeConditions != nil {
+		in, out := &in.MergeConditions, &out.MergeConditions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StrategicMerge != nil {
+		in, out := &in.StrategicMerge, &out.StrategicMerge
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ToEmbeddedJSON != nil {
+		in, out := &in.ToEmbeddedJSON, &out.ToEmbeddedJSON
+		*out = new(EmbeddedJSONPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchPolicy.
@@ -1141,6 +1630,45 @@ func (in *PatchSet) DeepCopy() *PatchSet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RangeTransform) DeepCopyInto(out *RangeTransform) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]RangeTransformBucket, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.FallbackValue.DeepCopyInto(&out.FallbackValue)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RangeTransform.
+func (in *RangeTransform) DeepCopy() *RangeTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(RangeTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RangeTransformBucket) DeepCopyInto(out *RangeTransformBucket) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RangeTransformBucket.
+func (in *RangeTransformBucket) DeepCopy() *RangeTransformBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(RangeTransformBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
 	*out = *in
@@ -1156,6 +1684,82 @@ func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectMatchTransform) DeepCopyInto(out *SelectMatchTransform) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+	if in.ReturnField != nil {
+		in, out := &in.ReturnField, &out.ReturnField
+		*out = new(string)
+		**out = **in
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(FromFieldPathPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectMatchTransform.
+func (in *SelectMatchTransform) DeepCopy() *SelectMatchTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectMatchTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SemverTransform) DeepCopyInto(out *SemverTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SemverTransform.
+func (in *SemverTransform) DeepCopy() *SemverTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(SemverTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetCombine) DeepCopyInto(out *SetCombine) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetCombine.
+func (in *SetCombine) DeepCopy() *SetCombine {
+	if in == nil {
+		return nil
+	}
+	out := new(SetCombine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StableSuffixTransform) DeepCopyInto(out *StableSuffixTransform) {
+	*out = *in
+	if in.Charset != nil {
+		in, out := &in.Charset, &out.Charset
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StableSuffixTransform.
+func (in *StableSuffixTransform) DeepCopy() *StableSuffixTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(StableSuffixTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreConfigReference) DeepCopyInto(out *StoreConfigReference) {
 	*out = *in
@@ -1209,6 +1813,31 @@ func (in *StringTransform) DeepCopyInto(out *StringTransform) {
 		*out = new(StringTransformRegexp)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Replace != nil {
+		in, out := &in.Replace, &out.Replace
+		*out = new(StringTransformReplace)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slice != nil {
+		in, out := &in.Slice, &out.Slice
+		*out = new(StringTransformSlice)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Escape != nil {
+		in, out := &in.Escape, &out.Escape
+		*out = new(StringTransformEscape)
+		**out = **in
+	}
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.Suffix != nil {
+		in, out := &in.Suffix, &out.Suffix
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransform.
@@ -1221,6 +1850,21 @@ func (in *StringTransform) DeepCopy() *StringTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringTransformEscape) DeepCopyInto(out *StringTransformEscape) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransformEscape.
+func (in *StringTransformEscape) DeepCopy() *StringTransformEscape {
+	if in == nil {
+		return nil
+	}
+	out := new(StringTransformEscape)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StringTransformRegexp) DeepCopyInto(out *StringTransformRegexp) {
 	*out = *in
@@ -1241,9 +1885,71 @@ func (in *StringTransformRegexp) DeepCopy() *StringTransformRegexp {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringTransformReplace) DeepCopyInto(out *StringTransformReplace) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransformReplace.
+func (in *StringTransformReplace) DeepCopy() *StringTransformReplace {
+	if in == nil {
+		return nil
+	}
+	out := new(StringTransformReplace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringTransformSlice) DeepCopyInto(out *StringTransformSlice) {
+	*out = *in
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransformSlice.
+func (in *StringTransformSlice) DeepCopy() *StringTransformSlice {
+	if in == nil {
+		return nil
+	}
+	out := new(StringTransformSlice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TernaryTransform) DeepCopyInto(out *TernaryTransform) {
+	*out = *in
+	in.True.DeepCopyInto(&out.True)
+	in.False.DeepCopyInto(&out.False)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TernaryTransform.
+func (in *TernaryTransform) DeepCopy() *TernaryTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(TernaryTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Transform) DeepCopyInto(out *Transform) {
 	*out = *in
+	if in.TransformSetName != nil {
+		in, out := &in.TransformSetName, &out.TransformSetName
+		*out = new(string)
+		**out = **in
+	}
 	if in.Math != nil {
 		in, out := &in.Math, &out.Math
 		*out = new(MathTransform)
@@ -1254,6 +1960,11 @@ func (in *Transform) DeepCopyInto(out *Transform) {
 		*out = new(MapTransform)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MapOptions != nil {
+		in, out := &in.MapOptions, &out.MapOptions
+		*out = new(MapTransformOptions)
+		**out = **in
+	}
 	if in.Match != nil {
 		in, out := &in.Match, &out.Match
 		*out = new(MatchTransform)
@@ -1269,6 +1980,101 @@ func (in *Transform) DeepCopyInto(out *Transform) {
 		*out = new(ConvertTransform)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Range != nil {
+		in, out := &in.Range, &out.Range
+		*out = new(RangeTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Aggregate != nil {
+		in, out := &in.Aggregate, &out.Aggregate
+		*out = new(AggregateTransform)
+		**out = **in
+	}
+	if in.Ternary != nil {
+		in, out := &in.Ternary, &out.Ternary
+		*out = new(TernaryTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Truncate != nil {
+		in, out := &in.Truncate, &out.Truncate
+		*out = new(TruncateTransform)
+		**out = **in
+	}
+	if in.NumberFormat != nil {
+		in, out := &in.NumberFormat, &out.NumberFormat
+		*out = new(NumberFormatTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CIDRMatch != nil {
+		in, out := &in.CIDRMatch, &out.CIDRMatch
+		*out = new(CIDRMatchTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PEM != nil {
+		in, out := &in.PEM, &out.PEM
+		*out = new(PEMTransform)
+		**out = **in
+	}
+	if in.Allowlist != nil {
+		in, out := &in.Allowlist, &out.Allowlist
+		*out = new(AllowlistTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConditionStatus != nil {
+		in, out := &in.ConditionStatus, &out.ConditionStatus
+		*out = new(ConditionStatusTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FieldSelect != nil {
+		in, out := &in.FieldSelect, &out.FieldSelect
+		*out = new(FieldSelectTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ValidateFormat != nil {
+		in, out := &in.ValidateFormat, &out.ValidateFormat
+		*out = new(ValidateFormatTransform)
+		**out = **in
+	}
+	if in.StableSuffix != nil {
+		in, out := &in.StableSuffix, &out.StableSuffix
+		*out = new(StableSuffixTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SelectMatch != nil {
+		in, out := &in.SelectMatch, &out.SelectMatch
+		*out = new(SelectMatchTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(LabelSelectorTransform)
+		**out = **in
+	}
+	if in.Case != nil {
+		in, out := &in.Case, &out.Case
+		*out = new(CaseTransform)
+		**out = **in
+	}
+	if in.Dedup != nil {
+		in, out := &in.Dedup, &out.Dedup
+		*out = new(DedupTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Semver != nil {
+		in, out := &in.Semver, &out.Semver
+		*out = new(SemverTransform)
+		**out = **in
+	}
+	if in.OnError != nil {
+		in, out := &in.OnError, &out.OnError
+		*out = new(TransformOnErrorPolicy)
+		**out = **in
+	}
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(TransformCondition)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Transform.
@@ -1281,6 +2087,68 @@ func (in *Transform) DeepCopy() *Transform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransformCondition) DeepCopyInto(out *TransformCondition) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Regexp != nil {
+		in, out := &in.Regexp, &out.Regexp
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransformCondition.
+func (in *TransformCondition) DeepCopy() *TransformCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(TransformCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransformSet) DeepCopyInto(out *TransformSet) {
+	*out = *in
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]Transform, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransformSet.
+func (in *TransformSet) DeepCopy() *TransformSet {
+	if in == nil {
+		return nil
+	}
+	out := new(TransformSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TruncateTransform) DeepCopyInto(out *TruncateTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TruncateTransform.
+func (in *TruncateTransform) DeepCopy() *TruncateTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(TruncateTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TypeReference) DeepCopyInto(out *TypeReference) {
 	*out = *in
@@ -1295,3 +2163,18 @@ func (in *TypeReference) DeepCopy() *TypeReference {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidateFormatTransform) DeepCopyInto(out *ValidateFormatTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidateFormatTransform.
+func (in *ValidateFormatTransform) DeepCopy() *ValidateFormatTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidateFormatTransform)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -59,6 +59,21 @@ type PatchSet struct {
 	Patches []Patch `json:"patches"`
 }
 
+// A TransformSet is a chain of transforms that can be reused by all patches
+// within a Composition.
+type TransformSet struct {
+	// Name of this TransformSet.
+	Name string `json:"name"`
+
+	// Transforms are applied in order, as though they were the transforms
+	// of each patch that references this TransformSet. They are validated by
+	// the Composition webhook rather than by the CRD's schema, which would
+	// otherwise grow too large.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Transforms []Transform `json:"transforms"`
+}
+
 // ComposedTemplate is used to provide information about how the composed resource
 // should be processed.
 type ComposedTemplate struct {
@@ -69,7 +84,8 @@ type ComposedTemplate struct {
 	// the resources array are named entries may added, deleted, and reordered
 	// as long as their names do not change. When entries are not named the
 	// length and order of the resources array should be treated as immutable.
-	// Either all or no entries must be named.
+	// Either all or no entries must be named. A FromComposedFieldPath patch
+	// may select the entry it reads from by name.
 	// +optional
 	Name *string `json:"name,omitempty"`
 
@@ -376,3 +392,20 @@ type StoreConfigReference struct {
 	// Name of the referenced StoreConfig.
 	Name string `json:"name"`
 }
+
+// A PatchErrorPolicy determines what happens when a composed resource can't be
+// rendered because one of its patches returned an error.
+type PatchErrorPolicy string
+
+// The possible values for a patch error policy.
+const (
+	// PatchErrorPolicyContinue skips composed resources that can't be
+	// rendered and continues to compose the others. A Warning event is
+	// emitted for each resource that is skipped.
+	PatchErrorPolicyContinue PatchErrorPolicy = "continue"
+
+	// PatchErrorPolicyAbort stops composing resources when any composed
+	// resource can't be rendered, and returns the errors for all resources
+	// that couldn't be rendered.
+	PatchErrorPolicyAbort PatchErrorPolicy = "abort"
+)
@@ -19,11 +19,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 
 	verrors "github.com/crossplane/crossplane/internal/validation/errors"
 )
@@ -33,17 +37,42 @@ type PatchType string
 
 // Patch types.
 const (
-	PatchTypeFromCompositeFieldPath   PatchType = "FromCompositeFieldPath" // Default
-	PatchTypeFromEnvironmentFieldPath PatchType = "FromEnvironmentFieldPath"
-	PatchTypePatchSet                 PatchType = "PatchSet"
-	PatchTypeToCompositeFieldPath     PatchType = "ToCompositeFieldPath"
-	PatchTypeToEnvironmentFieldPath   PatchType = "ToEnvironmentFieldPath"
-	PatchTypeCombineFromEnvironment   PatchType = "CombineFromEnvironment"
-	PatchTypeCombineFromComposite     PatchType = "CombineFromComposite"
-	PatchTypeCombineToComposite       PatchType = "CombineToComposite"
-	PatchTypeCombineToEnvironment     PatchType = "CombineToEnvironment"
+	PatchTypeFromCompositeFieldPath          PatchType = "FromCompositeFieldPath" // Default
+	PatchTypeFromEnvironmentFieldPath        PatchType = "FromEnvironmentFieldPath"
+	PatchTypePatchSet                        PatchType = "PatchSet"
+	PatchTypeToCompositeFieldPath            PatchType = "ToCompositeFieldPath"
+	PatchTypeToEnvironmentFieldPath          PatchType = "ToEnvironmentFieldPath"
+	PatchTypeCombineFromEnvironment          PatchType = "CombineFromEnvironment"
+	PatchTypeCombineFromComposite            PatchType = "CombineFromComposite"
+	PatchTypeCombineToComposite              PatchType = "CombineToComposite"
+	PatchTypeCombineToEnvironment            PatchType = "CombineToEnvironment"
+	PatchTypeNoop                            PatchType = "Noop"
+	PatchTypeFromComposedFieldPath           PatchType = "FromComposedFieldPath"
+	PatchTypeFromControllerConfig            PatchType = "FromControllerConfig"
+	PatchTypeFromConnectionSecretKey         PatchType = "FromConnectionSecretKey"
+	PatchTypeFromComposedConnectionSecretKey PatchType = "FromComposedConnectionSecretKey"
 )
 
+// ValidPatchTypes returns the list of valid patch types.
+func ValidPatchTypes() []PatchType {
+	return []PatchType{
+		PatchTypeFromCompositeFieldPath,
+		PatchTypeFromEnvironmentFieldPath,
+		PatchTypePatchSet,
+		PatchTypeToCompositeFieldPath,
+		PatchTypeToEnvironmentFieldPath,
+		PatchTypeCombineFromEnvironment,
+		PatchTypeCombineFromComposite,
+		PatchTypeCombineToComposite,
+		PatchTypeCombineToEnvironment,
+		PatchTypeNoop,
+		PatchTypeFromComposedFieldPath,
+		PatchTypeFromControllerConfig,
+		PatchTypeFromConnectionSecretKey,
+		PatchTypeFromComposedConnectionSecretKey,
+	}
+}
+
 // A FromFieldPathPolicy determines how to patch from a field path.
 type FromFieldPathPolicy string
 
@@ -63,6 +92,107 @@ type PatchPolicy struct {
 	// +optional
 	FromFieldPath *FromFieldPathPolicy `json:"fromFieldPath,omitempty"`
 	MergeOptions  *xpv1.MergeOptions   `json:"mergeOptions,omitempty"`
+
+	// FromFieldPathDefault is the value to patch if the fromFieldPath does
+	// not exist, for example a placeholder for a status field of a composed
+	// resource that has yet to be populated. The default is patched as is
+	// unless transformFromFieldPathDefault is true. It may only be set if the
+	// fromFieldPath policy is 'Optional', and only for patch types that read
+	// a fromFieldPath.
+	// +optional
+	FromFieldPathDefault *extv1.JSON `json:"fromFieldPathDefault,omitempty"`
+
+	// TransformFromFieldPathDefault applies the patch's transforms to the
+	// fromFieldPathDefault, as though it were the value of the
+	// fromFieldPath. For example a patch that increments a counter with a
+	// math transform may use a fromFieldPathDefault of 0 to patch 1 when
+	// the counter does not yet exist. The default is false, which means the
+	// fromFieldPathDefault is patched as is.
+	// +optional
+	TransformFromFieldPathDefault *bool `json:"transformFromFieldPathDefault,omitempty"`
+
+	// ImmutableAfterCreate specifies that a patch to a composed resource
+	// should only be applied until the composed resource is created. Once
+	// it exists the patch is skipped, so the field it patches keeps the
+	// value it was created with. The default is false, which means the
+	// patch is applied every time the composite resource is reconciled.
+	// +optional
+	ImmutableAfterCreate *bool `json:"immutableAfterCreate,omitempty"`
+
+	// SkipWhenDeleting specifies that a patch should not be applied while
+	// the composite resource is being deleted, i.e. once it has a deletion
+	// timestamp. This avoids patching fields of composed resources that are
+	// about to be deleted. The default is false, which means the patch is
+	// applied regardless of whether the composite resource is being
+	// deleted.
+	// +optional
+	SkipWhenDeleting *bool `json:"skipWhenDeleting,omitempty"`
+
+	// MergeConditions merges an array of conditions, for example the
+	// status.conditions of a composed resource, into the array of
+	// conditions at the toFieldPath by condition type, rather than
+	// replacing the whole array. A condition replaces the existing
+	// condition of the same type, but keeps its lastTransitionTime unless
+	// its status changed. Existing conditions of other types are kept. It
+	// may not be combined with mergeOptions, and is only supported for
+	// patch types that read a fromFieldPath.
+	// +optional
+	MergeConditions *bool `json:"mergeConditions,omitempty"`
+
+	// StrategicMerge merges an object into the object at the toFieldPath
+	// using Kubernetes strategic merge semantics, for example merging the
+	// containers of a Deployment manifest by name rather than replacing
+	// them. Merge keys are looked up using the apiVersion and kind of the
+	// object at the toFieldPath, or of the patched object if it has none.
+	// Objects of kinds that aren't built in to Kubernetes are merged like a
+	// JSON merge patch: objects are merged recursively, and any other value,
+	// including an array, replaces the existing value. A null value removes
+	// a field. It may not be combined with mergeOptions or mergeConditions,
+	// and is only supported for patch types that read a fromFieldPath.
+	// +optional
+	StrategicMerge *bool `json:"strategicMerge,omitempty"`
+
+	// ToEmbeddedJSON patches a field of the JSON object that is encoded as
+	// a string at the toFieldPath, rather than the toFieldPath itself. This
+	// is useful for fields of composed resources that are opaque JSON
+	// documents, for example a policy document. The JSON object is decoded,
+	// patched, and encoded again. It may not be combined with
+	// mergeConditions or strategicMerge.
+	// +optional
+	ToEmbeddedJSON *EmbeddedJSONPolicy `json:"toEmbeddedJSON,omitempty"`
+}
+
+// An EmbeddedJSONPolicy configures a patch to a field of a JSON object that is
+// encoded as a string. A string that doesn't exist, is empty, or is the JSON
+// null value is treated as an empty object. The patched object is encoded
+// without insignificant whitespace, with its keys in sorted order.
+type EmbeddedJSONPolicy struct {
+	// FieldPath of the field to patch within the embedded JSON object, e.g.
+	// Statement[0].Resource.
+	FieldPath string `json:"fieldPath"`
+
+	// InitializeInvalid replaces a string that isn't a JSON object with an
+	// empty object before it's patched. The default is false, which means a
+	// patch to a string that isn't a JSON object returns an error.
+	// +optional
+	InitializeInvalid *bool `json:"initializeInvalid,omitempty"`
+}
+
+// IsInitializeInvalid returns true if a string that isn't a JSON object should
+// be replaced with an empty object before it's patched.
+func (ep *EmbeddedJSONPolicy) IsInitializeInvalid() bool {
+	return ep != nil && ep.InitializeInvalid != nil && *ep.InitializeInvalid
+}
+
+// Validate checks this EmbeddedJSONPolicy is valid.
+func (ep *EmbeddedJSONPolicy) Validate() *field.Error {
+	if ep.FieldPath == "" {
+		return field.Required(field.NewPath("fieldPath"), "fieldPath must be set")
+	}
+	if _, err := fieldpath.Parse(ep.FieldPath); err != nil {
+		return field.Invalid(field.NewPath("fieldPath"), ep.FieldPath, err.Error())
+	}
+	return nil
 }
 
 // GetFromFieldPathPolicy returns the FromFieldPathPolicy for this PatchPolicy, defaulting to FromFieldPathPolicyOptional if not specified.
@@ -73,24 +203,122 @@ func (pp *PatchPolicy) GetFromFieldPathPolicy() FromFieldPathPolicy {
 	return *pp.FromFieldPath
 }
 
+// GetFromFieldPathDefault returns the value to patch if the fromFieldPath does
+// not exist, or nil if there is none.
+func (pp *PatchPolicy) GetFromFieldPathDefault() *extv1.JSON {
+	if pp == nil {
+		return nil
+	}
+	return pp.FromFieldPathDefault
+}
+
+// IsTransformFromFieldPathDefault returns true if the patch's transforms should
+// be applied to its fromFieldPathDefault.
+func (pp *PatchPolicy) IsTransformFromFieldPathDefault() bool {
+	return pp != nil && pp.TransformFromFieldPathDefault != nil && *pp.TransformFromFieldPathDefault
+}
+
+// IsImmutableAfterCreate returns true if the patch should only be applied
+// until the composed resource it patches is created.
+func (pp *PatchPolicy) IsImmutableAfterCreate() bool {
+	return pp != nil && pp.ImmutableAfterCreate != nil && *pp.ImmutableAfterCreate
+}
+
+// IsSkipWhenDeleting returns true if the patch should not be applied while the
+// composite resource is being deleted.
+func (pp *PatchPolicy) IsSkipWhenDeleting() bool {
+	return pp != nil && pp.SkipWhenDeleting != nil && *pp.SkipWhenDeleting
+}
+
+// GetToEmbeddedJSON returns the policy of a patch to a JSON object embedded in
+// a string, or nil if the patch doesn't patch one.
+func (pp *PatchPolicy) GetToEmbeddedJSON() *EmbeddedJSONPolicy {
+	if pp == nil {
+		return nil
+	}
+	return pp.ToEmbeddedJSON
+}
+
+// IsMergeConditions returns true if the patch should merge the conditions it
+// patches into the existing conditions by type.
+func (pp *PatchPolicy) IsMergeConditions() bool {
+	return pp != nil && pp.MergeConditions != nil && *pp.MergeConditions
+}
+
+// IsStrategicMerge returns true if the patch should merge the object it
+// patches into the existing object using strategic merge semantics.
+func (pp *PatchPolicy) IsStrategicMerge() bool {
+	return pp != nil && pp.StrategicMerge != nil && *pp.StrategicMerge
+}
+
 // Patch objects are applied between composite and composed resources. Their
 // behaviour depends on the Type selected. The default Type,
 // FromCompositeFieldPath, copies a value from the composite resource to
 // the composed resource, applying any defined transformers.
 type Patch struct {
 	// Type sets the patching behaviour to be used. Each patch type may require
-	// its own fields to be set on the Patch object.
+	// its own fields to be set on the Patch object. A Noop patch does nothing,
+	// and may be used to document the structure of a Composition. A
+	// FromComposedFieldPath patch copies a value from another composed
+	// resource of the same Composition. A FromControllerConfig patch copies a
+	// value from the configuration the controller was started with, for
+	// example platform-wide defaults. Crossplane exposes each value passed to
+	// its --composition-config flag at the field path of its key. A
+	// FromConnectionSecretKey patch copies the value of a key of the composite
+	// resource's connection secret. A FromComposedConnectionSecretKey patch
+	// copies the value of a key of the connection details of another composed
+	// resource of the same Composition.
 	// +optional
-	// +kubebuilder:validation:Enum=FromCompositeFieldPath;FromEnvironmentFieldPath;PatchSet;ToCompositeFieldPath;ToEnvironmentFieldPath;CombineFromEnvironment;CombineFromComposite;CombineToComposite;CombineToEnvironment
+	// +kubebuilder:validation:Enum=FromCompositeFieldPath;FromEnvironmentFieldPath;PatchSet;ToCompositeFieldPath;ToEnvironmentFieldPath;CombineFromEnvironment;CombineFromComposite;CombineToComposite;CombineToEnvironment;Noop;FromComposedFieldPath;FromControllerConfig;FromConnectionSecretKey;FromComposedConnectionSecretKey
 	// +kubebuilder:default=FromCompositeFieldPath
 	Type PatchType `json:"type,omitempty"`
 
+	// Description is a human-readable description of the intent of this
+	// patch, for example to document a Composition with a Noop patch. It is
+	// ignored when the patch is applied, but is kept when PatchSets are
+	// inlined and is reported by tooling such as linters.
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// Priority determines the order in which the patches of a composed
+	// resource template are applied. Patches are applied in ascending order
+	// of priority, so a patch overrides any patch with a lower priority that
+	// writes to the same field path. Patches with the same priority are
+	// applied in the order they are specified. A patch without a priority has
+	// priority 0. Patches of a PatchSet that don't specify a priority inherit
+	// the priority of the PatchSet patch that includes them. Priority only
+	// orders patches within the phase in which they are applied to the
+	// composed resource. Patches that read from the composite resource, the
+	// environment, or the controller's configuration are applied first, then
+	// FromConnectionSecretKey patches, then patches that read from other
+	// composed resources. A Composition is invalid if a patch would be
+	// overridden by a patch of a later phase that has a lower priority.
+	// +optional
+	Priority *int `json:"priority,omitempty"`
+
 	// FromFieldPath is the path of the field on the resource whose value is
 	// to be used as input. Required when type is FromCompositeFieldPath,
-	// FromEnvironmentFieldPath, ToCompositeFieldPath, ToEnvironmentFieldPath.
+	// FromEnvironmentFieldPath, ToCompositeFieldPath, ToEnvironmentFieldPath,
+	// FromComposedFieldPath, FromControllerConfig. A key that contains dots,
+	// such as an annotation key, must be enclosed in brackets and may be
+	// quoted, e.g. metadata.annotations['example.org/my.key'].
 	// +optional
 	FromFieldPath *string `json:"fromFieldPath,omitempty"`
 
+	// FromComposedResource selects the composed resource whose FromFieldPath
+	// or connection secret key is to be used as input. Required when type is
+	// FromComposedFieldPath or FromComposedConnectionSecretKey. A
+	// FromComposedConnectionSecretKey patch must select the composed resource
+	// by name.
+	// +optional
+	FromComposedResource *ComposedResourceSelector `json:"fromComposedResource,omitempty"`
+
+	// ConnectionSecretKey is the key of the connection secret whose value is
+	// to be used as input. Required when type is FromConnectionSecretKey or
+	// FromComposedConnectionSecretKey.
+	// +optional
+	ConnectionSecretKey *string `json:"connectionSecretKey,omitempty"`
+
 	// Combine is the patch configuration for a CombineFromComposite,
 	// CombineFromEnvironment, CombineToComposite or CombineToEnvironment patch.
 	// +optional
@@ -98,14 +326,52 @@ type Patch struct {
 
 	// ToFieldPath is the path of the field on the resource whose value will
 	// be changed with the result of transforms. Leave empty if you'd like to
-	// propagate to the same path as fromFieldPath.
+	// propagate to the same path as fromFieldPath. A key segment may include
+	// templates of the form {{ path }}, which are replaced with the string
+	// value at that path of the patch's source resource before the value is
+	// written, e.g. metadata.annotations[example.org/name-{{ spec.region }}].
+	// As for FromFieldPath, a key that contains dots must be enclosed in
+	// brackets.
 	// +optional
 	ToFieldPath *string `json:"toFieldPath,omitempty"`
 
+	// ToFieldPaths are additional paths of fields on the resource whose
+	// values will be changed with the result of transforms. The value is
+	// read and transformed once, then written to the ToFieldPath, if any,
+	// and to each of these field paths in order. Each write is independent;
+	// failing to write to one field path doesn't prevent writing to the
+	// others. A patch with ToFieldPaths doesn't default its ToFieldPath to
+	// its FromFieldPath. Not supported when type is PatchSet or Noop.
+	// +optional
+	ToFieldPaths []string `json:"toFieldPaths,omitempty"`
+
+	// SkipWhenValue is a value that causes the patch to be skipped. If the
+	// value to be patched equals it after transforms are applied, nothing is
+	// written to the ToFieldPath. For example an empty string may be used to
+	// avoid clearing a default set by a provider. Not supported when type is
+	// PatchSet or Noop.
+	// +optional
+	SkipWhenValue *extv1.JSON `json:"skipWhenValue,omitempty"`
+
+	// ExpectedType is the type the value to be patched must have after
+	// transforms are applied. If set, a patch whose value is of a different
+	// type returns an error rather than writing the value. Any integer type
+	// satisfies an expected integer type, and any number satisfies an
+	// expected float64. Not supported when type is PatchSet or Noop.
+	// +kubebuilder:validation:Enum=string;int;int64;int32;int16;bool;float64
+	// +optional
+	ExpectedType *TransformIOType `json:"expectedType,omitempty"`
+
 	// PatchSetName to include patches from. Required when type is PatchSet.
 	// +optional
 	PatchSetName *string `json:"patchSetName,omitempty"`
 
+	// When is a condition that must be met for the patches of a PatchSet to
+	// be included. A PatchSet reference whose condition is not met is
+	// dropped. Only supported when type is PatchSet.
+	// +optional
+	When *PatchCondition `json:"when,omitempty"`
+
 	// Transforms are the list of functions that are used as a FIFO pipe for the
 	// input to be transformed.
 	// +optional
@@ -114,6 +380,20 @@ type Patch struct {
 	// Policy configures the specifics of patching behaviour.
 	// +optional
 	Policy *PatchPolicy `json:"policy,omitempty"`
+
+	// Tags are arbitrary labels, such as the name of a phase of rendering,
+	// that may be used to select which patches are applied. Patches are
+	// applied regardless of their tags unless a caller selects by tag.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+}
+
+// GetDescription returns the Description for this Patch, or an empty string if it is nil.
+func (p *Patch) GetDescription() string {
+	if p.Description == nil {
+		return ""
+	}
+	return *p.Description
 }
 
 // GetFromFieldPath returns the FromFieldPath for this Patch, or an empty string if it is nil.
@@ -132,6 +412,24 @@ func (p *Patch) GetToFieldPath() string {
 	return *p.ToFieldPath
 }
 
+// GetToFieldPaths returns the field paths this Patch writes to: its
+// ToFieldPath, if any, followed by its ToFieldPaths. It doesn't default to the
+// FromFieldPath.
+func (p *Patch) GetToFieldPaths() []string {
+	if p.ToFieldPath == nil {
+		return p.ToFieldPaths
+	}
+	return append([]string{*p.ToFieldPath}, p.ToFieldPaths...)
+}
+
+// GetPriority returns the priority of this Patch, which is 0 if not set.
+func (p *Patch) GetPriority() int {
+	if p.Priority == nil {
+		return 0
+	}
+	return *p.Priority
+}
+
 // GetType returns the patch type. If the type is not set, it returns the default type.
 func (p *Patch) GetType() PatchType {
 	if p.Type == "" {
@@ -140,34 +438,613 @@ func (p *Patch) GetType() PatchType {
 	return p.Type
 }
 
+// Default the Patch object. Patch types that read from a single field path
+// default their ToFieldPath to their FromFieldPath, unless they write to
+// ToFieldPaths.
+func (p *Patch) Default() {
+	switch p.GetType() {
+	case PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeToCompositeFieldPath, PatchTypeToEnvironmentFieldPath, PatchTypeFromComposedFieldPath,
+		PatchTypeFromControllerConfig:
+		if p.ToFieldPath == nil && len(p.ToFieldPaths) == 0 && p.FromFieldPath != nil {
+			to := *p.FromFieldPath
+			p.ToFieldPath = &to
+		}
+	case PatchTypePatchSet, PatchTypeCombineFromEnvironment, PatchTypeCombineFromComposite, PatchTypeCombineToComposite, PatchTypeCombineToEnvironment, PatchTypeNoop,
+		PatchTypeFromConnectionSecretKey, PatchTypeFromComposedConnectionSecretKey:
+		// These patch types have no defaults.
+	}
+}
+
 // Validate the Patch object.
 func (p *Patch) Validate() *field.Error {
-	switch p.GetType() {
-	case PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeToCompositeFieldPath, PatchTypeToEnvironmentFieldPath:
-		if p.FromFieldPath == nil {
-			return field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type))
+	if err := p.validateFields(); err != nil {
+		return err
+	}
+	for i, transform := range p.Transforms {
+		if err := transform.Validate(false); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("transforms").Index(i))
+		}
+	}
+	if p.Combine != nil {
+		for i, v := range p.Combine.Variables {
+			for j, transform := range v.Transforms {
+				if err := transform.Validate(false); err != nil {
+					return verrors.WrapFieldError(err, field.NewPath("combine", "variables").Index(i).Child("transforms").Index(j))
+				}
+			}
 		}
+	}
+	return nil
+}
+
+// The fields each PatchType requires, in addition to its type.
+var patchTypeRequiredFields = map[PatchType][]string{
+	PatchTypeFromCompositeFieldPath:          {"fromFieldPath"},
+	PatchTypeFromEnvironmentFieldPath:        {"fromFieldPath"},
+	PatchTypeToCompositeFieldPath:            {"fromFieldPath"},
+	PatchTypeToEnvironmentFieldPath:          {"fromFieldPath"},
+	PatchTypePatchSet:                        {"patchSetName"},
+	PatchTypeCombineFromEnvironment:          {"combine"},
+	PatchTypeCombineFromComposite:            {"combine"},
+	PatchTypeCombineToComposite:              {"combine"},
+	PatchTypeCombineToEnvironment:            {"combine"},
+	PatchTypeNoop:                            {},
+	PatchTypeFromComposedFieldPath:           {"fromComposedResource", "fromFieldPath"},
+	PatchTypeFromControllerConfig:            {"fromFieldPath"},
+	PatchTypeFromConnectionSecretKey:         {"connectionSecretKey"},
+	PatchTypeFromComposedConnectionSecretKey: {"fromComposedResource", "connectionSecretKey"},
+}
+
+// The PatchTypes that require a toFieldPath or toFieldPaths, in addition to
+// the fields in patchTypeRequiredFields.
+var patchTypeRequiresToFieldPaths = map[PatchType]bool{
+	PatchTypeCombineFromEnvironment:          true,
+	PatchTypeCombineFromComposite:            true,
+	PatchTypeCombineToComposite:              true,
+	PatchTypeCombineToEnvironment:            true,
+	PatchTypeFromConnectionSecretKey:         true,
+	PatchTypeFromComposedConnectionSecretKey: true,
+}
+
+// Fields returns the JSON names of the fields that must be set for the patch's
+// type, and of the other fields that may be set for it. The type field itself
+// is not returned. A required entry of the form "a or b" means at least one of
+// those fields must be set. Both are nil if the type is unknown. Fields is
+// intended for tools, such as editors, that need to know which fields of a
+// patch are relevant.
+func (p *Patch) Fields() (required, optional []string) {
+	r, ok := patchTypeRequiredFields[p.GetType()]
+	if !ok {
+		return nil, nil
+	}
+	required = append([]string{}, r...)
+	if patchTypeRequiresToFieldPaths[p.GetType()] {
+		required = append(required, "toFieldPath or toFieldPaths")
+	}
+
+	// Fields that may be set for any type of patch.
+	optional = []string{"description", "tags"}
+
+	switch p.GetType() {
+	case PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeToCompositeFieldPath, PatchTypeToEnvironmentFieldPath,
+		PatchTypeFromControllerConfig, PatchTypeFromComposedFieldPath:
+		optional = append(optional, "priority", "toFieldPath", "toFieldPaths", "transforms", "policy", "skipWhenValue", "expectedType")
+	case PatchTypeFromConnectionSecretKey, PatchTypeFromComposedConnectionSecretKey, PatchTypeCombineFromEnvironment, PatchTypeCombineFromComposite,
+		PatchTypeCombineToComposite, PatchTypeCombineToEnvironment:
+		optional = append(optional, "priority", "transforms", "policy", "skipWhenValue", "expectedType")
 	case PatchTypePatchSet:
-		if p.PatchSetName == nil {
-			return field.Required(field.NewPath("patchSetName"), fmt.Sprintf("patchSetName must be set for patch type %s", p.Type))
+		optional = append(optional, "priority", "when")
+	case PatchTypeNoop:
+	}
+	return required, optional
+}
+
+// validateFields validates the fields of the Patch object, except for its
+// transforms. It returns the first of the errors returned by fieldErrors.
+func (p *Patch) validateFields() *field.Error {
+	if errs := p.fieldErrors(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// fieldErrors returns all errors in the fields of the Patch object, except for
+// its transforms.
+func (p *Patch) fieldErrors() field.ErrorList {
+	errs := p.validateTypeFields()
+	if err := p.validateUnsupportedFields(); err != nil {
+		errs = append(errs, err)
+	}
+	for i, path := range p.ToFieldPaths {
+		if path == "" {
+			errs = append(errs, field.Required(field.NewPath("toFieldPaths").Index(i), "toFieldPaths must not contain an empty field path"))
 		}
+	}
+	if p.ExpectedType != nil && !p.ExpectedType.IsValid() {
+		errs = append(errs, field.NotSupported(field.NewPath("expectedType"), *p.ExpectedType, []string{string(TransformIOTypeString), string(TransformIOTypeBool), string(TransformIOTypeInt), string(TransformIOTypeInt64), string(TransformIOTypeInt32), string(TransformIOTypeInt16), string(TransformIOTypeFloat64)}))
+	}
+	if err := p.validatePolicy(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.validateFromFieldPathDefaultPolicy(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// validateTypeFields validates the fields that are specific to the patch's
+// type.
+func (p *Patch) validateTypeFields() field.ErrorList {
+	switch p.GetType() {
+	case PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeToCompositeFieldPath, PatchTypeToEnvironmentFieldPath,
+		PatchTypeFromControllerConfig:
+		return p.validateFromFieldPathFields()
+	case PatchTypePatchSet:
+		return p.validatePatchSetFields()
+	case PatchTypeFromConnectionSecretKey:
+		return p.validateFromConnectionSecretKeyFields()
+	case PatchTypeFromComposedConnectionSecretKey:
+		return p.validateFromComposedConnectionSecretKeyFields()
 	case PatchTypeCombineFromEnvironment, PatchTypeCombineFromComposite, PatchTypeCombineToComposite, PatchTypeCombineToEnvironment:
-		if p.Combine == nil {
-			return field.Required(field.NewPath("combine"), fmt.Sprintf("combine must be set for patch type %s", p.Type))
-		}
-		if p.ToFieldPath == nil {
-			return field.Required(field.NewPath("toFieldPath"), fmt.Sprintf("toFieldPath must be set for patch type %s", p.Type))
-		}
+		return p.validateCombineFields()
+	case PatchTypeNoop:
+		// Noop patches have no required fields.
+		return nil
+	case PatchTypeFromComposedFieldPath:
+		return p.validateFromComposedFieldPathFields()
 	default:
 		// Should never happen
-		return field.Invalid(field.NewPath("type"), p.Type, "unknown patch type")
+		return field.ErrorList{field.Invalid(field.NewPath("type"), p.Type, "unknown patch type")}
 	}
-	for i, transform := range p.Transforms {
-		if err := transform.Validate(); err != nil {
-			return verrors.WrapFieldError(err, field.NewPath("transforms").Index(i))
+}
+
+// validateFromFieldPathFields validates the fields of a patch that reads a
+// single field path.
+func (p *Patch) validateFromFieldPathFields() field.ErrorList {
+	if p.FromFieldPath == nil {
+		return field.ErrorList{field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type))}
+	}
+	return nil
+}
+
+// validatePatchSetFields validates the fields of a patch that references a
+// PatchSet.
+func (p *Patch) validatePatchSetFields() field.ErrorList {
+	var errs field.ErrorList
+	if p.PatchSetName == nil {
+		errs = append(errs, field.Required(field.NewPath("patchSetName"), fmt.Sprintf("patchSetName must be set for patch type %s", p.Type)))
+	}
+	if p.When != nil {
+		if err := p.When.Validate(); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("when")))
+		}
+	}
+	return errs
+}
+
+// validateFromConnectionSecretKeyFields validates the fields of a patch that
+// reads from the composed resource's connection details.
+func (p *Patch) validateFromConnectionSecretKeyFields() field.ErrorList {
+	var errs field.ErrorList
+	if p.ConnectionSecretKey == nil {
+		errs = append(errs, field.Required(field.NewPath("connectionSecretKey"), fmt.Sprintf("connectionSecretKey must be set for patch type %s", p.Type)))
+	}
+	if err := p.validateToFieldPathSet(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// validateFromComposedConnectionSecretKeyFields validates the fields of a
+// patch that reads from another composed resource's connection details.
+func (p *Patch) validateFromComposedConnectionSecretKeyFields() field.ErrorList {
+	var errs field.ErrorList
+	switch {
+	case p.FromComposedResource == nil:
+		errs = append(errs, field.Required(field.NewPath("fromComposedResource"), fmt.Sprintf("fromComposedResource must be set for patch type %s", p.Type)))
+	case p.FromComposedResource.Name == nil:
+		// Connection details are resolved by the name of the composed
+		// resource.
+		errs = append(errs, field.Required(field.NewPath("fromComposedResource", "name"), fmt.Sprintf("fromComposedResource.name must be set for patch type %s", p.Type)))
+	default:
+		if err := p.FromComposedResource.Validate(); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("fromComposedResource")))
 		}
 	}
+	if p.ConnectionSecretKey == nil {
+		errs = append(errs, field.Required(field.NewPath("connectionSecretKey"), fmt.Sprintf("connectionSecretKey must be set for patch type %s", p.Type)))
+	}
+	if err := p.validateToFieldPathSet(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// validateFromComposedFieldPathFields validates the fields of a patch that
+// reads a field path of another composed resource.
+func (p *Patch) validateFromComposedFieldPathFields() field.ErrorList {
+	var errs field.ErrorList
+	if p.FromFieldPath == nil {
+		errs = append(errs, field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type)))
+	}
+	if p.FromComposedResource == nil {
+		return append(errs, field.Required(field.NewPath("fromComposedResource"), fmt.Sprintf("fromComposedResource must be set for patch type %s", p.Type)))
+	}
+	if err := p.FromComposedResource.Validate(); err != nil {
+		errs = append(errs, verrors.WrapFieldError(err, field.NewPath("fromComposedResource")))
+	}
+	return errs
+}
 
+// validateCombineFields validates the fields of a patch that combines
+// multiple field paths.
+func (p *Patch) validateCombineFields() field.ErrorList {
+	var errs field.ErrorList
+	if p.Combine == nil {
+		errs = append(errs, field.Required(field.NewPath("combine"), fmt.Sprintf("combine must be set for patch type %s", p.Type)))
+	}
+	if err := p.validateToFieldPathSet(); err != nil {
+		errs = append(errs, err)
+	}
+	if p.Combine == nil {
+		// The combine's variables and strategy can't be validated.
+		return errs
+	}
+	if err := p.validateCombineVariables(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.validateCombineStrategy(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// validateCombineVariables validates the variables of a combine patch.
+func (p *Patch) validateCombineVariables() *field.Error {
+	if p.Combine.Strategy == CombineStrategyPercentDiff && len(p.Combine.Variables) != 2 {
+		return field.Invalid(field.NewPath("combine", "variables"), len(p.Combine.Variables), "percentDiff combine strategy requires exactly two variables")
+	}
+	if p.Combine.Strategy == CombineStrategySet && len(p.Combine.Variables) != 2 {
+		return field.Invalid(field.NewPath("combine", "variables"), len(p.Combine.Variables), "set combine strategy requires exactly two variables")
+	}
+	for i, v := range p.Combine.Variables {
+		for k, t := range v.Transforms {
+			if t.Type == TransformTypeTransformSet {
+				return field.Invalid(field.NewPath("combine", "variables").Index(i).Child("transforms").Index(k).Child("type"), t.Type, "a transform of a combine variable cannot be of type transformSet")
+			}
+		}
+		if v.Policy == nil {
+			continue
+		}
+		switch *v.Policy {
+		case FromFieldPathPolicyOptional, FromFieldPathPolicyRequired:
+		default:
+			return field.Invalid(field.NewPath("combine", "variables").Index(i).Child("policy"), *v.Policy, "unknown fromFieldPath policy")
+		}
+	}
+	return nil
+}
+
+// validateCombineStrategy validates the configuration of a combine patch's
+// strategy.
+func (p *Patch) validateCombineStrategy() *field.Error {
+	switch p.Combine.Strategy {
+	case CombineStrategyMath:
+		if p.Combine.Math == nil {
+			return field.Required(field.NewPath("combine", "math"), "math combine strategy requires configuration")
+		}
+		return verrors.WrapFieldError(p.Combine.Math.Validate(), field.NewPath("combine", "math"))
+	case CombineStrategySet:
+		if p.Combine.Set == nil {
+			return field.Required(field.NewPath("combine", "set"), "set combine strategy requires configuration")
+		}
+		return verrors.WrapFieldError(p.Combine.Set.Validate(), field.NewPath("combine", "set"))
+	case CombineStrategyString, CombineStrategyCoalesce, CombineStrategyPercentDiff, CombineStrategyArray:
+		// These strategies have no configuration that needs validating.
+	}
+	return nil
+}
+
+// validateToFieldPathSet validates that a toFieldPath or toFieldPaths is set,
+// for patch types that have no default.
+func (p *Patch) validateToFieldPathSet() *field.Error {
+	if p.ToFieldPath == nil && len(p.ToFieldPaths) == 0 {
+		return field.Required(field.NewPath("toFieldPath"), fmt.Sprintf("toFieldPath or toFieldPaths must be set for patch type %s", p.Type))
+	}
+	return nil
+}
+
+// validateUnsupportedFields validates that fields aren't set for patch types
+// that don't support them.
+func (p *Patch) validateUnsupportedFields() *field.Error {
+	if p.When != nil && p.GetType() != PatchTypePatchSet {
+		return field.Invalid(field.NewPath("when"), p.When, fmt.Sprintf("when is not supported for patch type %s", p.Type))
+	}
+	if p.GetType() != PatchTypePatchSet && p.GetType() != PatchTypeNoop {
+		return nil
+	}
+	if p.SkipWhenValue != nil {
+		return field.Invalid(field.NewPath("skipWhenValue"), string(p.SkipWhenValue.Raw), fmt.Sprintf("skipWhenValue is not supported for patch type %s", p.Type))
+	}
+	if len(p.ToFieldPaths) > 0 {
+		return field.Invalid(field.NewPath("toFieldPaths"), p.ToFieldPaths, fmt.Sprintf("toFieldPaths is not supported for patch type %s", p.Type))
+	}
+	if p.ExpectedType != nil {
+		return field.Invalid(field.NewPath("expectedType"), *p.ExpectedType, fmt.Sprintf("expectedType is not supported for patch type %s", p.Type))
+	}
+	return nil
+}
+
+// validatePolicy validates the patch's policy, except for its
+// fromFieldPathDefault.
+func (p *Patch) validatePolicy() *field.Error {
+	if p.Policy.IsMergeConditions() {
+		if err := p.validateMergeConditions(); err != nil {
+			return err
+		}
+	}
+	if p.Policy.IsStrategicMerge() {
+		if err := p.validateStrategicMerge(); err != nil {
+			return err
+		}
+	}
+	if ep := p.Policy.GetToEmbeddedJSON(); ep != nil {
+		if err := p.validateToEmbeddedJSON(ep); err != nil {
+			return err
+		}
+	}
+	if p.usesTransform(TransformTypeRelativeTime) && !p.Policy.IsImmutableAfterCreate() {
+		// A relativeTime transform would patch a different value every
+		// time the composite resource is reconciled.
+		return field.Required(field.NewPath("policy", "immutableAfterCreate"), "immutableAfterCreate must be true for patches that use a relativeTime transform")
+	}
+	return nil
+}
+
+// validateFromFieldPathDefaultPolicy validates the patch's
+// fromFieldPathDefault policy.
+func (p *Patch) validateFromFieldPathDefaultPolicy() *field.Error {
+	if p.Policy.IsTransformFromFieldPathDefault() && p.Policy.GetFromFieldPathDefault() == nil {
+		return field.Required(field.NewPath("policy", "fromFieldPathDefault"), "fromFieldPathDefault must be set when transformFromFieldPathDefault is true")
+	}
+	if d := p.Policy.GetFromFieldPathDefault(); d != nil {
+		return p.validateFromFieldPathDefault(*d)
+	}
+	return nil
+}
+
+// usesTransform returns true if the patch, or any of its combine variables,
+// uses a transform of the supplied type.
+func (p *Patch) usesTransform(tt TransformType) bool {
+	ts := p.Transforms
+	if p.Combine != nil {
+		for _, v := range p.Combine.Variables {
+			ts = append(ts[:len(ts):len(ts)], v.Transforms...)
+		}
+	}
+	for _, t := range ts {
+		if t.Type == tt {
+			return true
+		}
+	}
+	return false
+}
+
+// readsFromFieldPath returns true if the patch's type reads a fromFieldPath.
+func (p *Patch) readsFromFieldPath() bool {
+	switch p.GetType() {
+	case PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeToCompositeFieldPath, PatchTypeToEnvironmentFieldPath,
+		PatchTypeFromComposedFieldPath, PatchTypeFromControllerConfig:
+		return true
+	case PatchTypePatchSet, PatchTypeCombineFromEnvironment, PatchTypeCombineFromComposite, PatchTypeCombineToComposite, PatchTypeCombineToEnvironment,
+		PatchTypeNoop, PatchTypeFromConnectionSecretKey, PatchTypeFromComposedConnectionSecretKey:
+		return false
+	}
+	return false
+}
+
+// readsFromComposedResource returns true if the patch's type reads from
+// another composed resource.
+func (p *Patch) readsFromComposedResource() bool {
+	return p.GetType() == PatchTypeFromComposedFieldPath || p.GetType() == PatchTypeFromComposedConnectionSecretKey
+}
+
+// validateMergeConditions validates a policy that merges conditions by type.
+func (p *Patch) validateMergeConditions() *field.Error {
+	path := field.NewPath("policy", "mergeConditions")
+	if !p.readsFromFieldPath() {
+		return field.Invalid(path, true, fmt.Sprintf("mergeConditions is not supported for patch type %s", p.Type))
+	}
+	if p.Policy.MergeOptions != nil {
+		return field.Invalid(path, true, "mergeConditions may not be combined with mergeOptions")
+	}
+	return nil
+}
+
+// validateStrategicMerge validates a policy that merges objects using
+// strategic merge semantics.
+func (p *Patch) validateStrategicMerge() *field.Error {
+	path := field.NewPath("policy", "strategicMerge")
+	if !p.readsFromFieldPath() {
+		return field.Invalid(path, true, fmt.Sprintf("strategicMerge is not supported for patch type %s", p.Type))
+	}
+	if p.Policy.MergeOptions != nil {
+		return field.Invalid(path, true, "strategicMerge may not be combined with mergeOptions")
+	}
+	if p.Policy.IsMergeConditions() {
+		return field.Invalid(path, true, "strategicMerge may not be combined with mergeConditions")
+	}
+	return nil
+}
+
+// validateToEmbeddedJSON validates a policy that patches a JSON object embedded
+// in a string.
+func (p *Patch) validateToEmbeddedJSON(ep *EmbeddedJSONPolicy) *field.Error {
+	path := field.NewPath("policy", "toEmbeddedJSON")
+	if p.GetType() == PatchTypePatchSet || p.GetType() == PatchTypeNoop {
+		return field.Invalid(path, ep.FieldPath, fmt.Sprintf("toEmbeddedJSON is not supported for patch type %s", p.Type))
+	}
+	if p.Policy.IsMergeConditions() {
+		return field.Invalid(path, ep.FieldPath, "toEmbeddedJSON may not be combined with mergeConditions")
+	}
+	if p.Policy.IsStrategicMerge() {
+		return field.Invalid(path, ep.FieldPath, "toEmbeddedJSON may not be combined with strategicMerge")
+	}
+	return verrors.WrapFieldError(ep.Validate(), path)
+}
+
+// validateFromFieldPathDefault validates the policy's default value for a
+// fromFieldPath that does not exist.
+func (p *Patch) validateFromFieldPathDefault(d extv1.JSON) *field.Error {
+	path := field.NewPath("policy", "fromFieldPathDefault")
+	switch p.GetType() {
+	case PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeToCompositeFieldPath, PatchTypeToEnvironmentFieldPath,
+		PatchTypeFromComposedFieldPath, PatchTypeFromControllerConfig:
+	case PatchTypePatchSet, PatchTypeCombineFromEnvironment, PatchTypeCombineFromComposite, PatchTypeCombineToComposite, PatchTypeCombineToEnvironment,
+		PatchTypeNoop, PatchTypeFromConnectionSecretKey, PatchTypeFromComposedConnectionSecretKey:
+		return field.Invalid(path, string(d.Raw), fmt.Sprintf("fromFieldPathDefault is not supported for patch type %s", p.Type))
+	}
+	if p.Policy.GetFromFieldPathPolicy() == FromFieldPathPolicyRequired {
+		return field.Invalid(path, string(d.Raw), "fromFieldPathDefault is not supported when the fromFieldPath policy is Required")
+	}
+	if !json.Valid(d.Raw) {
+		return field.Invalid(path, string(d.Raw), "fromFieldPathDefault is not valid JSON")
+	}
+	return nil
+}
+
+// MergePatches merges the supplied lists of patches into a single list. A patch
+// that writes to the same field paths of the same resource as an earlier patch
+// replaces it, taking its place in the list. Patches are otherwise kept in the
+// order they are supplied.
+func MergePatches(pss ...[]Patch) []Patch {
+	out := make([]Patch, 0)
+	seen := map[string]int{}
+	for _, ps := range pss {
+		for _, p := range ps {
+			k := strings.Join(p.targets(), "\n")
+			if k == "" {
+				out = append(out, p)
+				continue
+			}
+			if i, ok := seen[k]; ok {
+				out[i] = p
+				continue
+			}
+			seen[k] = len(out)
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// targets returns a key identifying the resource and field path for each field
+// path the patch writes to. It returns no keys if the patch doesn't write to a
+// field path.
+func (p *Patch) targets() []string {
+	var r string
+	switch p.GetType() {
+	case PatchTypeFromCompositeFieldPath, PatchTypeFromEnvironmentFieldPath, PatchTypeFromComposedFieldPath,
+		PatchTypeFromControllerConfig, PatchTypeFromConnectionSecretKey, PatchTypeFromComposedConnectionSecretKey, PatchTypeCombineFromComposite,
+		PatchTypeCombineFromEnvironment:
+		r = "composed"
+	case PatchTypeToCompositeFieldPath, PatchTypeCombineToComposite:
+		r = "composite"
+	case PatchTypeToEnvironmentFieldPath, PatchTypeCombineToEnvironment:
+		r = "environment"
+	case PatchTypePatchSet, PatchTypeNoop:
+		return nil
+	}
+
+	paths := p.GetToFieldPaths()
+	if len(paths) == 0 && p.FromFieldPath != nil {
+		// Patches that read from a single field path default to writing to
+		// the same field path.
+		paths = []string{*p.FromFieldPath}
+	}
+	keys := make([]string, len(paths))
+	for i, path := range paths {
+		keys[i] = r + ":" + path
+	}
+	return keys
+}
+
+// A PatchConditionSource is the resource a PatchCondition is evaluated
+// against.
+type PatchConditionSource string
+
+// Patch condition sources.
+const (
+	PatchConditionSourceComposite   PatchConditionSource = "Composite" // Default
+	PatchConditionSourceEnvironment PatchConditionSource = "Environment"
+)
+
+// A PatchCondition is a predicate on the value of a field of the composite
+// resource or the environment.
+type PatchCondition struct {
+	// Source is the resource whose field is evaluated. The default is
+	// 'Composite'.
+	// +optional
+	// +kubebuilder:validation:Enum=Composite;Environment
+	// +kubebuilder:default=Composite
+	Source PatchConditionSource `json:"source,omitempty"`
+
+	// FieldPath is the path of the field whose value is evaluated.
+	FieldPath string `json:"fieldPath"`
+
+	// Equals is the value the field must have for the condition to be met.
+	// If omitted, the condition is met if the field exists.
+	// +optional
+	Equals *extv1.JSON `json:"equals,omitempty"`
+}
+
+// GetSource returns the source of this PatchCondition, defaulting to
+// PatchConditionSourceComposite if not specified.
+func (c *PatchCondition) GetSource() PatchConditionSource {
+	if c.Source == "" {
+		return PatchConditionSourceComposite
+	}
+	return c.Source
+}
+
+// Validate the PatchCondition object.
+func (c *PatchCondition) Validate() *field.Error {
+	switch c.GetSource() {
+	case PatchConditionSourceComposite, PatchConditionSourceEnvironment:
+	default:
+		return field.Invalid(field.NewPath("source"), c.Source, "unknown patch condition source")
+	}
+	if c.FieldPath == "" {
+		return field.Required(field.NewPath("fieldPath"), "fieldPath must be set")
+	}
+	return nil
+}
+
+// A ComposedResourceSelector selects a composed resource of a Composition,
+// either by the name or by the index of the resource template it is composed
+// from.
+type ComposedResourceSelector struct {
+	// Name of the resource template the composed resource is composed from.
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// Index of the resource template the composed resource is composed from,
+	// in the Composition's array of resources.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Index *int `json:"index,omitempty"`
+}
+
+// Validate the ComposedResourceSelector object.
+func (s *ComposedResourceSelector) Validate() *field.Error {
+	switch {
+	case s.Name == nil && s.Index == nil:
+		return field.Required(field.NewPath("name"), "either name or index must be set")
+	case s.Name != nil && s.Index != nil:
+		return field.Invalid(field.NewPath("index"), *s.Index, "only one of name or index may be set")
+	case s.Index != nil && *s.Index < 0:
+		return field.Invalid(field.NewPath("index"), *s.Index, "index must not be negative")
+	}
 	return nil
 }
 
@@ -178,6 +1055,34 @@ type CombineVariable struct {
 	// FromFieldPath is the path of the field on the source whose value is
 	// to be used as input.
 	FromFieldPath string `json:"fromFieldPath"`
+
+	// Transforms are applied to the value of this variable before it is
+	// combined with the values of the other variables. Transforms of the
+	// patch are applied to the combined value. They cannot reference a
+	// TransformSet. They are validated by the Composition webhook rather than
+	// by the CRD's schema, which would otherwise grow too large.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Transforms []Transform `json:"transforms,omitempty"`
+
+	// Policy determines what happens if the FromFieldPath does not exist.
+	// A variable whose policy is 'Required' fails the patch if its field
+	// path does not exist, regardless of the combine strategy. The array
+	// strategy skips variables whose policy is 'Optional'. If unset, the
+	// fromFieldPath policy of the patch applies.
+	// +kubebuilder:validation:Enum=Optional;Required
+	// +optional
+	Policy *FromFieldPathPolicy `json:"policy,omitempty"`
+}
+
+// GetFromFieldPathPolicy returns the FromFieldPathPolicy for this variable,
+// falling back to the supplied policy of its patch if not specified.
+func (v *CombineVariable) GetFromFieldPathPolicy(pp *PatchPolicy) FromFieldPathPolicy {
+	if v.Policy == nil {
+		return pp.GetFromFieldPathPolicy()
+	}
+	return *v.Policy
 }
 
 // A CombineStrategy determines what strategy will be applied to combine
@@ -186,7 +1091,24 @@ type CombineStrategy string
 
 // CombineStrategy strategy definitions.
 const (
-	CombineStrategyString CombineStrategy = "string"
+	CombineStrategyString   CombineStrategy = "string"
+	CombineStrategyCoalesce CombineStrategy = "coalesce"
+
+	// CombineStrategyPercentDiff computes how much the second of exactly
+	// two numeric variables differs from the first, as a percentage of the
+	// first.
+	CombineStrategyPercentDiff CombineStrategy = "percentDiff"
+
+	// CombineStrategyMath adds or multiplies its numeric variables.
+	CombineStrategyMath CombineStrategy = "math"
+
+	// CombineStrategyArray collects the values of its variables that are
+	// not empty into an array.
+	CombineStrategyArray CombineStrategy = "array"
+
+	// CombineStrategySet computes the difference, intersection, or union of
+	// exactly two array variables.
+	CombineStrategySet CombineStrategy = "set"
 )
 
 // A Combine configures a patch that combines more than
@@ -198,14 +1120,115 @@ type Combine struct {
 	Variables []CombineVariable `json:"variables"`
 
 	// Strategy defines the strategy to use to combine the input variable values.
-	// Currently only string is supported.
-	// +kubebuilder:validation:Enum=string
+	// The string strategy formats the variables into a single string. The
+	// coalesce strategy uses the first variable that is not empty. The
+	// percentDiff strategy requires exactly two numeric variables, a desired
+	// and an observed value, and outputs the difference between them as a
+	// percentage of the desired value, e.g. 10 and 12 differ by 20 percent.
+	// The math strategy adds or multiplies numeric variables. The array
+	// strategy outputs an array of the variables that are not empty, in
+	// order, skipping optional variables whose field does not exist. A
+	// variable is empty if its value is null, or an empty string, array, or
+	// object. The set strategy requires exactly two array variables, and
+	// outputs their difference, intersection, or union.
+	// +kubebuilder:validation:Enum=string;coalesce;percentDiff;math;array;set
 	Strategy CombineStrategy `json:"strategy"`
 
 	// String declares that input variables should be combined into a single
 	// string, using the relevant settings for formatting purposes.
 	// +optional
 	String *StringCombine `json:"string,omitempty"`
+
+	// Coalesce configures the coalesce strategy.
+	// +optional
+	Coalesce *CoalesceCombine `json:"coalesce,omitempty"`
+
+	// Math configures the math strategy.
+	// +optional
+	Math *MathCombine `json:"math,omitempty"`
+
+	// Set configures the set strategy.
+	// +optional
+	Set *SetCombine `json:"set,omitempty"`
+}
+
+// A MathCombineOperation is an operation of a MathCombine.
+type MathCombineOperation string
+
+// Accepted MathCombineOperations.
+const (
+	MathCombineOperationAdd      MathCombineOperation = "Add"
+	MathCombineOperationMultiply MathCombineOperation = "Multiply"
+)
+
+// A MathCombine adds or multiplies its input variables. The operation is
+// applied to the variables in order, from first to last, e.g. a Multiply of
+// variables a, b, and c computes (a * b) * c. Each variable must be a number.
+// The result is an integer if all variables are integers, and a float
+// otherwise.
+type MathCombine struct {
+	// Operation to apply to the input variables.
+	// +kubebuilder:validation:Enum=Add;Multiply
+	Operation MathCombineOperation `json:"operation"`
+}
+
+// Validate checks this MathCombine is valid.
+func (m *MathCombine) Validate() *field.Error {
+	switch m.Operation {
+	case MathCombineOperationAdd, MathCombineOperationMultiply:
+		return nil
+	case "":
+		return field.Required(field.NewPath("operation"), "math combine requires an operation")
+	}
+	return field.Invalid(field.NewPath("operation"), m.Operation, "unknown math combine operation")
+}
+
+// A SetCombineOperation is an operation of a SetCombine.
+type SetCombineOperation string
+
+// Accepted SetCombineOperations.
+const (
+	SetCombineOperationDifference   SetCombineOperation = "Difference"
+	SetCombineOperationIntersection SetCombineOperation = "Intersection"
+	SetCombineOperationUnion        SetCombineOperation = "Union"
+)
+
+// A SetCombine treats its two array variables as sets, for example a desired
+// and a current array of firewall rules. A Difference returns the elements of
+// the first array that aren't in the second, an Intersection returns the
+// elements of the first array that are also in the second, and a Union returns
+// the elements of either array. The output contains each element once, in the
+// order it first appears in the first, then the second array. Elements are
+// equal if they have the same JSON representation, so the number 1 equals
+// 1.0 but not "1". Objects and arrays are equal only if all of their fields or
+// elements are equal; there is no way to compare objects by a subset of their
+// fields.
+type SetCombine struct {
+	// Operation to apply to the input variables.
+	// +kubebuilder:validation:Enum=Difference;Intersection;Union
+	Operation SetCombineOperation `json:"operation"`
+}
+
+// Validate checks this SetCombine is valid.
+func (s *SetCombine) Validate() *field.Error {
+	switch s.Operation {
+	case SetCombineOperationDifference, SetCombineOperationIntersection, SetCombineOperationUnion:
+		return nil
+	case "":
+		return field.Required(field.NewPath("operation"), "set combine requires an operation")
+	}
+	return field.Invalid(field.NewPath("operation"), s.Operation, "unknown set combine operation")
+}
+
+// A CoalesceCombine uses the value of the first input variable that is not
+// empty. A variable is empty if its field does not exist or is null, or if
+// its value is an empty string, array, or object. Numbers and booleans are
+// never empty, so 0 and false are used like any other value.
+type CoalesceCombine struct {
+	// Default is the value to use if all input variables are empty. If
+	// omitted, a patch whose input variables are all empty is not applied.
+	// +optional
+	Default *extv1.JSON `json:"default,omitempty"`
 }
 
 // A StringCombine combines multiple input values into a single string.
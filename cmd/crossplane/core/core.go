@@ -37,6 +37,7 @@ import (
 
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions"
+	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/pkg"
 	pkgcontroller "github.com/crossplane/crossplane/internal/controller/pkg/controller"
 	"github.com/crossplane/crossplane/internal/features"
@@ -83,6 +84,9 @@ type startCommand struct {
 	ESSTLSSecretName string        `help:"The name of the TLS Secret that will be used by Crossplane and providers as clients of External Secret Store plugins." env:"ESS_TLS_SECRET_NAME"`
 	ESSTLSCertsDir   string        `help:"The path of the folder which will store TLS certificates to be used by Crossplane and providers for communicating with External Secret Store plugins." env:"ESS_TLS_CERTS_DIR"`
 
+	MaxComposedRenderConcurrency int               `help:"The maximum number of composed resources of a composite resource that may be rendered concurrently." default:"1"`
+	CompositionConfig            map[string]string `help:"Values that FromControllerConfig patches may read, as key=value pairs. Each value is read from the field path of its key." placeholder:"KEY=VALUE;..."`
+
 	EnableEnvironmentConfigs                 bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
 	EnableExternalSecretStores               bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
 	EnableCompositionFunctions               bool `group:"Alpha Features:" help:"Enable support for Composition Functions."`
@@ -164,7 +168,13 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		}
 	}
 
-	if err := apiextensions.Setup(mgr, o); err != nil {
+	ao := apiextensionscontroller.Options{
+		Options:                   o,
+		ControllerConfig:          c.CompositionConfig,
+		ComposedRenderConcurrency: c.MaxComposedRenderConcurrency,
+	}
+
+	if err := apiextensions.Setup(mgr, ao); err != nil {
 		return errors.Wrap(err, "Cannot setup API extension controllers")
 	}
 
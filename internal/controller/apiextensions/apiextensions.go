@@ -20,16 +20,15 @@ package apiextensions
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/crossplane/crossplane-runtime/pkg/controller"
-
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composition"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/definition"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/offered"
 )
 
 // Setup API extensions controllers.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	if err := composition.Setup(mgr, o); err != nil {
+	if err := composition.Setup(mgr, o.Options); err != nil {
 		return err
 	}
 
@@ -37,5 +36,5 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return err
 	}
 
-	return offered.Setup(mgr, o)
+	return offered.Setup(mgr, o.Options)
 }
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"strconv"
+
+	"golang.org/x/sync/errgroup"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+const (
+	errCopyXR = "cannot copy composite resource"
+)
+
+type dryRunOptions struct {
+	concurrency int
+	config      ControllerConfig
+}
+
+// A DryRunOption configures how DryRunCompose renders composed resources.
+type DryRunOption func(o *dryRunOptions)
+
+// WithRenderConcurrency configures DryRunCompose to render up to the supplied
+// number of composed resources concurrently. Composed resources are rendered
+// one at a time by default. A Composition with patches to the environment is
+// always rendered one resource at a time, because a composed resource may
+// read from the environment what an earlier resource patched to it.
+func WithRenderConcurrency(n int) DryRunOption {
+	return func(o *dryRunOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithDryRunControllerConfig configures the controller configuration that
+// FromControllerConfig patches read from. Without it FromControllerConfig
+// patches behave as though the controller configuration is empty.
+func WithDryRunControllerConfig(c ControllerConfig) DryRunOption {
+	return func(o *dryRunOptions) {
+		o.config = c
+	}
+}
+
+// DryRunCompose renders the composed resources of the supplied composite
+// resource using the bases and patches of the supplied Composition, without
+// reading from or writing to an API server. The supplied composite resource
+// and environment are not modified; patches are applied to copies of them.
+//
+// An error rendering one composed resource doesn't prevent the others from
+// being rendered. Such errors are returned as the TemplateRenderErr of the
+// resource's state. An error is only returned if the Composition can't be
+// rendered at all.
+func DryRunCompose(xr resource.Composite, cs v1.CompositionSpec, e *env.Environment, o ...DryRunOption) ([]ComposedResourceState, error) {
+	do := &dryRunOptions{concurrency: 1}
+	for _, fn := range o {
+		fn(do)
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(xr)
+	if err != nil {
+		return nil, errors.Wrap(err, errCopyXR)
+	}
+	cp := &composite.Unstructured{Unstructured: kunstructured.Unstructured{Object: u}}
+
+	if e != nil {
+		e = &env.Environment{Unstructured: *e.Unstructured.DeepCopy()}
+	}
+	if err := applyEnvironmentPatches(cs.Environment, cp, e); err != nil {
+		return nil, err
+	}
+
+	ct, err := ComposedTemplates(cs.PatchSets, cs.Resources, WithPatchConditionSources(cp, e), WithTransformSets(cs.TransformSets))
+	if err != nil {
+		return nil, errors.Wrap(err, errInline)
+	}
+
+	order, err := v1.ApplyOrder(ct)
+	if err != nil {
+		return nil, err
+	}
+
+	cds := dryRunRenderAll(cp, ct, e, do)
+
+	// Patches from other composed resources are applied once all resources
+	// have been rendered, in apply order. A resource therefore sees the fully
+	// rendered state of any resource it reads from.
+	for _, i := range order {
+		if cds[i].TemplateRenderErr != nil {
+			continue
+		}
+		cds[i].TemplateRenderErr = RenderFromComposed(cp, cds[i].Resource, *cds[i].Template, cds)
+	}
+
+	return cds, nil
+}
+
+// dryRunRenderAll renders the composed resources of the supplied templates from
+// the supplied composite resource and environment.
+func dryRunRenderAll(cp resource.Composite, ct []v1.ComposedTemplate, e *env.Environment, do *dryRunOptions) []ComposedResourceState {
+	concurrency := do.concurrency
+	if concurrency < 1 || (e != nil && patchesToEnvironment(ct)) {
+		concurrency = 1
+	}
+
+	// The composite resource and environment are only read while rendering
+	// composed resources, so each resource may be rendered concurrently. Each
+	// renders into its own state, so the results don't depend on the order in
+	// which rendering completes.
+	cds := make([]ComposedResourceState, len(ct))
+	g := &errgroup.Group{}
+	g.SetLimit(concurrency)
+	for i := range ct {
+		i, t := i, ct[i] // Pin the range variables before using them in a Goroutine.
+		g.Go(func() error {
+			cd := composed.New()
+			cds[i] = ComposedResourceState{
+				// If this resource is anonymous its "name" is just its index.
+				ComposedResource:  ComposedResource{ResourceName: pointer.StringDeref(t.Name, strconv.Itoa(i))},
+				Template:          &t,
+				Resource:          cd,
+				TemplateRenderErr: dryRunRender(cp, cd, t, e, do.config),
+			}
+			return nil
+		})
+	}
+	_ = g.Wait() // Render errors are recorded in each resource's state.
+	return cds
+}
+
+// patchesToEnvironment returns true if any of the supplied templates patch to
+// the environment.
+func patchesToEnvironment(ct []v1.ComposedTemplate) bool {
+	for _, t := range ct {
+		for _, p := range t.Patches {
+			if t := p.GetType(); t == v1.PatchTypeToEnvironmentFieldPath || t == v1.PatchTypeCombineToEnvironment {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dryRunRender renders the supplied composed resource from its template, the
+// supplied composite resource, the supplied environment, and the supplied
+// controller configuration.
+func dryRunRender(cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, e *env.Environment, c ControllerConfig) error {
+	if err := json.Unmarshal(t.Base.Raw, cd); err != nil {
+		return errors.Wrap(err, errUnmarshal)
+	}
+
+	for i := range t.Patches {
+		if err := Apply(t.Patches[i], cp, cd, OnlyPatchTypes(patchTypesFromXR()...)); err != nil {
+			return errors.Wrapf(err, errFmtPatch, i)
+		}
+		if err := Apply(t.Patches[i], cp, cd, OnlyPatchTypes(v1.PatchTypeFromControllerConfig), WithControllerConfig(c)); err != nil {
+			return errors.Wrapf(err, errFmtPatch, i)
+		}
+		if e != nil {
+			if err := ApplyToObjects(t.Patches[i], e, cd, OnlyPatchTypes(patchTypesFromToEnvironment()...)); err != nil {
+				return errors.Wrapf(err, errFmtPatch, i)
+			}
+		}
+	}
+
+	if t.Name != nil {
+		SetCompositionResourceName(cd, *t.Name)
+	}
+	return nil
+}
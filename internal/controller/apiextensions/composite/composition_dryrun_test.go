@@ -0,0 +1,477 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+func TestDryRunCompose(t *testing.T) {
+	xr := func() *composite.Unstructured {
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "XR",
+			"spec":       map[string]any{"region": "us-west-2"},
+		}}}
+	}
+	cd := func(o map[string]any) *composed.Unstructured {
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: o}}
+	}
+	base := runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)}
+
+	type args struct {
+		xr  *composite.Unstructured
+		cs  v1.CompositionSpec
+		env *env.Environment
+		o   []DryRunOption
+	}
+	type want struct {
+		cds []ComposedResourceState
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UndefinedPatchSet": {
+			reason: "We should return an error if we can't inline patch sets.",
+			args: args{
+				xr: xr(),
+				cs: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Base:    base,
+						Patches: []v1.Patch{{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("nope")}},
+					}},
+				},
+			},
+			want: want{
+				err: errors.Wrap(utilerrors.NewAggregate([]error{&v1.PatchSetReferenceError{ResourceIndex: 0, PatchIndex: 0, Err: v1.UndefinedPatchSetError("nope")}}), errInline),
+			},
+		},
+		"DependencyCycle": {
+			reason: "We should return an error if patches from composed resources form a cycle.",
+			args: args{
+				xr: xr(),
+				cs: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{
+						{
+							Name: pointer.String("a"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:                 v1.PatchTypeFromComposedFieldPath,
+								FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("b")},
+								FromFieldPath:        pointer.String("spec.forProvider.region"),
+							}},
+						},
+						{
+							Name: pointer.String("b"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:                 v1.PatchTypeFromComposedFieldPath,
+								FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("a")},
+								FromFieldPath:        pointer.String("spec.forProvider.region"),
+							}},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(v1.ErrDependencyCycle, "cannot order resources %s", "a, b"),
+			},
+		},
+		"RenderedInApplyOrder": {
+			reason: "A resource should see what a resource after it patched from another composed resource, if it reads from it.",
+			args: args{
+				xr: xr(),
+				cs: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{
+						{
+							Name: pointer.String("policy"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:                 v1.PatchTypeFromComposedFieldPath,
+								FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("role")},
+								FromFieldPath:        pointer.String("spec.forProvider.bucketRegion"),
+								ToFieldPath:          pointer.String("spec.forProvider.roleRegion"),
+							}},
+						},
+						{
+							Name: pointer.String("role"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:                 v1.PatchTypeFromComposedFieldPath,
+								FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("bucket")},
+								FromFieldPath:        pointer.String("spec.forProvider.region"),
+								ToFieldPath:          pointer.String("spec.forProvider.bucketRegion"),
+							}},
+						},
+						{
+							Name: pointer.String("bucket"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:          v1.PatchTypeFromCompositeFieldPath,
+								FromFieldPath: pointer.String("spec.region"),
+								ToFieldPath:   pointer.String("spec.forProvider.region"),
+							}},
+						},
+					},
+				},
+			},
+			want: want{
+				cds: []ComposedResourceState{
+					{
+						ComposedResource: ComposedResource{ResourceName: "policy"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "policy"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"roleRegion": "us-west-2"}},
+						}),
+					},
+					{
+						ComposedResource: ComposedResource{ResourceName: "role"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "role"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"bucketRegion": "us-west-2"}},
+						}),
+					},
+					{
+						ComposedResource: ComposedResource{ResourceName: "bucket"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "bucket"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"region": "us-west-2"}},
+						}),
+					},
+				},
+			},
+		},
+		"RenderedWithErrors": {
+			reason: "We should render every resource, recording errors per resource.",
+			args: args{
+				xr: xr(),
+				cs: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{
+						{
+							Name: pointer.String("bucket"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:          v1.PatchTypeFromCompositeFieldPath,
+								FromFieldPath: pointer.String("spec.region"),
+								ToFieldPath:   pointer.String("spec.forProvider.region"),
+							}},
+						},
+						{
+							Name: pointer.String("broken"),
+							Base: runtime.RawExtension{Raw: []byte(`{`)},
+						},
+						{
+							Name: pointer.String("policy"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:                 v1.PatchTypeFromComposedFieldPath,
+								FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("bucket")},
+								FromFieldPath:        pointer.String("spec.forProvider.region"),
+								ToFieldPath:          pointer.String("spec.forProvider.bucketRegion"),
+							}},
+						},
+					},
+				},
+			},
+			want: want{
+				cds: []ComposedResourceState{
+					{
+						ComposedResource: ComposedResource{ResourceName: "bucket"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "bucket"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"region": "us-west-2"}},
+						}),
+					},
+					{
+						ComposedResource:  ComposedResource{ResourceName: "broken"},
+						Resource:          cd(map[string]any{}),
+						TemplateRenderErr: errors.Wrap(errors.New("unexpected end of JSON input"), errUnmarshal),
+					},
+					{
+						ComposedResource: ComposedResource{ResourceName: "policy"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "policy"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"bucketRegion": "us-west-2"}},
+						}),
+					},
+				},
+			},
+		},
+		"RenderedConcurrently": {
+			reason: "Rendering resources concurrently should produce the same results as rendering them one at a time.",
+			args: args{
+				xr: xr(),
+				cs: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{
+						{
+							Name: pointer.String("bucket"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:          v1.PatchTypeFromCompositeFieldPath,
+								FromFieldPath: pointer.String("spec.region"),
+								ToFieldPath:   pointer.String("spec.forProvider.region"),
+							}},
+						},
+						{
+							Name: pointer.String("policy"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:                 v1.PatchTypeFromComposedFieldPath,
+								FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("bucket")},
+								FromFieldPath:        pointer.String("spec.forProvider.region"),
+								ToFieldPath:          pointer.String("spec.forProvider.bucketRegion"),
+							}},
+						},
+					},
+				},
+				o: []DryRunOption{WithRenderConcurrency(2)},
+			},
+			want: want{
+				cds: []ComposedResourceState{
+					{
+						ComposedResource: ComposedResource{ResourceName: "bucket"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "bucket"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"region": "us-west-2"}},
+						}),
+					},
+					{
+						ComposedResource: ComposedResource{ResourceName: "policy"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "policy"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"bucketRegion": "us-west-2"}},
+						}),
+					},
+				},
+			},
+		},
+		"PatchesToEnvironmentRenderedInOrder": {
+			reason: "A resource should read what an earlier resource patched to the environment, even if concurrency is configured.",
+			args: args{
+				xr: xr(),
+				env: &env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "internal.crossplane.io/v1alpha1",
+					"kind":       "Environment",
+				}}},
+				cs: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{
+						{
+							Name: pointer.String("bucket"),
+							Base: base,
+							Patches: []v1.Patch{
+								{
+									Type:          v1.PatchTypeFromCompositeFieldPath,
+									FromFieldPath: pointer.String("spec.region"),
+									ToFieldPath:   pointer.String("spec.forProvider.region"),
+								},
+								{
+									Type:          v1.PatchTypeToEnvironmentFieldPath,
+									FromFieldPath: pointer.String("spec.forProvider.region"),
+									ToFieldPath:   pointer.String("region"),
+								},
+							},
+						},
+						{
+							Name: pointer.String("policy"),
+							Base: base,
+							Patches: []v1.Patch{{
+								Type:          v1.PatchTypeFromEnvironmentFieldPath,
+								FromFieldPath: pointer.String("region"),
+								ToFieldPath:   pointer.String("spec.forProvider.bucketRegion"),
+							}},
+						},
+					},
+				},
+				o: []DryRunOption{WithRenderConcurrency(2)},
+			},
+			want: want{
+				cds: []ComposedResourceState{
+					{
+						ComposedResource: ComposedResource{ResourceName: "bucket"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "bucket"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"region": "us-west-2"}},
+						}),
+					},
+					{
+						ComposedResource: ComposedResource{ResourceName: "policy"},
+						Resource: cd(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "policy"},
+							},
+							"spec": map[string]any{"forProvider": map[string]any{"bucketRegion": "us-west-2"}},
+						}),
+					},
+				},
+			},
+		},
+		"ControllerConfig": {
+			reason: "We should render patches from the supplied controller configuration.",
+			args: args{
+				xr: xr(),
+				cs: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Name: pointer.String("bucket"),
+						Base: base,
+						Patches: []v1.Patch{{
+							Type:          v1.PatchTypeFromControllerConfig,
+							FromFieldPath: pointer.String("defaults.tier"),
+							ToFieldPath:   pointer.String("spec.forProvider.tier"),
+						}},
+					}},
+				},
+				o: []DryRunOption{WithDryRunControllerConfig(fieldpath.Pave(map[string]any{
+					"defaults": map[string]any{"tier": "standard"},
+				}))},
+			},
+			want: want{
+				cds: []ComposedResourceState{{
+					ComposedResource: ComposedResource{ResourceName: "bucket"},
+					Resource: cd(map[string]any{
+						"apiVersion": "example.org/v1",
+						"kind":       "Bucket",
+						"metadata": map[string]any{
+							"annotations": map[string]any{AnnotationKeyCompositionResourceName: "bucket"},
+						},
+						"spec": map[string]any{"forProvider": map[string]any{"tier": "standard"}},
+					}),
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := tc.args.xr.DeepCopyObject()
+			cds, err := DryRunCompose(tc.args.xr, tc.args.cs, tc.args.env, tc.args.o...)
+			if diff := cmp.Diff(tc.want.cds, cds, test.EquateErrors(), cmpopts.IgnoreFields(ComposedResourceState{}, "Template")); diff != "" {
+				t.Errorf("\n%s\nDryRunCompose(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDryRunCompose(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(in, tc.args.xr.DeepCopyObject()); diff != "" {
+				t.Errorf("\n%s\nDryRunCompose(...): the composite resource should not be modified: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func BenchmarkDryRunCompose(b *testing.B) {
+	xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "XR",
+		"spec": map[string]any{
+			"region": "us-west-2",
+			"tags":   map[string]any{"team": "a", "env": "prod"},
+		},
+	}}}
+
+	cs := v1.CompositionSpec{}
+	for i := 0; i < 200; i++ {
+		cs.Resources = append(cs.Resources, v1.ComposedTemplate{
+			Name: pointer.String(fmt.Sprintf("bucket-%d", i)),
+			Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)},
+			Patches: []v1.Patch{
+				{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPath:   pointer.String("spec.forProvider.region"),
+				},
+				{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.tags"),
+					ToFieldPath:   pointer.String("spec.forProvider.tags"),
+				},
+				{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{{FromFieldPath: "spec.region"}, {FromFieldPath: "spec.tags.team"}},
+						Strategy:  v1.CombineStrategyString,
+						String:    &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("metadata.name"),
+				},
+			},
+		})
+	}
+
+	for _, n := range []int{1, 8} {
+		b.Run(fmt.Sprintf("Concurrency%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := DryRunCompose(xr, cs, nil, WithRenderConcurrency(n)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
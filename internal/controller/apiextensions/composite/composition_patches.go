@@ -18,30 +18,419 @@ package composite
 
 import (
 	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 )
 
 const (
-	errPatchSetType             = "a patch in a PatchSet cannot be of type PatchSet"
 	errCombineRequiresVariables = "combine patch types require at least one variable"
+	errFmtCombineVariable       = "cannot transform combine variable at index %d"
+	errCoalesceAllEmpty         = "all combine variables are empty and no default is configured"
+	errCoalesceDefault          = "cannot unmarshal coalesce default value"
+	errPercentDiffZero          = "cannot compute a percent difference from a desired value of zero"
+	errFromFieldPathDefault     = "cannot unmarshal fromFieldPath default value"
+	errMergeConditionsNonArray  = "cannot merge conditions: value is not an array"
+	errMergeConditionsExisting  = "cannot merge conditions: existing value is not an array"
+	errStrategicMergeNonObject  = "cannot strategic merge: value is not an object"
+	errStrategicMergeExisting   = "cannot strategic merge: existing value is not an object"
+	errStrategicMerge           = "cannot strategic merge"
+	errEmbeddedJSONNonString    = "cannot patch embedded JSON: existing value is not a string"
+	errEmbeddedJSONInvalid      = "cannot patch embedded JSON: existing value is not a JSON object"
+	errEmbeddedJSONEncode       = "cannot encode embedded JSON"
 
-	errFmtUndefinedPatchSet           = "cannot find PatchSet by name %s"
-	errFmtInvalidPatchType            = "patch type %s is unsupported"
-	errFmtCombineStrategyNotSupported = "combine strategy %s is not supported"
-	errFmtCombineConfigMissing        = "given combine strategy %s requires configuration"
-	errFmtCombineStrategyFailed       = "%s strategy could not combine"
-	errFmtExpandingArrayFieldPaths    = "cannot expand ToFieldPath %s"
+	errFmtCombineStrategyNotSupported         = "combine strategy %s is not supported"
+	errFmtCombineConfigMissing                = "given combine strategy %s requires configuration"
+	errFmtCombineStrategyFailed               = "%s strategy could not combine"
+	errFmtPercentDiffVariables                = "percentDiff strategy requires exactly two variables, got %d"
+	errFmtPercentDiffNonNumber                = "percentDiff strategy requires numeric variables, variable %d is not a number"
+	errFmtMathCombineNonNumber                = "math strategy requires numeric variables, variable %d is not a number"
+	errFmtMathCombineOperation                = "math strategy operation %s is not supported"
+	errFmtSetCombineVariables                 = "set strategy requires exactly two variables, got %d"
+	errFmtSetCombineNonArray                  = "set strategy requires array variables, variable %d is not an array"
+	errFmtSetCombineOperation                 = "set strategy operation %s is not supported"
+	errFmtSetCombineElement                   = "cannot compare element at index %d of variable %d"
+	errFmtExpandingArrayFieldPaths            = "cannot expand ToFieldPath %s"
+	errFmtResolveToFieldPathKey               = "cannot resolve ToFieldPath key template %s"
+	errFmtToFieldPathKeyNotString             = "ToFieldPath key template %s must resolve to a string, got %T"
+	errFmtToFieldPathKeyEmpty                 = "ToFieldPath key template %s resolved to an empty string"
+	errFmtToFieldPathKeyInvalid               = "ToFieldPath key template %s resolved to invalid key %q"
+	errFmtPatchToFieldPath                    = "cannot patch to field path %s"
+	errFmtComposedResourceNotFound            = "cannot find composed resource %q"
+	errFmtComposedResourceIdxNotFound         = "cannot find composed resource at index %d"
+	errFmtPatchConditionSource                = "patch condition source %s is not supported"
+	errPatchConditionValue                    = "cannot unmarshal patch condition value"
+	errSkipWhenValue                          = "cannot unmarshal skipWhenValue"
+	errTransformConditionValue                = "cannot unmarshal transform condition value"
+	errTransformConditionRegexp               = "cannot compile transform condition regexp"
+	errFmtTransformConditionOperator          = "transform condition operator %s is not supported"
+	errFmtTransformConditionMissing           = "%s is required by transform condition operator %s"
+	errFmtTransformConditionAtIndex           = "cannot evaluate condition of transform at index %d"
+	errFmtResolveConnectionSecretKey          = "cannot resolve connection secret key %s"
+	errFmtConnectionSecretKeyNotFound         = "connection secret key %s not found"
+	errFmtTransformConnectionSecretKey        = "cannot transform the value of connection secret key %s"
+	errFmtResolveComposedConnectionSecretKey  = "cannot resolve connection secret key %s of composed resource %q"
+	errFmtComposedConnectionSecretKeyNotFound = "connection secret key %s of composed resource %q not found"
+	errFmtMergeConditionsNotCondition         = "cannot merge conditions: element at index %d is not a condition"
+	errFmtMergeConditionsExistingIdx          = "cannot merge conditions: existing element at index %d is not a condition"
+	errFmtFieldPathFilterNotArray             = "cannot filter %s: not an array"
+	errFmtFieldPathFilterNoMatch              = "no element of %s has %s %s"
+	errFmtUnexpectedOutputType                = "expected a value of type %s to patch, got %s"
 )
 
+// A PatchResult indicates what applying a patch did.
+type PatchResult string
+
+// Patch results.
+const (
+	// PatchResultApplied indicates the patch changed the object it patches.
+	PatchResultApplied PatchResult = "Applied"
+
+	// PatchResultSkippedOptional indicates the patch didn't write a value,
+	// typically because its optional fromFieldPath doesn't exist.
+	PatchResultSkippedOptional PatchResult = "SkippedOptional"
+
+	// PatchResultNoChange indicates the patch wrote a value identical to the
+	// existing value, leaving the object it patches unchanged.
+	PatchResultNoChange PatchResult = "NoChange"
+)
+
+// ErrPatchSkipped is returned by ResolvePatch when the supplied patch would not
+// write a value, for example because its optional fromFieldPath doesn't exist.
+var ErrPatchSkipped = errors.New("patch would be skipped")
+
+// toFieldPathKeyTemplate matches a templated key segment within a ToFieldPath,
+// e.g. the {{ spec.region }} in metadata.annotations[example.org/name-{{ spec.region }}].
+var toFieldPathKeyTemplate = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+
+// fieldPathFilter matches a segment of a field path that selects the first
+// element of an array whose field has a value, e.g. the [kind=Bucket] in
+// spec.resourceRefs[kind=Bucket].name. A quoted key segment, such as the
+// ['example.org/a=b'] in metadata.annotations['example.org/a=b'], is a key
+// even if it contains an equals sign.
+var fieldPathFilter = regexp.MustCompile(`\[([^\[\]='"][^\[\]=]*)=([^\[\]]*)\]`)
+
+// A FieldPathResolver resolves the values at field paths of an object.
+type FieldPathResolver interface {
+	GetValue(path string) (any, error)
+}
+
+// A FieldPathResolverFn returns a FieldPathResolver for the supplied object.
+type FieldPathResolverFn func(o runtime.Object) (FieldPathResolver, error)
+
+// PaveFieldPathResolver returns a FieldPathResolver that resolves field paths
+// by converting the supplied object to unstructured data and paving it. The
+// apiVersion and kind fields resolve to the object's GroupVersionKind even if
+// the object, like many typed objects, doesn't serialize its type metadata.
+// A field path may select the first element of an array whose field has a
+// value, e.g. spec.resourceRefs[kind=Bucket].name.
+func PaveFieldPathResolver(o runtime.Object) (FieldPathResolver, error) {
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, err
+	}
+	apiVersion, kind := o.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+	if _, ok := m["apiVersion"]; !ok && apiVersion != "" {
+		m["apiVersion"] = apiVersion
+	}
+	if _, ok := m["kind"]; !ok && kind != "" {
+		m["kind"] = kind
+	}
+	return filteringFieldPathResolver{fieldpath.Pave(m)}, nil
+}
+
+// A filteringFieldPathResolver resolves field paths that may select an element
+// of an array by the value of one of its fields.
+type filteringFieldPathResolver struct {
+	FieldPathResolver
+}
+
+// GetValue replaces each filter in the supplied path with the index of the
+// first element it selects, then returns the value at the resulting path. It
+// returns an error that satisfies fieldpath.IsNotFound if a filter selects no
+// element.
+func (r filteringFieldPathResolver) GetValue(path string) (any, error) {
+	for {
+		loc := fieldPathFilter.FindStringSubmatchIndex(path)
+		if loc == nil {
+			return r.FieldPathResolver.GetValue(path)
+		}
+		array, field, value := path[:loc[0]], path[loc[2]:loc[3]], path[loc[4]:loc[5]]
+		v, err := r.FieldPathResolver.GetValue(array)
+		if err != nil {
+			return nil, err
+		}
+		elems, ok := v.([]any)
+		if !ok {
+			return nil, errors.Errorf(errFmtFieldPathFilterNotArray, array)
+		}
+		i := -1
+		for j, e := range elems {
+			if m, ok := e.(map[string]any); ok && m[field] != nil && fmt.Sprint(m[field]) == value {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			return nil, notFoundError{errors.Errorf(errFmtFieldPathFilterNoMatch, array, field, value)}
+		}
+		path = fmt.Sprintf("%s[%d]%s", array, i, path[loc[1]:])
+	}
+}
+
+// A ControllerConfig exposes values that were supplied to the controller at
+// runtime, for example platform-wide defaults, to FromControllerConfig
+// patches. Values are read by field path. A fieldpath.Paved is a valid
+// ControllerConfig.
+type ControllerConfig interface {
+	// GetValue returns the value at the supplied field path. It returns an
+	// error that satisfies fieldpath.IsNotFound if there is no such value.
+	GetValue(path string) (any, error)
+}
+
+// NewControllerConfig returns a ControllerConfig that exposes each of the
+// supplied values at the field path of its key.
+func NewControllerConfig(values map[string]string) ControllerConfig {
+	cfg := make(map[string]any, len(values))
+	for k, v := range values {
+		cfg[k] = v
+	}
+	return fieldpath.Pave(cfg)
+}
+
+// A ConnectionSecretResolver resolves the values of the keys of a connection
+// secret for FromConnectionSecretKey patches, for example by reading the
+// composite resource's connection secret.
+type ConnectionSecretResolver interface {
+	// ResolveConnectionSecretKey returns the value of the supplied key of the
+	// connection secret, and whether the key exists.
+	ResolveConnectionSecretKey(key string) (value []byte, exists bool, err error)
+}
+
+// A ConnectionSecretResolverFn is a function that satisfies the
+// ConnectionSecretResolver interface.
+type ConnectionSecretResolverFn func(key string) ([]byte, bool, error)
+
+// ResolveConnectionSecretKey returns the value of the supplied key of the
+// connection secret, and whether the key exists.
+func (fn ConnectionSecretResolverFn) ResolveConnectionSecretKey(key string) ([]byte, bool, error) {
+	return fn(key)
+}
+
+// ConnectionSecretData returns a ConnectionSecretResolver that resolves keys
+// from the supplied connection secret data.
+func ConnectionSecretData(data map[string][]byte) ConnectionSecretResolverFn {
+	return func(key string) ([]byte, bool, error) {
+		v, ok := data[key]
+		return v, ok, nil
+	}
+}
+
+// A ComposedConnectionSecretResolver resolves the values of the keys of the
+// connection details of the composed resources of a Composition for
+// FromComposedConnectionSecretKey patches.
+type ComposedConnectionSecretResolver interface {
+	// ResolveComposedConnectionSecretKey returns the value of the supplied key
+	// of the connection details of the named composed resource, and whether
+	// the key exists.
+	ResolveComposedConnectionSecretKey(name, key string) (value []byte, exists bool, err error)
+}
+
+// A ComposedConnectionSecretResolverFn is a function that satisfies the
+// ComposedConnectionSecretResolver interface.
+type ComposedConnectionSecretResolverFn func(name, key string) ([]byte, bool, error)
+
+// ResolveComposedConnectionSecretKey returns the value of the supplied key of
+// the connection details of the named composed resource, and whether the key
+// exists.
+func (fn ComposedConnectionSecretResolverFn) ResolveComposedConnectionSecretKey(name, key string) ([]byte, bool, error) {
+	return fn(name, key)
+}
+
+// ComposedConnectionDetails returns a ComposedConnectionSecretResolver that
+// resolves keys from the connection details of the supplied composed
+// resources. Resources that could not be rendered are treated as though they
+// have no connection details.
+func ComposedConnectionDetails(cds []ComposedResourceState) ComposedConnectionSecretResolverFn {
+	return func(name, key string) ([]byte, bool, error) {
+		for _, cd := range cds {
+			if cd.ResourceName != name || cd.Resource == nil || cd.TemplateRenderErr != nil {
+				continue
+			}
+			v, ok := cd.ConnectionDetails[key]
+			return v, ok, nil
+		}
+		return nil, false, nil
+	}
+}
+
+type applyOptions struct {
+	only            []v1.PatchType
+	tags            []string
+	resolver        FieldPathResolverFn
+	composed        []ComposedResourceState
+	config          ControllerConfig
+	secrets         ConnectionSecretResolver
+	composedSecrets ComposedConnectionSecretResolver
+	exists          bool
+	deleting        bool
+
+	skipTransformErrors bool
+	transformErrorFn    TransformErrorFn
+
+	// resolved, if set, is called with the value a patch would write instead
+	// of writing it.
+	resolved func(v any)
+
+	// writing, if set, is called when a patch is about to write a value.
+	writing func()
+}
+
+// A TransformErrorFn is called with a patch that was skipped because one of
+// its transforms returned the supplied error.
+type TransformErrorFn func(p v1.Patch, err error)
+
+// An ApplyOption configures how a patch is applied.
+type ApplyOption func(o *applyOptions)
+
+// OnlyPatchTypes filters the patches that are applied to those of the
+// supplied types. All patch types are applied by default.
+func OnlyPatchTypes(t ...v1.PatchType) ApplyOption {
+	return func(o *applyOptions) {
+		o.only = append(o.only, t...)
+	}
+}
+
+// OnlyPatchTags filters the patches that are applied to those with any of the
+// supplied tags. It may be combined with OnlyPatchTypes, in which case a patch
+// must match both filters to be applied. Patches are applied regardless of
+// their tags by default.
+func OnlyPatchTags(t ...string) ApplyOption {
+	return func(o *applyOptions) {
+		o.tags = append(o.tags, t...)
+	}
+}
+
+// WithFieldPathResolver configures how the field paths a patch reads from are
+// resolved. Field paths are resolved by PaveFieldPathResolver by default.
+func WithFieldPathResolver(fn FieldPathResolverFn) ApplyOption {
+	return func(o *applyOptions) {
+		o.resolver = fn
+	}
+}
+
+// WithComposedResources supplies the composed resources a FromComposedFieldPath
+// patch may read from. They must be in the same order as the resource
+// templates of their Composition. Resources that could not be rendered are
+// treated as though they don't exist.
+func WithComposedResources(cds []ComposedResourceState) ApplyOption {
+	return func(o *applyOptions) {
+		o.composed = cds
+	}
+}
+
+// WithControllerConfig supplies the controller configuration a
+// FromControllerConfig patch reads from. Without it FromControllerConfig
+// patches behave as though the controller configuration is empty.
+func WithControllerConfig(c ControllerConfig) ApplyOption {
+	return func(o *applyOptions) {
+		o.config = c
+	}
+}
+
+// WithConnectionSecretResolver supplies the resolver a FromConnectionSecretKey
+// patch reads from. Without it FromConnectionSecretKey patches behave as
+// though the connection secret has no keys.
+func WithConnectionSecretResolver(r ConnectionSecretResolver) ApplyOption {
+	return func(o *applyOptions) {
+		o.secrets = r
+	}
+}
+
+// WithComposedConnectionSecretResolver supplies the resolver a
+// FromComposedConnectionSecretKey patch reads from. Without it keys are
+// resolved from the connection details of the composed resources supplied by
+// the WithComposedResources option.
+func WithComposedConnectionSecretResolver(r ComposedConnectionSecretResolver) ApplyOption {
+	return func(o *applyOptions) {
+		o.composedSecrets = r
+	}
+}
+
+// WithComposedResourceExists indicates whether the composed resource being
+// patched already exists. Patches with an ImmutableAfterCreate policy are not
+// applied to composed resources that exist. Composed resources are assumed not
+// to exist by default.
+func WithComposedResourceExists(exists bool) ApplyOption {
+	return func(o *applyOptions) {
+		o.exists = exists
+	}
+}
+
+// WithCompositeDeleting indicates whether the composite resource is being
+// deleted. Patches with a SkipWhenDeleting policy are not applied while the
+// composite resource is being deleted. The composite resource is assumed not
+// to be being deleted by default.
+func WithCompositeDeleting(deleting bool) ApplyOption {
+	return func(o *applyOptions) {
+		o.deleting = deleting
+	}
+}
+
+// SkipOptionalTransformErrors treats a transform error of a patch whose
+// fromFieldPath policy is optional like a fromFieldPath that doesn't exist;
+// the patch is skipped rather than returning the error. Patches whose
+// fromFieldPath policy is required still return transform errors. The
+// supplied function, if not nil, is called with each patch that is skipped
+// and its transform error, so that skipped transforms may be logged or
+// counted separately from missing field paths.
+func SkipOptionalTransformErrors(fn TransformErrorFn) ApplyOption {
+	return func(o *applyOptions) {
+		o.skipTransformErrors = true
+		o.transformErrorFn = fn
+	}
+}
+
+// skipTransformError returns true if the supplied patch should be skipped
+// rather than failed because of the supplied transform error of a value read
+// with the supplied fromFieldPath policy.
+func (o *applyOptions) skipTransformError(p v1.Patch, policy v1.FromFieldPathPolicy, err error) bool {
+	if !o.skipTransformErrors || policy != v1.FromFieldPathPolicyOptional {
+		return false
+	}
+	if o.transformErrorFn != nil {
+		o.transformErrorFn(p, err)
+	}
+	return true
+}
+
+func newApplyOptions(o ...ApplyOption) *applyOptions {
+	ao := &applyOptions{resolver: PaveFieldPathResolver}
+	for _, fn := range o {
+		fn(ao)
+	}
+	return ao
+}
+
 // ApplyEnvironmentPatch executes a patching operation between the cp and env objects.
 func ApplyEnvironmentPatch(p v1.EnvironmentPatch, cp, env runtime.Object) error {
 	// TODO(negz): Should this take composite.Resource and *env.Environment as
@@ -54,6 +443,7 @@ func ApplyEnvironmentPatch(p v1.EnvironmentPatch, cp, env runtime.Object) error
 		FromFieldPath: p.FromFieldPath,
 		Combine:       p.Combine,
 		ToFieldPath:   p.ToFieldPath,
+		ToFieldPaths:  p.ToFieldPaths,
 		Transforms:    p.Transforms,
 		Policy:        p.Policy,
 	}
@@ -61,41 +451,155 @@ func ApplyEnvironmentPatch(p v1.EnvironmentPatch, cp, env runtime.Object) error
 		regularPatch,
 		cp,
 		env,
-		v1.PatchTypeFromCompositeFieldPath,
-		v1.PatchTypeCombineFromComposite,
-		v1.PatchTypeToCompositeFieldPath,
-		v1.PatchTypeCombineToComposite,
+		OnlyPatchTypes(
+			v1.PatchTypeFromCompositeFieldPath,
+			v1.PatchTypeCombineFromComposite,
+			v1.PatchTypeToCompositeFieldPath,
+			v1.PatchTypeCombineToComposite,
+		),
 	)
 }
 
 // Apply executes a patching operation between the from and to resources.
-// Applies all patch types unless an 'only' filter is supplied.
-func Apply(p v1.Patch, cp resource.Composite, cd resource.Composed, only ...v1.PatchType) error {
-	return ApplyToObjects(p, cp, cd, only...)
+// Applies all patch types unless the OnlyPatchTypes option is supplied.
+func Apply(p v1.Patch, cp resource.Composite, cd resource.Composed, o ...ApplyOption) error {
+	return ApplyToObjects(p, cp, cd, o...)
+}
+
+// ApplyWithResult works like Apply, but also returns whether the patch changed
+// the resource it patches. Callers may use the result to determine whether the
+// resource needs to be updated. No result is returned with an error.
+func ApplyWithResult(p v1.Patch, cp resource.Composite, cd resource.Composed, o ...ApplyOption) (PatchResult, error) {
+	var to runtime.Object = cp
+	if patchesComposed(p) {
+		to = cd
+	}
+	existing := to.DeepCopyObject()
+
+	writing := false
+	track := func(ao *applyOptions) {
+		ao.writing = func() { writing = true }
+	}
+	if err := Apply(p, cp, cd, append(o[:len(o):len(o)], track)...); err != nil {
+		return "", err
+	}
+	if !writing {
+		return PatchResultSkippedOptional, nil
+	}
+	if reflect.DeepEqual(existing, to) {
+		return PatchResultNoChange, nil
+	}
+	return PatchResultApplied, nil
+}
+
+// ResolvePatch returns the value the supplied patch would write if it were
+// applied, without writing it. The value is read and transformed exactly as
+// Apply would. ErrPatchSkipped is returned if the patch would not write a
+// value, for example because it is filtered by the supplied options, or
+// because its optional fromFieldPath doesn't exist.
+func ResolvePatch(p v1.Patch, cp resource.Composite, cd resource.Composed, o ...ApplyOption) (any, error) {
+	var out any
+	resolved := false
+	capture := func(ao *applyOptions) {
+		ao.resolved = func(v any) {
+			out = v
+			resolved = true
+		}
+	}
+	if err := Apply(p, cp, cd, append(o[:len(o):len(o)], capture)...); err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, ErrPatchSkipped
+	}
+	return out, nil
+}
+
+// ApplyResource applies the supplied patches of a composed resource's template
+// in order, stopping at the first patch that returns an error. The error is
+// wrapped with the index of the patch. The supplied options, for example
+// OnlyPatchTypes, are passed to each patch.
+func ApplyResource(cp resource.Composite, cd resource.Composed, ps []v1.Patch, o ...ApplyOption) error {
+	for i := range ps {
+		if err := Apply(ps[i], cp, cd, o...); err != nil {
+			return errors.Wrapf(err, errFmtPatch, i)
+		}
+	}
+	return nil
+}
+
+// ApplyResourceContinue works like ApplyResource, but applies every patch even
+// if an earlier patch returns an error. It returns an aggregate of the errors
+// returned by the patches, each wrapped with the index of its patch.
+func ApplyResourceContinue(cp resource.Composite, cd resource.Composed, ps []v1.Patch, o ...ApplyOption) error {
+	errs := make([]error, 0)
+	for i := range ps {
+		if err := Apply(ps[i], cp, cd, o...); err != nil {
+			errs = append(errs, errors.Wrapf(err, errFmtPatch, i))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
 }
 
 // ApplyToObjects works like c.Apply but accepts any kind of runtime.Object
 // (such as EnvironmentConfigs).
 // It might be vulnerable to conversion panics
 // (see https://github.com/crossplane/crossplane/pull/3394 for details).
-func ApplyToObjects(p v1.Patch, cp, cd runtime.Object, only ...v1.PatchType) error {
-	if filterPatch(p, only...) {
+func ApplyToObjects(p v1.Patch, cp, cd runtime.Object, o ...ApplyOption) error { //nolint:gocyclo // This is a long but simple/same-y switch.
+	if newApplyOptions(o...).skip(p) {
 		return nil
 	}
 
 	switch p.GetType() {
 	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeFromEnvironmentFieldPath:
-		return ApplyFromFieldPathPatch(p, cp, cd)
+		return ApplyFromFieldPathPatch(p, cp, cd, o...)
 	case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeToEnvironmentFieldPath:
-		return ApplyFromFieldPathPatch(p, cd, cp)
+		return ApplyFromFieldPathPatch(p, cd, cp, o...)
 	case v1.PatchTypeCombineFromComposite, v1.PatchTypeCombineFromEnvironment:
-		return ApplyCombineFromVariablesPatch(p, cp, cd)
+		return ApplyCombineFromVariablesPatch(p, cp, cd, o...)
 	case v1.PatchTypeCombineToComposite, v1.PatchTypeCombineToEnvironment:
-		return ApplyCombineFromVariablesPatch(p, cd, cp)
+		return ApplyCombineFromVariablesPatch(p, cd, cp, o...)
+	case v1.PatchTypeFromComposedFieldPath:
+		return ApplyFromComposedFieldPathPatch(p, cd, o...)
+	case v1.PatchTypeFromControllerConfig:
+		return ApplyFromControllerConfigPatch(p, cd, o...)
+	case v1.PatchTypeFromConnectionSecretKey:
+		return ApplyFromConnectionSecretKeyPatch(p, cp, cd, o...)
+	case v1.PatchTypeFromComposedConnectionSecretKey:
+		return ApplyFromComposedConnectionSecretKeyPatch(p, cd, o...)
 	case v1.PatchTypePatchSet:
 		// Already resolved - nothing to do.
+	case v1.PatchTypeNoop:
+		return nil
+	}
+	return v1.InvalidPatchTypeError(p.Type)
+}
+
+// skip returns true if the supplied patch should not be applied, either
+// because it's filtered out by type or tag, because it's immutable after the
+// composed resource is created, or because it's skipped while the composite
+// resource is being deleted.
+func (o *applyOptions) skip(p v1.Patch) bool {
+	if filterPatch(p, o.only...) || filterPatchTags(p, o.tags...) {
+		return true
+	}
+	if o.exists && p.Policy.IsImmutableAfterCreate() && patchesComposed(p) {
+		return true
 	}
-	return errors.Errorf(errFmtInvalidPatchType, p.Type)
+	return o.deleting && p.Policy.IsSkipWhenDeleting()
+}
+
+// patchesComposed returns true if the supplied patch writes to the composed
+// resource.
+func patchesComposed(p v1.Patch) bool {
+	switch p.GetType() { //nolint:exhaustive // Only patch types that write to the composed resource are relevant.
+	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeFromEnvironmentFieldPath,
+		v1.PatchTypeCombineFromComposite, v1.PatchTypeCombineFromEnvironment,
+		v1.PatchTypeFromComposedFieldPath, v1.PatchTypeFromControllerConfig,
+		v1.PatchTypeFromConnectionSecretKey, v1.PatchTypeFromComposedConnectionSecretKey:
+		return true
+	}
+	return false
 }
 
 // filterPatch returns true if patch should be filtered (not applied)
@@ -113,14 +617,52 @@ func filterPatch(p v1.Patch, only ...v1.PatchType) bool {
 	return true
 }
 
-// ResolveTransforms applies a list of transforms to a patch value.
+// filterPatchTags returns true if patch should be filtered (not applied)
+// because it has none of the supplied tags.
+func filterPatchTags(p v1.Patch, tags ...string) bool {
+	// filter does not apply if not set
+	if len(tags) == 0 {
+		return false
+	}
+
+	for _, t := range tags {
+		for _, pt := range p.Tags {
+			if t == pt {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ResolveTransforms applies a list of transforms to a patch value. A transform
+// whose OnError policy is 'skip' passes its input through unchanged if it
+// returns an error, and a transform whose When condition isn't met passes its
+// input through unchanged without running.
 func ResolveTransforms(c v1.Patch, input any) (any, error) {
-	var err error
-	for i, t := range c.Transforms {
-		if input, err = Resolve(t, input); err != nil {
+	return resolveTransforms(c.Transforms, input)
+}
+
+func resolveTransforms(ts []v1.Transform, input any) (any, error) {
+	for i, t := range ts {
+		if t.When != nil {
+			ok, err := EvaluateTransformCondition(*t.When, input)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtTransformConditionAtIndex, i)
+			}
+			if !ok {
+				continue
+			}
+		}
+		out, err := Resolve(t, input)
+		if err != nil {
+			if t.GetOnError() == v1.TransformOnErrorPolicySkip {
+				continue
+			}
 			// TODO(negz): Including the type might help find the offending transform faster.
 			return nil, errors.Wrapf(err, errFmtTransformAtIndex, i)
 		}
+		input = out
 	}
 	return input, nil
 }
@@ -155,187 +697,1348 @@ func patchFieldValueToMultiple(fieldPath string, value any, to runtime.Object, m
 // ApplyFromFieldPathPatch patches the "to" resource, using a source field
 // on the "from" resource. Values may be transformed if any are defined on
 // the patch.
-func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
+func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object, o ...ApplyOption) error {
 	if p.FromFieldPath == nil {
 		return errors.Errorf(errFmtRequiredField, "FromFieldPath", p.Type)
 	}
 
 	// Default to patching the same field on the composed resource.
-	if p.ToFieldPath == nil {
+	if p.ToFieldPath == nil && len(p.ToFieldPaths) == 0 {
 		p.ToFieldPath = p.FromFieldPath
 	}
 
-	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+	ao := newApplyOptions(o...)
+	src, err := ao.resolver(from)
 	if err != nil {
 		return err
 	}
 
-	in, err := fieldpath.Pave(fromMap).GetValue(*p.FromFieldPath)
-	if IsOptionalFieldPathNotFound(err, p.Policy) {
-		return nil
+	in, transform, skip, err := fromFieldPathInput(p, src)
+	if err != nil || skip {
+		return err
 	}
+
+	toFieldPaths, err := resolveToFieldPaths(p.GetToFieldPaths(), src)
 	if err != nil {
 		return err
 	}
 
+	// The value is read before it's written, so a patch may read from and
+	// write to the same field path of the same object, for example to
+	// increment a counter.
+	out, write, err := ao.output(p, in, transform)
+	if err != nil || !write {
+		return err
+	}
+
+	return writeToFieldPaths(toFieldPaths, func(toFieldPath string) error {
+		return patchValueToObject(p, toFieldPath, out, to)
+	})
+}
+
+// fromFieldPathInput returns the value the supplied patch reads from its
+// FromFieldPath of the supplied source, or the patch's FromFieldPathDefault if
+// an optional FromFieldPath doesn't exist. It returns true if the value should
+// be transformed, which a default is only if the patch's policy says so, and
+// whether the patch should be skipped because neither exists.
+func fromFieldPathInput(p v1.Patch, src FieldPathResolver) (in any, transform, skip bool, err error) {
+	in, err = src.GetValue(*p.FromFieldPath)
+	if err == nil {
+		return in, true, false, nil
+	}
+	if !IsOptionalFieldPathNotFound(err, p.Policy) {
+		return nil, false, false, err
+	}
+	d := p.Policy.GetFromFieldPathDefault()
+	if d == nil {
+		return nil, false, true, nil
+	}
+	if err := json.Unmarshal(d.Raw, &in); err != nil {
+		return nil, false, false, errors.Wrap(err, errFromFieldPathDefault)
+	}
+	return in, p.Policy.IsTransformFromFieldPathDefault(), false, nil
+}
+
+// patchValueToObject writes the supplied value to the supplied field path of
+// the supplied object, as the supplied patch's policy dictates.
+func patchValueToObject(p v1.Patch, toFieldPath string, out any, to runtime.Object) error {
 	var mo *xpv1.MergeOptions
 	if p.Policy != nil {
 		mo = p.Policy.MergeOptions
 	}
-
-	// Apply transform pipeline
-	out, err := ResolveTransforms(p, in)
-	if err != nil {
-		return err
+	if ep := p.Policy.GetToEmbeddedJSON(); ep != nil {
+		return embeddedJSONToObject(toFieldPath, ep, out, to, mo)
+	}
+	if p.Policy.IsMergeConditions() {
+		return mergeConditionsToObject(toFieldPath, out, to)
+	}
+	if p.Policy.IsStrategicMerge() {
+		return strategicMergeToObject(toFieldPath, out, to)
 	}
 
 	// Patch all expanded fields if the ToFieldPath contains wildcards
-	if strings.Contains(*p.ToFieldPath, "[*]") {
-		return patchFieldValueToMultiple(*p.ToFieldPath, out, to, mo)
+	if strings.Contains(toFieldPath, "[*]") {
+		return patchFieldValueToMultiple(toFieldPath, out, to, mo)
 	}
 
-	return patchFieldValueToObject(*p.ToFieldPath, out, to, mo)
+	return patchFieldValueToObject(toFieldPath, out, to, mo)
 }
 
-// ApplyCombineFromVariablesPatch patches the "to" resource, taking a list of
-// input variables and combining them into a single output value.
-// The single output value may then be further transformed if they are defined
-// on the patch.
-func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
-	// Combine patch requires configuration
-	if p.Combine == nil {
-		return errors.Errorf(errFmtRequiredField, "Combine", p.Type)
-	}
-	// Destination field path is required since we can't default to multiple
-	// fields.
-	if p.ToFieldPath == nil {
-		return errors.Errorf(errFmtRequiredField, "ToFieldPath", p.Type)
+// resolveToFieldPaths resolves any templated key segments in each of the
+// supplied field paths. See ResolveToFieldPath.
+func resolveToFieldPaths(paths []string, src FieldPathResolver) ([]string, error) {
+	out := make([]string, len(paths))
+	for i, path := range paths {
+		r, err := ResolveToFieldPath(path, src)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = r
 	}
+	return out, nil
+}
 
-	vl := len(p.Combine.Variables)
+// writeToFieldPaths calls the supplied function to write a patch's value to
+// each of the supplied field paths. Writes are independent; failing to write to
+// one field path doesn't prevent writing to the others. The error of a patch
+// that writes to a single field path is returned as is.
+func writeToFieldPaths(paths []string, write func(path string) error) error {
+	if len(paths) == 1 {
+		return write(paths[0])
+	}
+	errs := make([]error, 0)
+	for _, path := range paths {
+		if err := write(path); err != nil {
+			errs = append(errs, errors.Wrapf(err, errFmtPatchToFieldPath, path))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
 
-	if vl < 1 {
-		return errors.New(errCombineRequiresVariables)
+// mergeConditionsToObject merges the supplied array of conditions into the
+// array of conditions at the given path of the "to" object by condition type.
+// A condition replaces the existing condition of the same type, keeping the
+// existing lastTransitionTime if its status is unchanged. Other existing
+// conditions are kept, and new conditions are appended.
+func mergeConditionsToObject(fieldPath string, value any, to runtime.Object) error {
+	in, ok := value.([]any)
+	if !ok {
+		return errors.New(errMergeConditionsNonArray)
 	}
 
-	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+	v, err := existingValue(fieldPath, to)
 	if err != nil {
 		return err
 	}
-
-	in := make([]any, vl)
-
-	// Get value of each variable
-	// NOTE: This currently assumes all variables define a 'fromFieldPath'
-	// value. If we add new variable types, this may not be the case and
-	// this code may be better served split out into a dedicated function.
-	for i, sp := range p.Combine.Variables {
-		iv, err := fieldpath.Pave(fromMap).GetValue(sp.FromFieldPath)
-
-		// If any source field is not found, we will not
-		// apply the patch. This is to avoid situations
-		// where a combine patch is expecting a fixed
-		// number of inputs (e.g. a string format
-		// expecting 3 fields '%s-%s-%s' but only
-		// receiving 2 values).
-		if IsOptionalFieldPathNotFound(err, p.Policy) {
-			return nil
-		}
-		if err != nil {
-			return err
+	var existing []any
+	if v != nil {
+		if existing, ok = v.([]any); !ok {
+			return errors.New(errMergeConditionsExisting)
 		}
-		in[i] = iv
 	}
 
-	// Combine input values
-	cb, err := Combine(*p.Combine, in)
+	out, err := mergeConditions(existing, in)
 	if err != nil {
 		return err
 	}
+	return patchFieldValueToObject(fieldPath, out, to, nil)
+}
 
-	// Apply transform pipeline
-	out, err := ResolveTransforms(p, cb)
+// existingValue returns the value at the given path of the "to" object, or nil
+// if there is none.
+func existingValue(fieldPath string, to runtime.Object) (any, error) {
+	paved, err := fieldpath.PaveObject(to)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	v, err := paved.GetValue(fieldPath)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return nil, err
+	}
+	return v, nil
+}
 
-	return patchFieldValueToObject(*p.ToFieldPath, out, to, nil)
+// mergeConditions merges the supplied conditions into the supplied existing
+// conditions by condition type. See mergeConditionsToObject.
+func mergeConditions(existing, in []any) ([]any, error) {
+	out := make([]any, 0, len(existing)+len(in))
+	idx := make(map[string]int, len(existing)+len(in))
+	for i, c := range existing {
+		_, t, ok := asCondition(c)
+		if !ok {
+			return nil, errors.Errorf(errFmtMergeConditionsExistingIdx, i)
+		}
+		idx[t] = len(out)
+		out = append(out, c)
+	}
+	for i, c := range in {
+		m, t, ok := asCondition(c)
+		if !ok {
+			return nil, errors.Errorf(errFmtMergeConditionsNotCondition, i)
+		}
+		j, ok := idx[t]
+		if !ok {
+			idx[t] = len(out)
+			out = append(out, c)
+			continue
+		}
+		old, _, _ := asCondition(out[j])
+		out[j] = replaceCondition(old, m)
+	}
+	return out, nil
 }
 
-// IsOptionalFieldPathNotFound returns true if the supplied error indicates a
-// field path was not found, and the supplied policy indicates a patch from that
-// field path was optional.
-func IsOptionalFieldPathNotFound(err error, p *v1.PatchPolicy) bool {
-	switch {
-	case p == nil:
-		fallthrough
-	case p.FromFieldPath == nil:
-		fallthrough
-	case *p.FromFieldPath == v1.FromFieldPathPolicyOptional:
-		return fieldpath.IsNotFound(err)
-	default:
-		return false
+// replaceCondition returns a copy of the supplied new condition that keeps the
+// lastTransitionTime of the supplied old condition if its status is unchanged.
+func replaceCondition(old, m map[string]any) map[string]any {
+	nc := make(map[string]any, len(m))
+	for k, v := range m {
+		nc[k] = v
 	}
+	if ltt, ok := old["lastTransitionTime"]; ok && reflect.DeepEqual(old["status"], nc["status"]) {
+		nc["lastTransitionTime"] = ltt
+	}
+	return nc
 }
 
-// Combine calls the appropriate combiner.
-func Combine(c v1.Combine, vars []any) (any, error) {
-	var out any
-	var err error
+// strategicMergeToObject merges the supplied object into the object at the
+// given path of the "to" object using strategic merge semantics. Merge keys
+// are looked up by the apiVersion and kind of the existing object, or of the
+// supplied object if the existing object has none. Objects whose kind isn't
+// built in to Kubernetes are merged like a JSON merge patch.
+func strategicMergeToObject(fieldPath string, value any, to runtime.Object) error {
+	patch, ok := value.(map[string]any)
+	if !ok {
+		return errors.New(errStrategicMergeNonObject)
+	}
 
-	switch c.Strategy {
-	case v1.CombineStrategyString:
-		if c.String == nil {
-			return nil, errors.Errorf(errFmtCombineConfigMissing, c.Strategy)
+	paved, err := fieldpath.PaveObject(to)
+	if err != nil {
+		return err
+	}
+	v, err := paved.GetValue(fieldPath)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return err
+	}
+	original := map[string]any{}
+	if v != nil {
+		if original, ok = v.(map[string]any); !ok {
+			return errors.New(errStrategicMergeExisting)
 		}
-		out, err = CombineString(c.String.Format, vars)
-	default:
-		return nil, errors.Errorf(errFmtCombineStrategyNotSupported, c.Strategy)
 	}
 
-	// Note: There are currently no tests or triggers to exercise this error as
-	// our only strategy ("String") uses fmt.Sprintf, which cannot return an error.
-	return out, errors.Wrapf(err, errFmtCombineStrategyFailed, string(c.Strategy))
+	var merged map[string]any
+	if obj, ok := builtinObject(original, patch); ok {
+		merged, err = strategicpatch.StrategicMergeMapPatch(original, patch, obj)
+		if err != nil {
+			return errors.Wrap(err, errStrategicMerge)
+		}
+	} else {
+		merged = mergeObjects(original, patch)
+	}
+
+	if err := paved.SetValue(fieldPath, merged); err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(paved.UnstructuredContent(), to)
 }
 
-// CombineString returns a single output by running a string format with all of
+// embeddedJSONToObject patches the supplied value to the field path of the
+// embedded JSON object that is encoded as a string at the given path of the
+// "to" object. A string that doesn't exist, is empty, or is null is patched as
+// though it were an empty object. A string that isn't a JSON object returns an
+// error, unless the policy initializes it to an empty object.
+func embeddedJSONToObject(fieldPath string, ep *v1.EmbeddedJSONPolicy, value any, to runtime.Object, mo *xpv1.MergeOptions) error {
+	v, err := existingValue(fieldPath, to)
+	if err != nil {
+		return err
+	}
+	s := ""
+	if v != nil {
+		var ok bool
+		if s, ok = v.(string); !ok {
+			return errors.New(errEmbeddedJSONNonString)
+		}
+	}
+
+	doc, err := embeddedJSONDocument(s, ep)
+	if err != nil {
+		return err
+	}
+
+	embedded := fieldpath.Pave(doc)
+	if err := embedded.MergeValue(ep.FieldPath, value, mo); err != nil {
+		return err
+	}
+	b, err := json.Marshal(embedded.UnstructuredContent())
+	if err != nil {
+		return errors.Wrap(err, errEmbeddedJSONEncode)
+	}
+	return patchFieldValueToObject(fieldPath, string(b), to, nil)
+}
+
+// embeddedJSONDocument returns the JSON object embedded in the supplied string.
+// An empty string embeds an empty object, as does invalid JSON if the supplied
+// policy initializes it.
+func embeddedJSONDocument(s string, ep *v1.EmbeddedJSONPolicy) (map[string]any, error) {
+	var doc map[string]any
+	if strings.TrimSpace(s) != "" {
+		if err := json.Unmarshal([]byte(s), &doc); err != nil {
+			if !ep.IsInitializeInvalid() {
+				return nil, errors.Wrap(err, errEmbeddedJSONInvalid)
+			}
+			doc = nil
+		}
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+// builtinObject returns a new object of the kind of the first of the supplied
+// objects that has an apiVersion and kind, if it's a kind built in to
+// Kubernetes.
+func builtinObject(objs ...map[string]any) (runtime.Object, bool) {
+	for _, o := range objs {
+		av, _ := o["apiVersion"].(string)
+		k, _ := o["kind"].(string)
+		if av == "" || k == "" {
+			continue
+		}
+		obj, err := kscheme.Scheme.New(schema.FromAPIVersionAndKind(av, k))
+		return obj, err == nil
+	}
+	return nil, false
+}
+
+// mergeObjects merges the supplied patch into a copy of the original object
+// like a JSON merge patch (RFC 7386). Objects are merged recursively, a null
+// value removes a field, and any other value replaces the existing value.
+func mergeObjects(original, patch map[string]any) map[string]any {
+	out := make(map[string]any, len(original)+len(patch))
+	for k, v := range original {
+		out[k] = v
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		if pm, ok := v.(map[string]any); ok {
+			om, _ := out[k].(map[string]any)
+			out[k] = mergeObjects(om, pm)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// asCondition returns the supplied condition as an object, its type, and
+// whether it is a condition, i.e. an object with a type.
+func asCondition(c any) (map[string]any, string, bool) {
+	m, ok := c.(map[string]any)
+	if !ok {
+		return nil, "", false
+	}
+	t, ok := m["type"].(string)
+	return m, t, ok && t != ""
+}
+
+// ApplyFromComposedFieldPathPatch patches the "to" resource, using a source
+// field on another composed resource. The source resource is selected from
+// those supplied by the WithComposedResources option. A source resource that
+// doesn't exist is handled like a source field path that doesn't exist: the
+// patch returns an error if its policy requires the source field path, patches
+// its FromFieldPathDefault if it has one, and is otherwise a no-op.
+func ApplyFromComposedFieldPathPatch(p v1.Patch, to runtime.Object, o ...ApplyOption) error {
+	if p.FromComposedResource == nil {
+		return errors.Errorf(errFmtRequiredField, "FromComposedResource", p.Type)
+	}
+
+	from, err := selectComposedResource(*p.FromComposedResource, newApplyOptions(o...).composed)
+	if err != nil {
+		if p.Policy.GetFromFieldPathPolicy() == v1.FromFieldPathPolicyRequired {
+			return err
+		}
+		if p.Policy.GetFromFieldPathDefault() == nil {
+			return nil
+		}
+		// Patch from an empty source, in which the FromFieldPath doesn't
+		// exist.
+		empty := WithFieldPathResolver(func(_ runtime.Object) (FieldPathResolver, error) { return fieldpath.Pave(map[string]any{}), nil })
+		return ApplyFromFieldPathPatch(p, nil, to, append(o[:len(o):len(o)], empty)...)
+	}
+
+	return ApplyFromFieldPathPatch(p, from, to, o...)
+}
+
+// ApplyFromControllerConfigPatch patches the "to" resource, using a value from
+// the controller configuration supplied by the WithControllerConfig option.
+// A value that doesn't exist, including because no controller configuration
+// was supplied, is handled according to the patch's policy.
+func ApplyFromControllerConfigPatch(p v1.Patch, to runtime.Object, o ...ApplyOption) error {
+	var cfg ControllerConfig = fieldpath.Pave(map[string]any{})
+	if c := newApplyOptions(o...).config; c != nil {
+		cfg = c
+	}
+	resolver := WithFieldPathResolver(func(_ runtime.Object) (FieldPathResolver, error) { return cfg, nil })
+	return ApplyFromFieldPathPatch(p, nil, to, append(o[:len(o):len(o)], resolver)...)
+}
+
+// ApplyFromConnectionSecretKeyPatch patches the "to" resource, using the value
+// of a connection secret key resolved by the resolver supplied by the
+// WithConnectionSecretResolver option. A key that doesn't exist, including
+// because no resolver was supplied, is handled according to the patch's
+// policy. Templated key segments of the patch's ToFieldPaths are resolved using
+// the "from" resource, whose connection secret the key belongs to. Errors never
+// include the value of the key.
+func ApplyFromConnectionSecretKeyPatch(p v1.Patch, from, to runtime.Object, o ...ApplyOption) error {
+	if p.ConnectionSecretKey == nil {
+		return errors.Errorf(errFmtRequiredField, "ConnectionSecretKey", p.Type)
+	}
+	if p.ToFieldPath == nil && len(p.ToFieldPaths) == 0 {
+		return errors.Errorf(errFmtRequiredField, "ToFieldPath", p.Type)
+	}
+	key := *p.ConnectionSecretKey
+
+	ao := newApplyOptions(o...)
+	var val []byte
+	var exists bool
+	if r := ao.secrets; r != nil {
+		var err error
+		val, exists, err = r.ResolveConnectionSecretKey(key)
+		if err != nil {
+			return errors.Wrapf(err, errFmtResolveConnectionSecretKey, key)
+		}
+	}
+	if !exists {
+		if p.Policy.GetFromFieldPathPolicy() == v1.FromFieldPathPolicyRequired {
+			return errors.Errorf(errFmtConnectionSecretKeyNotFound, key)
+		}
+		return nil
+	}
+	src, err := ao.resolver(from)
+	if err != nil {
+		return err
+	}
+	return applyConnectionSecretValue(p, key, val, src, to, ao)
+}
+
+// ApplyFromComposedConnectionSecretKeyPatch patches the "to" resource, using
+// the value of a key of the connection details of another composed resource,
+// selected by name. The value is resolved by the resolver supplied by the
+// WithComposedConnectionSecretResolver option, or else read from the composed
+// resources supplied by the WithComposedResources option. A key that doesn't
+// exist, including because the composed resource doesn't exist, is handled
+// according to the patch's policy. Templated key segments of the patch's
+// ToFieldPaths are resolved using the composed resource, if it was supplied by
+// the WithComposedResources option. Errors never include the value of the key.
+func ApplyFromComposedConnectionSecretKeyPatch(p v1.Patch, to runtime.Object, o ...ApplyOption) error {
+	if p.FromComposedResource == nil || p.FromComposedResource.Name == nil {
+		return errors.Errorf(errFmtRequiredField, "FromComposedResource.Name", p.Type)
+	}
+	if p.ConnectionSecretKey == nil {
+		return errors.Errorf(errFmtRequiredField, "ConnectionSecretKey", p.Type)
+	}
+	if p.ToFieldPath == nil && len(p.ToFieldPaths) == 0 {
+		return errors.Errorf(errFmtRequiredField, "ToFieldPath", p.Type)
+	}
+	name, key := *p.FromComposedResource.Name, *p.ConnectionSecretKey
+
+	ao := newApplyOptions(o...)
+	var r ComposedConnectionSecretResolver = ComposedConnectionDetails(ao.composed)
+	if ao.composedSecrets != nil {
+		r = ao.composedSecrets
+	}
+	val, exists, err := r.ResolveComposedConnectionSecretKey(name, key)
+	if err != nil {
+		return errors.Wrapf(err, errFmtResolveComposedConnectionSecretKey, key, name)
+	}
+	if !exists {
+		if p.Policy.GetFromFieldPathPolicy() == v1.FromFieldPathPolicyRequired {
+			return errors.Errorf(errFmtComposedConnectionSecretKeyNotFound, key, name)
+		}
+		return nil
+	}
+	var src FieldPathResolver = fieldpath.Pave(map[string]any{})
+	if from, err := selectComposedResource(v1.ComposedResourceSelector{Name: &name}, ao.composed); err == nil {
+		if src, err = ao.resolver(from); err != nil {
+			return err
+		}
+	}
+	return applyConnectionSecretValue(p, key, val, src, to, ao)
+}
+
+// applyConnectionSecretValue patches the "to" resource using the supplied value
+// of the supplied connection secret key, like ApplyFromFieldPathPatch. Templated
+// key segments of the patch's ToFieldPaths are resolved using the supplied
+// source. Errors never include the value.
+func applyConnectionSecretValue(p v1.Patch, key string, val []byte, src FieldPathResolver, to runtime.Object, ao *applyOptions) error {
+	toFieldPaths, err := resolveToFieldPaths(p.GetToFieldPaths(), src)
+	if err != nil {
+		return err
+	}
+
+	out, err := ResolveTransforms(p, string(val))
+	if err != nil {
+		// Transform errors may include their input, so we don't return them.
+		err = errors.Errorf(errFmtTransformConnectionSecretKey, key)
+		if ao.skipTransformError(p, p.Policy.GetFromFieldPathPolicy(), err) {
+			return nil
+		}
+		return err
+	}
+	if write, err := ao.readyToWrite(p, out); err != nil || !write {
+		return err
+	}
+
+	return writeToFieldPaths(toFieldPaths, func(toFieldPath string) error {
+		return patchValueToObject(p, toFieldPath, out, to)
+	})
+}
+
+func selectComposedResource(s v1.ComposedResourceSelector, cds []ComposedResourceState) (resource.Composed, error) {
+	if s.Index != nil {
+		i := *s.Index
+		if i < 0 || i >= len(cds) || cds[i].Resource == nil || cds[i].TemplateRenderErr != nil {
+			return nil, errors.Errorf(errFmtComposedResourceIdxNotFound, i)
+		}
+		return cds[i].Resource, nil
+	}
+
+	name := pointer.StringDeref(s.Name, "")
+	for _, cd := range cds {
+		if cd.ResourceName != name {
+			continue
+		}
+		if cd.Resource == nil || cd.TemplateRenderErr != nil {
+			break
+		}
+		return cd.Resource, nil
+	}
+	return nil, errors.Errorf(errFmtComposedResourceNotFound, name)
+}
+
+// ResolveToFieldPath resolves any templated key segments in the supplied
+// ToFieldPath by replacing each {{ path }} with the string value found at that
+// path of the supplied source. This allows a patch to write to a key that is
+// derived from another field, for example an annotation whose key includes the
+// composite resource's region.
+func ResolveToFieldPath(path string, src FieldPathResolver) (string, error) {
+	var rerr error
+	out := toFieldPathKeyTemplate.ReplaceAllStringFunc(path, func(tmpl string) string {
+		if rerr != nil {
+			return tmpl
+		}
+		fp := toFieldPathKeyTemplate.FindStringSubmatch(tmpl)[1]
+		v, err := src.GetValue(fp)
+		if err != nil {
+			rerr = errors.Wrapf(err, errFmtResolveToFieldPathKey, tmpl)
+			return tmpl
+		}
+		s, ok := v.(string)
+		if !ok {
+			rerr = errors.Errorf(errFmtToFieldPathKeyNotString, tmpl, v)
+			return tmpl
+		}
+		if s == "" {
+			rerr = errors.Errorf(errFmtToFieldPathKeyEmpty, tmpl)
+			return tmpl
+		}
+		// The resolved key must not change the structure of the path it is
+		// substituted into.
+		if strings.ContainsAny(s, "[]{}") {
+			rerr = errors.Errorf(errFmtToFieldPathKeyInvalid, tmpl, s)
+			return tmpl
+		}
+		return s
+	})
+	if rerr != nil {
+		return "", rerr
+	}
+	return out, nil
+}
+
+// ApplyCombineFromVariablesPatch patches the "to" resource, taking a list of
+// input variables and combining them into a single output value.
+// The single output value may then be further transformed if they are defined
+// on the patch.
+func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object, o ...ApplyOption) error {
+	// Destination field path is required since we can't default to multiple
+	// fields.
+	if p.ToFieldPath == nil && len(p.ToFieldPaths) == 0 {
+		return errors.Errorf(errFmtRequiredField, "ToFieldPath", p.Type)
+	}
+
+	ao := newApplyOptions(o...)
+	src, err := ao.resolver(from)
+	if err != nil {
+		return err
+	}
+
+	cb, skip, err := combineValues(p, src, ao)
+	if err != nil || skip {
+		return err
+	}
+
+	toFieldPaths, err := resolveToFieldPaths(p.GetToFieldPaths(), src)
+	if err != nil {
+		return err
+	}
+
+	// Apply transform pipeline
+	out, write, err := ao.output(p, cb, true)
+	if err != nil || !write {
+		return err
+	}
+
+	return writeToFieldPaths(toFieldPaths, func(toFieldPath string) error {
+		return patchValueToObject(p, toFieldPath, out, to)
+	})
+}
+
+// combineValues returns the combined value of the supplied combine patch's
+// variables, read from the supplied source. It returns true if the patch
+// should be skipped.
+func combineValues(p v1.Patch, src FieldPathResolver, ao *applyOptions) (any, bool, error) {
+	// Combine patch requires configuration
+	if p.Combine == nil {
+		return nil, false, errors.Errorf(errFmtRequiredField, "Combine", p.Type)
+	}
+	if len(p.Combine.Variables) < 1 {
+		return nil, false, errors.New(errCombineRequiresVariables)
+	}
+
+	in, skip, err := combineVariables(p, src, ao)
+	if err != nil || skip {
+		return nil, skip, err
+	}
+
+	// Combine input values
+	cb, err := Combine(*p.Combine, in)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Only a coalesce of empty variables without a default has no value.
+	if cb == nil {
+		if p.Policy.GetFromFieldPathPolicy() == v1.FromFieldPathPolicyRequired {
+			return nil, false, errors.New(errCoalesceAllEmpty)
+		}
+		return nil, true, nil
+	}
+	return cb, false, nil
+}
+
+// output returns the value the supplied patch should write given the supplied
+// input, transforming it if requested. It returns true if the value should be
+// written.
+func (o *applyOptions) output(p v1.Patch, in any, transform bool) (any, bool, error) {
+	out := in
+	if transform {
+		var skip bool
+		var err error
+		if out, skip, err = o.transform(p, in); err != nil || skip {
+			return nil, false, err
+		}
+	}
+	write, err := o.readyToWrite(p, out)
+	return out, write, err
+}
+
+// transform resolves the supplied patch's transforms with the supplied input.
+// It returns true if the patch should be skipped, either because an optional
+// patch's optional fieldSelect or selectMatch transform selected a field that
+// doesn't exist or because transform errors are skipped.
+func (o *applyOptions) transform(p v1.Patch, in any) (out any, skip bool, err error) {
+	out, err = ResolveTransforms(p, in)
+	if IsOptionalFieldPathNotFound(err, p.Policy) {
+		return nil, true, nil
+	}
+	if err != nil && o.skipTransformError(p, p.Policy.GetFromFieldPathPolicy(), err) {
+		return nil, true, nil
+	}
+	return out, false, err
+}
+
+// readyToWrite checks the supplied value a patch would write against the
+// patch's skipWhenValue and expectedType, and passes it to the resolved or
+// writing callbacks. It returns true if the value should be written.
+func (o *applyOptions) readyToWrite(p v1.Patch, out any) (bool, error) {
+	if skip, err := skipValue(p, out); err != nil || skip {
+		return false, err
+	}
+	if err := checkExpectedType(p, out); err != nil {
+		return false, err
+	}
+	if o.resolved != nil {
+		o.resolved(out)
+		return false, nil
+	}
+	if o.writing != nil {
+		o.writing()
+	}
+	return true, nil
+}
+
+// combineVariables returns the transformed value of each of the supplied
+// combine patch's variables, read from the supplied source. Variables that are
+// omitted have a nil value. It returns true if the patch should be skipped.
+func combineVariables(p v1.Patch, src FieldPathResolver, ao *applyOptions) ([]any, bool, error) {
+	in := make([]any, len(p.Combine.Variables))
+
+	// Get value of each variable
+	// NOTE: This currently assumes all variables define a 'fromFieldPath'
+	// value. If we add new variable types, this may not be the case and
+	// this code may be better served split out into a dedicated function.
+	for i, sp := range p.Combine.Variables {
+		iv, err := src.GetValue(sp.FromFieldPath)
+		omit, skip, err := combineVariableNotFound(p, sp, err)
+		if err != nil || skip {
+			return nil, skip, err
+		}
+		if omit {
+			continue
+		}
+
+		// Transform each variable before it's combined with the others.
+		iv, err = resolveTransforms(sp.Transforms, iv)
+		if fieldpath.IsNotFound(err) && sp.GetFromFieldPathPolicy(p.Policy) == v1.FromFieldPathPolicyOptional {
+			return nil, true, nil
+		}
+		if err != nil {
+			err = errors.Wrapf(err, errFmtCombineVariable, i)
+			if ao.skipTransformError(p, sp.GetFromFieldPathPolicy(p.Policy), err) {
+				return nil, true, nil
+			}
+			return nil, false, err
+		}
+		in[i] = iv
+	}
+	return in, false, nil
+}
+
+// combineVariableNotFound handles the supplied error getting the value of the
+// supplied variable of a combine patch according to the variable's policy. It
+// returns whether the variable should be omitted, and whether the patch should
+// be skipped.
+func combineVariableNotFound(p v1.Patch, v v1.CombineVariable, err error) (omit, skip bool, rerr error) {
+	if !fieldpath.IsNotFound(err) {
+		return false, false, err
+	}
+
+	policy := v.GetFromFieldPathPolicy(p.Policy)
+	switch {
+	case v.Policy != nil && policy == v1.FromFieldPathPolicyRequired:
+		// A required variable must exist regardless of the strategy.
+		return false, false, err
+	case p.Combine.Strategy == v1.CombineStrategyCoalesce:
+		// Coalescing skips source fields that are not found.
+		return true, false, nil
+	case p.Combine.Strategy == v1.CombineStrategyArray && policy == v1.FromFieldPathPolicyOptional:
+		// Arrays skip optional source fields that are not found.
+		return true, false, nil
+	case IsOptionalFieldPathNotFound(err, p.Policy):
+		// If any source field is not found, we will not apply the patch.
+		// This is to avoid situations where a combine patch is expecting a
+		// fixed number of inputs (e.g. a string format expecting 3 fields
+		// '%s-%s-%s' but only receiving 2 values).
+		return false, true, nil
+	}
+	return false, false, err
+}
+
+// IsOptionalFieldPathNotFound returns true if the supplied error indicates a
+// field path was not found, and the supplied policy indicates a patch from that
+// field path was optional.
+func IsOptionalFieldPathNotFound(err error, p *v1.PatchPolicy) bool {
+	switch {
+	case p == nil:
+		fallthrough
+	case p.FromFieldPath == nil:
+		fallthrough
+	case *p.FromFieldPath == v1.FromFieldPathPolicyOptional:
+		return fieldpath.IsNotFound(err)
+	default:
+		return false
+	}
+}
+
+// Combine calls the appropriate combiner.
+func Combine(c v1.Combine, vars []any) (any, error) {
+	var out any
+	var err error
+
+	switch c.Strategy {
+	case v1.CombineStrategyString:
+		if c.String == nil {
+			return nil, errors.Errorf(errFmtCombineConfigMissing, c.Strategy)
+		}
+		out, err = CombineString(c.String.Format, vars)
+	case v1.CombineStrategyCoalesce:
+		out, err = CombineCoalesce(coalesceDefault(c.Coalesce), vars)
+	case v1.CombineStrategyPercentDiff:
+		out, err = CombinePercentDiff(vars)
+	case v1.CombineStrategyMath:
+		if c.Math == nil {
+			return nil, errors.Errorf(errFmtCombineConfigMissing, c.Strategy)
+		}
+		out, err = CombineMath(c.Math.Operation, vars)
+	case v1.CombineStrategyArray:
+		out, err = CombineArray(vars)
+	case v1.CombineStrategySet:
+		if c.Set == nil {
+			return nil, errors.Errorf(errFmtCombineConfigMissing, c.Strategy)
+		}
+		out, err = CombineSet(c.Set.Operation, vars)
+	default:
+		return nil, errors.Errorf(errFmtCombineStrategyNotSupported, c.Strategy)
+	}
+
+	return out, errors.Wrapf(err, errFmtCombineStrategyFailed, string(c.Strategy))
+}
+
+// coalesceDefault returns the default of the supplied coalesce configuration,
+// which may be nil.
+func coalesceDefault(c *v1.CoalesceCombine) *extv1.JSON {
+	if c == nil {
+		return nil
+	}
+	return c.Default
+}
+
+// CombineString returns a single output by running a string format with all of
 // its input variables.
 func CombineString(format string, vars []any) (any, error) {
 	return fmt.Sprintf(format, vars...), nil
 }
 
-// ComposedTemplates returns the supplied composed resource templates with any
-// supplied patchsets dereferenced.
-func ComposedTemplates(pss []v1.PatchSet, cts []v1.ComposedTemplate) ([]v1.ComposedTemplate, error) {
-	pn := make(map[string][]v1.Patch)
-	for _, s := range pss {
-		for _, p := range s.Patches {
-			if p.Type == v1.PatchTypePatchSet {
-				return nil, errors.New(errPatchSetType)
-			}
+// CombineCoalesce returns the first of its input variables that is not empty,
+// or the supplied default if all are empty. A variable is empty if it is nil,
+// or an empty string, array, or object. It returns nil if all variables are
+// empty and there is no default.
+func CombineCoalesce(dflt *extv1.JSON, vars []any) (any, error) {
+	for _, v := range vars {
+		if !isEmpty(v) {
+			return v, nil
 		}
-		pn[s.Name] = s.Patches
 	}
+	if dflt == nil {
+		return nil, nil
+	}
+	var out any
+	if err := json.Unmarshal(dflt.Raw, &out); err != nil {
+		return nil, errors.Wrap(err, errCoalesceDefault)
+	}
+	return out, nil
+}
 
-	ct := make([]v1.ComposedTemplate, len(cts))
-	for i, r := range cts {
-		var po []v1.Patch
-		for _, p := range r.Patches {
-			if p.Type != v1.PatchTypePatchSet {
-				po = append(po, p)
-				continue
-			}
-			if p.PatchSetName == nil {
-				return nil, errors.Errorf(errFmtRequiredField, "PatchSetName", p.Type)
+// CombinePercentDiff returns how much the second of its two input variables,
+// an observed value, differs from the first, a desired value, as a percentage
+// of the desired value. The result is a float64, which is negative if the
+// observed value is less than the desired value. Both variables must be
+// numbers, and the desired value may only be zero if the observed value is
+// too.
+func CombinePercentDiff(vars []any) (any, error) {
+	if len(vars) != 2 {
+		return nil, errors.Errorf(errFmtPercentDiffVariables, len(vars))
+	}
+	n := make([]float64, len(vars))
+	for i, v := range vars {
+		switch t := v.(type) {
+		case int64:
+			n[i] = float64(t)
+		case int:
+			n[i] = float64(t)
+		case float64:
+			n[i] = t
+		default:
+			return nil, errors.Errorf(errFmtPercentDiffNonNumber, i)
+		}
+	}
+	desired, observed := n[0], n[1]
+	if desired == 0 {
+		if observed == 0 {
+			return float64(0), nil
+		}
+		return nil, errors.New(errPercentDiffZero)
+	}
+	return (observed - desired) / math.Abs(desired) * 100, nil
+}
+
+// CombineMath returns the result of applying the supplied operation to its
+// input variables in order, from first to last. All variables must be numbers.
+// The result is an int64 if all variables are integers, and a float64
+// otherwise.
+func CombineMath(op v1.MathCombineOperation, vars []any) (any, error) {
+	if len(vars) == 0 {
+		return nil, errors.New(errCombineRequiresVariables)
+	}
+	if op != v1.MathCombineOperationAdd && op != v1.MathCombineOperationMultiply {
+		return nil, errors.Errorf(errFmtMathCombineOperation, op)
+	}
+
+	ints := make([]int64, len(vars))
+	floats := make([]float64, len(vars))
+	integer := true
+	for i, v := range vars {
+		switch t := v.(type) {
+		case int64:
+			ints[i], floats[i] = t, float64(t)
+		case int:
+			ints[i], floats[i] = int64(t), float64(t)
+		case float64:
+			floats[i] = t
+			integer = false
+		default:
+			return nil, errors.Errorf(errFmtMathCombineNonNumber, i)
+		}
+	}
+
+	if integer {
+		return combineNumbers(op, ints), nil
+	}
+	return combineNumbers(op, floats), nil
+}
+
+// combineNumbers returns the sum or product of the supplied non-empty array of
+// numbers.
+func combineNumbers[T int64 | float64](op v1.MathCombineOperation, ns []T) T {
+	out := ns[0]
+	for _, n := range ns[1:] {
+		if op == v1.MathCombineOperationAdd {
+			out += n
+			continue
+		}
+		out *= n
+	}
+	return out
+}
+
+// CombineArray returns an array of its input variables that are not empty, in
+// order. A variable is empty if it is nil, or an empty string, array, or
+// object. It returns an empty array if all variables are empty.
+func CombineArray(vars []any) (any, error) {
+	out := make([]any, 0, len(vars))
+	for _, v := range vars {
+		if !isEmpty(v) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// CombineSet returns the result of applying the supplied set operation to its
+// two input variables, which must be arrays. The result contains each element
+// once, in the order it first appears in the first, then the second variable.
+// Elements are equal if their JSON encodings are equal.
+func CombineSet(op v1.SetCombineOperation, vars []any) (any, error) {
+	if len(vars) != 2 {
+		return nil, errors.Errorf(errFmtSetCombineVariables, len(vars))
+	}
+	switch op {
+	case v1.SetCombineOperationDifference, v1.SetCombineOperationIntersection, v1.SetCombineOperationUnion:
+	default:
+		return nil, errors.Errorf(errFmtSetCombineOperation, op)
+	}
+
+	arrays, keys, err := setElements(vars)
+	if err != nil {
+		return nil, err
+	}
+	return combineSets(op, arrays, keys), nil
+}
+
+// setElements returns each of the supplied variables as an array, along with
+// the JSON encoding of each element of each array, by which elements are
+// compared.
+func setElements(vars []any) (arrays [][]any, keys [][]string, err error) {
+	arrays = make([][]any, len(vars))
+	keys = make([][]string, len(vars))
+	for i, v := range vars {
+		a, ok := v.([]any)
+		if !ok {
+			return nil, nil, errors.Errorf(errFmtSetCombineNonArray, i)
+		}
+		arrays[i] = a
+		keys[i] = make([]string, len(a))
+		for j, e := range a {
+			k, err := json.Marshal(e)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, errFmtSetCombineElement, j, i)
 			}
-			ps, ok := pn[*p.PatchSetName]
-			if !ok {
-				return nil, errors.Errorf(errFmtUndefinedPatchSet, *p.PatchSetName)
+			keys[i][j] = string(k)
+		}
+	}
+	return arrays, keys, nil
+}
+
+// combineSets applies the supplied set operation to the supplied two arrays,
+// whose elements are compared by the supplied keys. Elements are returned in
+// the order they first appear, without duplicates.
+func combineSets(op v1.SetCombineOperation, arrays [][]any, keys [][]string) []any {
+	second := make(map[string]bool, len(keys[1]))
+	for _, k := range keys[1] {
+		second[k] = true
+	}
+
+	out := make([]any, 0)
+	seen := make(map[string]bool)
+	add := func(k string, e any) {
+		if !seen[k] {
+			seen[k] = true
+			out = append(out, e)
+		}
+	}
+	for j, k := range keys[0] {
+		switch {
+		case op == v1.SetCombineOperationDifference && second[k]:
+		case op == v1.SetCombineOperationIntersection && !second[k]:
+		default:
+			add(k, arrays[0][j])
+		}
+	}
+	if op == v1.SetCombineOperationUnion {
+		for j, k := range keys[1] {
+			add(k, arrays[1][j])
+		}
+	}
+	return out
+}
+
+// isEmpty returns true if the supplied value is empty for the purposes of
+// CombineCoalesce and CombineArray. Numbers and booleans are never empty.
+func isEmpty(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	default:
+		return false
+	}
+}
+
+type inlineOptions struct {
+	evaluate bool
+	cp       resource.Composite
+	env      *env.Environment
+	tss      []v1.TransformSet
+}
+
+// An InlineOption configures how patch sets are inlined.
+type InlineOption func(o *inlineOptions)
+
+// WithPatchConditionSources supplies the composite resource and environment
+// that the conditions of PatchSet references are evaluated against. The
+// environment may be nil. Conditional references are inlined regardless of
+// their conditions by default.
+func WithPatchConditionSources(cp resource.Composite, e *env.Environment) InlineOption {
+	return func(o *inlineOptions) {
+		o.evaluate = true
+		o.cp = cp
+		o.env = e
+	}
+}
+
+// WithTransformSets supplies the transform sets that transforms of type
+// transformSet are replaced with. Transforms that reference a transform set are
+// invalid if no transform sets are supplied.
+func WithTransformSets(tss []v1.TransformSet) InlineOption {
+	return func(o *inlineOptions) {
+		o.tss = tss
+	}
+}
+
+// ComposedTemplates returns the supplied composed resource templates with any
+// supplied patchsets and transform sets dereferenced, per v1.InlineResources.
+// References to patchsets whose conditions are not met are dropped.
+func ComposedTemplates(pss []v1.PatchSet, cts []v1.ComposedTemplate, o ...InlineOption) ([]v1.ComposedTemplate, error) {
+	io := &inlineOptions{}
+	for _, fn := range o {
+		fn(io)
+	}
+
+	var met v1.PatchConditionEvaluator
+	if io.evaluate {
+		met = func(c v1.PatchCondition) (bool, error) {
+			return EvaluatePatchCondition(c, io.cp, io.env)
+		}
+	}
+	return v1.InlineResources(pss, io.tss, cts, met)
+}
+
+// EvaluatePatchCondition returns true if the supplied condition is met by the
+// supplied composite resource or environment. A condition on a field that
+// doesn't exist, or on an environment that is nil, is not met.
+func EvaluatePatchCondition(c v1.PatchCondition, cp resource.Composite, e *env.Environment) (bool, error) {
+	var o runtime.Object
+	switch c.GetSource() {
+	case v1.PatchConditionSourceComposite:
+		if cp == nil {
+			return false, nil
+		}
+		o = cp
+	case v1.PatchConditionSourceEnvironment:
+		if e == nil {
+			return false, nil
+		}
+		o = e
+	default:
+		return false, errors.Errorf(errFmtPatchConditionSource, c.Source)
+	}
+
+	r, err := PaveFieldPathResolver(o)
+	if err != nil {
+		return false, err
+	}
+	got, err := r.GetValue(c.FieldPath)
+	if fieldpath.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if c.Equals == nil {
+		return true, nil
+	}
+
+	var want any
+	if err := json.Unmarshal(c.Equals.Raw, &want); err != nil {
+		return false, errors.Wrap(err, errPatchConditionValue)
+	}
+	return jsonEqual(want, got)
+}
+
+// EvaluateTransformCondition returns true if the supplied input of a transform
+// meets the supplied condition. A Contains or Regexp condition is not met by
+// input of a type it doesn't apply to.
+func EvaluateTransformCondition(c v1.TransformCondition, input any) (bool, error) {
+	switch c.Operator {
+	case v1.TransformConditionOperatorRegexp:
+		return evaluateRegexpCondition(c, input)
+	case v1.TransformConditionOperatorEqual, v1.TransformConditionOperatorNotEqual, v1.TransformConditionOperatorContains:
+		return evaluateValueCondition(c, input)
+	}
+	return false, errors.Errorf(errFmtTransformConditionOperator, c.Operator)
+}
+
+// evaluateRegexpCondition returns true if the supplied input is a string that
+// matches the regular expression of the supplied Regexp condition.
+func evaluateRegexpCondition(c v1.TransformCondition, input any) (bool, error) {
+	if c.Regexp == nil {
+		return false, errors.Errorf(errFmtTransformConditionMissing, "regexp", c.Operator)
+	}
+	re, err := compiledRegexps.Compile(*c.Regexp)
+	if err != nil {
+		return false, errors.Wrap(err, errTransformConditionRegexp)
+	}
+	s, ok := input.(string)
+	return ok && re.MatchString(s), nil
+}
+
+// evaluateValueCondition returns true if the supplied input meets the supplied
+// Equal, NotEqual, or Contains condition, which compares it to a value.
+func evaluateValueCondition(c v1.TransformCondition, input any) (bool, error) {
+	if c.Value == nil {
+		return false, errors.Errorf(errFmtTransformConditionMissing, "value", c.Operator)
+	}
+	var want any
+	if err := json.Unmarshal(c.Value.Raw, &want); err != nil {
+		return false, errors.Wrap(err, errTransformConditionValue)
+	}
+	if c.Operator == v1.TransformConditionOperatorContains {
+		return containsValue(input, want)
+	}
+	eq, err := jsonEqual(want, input)
+	if c.Operator == v1.TransformConditionOperatorNotEqual {
+		return !eq, err
+	}
+	return eq, err
+}
+
+// containsValue returns true if the supplied input is a string that contains
+// the supplied string, or an array that contains the supplied value.
+func containsValue(input, want any) (bool, error) {
+	switch in := input.(type) {
+	case string:
+		s, ok := want.(string)
+		return ok && strings.Contains(in, s), nil
+	case []any:
+		for _, e := range in {
+			eq, err := jsonEqual(want, e)
+			if err != nil || eq {
+				return eq, err
 			}
-			po = append(po, ps...)
 		}
-		ct[i] = r
-		ct[i].Patches = po
 	}
-	return ct, nil
+	return false, nil
+}
+
+// jsonEqual returns true if the supplied value, unmarshalled from JSON, is
+// equal to the supplied value. The latter is round tripped through JSON so
+// that it's comparable with the unmarshalled value, e.g. so that all numbers
+// are float64.
+func jsonEqual(want, got any) (bool, error) {
+	b, err := json.Marshal(got)
+	if err != nil {
+		return false, err
+	}
+	got = nil
+	if err := json.Unmarshal(b, &got); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(want, got), nil
+}
+
+// skipValue returns true if the supplied value, which a patch is about to
+// write, equals the patch's SkipWhenValue.
+func skipValue(p v1.Patch, v any) (bool, error) {
+	if p.SkipWhenValue == nil {
+		return false, nil
+	}
+	var want any
+	if err := json.Unmarshal(p.SkipWhenValue.Raw, &want); err != nil {
+		return false, errors.Wrap(err, errSkipWhenValue)
+	}
+	return jsonEqual(want, v)
+}
+
+// checkExpectedType returns an error if the supplied value to be patched isn't
+// of the patch's expected type, if any. Values read from unstructured data
+// represent all integers as int64, so any integer type satisfies an expected
+// integer type. Any number satisfies an expected float64.
+func checkExpectedType(p v1.Patch, v any) error {
+	if p.ExpectedType == nil {
+		return nil
+	}
+	if v == nil {
+		return errors.Errorf(errFmtUnexpectedOutputType, *p.ExpectedType, "nil")
+	}
+	want, got := *p.ExpectedType, transformIOType(v)
+	switch {
+	case want == got:
+		return nil
+	case isIntType(want) && isIntType(got):
+		return nil
+	case want == v1.TransformIOTypeFloat64 && isIntType(got):
+		return nil
+	}
+	return errors.Errorf(errFmtUnexpectedOutputType, want, got)
+}
+
+// PatchFieldPaths returns the sorted, deduplicated field paths of the composite
+// resource and of composed resources that the supplied patch sets, composed
+// templates, and environment patches read from or write to. Patch sets and
+// transform sets are inlined before the field paths are computed. The field
+// paths read by combine patches' variables are included. Field paths of the
+// environment, the controller's configuration, and connection secrets are not.
+func PatchFieldPaths(pss []v1.PatchSet, tss []v1.TransformSet, cts []v1.ComposedTemplate, env *v1.EnvironmentConfiguration) (composite, composed []string, err error) {
+	ct, err := ComposedTemplates(pss, cts, WithTransformSets(tss))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fp := fieldPaths{composite: map[string]bool{}, composed: map[string]bool{}}
+	if env != nil {
+		for _, p := range env.Patches {
+			fp.addEnvironmentPatch(p)
+		}
+	}
+	for _, t := range ct {
+		for _, p := range t.Patches {
+			fp.addPatch(p)
+		}
+	}
+
+	return sortedKeys(fp.composite), sortedKeys(fp.composed), nil
+}
+
+// fieldPaths are the field paths of the composite and composed resources that
+// patches read from or write to.
+type fieldPaths struct {
+	composite map[string]bool
+	composed  map[string]bool
+}
+
+// addPatch adds the field paths the supplied patch of a composed template
+// reads from and writes to.
+func (fp fieldPaths) addPatch(p v1.Patch) {
+	p.Default()
+	reads, writes := readFieldPaths(p), p.GetToFieldPaths()
+	switch p.GetType() {
+	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite:
+		addAll(fp.composite, reads...)
+		addAll(fp.composed, writes...)
+	case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+		addAll(fp.composed, reads...)
+		addAll(fp.composite, writes...)
+	case v1.PatchTypeFromComposedFieldPath:
+		// These patches read from another composed resource.
+		addAll(fp.composed, reads...)
+		addAll(fp.composed, writes...)
+	case v1.PatchTypeToEnvironmentFieldPath, v1.PatchTypeCombineToEnvironment:
+		addAll(fp.composed, reads...)
+	case v1.PatchTypeFromEnvironmentFieldPath, v1.PatchTypeCombineFromEnvironment, v1.PatchTypeFromControllerConfig,
+		v1.PatchTypeFromConnectionSecretKey, v1.PatchTypeFromComposedConnectionSecretKey:
+		// These patches read from the environment, the controller's
+		// configuration, or a connection secret.
+		addAll(fp.composed, writes...)
+	case v1.PatchTypePatchSet, v1.PatchTypeNoop:
+		// These patches don't read from or write to a resource.
+	}
+}
+
+// addEnvironmentPatch adds the field paths of the composite resource the
+// supplied environment patch reads from or writes to.
+func (fp fieldPaths) addEnvironmentPatch(ep v1.EnvironmentPatch) {
+	ep.Default()
+	p := v1.Patch{Type: ep.Type, FromFieldPath: ep.FromFieldPath, Combine: ep.Combine, ToFieldPath: ep.ToFieldPath, ToFieldPaths: ep.ToFieldPaths}
+	switch p.GetType() {
+	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite:
+		addAll(fp.composite, readFieldPaths(p)...)
+	case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+		addAll(fp.composite, p.GetToFieldPaths()...)
+	case v1.PatchTypeFromEnvironmentFieldPath, v1.PatchTypeToEnvironmentFieldPath, v1.PatchTypeCombineFromEnvironment, v1.PatchTypeCombineToEnvironment,
+		v1.PatchTypePatchSet, v1.PatchTypeNoop, v1.PatchTypeFromComposedFieldPath, v1.PatchTypeFromControllerConfig,
+		v1.PatchTypeFromConnectionSecretKey, v1.PatchTypeFromComposedConnectionSecretKey:
+		// These patch types aren't supported by environment patches.
+	}
+}
+
+// readFieldPaths returns the field paths the supplied patch reads from; its
+// FromFieldPath, or the field paths of its combine variables.
+func readFieldPaths(p v1.Patch) []string {
+	if p.Combine != nil {
+		out := make([]string, len(p.Combine.Variables))
+		for i, v := range p.Combine.Variables {
+			out[i] = v.FromFieldPath
+		}
+		return out
+	}
+	if p.FromFieldPath != nil {
+		return []string{*p.FromFieldPath}
+	}
+	return nil
+}
+
+func addAll(m map[string]bool, keys ...string) {
+	for _, k := range keys {
+		m[k] = true
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
 }
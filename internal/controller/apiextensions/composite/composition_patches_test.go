@@ -18,22 +18,31 @@ package composite
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/utils/pointer"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 )
 
 func TestPatchApply(t *testing.T) {
@@ -49,10 +58,13 @@ func TestPatchApply(t *testing.T) {
 	}
 
 	type args struct {
-		patch v1.Patch
-		cp    *fake.Composite
-		cd    *fake.Composed
-		only  []v1.PatchType
+		patch    v1.Patch
+		cp       *fake.Composite
+		cd       *fake.Composed
+		only     []v1.PatchType
+		tags     []string
+		exists   bool
+		deleting bool
 	}
 	type want struct {
 		cp  *fake.Composite
@@ -92,43 +104,37 @@ func TestPatchApply(t *testing.T) {
 				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
 			},
 			want: want{
-				err: errors.Errorf(errFmtInvalidPatchType, "invalid-patchtype"),
+				err: v1.InvalidPatchTypeError("invalid-patchtype"),
 			},
 		},
-		"ValidCompositeFieldPathPatch": {
-			reason: "Should correctly apply a CompositeFieldPathPatch with valid settings",
+		"NoopPatch": {
+			reason: "Should do nothing when a Noop patch is applied",
 			args: args{
 				patch: v1.Patch{
-					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.labels"),
-					ToFieldPath:   pointer.String("objectMeta.labels"),
+					Type:        v1.PatchTypeNoop,
+					Description: pointer.String("Anchor"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
+						Name:   "cp",
+						Labels: map[string]string{"Test": "blah"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
-				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
-				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
 			},
 			want: want{
-				cd: &fake.Composed{
+				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
+						Name:   "cp",
+						Labels: map[string]string{"Test": "blah"},
 					},
+					ConnectionDetailsLastPublishedTimer: lpt,
 				},
-				err: nil,
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
 			},
 		},
-		"ValidCompositeFieldPathPatchWithNilLastPublishTime": {
+		"ValidCompositeFieldPathPatch": {
 			reason: "Should correctly apply a CompositeFieldPathPatch with valid settings",
 			args: args{
 				patch: v1.Patch{
@@ -143,6 +149,7 @@ func TestPatchApply(t *testing.T) {
 							"Test": "blah",
 						},
 					},
+					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
@@ -160,152 +167,126 @@ func TestPatchApply(t *testing.T) {
 				err: nil,
 			},
 		},
-		"ValidCompositeFieldPathPatchWithWildcards": {
-			reason: "When passed a wildcarded path, adds a field to each element of an array",
+		"TagFilterMatches": {
+			reason: "Should patch when the patch has one of the selected tags",
 			args: args{
 				patch: v1.Patch{
 					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.name"),
-					ToFieldPath:   pointer.String("objectMeta.ownerReferences[*].name"),
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					Tags:          []string{"early", "network"},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "test",
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						OwnerReferences: []metav1.OwnerReference{
-							{
-								Name:       "",
-								APIVersion: "v1",
-							},
-							{
-								Name:       "",
-								APIVersion: "v1alpha1",
-							},
-						},
-					},
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
+				tags: []string{"late", "early"},
 			},
 			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						OwnerReferences: []metav1.OwnerReference{
-							{
-								Name:       "test",
-								APIVersion: "v1",
-							},
-							{
-								Name:       "test",
-								APIVersion: "v1alpha1",
-							},
-						},
+						Name:   "cd",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 				},
 			},
 		},
-		"InvalidCompositeFieldPathPatchWithWildcards": {
-			reason: "When passed a wildcarded path, throws an error if ToFieldPath cannot be expanded",
+		"TagFilterDoesNotMatch": {
+			reason: "Should not patch when the patch has none of the selected tags",
 			args: args{
 				patch: v1.Patch{
 					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.name"),
-					ToFieldPath:   pointer.String("objectMeta.ownerReferences[*].badField"),
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					Tags:          []string{"early"},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "test",
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						OwnerReferences: []metav1.OwnerReference{
-							{
-								Name:       "test",
-								APIVersion: "v1",
-							},
-						},
-					},
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
+				tags: []string{"late"},
 			},
 			want: want{
-				err: errors.Errorf(errFmtExpandingArrayFieldPaths, "objectMeta.ownerReferences[*].badField"),
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
 			},
 		},
-		"MissingOptionalFieldPath": {
-			reason: "A FromFieldPath patch should be a no-op when an optional fromFieldPath doesn't exist",
+		"TagAndTypeFilter": {
+			reason: "Should not patch when the patch has a selected tag but not a selected type",
 			args: args{
 				patch: v1.Patch{
 					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.labels"),
-					ToFieldPath:   pointer.String("objectMeta.labels"),
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					Tags:          []string{"early"},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
+				only: []v1.PatchType{v1.PatchTypeToCompositeFieldPath},
+				tags: []string{"early"},
 			},
 			want: want{
 				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-					},
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
-				err: nil,
 			},
 		},
-		"MissingRequiredFieldPath": {
-			reason: "A FromFieldPath patch should return an error when a required fromFieldPath doesn't exist",
+		"ImmutableAfterCreateExists": {
+			reason: "Should not patch a composed resource that exists when the patch is immutable after create",
 			args: args{
 				patch: v1.Patch{
 					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("wat"),
-					Policy: &v1.PatchPolicy{
-						FromFieldPath: func() *v1.FromFieldPathPolicy {
-							s := v1.FromFieldPathPolicyRequired
-							return &s
-						}(),
-					},
-					ToFieldPath: pointer.String("wat"),
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					Policy:        &v1.PatchPolicy{ImmutableAfterCreate: pointer.Bool(true)},
 				},
 				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
+					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
+				exists: true,
 			},
 			want: want{
 				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-					},
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
-				err: errNotFound("wat"),
 			},
 		},
-		"ValidFromEnvironmentFieldPathPatch": {
-			reason: "Should correctly apply a FromEnvironmentFieldPathPatch with valid settings",
+		"ImmutableAfterCreateNotExists": {
+			reason: "Should patch a composed resource that doesn't exist yet when the patch is immutable after create",
 			args: args{
 				patch: v1.Patch{
-					Type:          v1.PatchTypeFromEnvironmentFieldPath,
-					FromFieldPath: pointer.String("objectMeta.labels"),
-					ToFieldPath:   pointer.String("objectMeta.labels"),
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					Policy:        &v1.PatchPolicy{ImmutableAfterCreate: pointer.Bool(true)},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
@@ -316,555 +297,549 @@ func TestPatchApply(t *testing.T) {
 			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
+						Name:   "cd",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 				},
-				err: nil,
 			},
 		},
-		"MissingFromEnvironmentRequiredFieldPath": {
-			reason: "A FromFieldPath patch should return an error when a required fromFieldPath doesn't exist",
+		"ImmutableAfterCreateToComposite": {
+			reason: "Should patch the composite resource from a composed resource that exists when the patch is immutable after create",
 			args: args{
 				patch: v1.Patch{
-					Type:          v1.PatchTypeFromEnvironmentFieldPath,
-					FromFieldPath: pointer.String("wat"),
-					Policy: &v1.PatchPolicy{
-						FromFieldPath: func() *v1.FromFieldPathPolicy {
-							s := v1.FromFieldPathPolicyRequired
-							return &s
-						}(),
-					},
-					ToFieldPath: pointer.String("wat"),
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					Policy:        &v1.PatchPolicy{ImmutableAfterCreate: pointer.Bool(true)},
 				},
 				cp: &fake.Composite{
+					ObjectMeta:                          metav1.ObjectMeta{Name: "cp"},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cd",
+						Labels: map[string]string{"region": "eu-west-1"},
+					},
 				},
+				exists: true,
 			},
 			want: want{
-				cd: &fake.Composed{
+				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
+					ConnectionDetailsLastPublishedTimer: lpt,
 				},
-				err: errNotFound("wat"),
 			},
 		},
-		"MergeOptionsKeepMapValues": {
-			reason: "Setting mergeOptions.keepMapValues = true adds new map values to existing ones",
+		"SkipWhenDeletingDeleting": {
+			reason: "Should not patch while the composite resource is being deleted when the patch skips when deleting",
 			args: args{
 				patch: v1.Patch{
 					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.labels"),
-					Policy: &v1.PatchPolicy{
-						MergeOptions: &xpv1.MergeOptions{
-							KeepMapValues: pointer.Bool(true),
-						},
-					},
-					ToFieldPath: pointer.String("objectMeta.labels"),
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					Policy:        &v1.PatchPolicy{SkipWhenDeleting: pointer.Bool(true)},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"labelone": "foo",
-							"labeltwo": "bar",
-						},
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"labelthree": "baz",
-						},
-					},
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
+				deleting: true,
 			},
 			want: want{
 				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"labelone":   "foo",
-							"labeltwo":   "bar",
-							"labelthree": "baz",
-						},
-					},
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
-				err: nil,
 			},
 		},
-		"FilterExcludeCompositeFieldPathPatch": {
-			reason: "Should not apply the patch as the v1.PatchType is not present in filter.",
+		"SkipWhenDeletingNotDeleting": {
+			reason: "Should patch while the composite resource is not being deleted when the patch skips when deleting",
 			args: args{
 				patch: v1.Patch{
 					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.labels"),
-					ToFieldPath:   pointer.String("objectMeta.labels"),
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					Policy:        &v1.PatchPolicy{SkipWhenDeleting: pointer.Bool(true)},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
-				only: []v1.PatchType{v1.PatchTypePatchSet},
 			},
 			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
+						Name:   "cd",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 				},
-				err: nil,
 			},
 		},
-		"FilterIncludeCompositeFieldPathPatch": {
-			reason: "Should apply the patch as the v1.PatchType is present in filter.",
+		"DeletingWithoutSkipWhenDeleting": {
+			reason: "Should patch while the composite resource is being deleted when the patch doesn't skip when deleting",
 			args: args{
 				patch: v1.Patch{
 					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.labels"),
-					ToFieldPath:   pointer.String("objectMeta.labels"),
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
-				only: []v1.PatchType{v1.PatchTypeFromCompositeFieldPath},
+				deleting: true,
 			},
 			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						}},
+						Name:   "cd",
+						Labels: map[string]string{"region": "eu-west-1"},
+					},
 				},
-				err: nil,
 			},
 		},
-		"DefaultToFieldCompositeFieldPathPatch": {
-			reason: "Should correctly default the ToFieldPath value if not specified.",
+		"SkipWhenValueMatches": {
+			reason: "Should not patch when the transformed value equals the patch's SkipWhenValue",
 			args: args{
 				patch: v1.Patch{
 					Type:          v1.PatchTypeFromCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.labels"),
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					SkipWhenValue: &extv1.JSON{Raw: []byte(`""`)},
+					Transforms: []v1.Transform{{
+						Type: v1.TransformTypeMap,
+						Map:  &v1.MapTransform{Pairs: map[string]extv1.JSON{"default": {Raw: []byte(`""`)}}},
+					}},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
+						Name:   "cp",
+						Labels: map[string]string{"region": "default"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
+						Name:   "cd",
+						Labels: map[string]string{"region": "us-east-1"},
 					},
 				},
 			},
 			want: want{
-				cp: &fake.Composite{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
-					},
-					ConnectionDetailsLastPublishedTimer: lpt,
-				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						}},
+						Name:   "cd",
+						Labels: map[string]string{"region": "us-east-1"},
+					},
 				},
-				err: nil,
 			},
 		},
-		"ValidToCompositeFieldPathPatch": {
-			reason: "Should correctly apply a ToCompositeFieldPath patch with valid settings",
+		"SkipWhenValueDoesNotMatch": {
+			reason: "Should patch when the value doesn't equal the patch's SkipWhenValue",
 			args: args{
 				patch: v1.Patch{
-					Type:          v1.PatchTypeToCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.labels"),
-					ToFieldPath:   pointer.String("objectMeta.labels"),
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					SkipWhenValue: &extv1.JSON{Raw: []byte(`""`)},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
+						Name:   "cd",
+						Labels: map[string]string{"region": "us-east-1"},
 					},
 				},
 			},
 			want: want{
-				cp: &fake.Composite{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
-					},
-					ConnectionDetailsLastPublishedTimer: lpt,
-				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						}},
+						Name:   "cd",
+						Labels: map[string]string{"region": "eu-west-1"},
+					},
 				},
-				err: nil,
 			},
 		},
-		"ValidToCompositeFieldPathPatchWithWildcards": {
-			reason: "When passed a wildcarded path, adds a field to each element of an array",
+		"SkipWhenValueCombine": {
+			reason: "Should not patch when a combined value equals the patch's SkipWhenValue",
 			args: args{
 				patch: v1.Patch{
-					Type:          v1.PatchTypeToCompositeFieldPath,
-					FromFieldPath: pointer.String("objectMeta.name"),
-					ToFieldPath:   pointer.String("objectMeta.ownerReferences[*].name"),
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.source1"},
+							{FromFieldPath: "objectMeta.labels.source2"},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath:   pointer.String("objectMeta.labels.destination"),
+					SkipWhenValue: &extv1.JSON{Raw: []byte(`"foo-bar"`)},
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						OwnerReferences: []metav1.OwnerReference{
-							{
-								Name:       "",
-								APIVersion: "v1",
-							},
-							{
-								Name:       "",
-								APIVersion: "v1alpha1",
-							},
-						},
+						Name:   "cp",
+						Labels: map[string]string{"source1": "foo", "source2": "bar"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
-				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "test",
-					},
-				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
 			},
 			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+		},
+		"InvalidSkipWhenValue": {
+			reason: "Should return an error when the patch's SkipWhenValue isn't valid JSON",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels.region"),
+					SkipWhenValue: &extv1.JSON{Raw: []byte(`{`)},
+				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						OwnerReferences: []metav1.OwnerReference{
-							{
-								Name:       "test",
-								APIVersion: "v1",
-							},
-							{
-								Name:       "test",
-								APIVersion: "v1alpha1",
-							},
-						},
+						Name:   "cp",
+						Labels: map[string]string{"region": "eu-west-1"},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				cd:  &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+				err: errors.Wrap(errors.New("unexpected end of JSON input"), errSkipWhenValue),
 			},
 		},
-		"ValidToEnvironmentFieldPathPatch": {
-			reason: "Should correctly apply a ToEnvironmentFieldPath patch with valid settings",
+		"ValidCompositeFieldPathPatchWithNilLastPublishTime": {
+			reason: "Should correctly apply a CompositeFieldPathPatch with valid settings",
 			args: args{
 				patch: v1.Patch{
-					Type:          v1.PatchTypeToEnvironmentFieldPath,
+					Type:          v1.PatchTypeFromCompositeFieldPath,
 					FromFieldPath: pointer.String("objectMeta.labels"),
 					ToFieldPath:   pointer.String("objectMeta.labels"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
-					},
-					ConnectionDetailsLastPublishedTimer: lpt,
-				},
-				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
 						Labels: map[string]string{
 							"Test": "blah",
 						},
 					},
 				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
 			},
 			want: want{
-				cp: &fake.Composite{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
-					},
-					ConnectionDetailsLastPublishedTimer: lpt,
-				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
 						Labels: map[string]string{
 							"Test": "blah",
-						}},
+						},
+					},
 				},
 				err: nil,
 			},
 		},
-		"MissingCombineFromCompositeConfig": {
-			reason: "Should return an error if Combine config is not passed",
+		"ValidCompositeFieldPathPatchWithWildcards": {
+			reason: "When passed a wildcarded path, adds a field to each element of an array",
 			args: args{
 				patch: v1.Patch{
-					Type:        v1.PatchTypeCombineFromComposite,
-					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.name"),
+					ToFieldPath:   pointer.String("objectMeta.ownerReferences[*].name"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
-						},
+						Name: "test",
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								Name:       "",
+								APIVersion: "v1",
+							},
+							{
+								Name:       "",
+								APIVersion: "v1alpha1",
+							},
 						},
 					},
 				},
 			},
 			want: want{
-				cp: &fake.Composite{
+				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								Name:       "test",
+								APIVersion: "v1",
+							},
+							{
+								Name:       "test",
+								APIVersion: "v1alpha1",
+							},
 						},
 					},
-					ConnectionDetailsLastPublishedTimer: lpt,
 				},
-				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						}},
-				},
-				err: errors.Errorf(errFmtRequiredField, "Combine", v1.PatchTypeCombineFromComposite),
 			},
 		},
-		"MissingCombineFromEnvironmentConfig": {
-			reason: "Should return an error if Combine config is not passed",
+		"ValidCompositeFieldPathPatchWithTemplatedKey": {
+			reason: "Should resolve a templated key segment in the ToFieldPath before patching",
 			args: args{
 				patch: v1.Patch{
-					Type:        v1.PatchTypeCombineFromEnvironment,
-					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels.name"),
+					ToFieldPath:   pointer.String("objectMeta.annotations[crossplane.io/external-name-{{ objectMeta.labels.region }}]"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
 						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
+							"name":   "cool",
+							"region": "us-west-2",
 						},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
+						Annotations: map[string]string{
+							"crossplane.io/external-name-us-west-2": "cool",
 						},
 					},
 				},
 			},
-			want: want{
+		},
+		"InvalidCompositeFieldPathPatchWithEmptyTemplatedKey": {
+			reason: "Should return an error if a templated key segment in the ToFieldPath resolves to an empty string",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels.name"),
+					ToFieldPath:   pointer.String("objectMeta.annotations[crossplane.io/external-name-{{ objectMeta.labels.region }}]"),
+				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
 						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
+							"name":   "cool",
+							"region": "",
 						},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						}},
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
-				err: errors.Errorf(errFmtRequiredField, "Combine", v1.PatchTypeCombineFromEnvironment),
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+				err: errors.Errorf(errFmtToFieldPathKeyEmpty, "{{ objectMeta.labels.region }}"),
 			},
 		},
-		"MissingCombineStrategyFromCompositeConfig": {
-			reason: "Should return an error if Combine strategy config is not passed",
+		"InvalidCompositeFieldPathPatchWithWildcards": {
+			reason: "When passed a wildcarded path, throws an error if ToFieldPath cannot be expanded",
 			args: args{
 				patch: v1.Patch{
-					Type: v1.PatchTypeCombineFromComposite,
-					Combine: &v1.Combine{
-						Variables: []v1.CombineVariable{
-							{FromFieldPath: "objectMeta.labels.source1"},
-							{FromFieldPath: "objectMeta.labels.source2"},
-						},
-						Strategy: v1.CombineStrategyString,
-					},
-					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.name"),
+					ToFieldPath:   pointer.String("objectMeta.ownerReferences[*].badField"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
-						},
+						Name: "test",
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								Name:       "test",
+								APIVersion: "v1",
+							},
 						},
 					},
 				},
 			},
 			want: want{
+				err: errors.Errorf(errFmtExpandingArrayFieldPaths, "objectMeta.ownerReferences[*].badField"),
+			},
+		},
+		"MissingOptionalFieldPath": {
+			reason: "A FromFieldPath patch should be a no-op when an optional fromFieldPath doesn't exist",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
+					ToFieldPath:   pointer.String("objectMeta.labels"),
+				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
-						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
-						},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						}},
+					},
 				},
-				err: errors.Errorf(errFmtCombineConfigMissing, v1.CombineStrategyString),
+				err: nil,
 			},
 		},
-		"MissingCombineVariablesFromCompositeConfig": {
-			reason: "Should return an error if no variables have been passed",
+		"MissingRequiredFieldPath": {
+			reason: "A FromFieldPath patch should return an error when a required fromFieldPath doesn't exist",
 			args: args{
 				patch: v1.Patch{
-					Type: v1.PatchTypeCombineFromComposite,
-					Combine: &v1.Combine{
-						Variables: []v1.CombineVariable{},
-						Strategy:  v1.CombineStrategyString,
-						String:    &v1.StringCombine{Format: "%s-%s"},
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("wat"),
+					Policy: &v1.PatchPolicy{
+						FromFieldPath: func() *v1.FromFieldPathPolicy {
+							s := v1.FromFieldPathPolicyRequired
+							return &s
+						}(),
 					},
-					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					ToFieldPath: pointer.String("wat"),
 				},
 				cp: &fake.Composite{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
-						},
-					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
-						Labels: map[string]string{
-							"Test": "blah",
-						},
 					},
 				},
+				err: errNotFound("wat"),
 			},
-			want: want{
+		},
+		"ValidFromEnvironmentFieldPathPatch": {
+			reason: "Should correctly apply a FromEnvironmentFieldPathPatch with valid settings",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromEnvironmentFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
+					ToFieldPath:   pointer.String("objectMeta.labels"),
+				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
 						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
+							"Test": "blah",
 						},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
 						Labels: map[string]string{
 							"Test": "blah",
-						}},
-				},
-				err: errors.New(errCombineRequiresVariables),
+						},
+					},
+				},
+				err: nil,
 			},
 		},
-		"NoOpOptionalInputFieldFromCompositeConfig": {
-			// Note: OptionalFieldPathNotFound is tested below, but we want to
-			// test that we abort the patch if _any_ of our source fields are
-			// not available.
-			reason: "Should return no error and not apply patch if an optional variable is missing",
+		"MissingFromEnvironmentRequiredFieldPath": {
+			reason: "A FromFieldPath patch should return an error when a required fromFieldPath doesn't exist",
 			args: args{
 				patch: v1.Patch{
-					Type: v1.PatchTypeCombineFromComposite,
-					Combine: &v1.Combine{
-						Variables: []v1.CombineVariable{
-							{FromFieldPath: "objectMeta.labels.source1"},
-							{FromFieldPath: "objectMeta.labels.source2"},
-							{FromFieldPath: "objectMeta.labels.source3"},
+					Type:          v1.PatchTypeFromEnvironmentFieldPath,
+					FromFieldPath: pointer.String("wat"),
+					Policy: &v1.PatchPolicy{
+						FromFieldPath: func() *v1.FromFieldPathPolicy {
+							s := v1.FromFieldPathPolicyRequired
+							return &s
+						}(),
+					},
+					ToFieldPath: pointer.String("wat"),
+				},
+				cp: &fake.Composite{
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+					},
+				},
+				err: errNotFound("wat"),
+			},
+		},
+		"MergeOptionsKeepMapValues": {
+			reason: "Setting mergeOptions.keepMapValues = true adds new map values to existing ones",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
+					Policy: &v1.PatchPolicy{
+						MergeOptions: &xpv1.MergeOptions{
+							KeepMapValues: pointer.Bool(true),
 						},
-						Strategy: v1.CombineStrategyString,
-						String:   &v1.StringCombine{Format: "%s-%s"},
 					},
-					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					ToFieldPath: pointer.String("objectMeta.labels"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
 						Labels: map[string]string{
-							"source1": "foo",
-							"source3": "baz",
+							"labelone": "foo",
+							"labeltwo": "bar",
 						},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
@@ -873,34 +848,27 @@ func TestPatchApply(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
 						Labels: map[string]string{
-							"Test": "blah",
+							"labelthree": "baz",
 						},
 					},
 				},
 			},
 			want: want{
-				cp: &fake.Composite{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"source1": "foo",
-							"source3": "baz",
-						},
-					},
-					ConnectionDetailsLastPublishedTimer: lpt,
-				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
 						Labels: map[string]string{
-							"Test": "blah",
-						}},
+							"labelone":   "foo",
+							"labeltwo":   "bar",
+							"labelthree": "baz",
+						},
+					},
 				},
 				err: nil,
 			},
 		},
-		"ValidCombineFromComposite": {
-			reason: "Should correctly apply a CombineFromComposite patch with valid settings",
+		"CombineMergeOptionsAppendSlice": {
+			reason: "Setting mergeOptions.appendSlice = true on a combine patch appends the combined array to the existing one",
 			args: args{
 				patch: v1.Patch{
 					Type: v1.PatchTypeCombineFromComposite,
@@ -909,10 +877,14 @@ func TestPatchApply(t *testing.T) {
 							{FromFieldPath: "objectMeta.labels.source1"},
 							{FromFieldPath: "objectMeta.labels.source2"},
 						},
-						Strategy: v1.CombineStrategyString,
-						String:   &v1.StringCombine{Format: "%s-%s"},
+						Strategy: v1.CombineStrategyArray,
 					},
-					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					Policy: &v1.PatchPolicy{
+						MergeOptions: &xpv1.MergeOptions{
+							AppendSlice: pointer.Bool(true),
+						},
+					},
+					ToFieldPath: pointer.String("objectMeta.finalizers"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
@@ -926,49 +898,91 @@ func TestPatchApply(t *testing.T) {
 				},
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
+						Name:       "cd",
+						Finalizers: []string{"existing"},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"existing", "foo", "bar"},
+					},
+				},
+				err: nil,
+			},
+		},
+		"FilterExcludeCompositeFieldPathPatch": {
+			reason: "Should not apply the patch as the v1.PatchType is not present in filter.",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
+					ToFieldPath:   pointer.String("objectMeta.labels"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
 						Labels: map[string]string{
 							"Test": "blah",
 						},
 					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
 				},
+				only: []v1.PatchType{v1.PatchTypePatchSet},
 			},
 			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+					},
+				},
+				err: nil,
+			},
+		},
+		"FilterIncludeCompositeFieldPathPatch": {
+			reason: "Should apply the patch as the v1.PatchType is present in filter.",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
+					ToFieldPath:   pointer.String("objectMeta.labels"),
+				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
 						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
+							"Test": "blah",
 						},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
 				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+				only: []v1.PatchType{v1.PatchTypeFromCompositeFieldPath},
+			},
+			want: want{
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
 						Labels: map[string]string{
-							"Test":        "blah",
-							"destination": "foo-bar",
+							"Test": "blah",
 						}},
 				},
 				err: nil,
 			},
 		},
-		"ValidCombineToComposite": {
-			reason: "Should correctly apply a CombineToComposite patch with valid settings",
+		"DefaultToFieldCompositeFieldPathPatch": {
+			reason: "Should correctly default the ToFieldPath value if not specified.",
 			args: args{
 				patch: v1.Patch{
-					Type: v1.PatchTypeCombineToComposite,
-					Combine: &v1.Combine{
-						Variables: []v1.CombineVariable{
-							{FromFieldPath: "objectMeta.labels.source1"},
-							{FromFieldPath: "objectMeta.labels.source2"},
-						},
-						Strategy: v1.CombineStrategyString,
-						String:   &v1.StringCombine{Format: "%s-%s"},
-					},
-					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
 				},
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
@@ -982,10 +996,6 @@ func TestPatchApply(t *testing.T) {
 				cd: &fake.Composed{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
-						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
-						},
 					},
 				},
 			},
@@ -994,8 +1004,7 @@ func TestPatchApply(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
 						Labels: map[string]string{
-							"Test":        "blah",
-							"destination": "foo-bar",
+							"Test": "blah",
 						},
 					},
 					ConnectionDetailsLastPublishedTimer: lpt,
@@ -1004,28 +1013,36 @@ func TestPatchApply(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
 						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
+							"Test": "blah",
 						}},
 				},
 				err: nil,
 			},
 		},
-		"ValidCombineToEnvironment": {
-			reason: "Should correctly apply a CombineToEnvironment patch with valid settings",
+		"ValidToCompositeFieldPathPatch": {
+			reason: "Should correctly apply a ToCompositeFieldPath patch with valid settings",
 			args: args{
 				patch: v1.Patch{
-					Type: v1.PatchTypeCombineToEnvironment,
-					Combine: &v1.Combine{
-						Variables: []v1.CombineVariable{
-							{FromFieldPath: "objectMeta.labels.source1"},
-							{FromFieldPath: "objectMeta.labels.source2"},
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
+					ToFieldPath:   pointer.String("objectMeta.labels"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
 						},
-						Strategy: v1.CombineStrategyString,
-						String:   &v1.StringCombine{Format: "%s-%s"},
 					},
-					ToFieldPath: pointer.String("objectMeta.labels.destination"),
 				},
+			},
+			want: want{
 				cp: &fake.Composite{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cp",
@@ -1039,52 +1056,4064 @@ func TestPatchApply(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "cd",
 						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
+							"Test": "blah",
+						}},
+				},
+				err: nil,
+			},
+		},
+		"ValidToCompositeFieldPathPatchWithWildcards": {
+			reason: "When passed a wildcarded path, adds a field to each element of an array",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.name"),
+					ToFieldPath:   pointer.String("objectMeta.ownerReferences[*].name"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								Name:       "",
+								APIVersion: "v1",
+							},
+							{
+								Name:       "",
+								APIVersion: "v1alpha1",
+							},
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								Name:       "test",
+								APIVersion: "v1",
+							},
+							{
+								Name:       "test",
+								APIVersion: "v1alpha1",
+							},
 						},
 					},
+					ConnectionDetailsLastPublishedTimer: lpt,
 				},
 			},
+		},
+		"ValidToEnvironmentFieldPathPatch": {
+			reason: "Should correctly apply a ToEnvironmentFieldPath patch with valid settings",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToEnvironmentFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
+					ToFieldPath:   pointer.String("objectMeta.labels"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						}},
+				},
+				err: nil,
+			},
+		},
+		"MissingCombineFromCompositeConfig": {
+			reason: "Should return an error if Combine config is not passed",
+			args: args{
+				patch: v1.Patch{
+					Type:        v1.PatchTypeCombineFromComposite,
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						}},
+				},
+				err: errors.Errorf(errFmtRequiredField, "Combine", v1.PatchTypeCombineFromComposite),
+			},
+		},
+		"MissingCombineFromEnvironmentConfig": {
+			reason: "Should return an error if Combine config is not passed",
+			args: args{
+				patch: v1.Patch{
+					Type:        v1.PatchTypeCombineFromEnvironment,
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						}},
+				},
+				err: errors.Errorf(errFmtRequiredField, "Combine", v1.PatchTypeCombineFromEnvironment),
+			},
+		},
+		"MissingCombineStrategyFromCompositeConfig": {
+			reason: "Should return an error if Combine strategy config is not passed",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.source1"},
+							{FromFieldPath: "objectMeta.labels.source2"},
+						},
+						Strategy: v1.CombineStrategyString,
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						}},
+				},
+				err: errors.Errorf(errFmtCombineConfigMissing, v1.CombineStrategyString),
+			},
+		},
+		"CombineVariableTransforms": {
+			reason: "Should transform each variable before combining them, then transform the combined value",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{
+								FromFieldPath: "objectMeta.labels.region",
+								Transforms: []v1.Transform{{
+									Type:   v1.TransformTypeString,
+									String: &v1.StringTransform{Type: v1.StringTransformTypeConvert, Convert: &[]v1.StringConversionType{v1.StringConversionTypeToLower}[0]},
+								}},
+							},
+							{FromFieldPath: "objectMeta.labels.name"},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					Transforms: []v1.Transform{{
+						Type:   v1.TransformTypeString,
+						String: &v1.StringTransform{Type: v1.StringTransformTypeFormat, Format: pointer.String("db-%s")},
+					}},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"region": "US-EAST-1",
+							"name":   "Orders",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cd",
+						Labels: map[string]string{"destination": "db-us-east-1-Orders"},
+					},
+				},
+			},
+		},
+		"CombineVariableTransformError": {
+			reason: "Should return an error if a variable's transforms fail",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.region"},
+							{
+								FromFieldPath: "objectMeta.labels.name",
+								Transforms: []v1.Transform{{
+									Type: v1.TransformTypeMath,
+									Math: &v1.MathTransform{Multiply: pointer.Int64(2)},
+								}},
+							},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"region": "us-east-1",
+							"name":   "orders",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+				err: errors.Wrapf(errors.Wrapf(errors.Wrapf(errors.New(errMathInputNonNumber), errFmtTransformTypeFailed, v1.TransformTypeMath), errFmtTransformAtIndex, 0), errFmtCombineVariable, 1),
+			},
+		},
+		"MissingCombineVariablesFromCompositeConfig": {
+			reason: "Should return an error if no variables have been passed",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{},
+						Strategy:  v1.CombineStrategyString,
+						String:    &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						}},
+				},
+				err: errors.New(errCombineRequiresVariables),
+			},
+		},
+		"NoOpOptionalInputFieldFromCompositeConfig": {
+			// Note: OptionalFieldPathNotFound is tested below, but we want to
+			// test that we abort the patch if _any_ of our source fields are
+			// not available.
+			reason: "Should return no error and not apply patch if an optional variable is missing",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.source1"},
+							{FromFieldPath: "objectMeta.labels.source2"},
+							{FromFieldPath: "objectMeta.labels.source3"},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source3": "baz",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source3": "baz",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						}},
+				},
+				err: nil,
+			},
+		},
+		"ValidCombineFromComposite": {
+			reason: "Should correctly apply a CombineFromComposite patch with valid settings",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.source1"},
+							{FromFieldPath: "objectMeta.labels.source2"},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test":        "blah",
+							"destination": "foo-bar",
+						}},
+				},
+				err: nil,
+			},
+		},
+		"ValidCombineToComposite": {
+			reason: "Should correctly apply a CombineToComposite patch with valid settings",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineToComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.source1"},
+							{FromFieldPath: "objectMeta.labels.source2"},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test":        "blah",
+							"destination": "foo-bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						}},
+				},
+				err: nil,
+			},
+		},
+		"ValidCombineToEnvironment": {
+			reason: "Should correctly apply a CombineToEnvironment patch with valid settings",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineToEnvironment,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.source1"},
+							{FromFieldPath: "objectMeta.labels.source2"},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test":        "blah",
+							"destination": "foo-bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						}},
+				},
+				err: nil,
+			},
+		},
+		"ValidCombineCoalesceFromComposite": {
+			reason: "Should patch the first variable that exists and is not empty",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.missing"},
+							{FromFieldPath: "objectMeta.labels.empty"},
+							{FromFieldPath: "objectMeta.labels.source"},
+						},
+						Strategy: v1.CombineStrategyCoalesce,
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"empty":  "",
+							"source": "foo",
+						},
+					},
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"destination": "foo",
+						}},
+				},
+			},
+		},
+		"CombineCoalesceAllEmptyRequired": {
+			reason: "Should return an error if all variables of a required coalesce are empty and there is no default",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.missing"},
+						},
+						Strategy: v1.CombineStrategyCoalesce,
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+					Policy: &v1.PatchPolicy{
+						FromFieldPath: func() *v1.FromFieldPathPolicy {
+							s := v1.FromFieldPathPolicyRequired
+							return &s
+						}(),
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{Name: "cp"},
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+				err: errors.New(errCoalesceAllEmpty),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ncp := tc.args.cp.DeepCopyObject().(resource.Composite)
+			err := Apply(tc.args.patch, ncp, tc.args.cd, OnlyPatchTypes(tc.args.only...), OnlyPatchTags(tc.args.tags...), WithComposedResourceExists(tc.args.exists), WithCompositeDeleting(tc.args.deleting))
+
+			if tc.want.cp != nil {
+				if diff := cmp.Diff(tc.want.cp, ncp); diff != "" {
+					t.Errorf("\n%s\nApply(cp): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+			if tc.want.cd != nil {
+				if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+					t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSkipOptionalTransformErrors(t *testing.T) {
+	required := v1.FromFieldPathPolicyRequired
+	toInt := v1.Transform{
+		Type:    v1.TransformTypeConvert,
+		Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeInt64},
+	}
+	_, errParse := ResolveConvert(*toInt.Convert, "nope")
+	errTransform := errors.Wrapf(errors.Wrapf(errParse, errFmtTransformTypeFailed, string(v1.TransformTypeConvert)), errFmtTransformAtIndex, 0)
+
+	xr := func() *composite.Unstructured {
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "XR",
+			"spec":       map[string]any{"replicas": "nope"},
+		}}}
+	}
+	cd := func() *composed.Unstructured {
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Composed",
+		}}}
+	}
+
+	type args struct {
+		patch v1.Patch
+		skip  bool
+	}
+	type want struct {
+		cd      *composed.Unstructured
+		err     error
+		skipped error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"OptionalTransformError": {
+			reason: "A transform error of an optional patch should skip the patch and be reported.",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.replicas"),
+					Transforms:    []v1.Transform{toInt},
+				},
+				skip: true,
+			},
+			want: want{
+				cd:      cd(),
+				skipped: errTransform,
+			},
+		},
+		"RequiredTransformError": {
+			reason: "A transform error of a required patch should be returned.",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.replicas"),
+					Transforms:    []v1.Transform{toInt},
+					Policy:        &v1.PatchPolicy{FromFieldPath: &required},
+				},
+				skip: true,
+			},
+			want: want{
+				cd:  cd(),
+				err: errTransform,
+			},
+		},
+		"OptionNotSupplied": {
+			reason: "A transform error of an optional patch should be returned if transform errors aren't skipped.",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.replicas"),
+					Transforms:    []v1.Transform{toInt},
+				},
+			},
+			want: want{
+				cd:  cd(),
+				err: errTransform,
+			},
+		},
+		"OptionalCombineVariableTransformError": {
+			reason: "A transform error of an optional combine variable should skip the patch and be reported.",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Strategy:  v1.CombineStrategyString,
+						Variables: []v1.CombineVariable{{FromFieldPath: "spec.replicas", Transforms: []v1.Transform{toInt}}},
+						String:    &v1.StringCombine{Format: "%d"},
+					},
+					ToFieldPath: pointer.String("spec.replicas"),
+				},
+				skip: true,
+			},
+			want: want{
+				cd:      cd(),
+				skipped: errors.Wrapf(errTransform, errFmtCombineVariable, 0),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var skipped error
+			o := []ApplyOption{}
+			if tc.args.skip {
+				o = append(o, SkipOptionalTransformErrors(func(_ v1.Patch, err error) { skipped = err }))
+			}
+			got := cd()
+			err := Apply(tc.args.patch, xr(), got, o...)
+			if diff := cmp.Diff(tc.want.cd, got); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.skipped, skipped, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(skipped): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCombineCoalesce(t *testing.T) {
+	type args struct {
+		dflt *extv1.JSON
+		vars []any
+	}
+	type want struct {
+		out any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FirstNotEmpty": {
+			reason: "Nil values, empty strings, empty arrays, and empty objects should be skipped.",
+			args: args{
+				vars: []any{nil, "", []any{}, map[string]any{}, "foo", "bar"},
+			},
+			want: want{out: "foo"},
+		},
+		"ZeroNumber": {
+			reason: "Numbers should never be empty, even if they're zero.",
+			args: args{
+				vars: []any{nil, int64(0), int64(1)},
+			},
+			want: want{out: int64(0)},
+		},
+		"FalseBool": {
+			reason: "Booleans should never be empty, even if they're false.",
+			args: args{
+				vars: []any{"", false, true},
+			},
+			want: want{out: false},
+		},
+		"Default": {
+			reason: "The default should be returned if all variables are empty.",
+			args: args{
+				dflt: &extv1.JSON{Raw: []byte(`{"foo":"bar"}`)},
+				vars: []any{nil, ""},
+			},
+			want: want{out: map[string]any{"foo": "bar"}},
+		},
+		"NoDefault": {
+			reason: "Nil should be returned if all variables are empty and there is no default.",
+			args: args{
+				vars: []any{nil, ""},
+			},
+			want: want{out: nil},
+		},
+		"InvalidDefault": {
+			reason: "An error should be returned if the default isn't valid JSON.",
+			args: args{
+				dflt: &extv1.JSON{Raw: []byte(`{`)},
+				vars: []any{nil},
+			},
+			want: want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errCoalesceDefault)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := CombineCoalesce(tc.args.dflt, tc.args.vars)
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("\n%s\nCombineCoalesce(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCombineCoalesce(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCombineMath(t *testing.T) {
+	type args struct {
+		op   v1.MathCombineOperation
+		vars []any
+	}
+	type want struct {
+		out any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"MultiplyIntegers": {
+			reason: "Multiplying integers should return an integer.",
+			args:   args{op: v1.MathCombineOperationMultiply, vars: []any{int64(3), 4, int64(2)}},
+			want:   want{out: int64(24)},
+		},
+		"AddIntegers": {
+			reason: "Adding integers should return an integer.",
+			args:   args{op: v1.MathCombineOperationAdd, vars: []any{int64(3), int64(-4)}},
+			want:   want{out: int64(-1)},
+		},
+		"MultiplyFloat": {
+			reason: "Multiplying any float should return a float.",
+			args:   args{op: v1.MathCombineOperationMultiply, vars: []any{int64(3), float64(1.5)}},
+			want:   want{out: float64(4.5)},
+		},
+		"SingleVariable": {
+			reason: "A single variable should be returned as is.",
+			args:   args{op: v1.MathCombineOperationAdd, vars: []any{int64(7)}},
+			want:   want{out: int64(7)},
+		},
+		"NonNumber": {
+			reason: "An error should be returned if a variable isn't a number.",
+			args:   args{op: v1.MathCombineOperationMultiply, vars: []any{int64(1), "2"}},
+			want:   want{err: errors.Errorf(errFmtMathCombineNonNumber, 1)},
+		},
+		"UnknownOperation": {
+			reason: "An error should be returned if the operation isn't supported.",
+			args:   args{op: "Divide", vars: []any{int64(1), int64(2)}},
+			want:   want{err: errors.Errorf(errFmtMathCombineOperation, "Divide")},
+		},
+		"NoVariables": {
+			reason: "An error should be returned if there are no variables.",
+			args:   args{op: v1.MathCombineOperationAdd},
+			want:   want{err: errors.New(errCombineRequiresVariables)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := CombineMath(tc.args.op, tc.args.vars)
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("\n%s\nCombineMath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCombineMath(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCombineSet(t *testing.T) {
+	desired := []any{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+	current := []any{"192.168.0.0/16", "100.64.0.0/10", "10.0.0.0/8"}
+
+	type args struct {
+		op   v1.SetCombineOperation
+		vars []any
+	}
+	type want struct {
+		out any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Difference": {
+			reason: "A difference should return the elements of the first array that aren't in the second.",
+			args:   args{op: v1.SetCombineOperationDifference, vars: []any{desired, current}},
+			want:   want{out: []any{"172.16.0.0/12"}},
+		},
+		"Intersection": {
+			reason: "An intersection should return the elements of the first array that are also in the second, in the order of the first.",
+			args:   args{op: v1.SetCombineOperationIntersection, vars: []any{desired, current}},
+			want:   want{out: []any{"10.0.0.0/8", "192.168.0.0/16"}},
+		},
+		"Union": {
+			reason: "A union should return the elements of the first array, followed by the elements of the second that aren't in the first.",
+			args:   args{op: v1.SetCombineOperationUnion, vars: []any{desired, current}},
+			want:   want{out: []any{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10"}},
+		},
+		"Duplicates": {
+			reason: "Each element should be returned once.",
+			args:   args{op: v1.SetCombineOperationUnion, vars: []any{[]any{"a", "a"}, []any{"b", "a", "b"}}},
+			want:   want{out: []any{"a", "b"}},
+		},
+		"EmptyResult": {
+			reason: "A difference of equal arrays should return an empty array.",
+			args:   args{op: v1.SetCombineOperationDifference, vars: []any{desired, desired}},
+			want:   want{out: []any{}},
+		},
+		"Numbers": {
+			reason: "Integers and floats with the same value should be equal, but not strings.",
+			args:   args{op: v1.SetCombineOperationIntersection, vars: []any{[]any{int64(1), int64(2), int64(3)}, []any{float64(1), "2", int64(3)}}},
+			want:   want{out: []any{int64(1), int64(3)}},
+		},
+		"Objects": {
+			reason: "Objects should be equal only if all of their fields are equal.",
+			args: args{op: v1.SetCombineOperationDifference, vars: []any{
+				[]any{
+					map[string]any{"port": int64(443), "protocol": "tcp"},
+					map[string]any{"port": int64(53), "protocol": "udp"},
+				},
+				[]any{
+					map[string]any{"protocol": "tcp", "port": int64(443)},
+					map[string]any{"port": int64(53)},
+				},
+			}},
+			want: want{out: []any{map[string]any{"port": int64(53), "protocol": "udp"}}},
+		},
+		"NonArray": {
+			reason: "An error should be returned if a variable isn't an array.",
+			args:   args{op: v1.SetCombineOperationUnion, vars: []any{desired, "10.0.0.0/8"}},
+			want:   want{err: errors.Errorf(errFmtSetCombineNonArray, 1)},
+		},
+		"WrongNumberOfVariables": {
+			reason: "An error should be returned if there aren't exactly two variables.",
+			args:   args{op: v1.SetCombineOperationUnion, vars: []any{desired}},
+			want:   want{err: errors.Errorf(errFmtSetCombineVariables, 1)},
+		},
+		"UnknownOperation": {
+			reason: "An error should be returned if the operation isn't supported.",
+			args:   args{op: "SymmetricDifference", vars: []any{desired, current}},
+			want:   want{err: errors.Errorf(errFmtSetCombineOperation, "SymmetricDifference")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := CombineSet(tc.args.op, tc.args.vars)
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("\n%s\nCombineSet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCombineSet(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCombinePercentDiff(t *testing.T) {
+	type want struct {
+		out any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		vars   []any
+		want   want
+	}{
+		"Increase": {
+			reason: "An observed value greater than the desired value should be a positive percentage.",
+			vars:   []any{int64(10), int64(12)},
+			want:   want{out: float64(20)},
+		},
+		"Decrease": {
+			reason: "An observed value less than the desired value should be a negative percentage.",
+			vars:   []any{float64(8), 6},
+			want:   want{out: float64(-25)},
+		},
+		"NegativeDesired": {
+			reason: "The difference should be relative to the magnitude of a negative desired value.",
+			vars:   []any{int64(-10), int64(-5)},
+			want:   want{out: float64(50)},
+		},
+		"BothZero": {
+			reason: "Equal values of zero should not differ.",
+			vars:   []any{int64(0), float64(0)},
+			want:   want{out: float64(0)},
+		},
+		"DesiredZero": {
+			reason: "An error should be returned if only the desired value is zero.",
+			vars:   []any{int64(0), int64(1)},
+			want:   want{err: errors.New(errPercentDiffZero)},
+		},
+		"NonNumber": {
+			reason: "An error should be returned if a variable isn't a number.",
+			vars:   []any{int64(1), "2"},
+			want:   want{err: errors.Errorf(errFmtPercentDiffNonNumber, 1)},
+		},
+		"WrongNumberOfVariables": {
+			reason: "An error should be returned if there aren't exactly two variables.",
+			vars:   []any{int64(1)},
+			want:   want{err: errors.Errorf(errFmtPercentDiffVariables, 1)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := CombinePercentDiff(tc.vars)
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("\n%s\nCombinePercentDiff(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCombinePercentDiff(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// A fieldPathResolverFn is a FieldPathResolver backed by a function.
+type fieldPathResolverFn func(path string) (any, error)
+
+func (fn fieldPathResolverFn) GetValue(path string) (any, error) {
+	return fn(path)
+}
+
+func TestApplyWithFieldPathResolver(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		patch v1.Patch
+		fn    FieldPathResolverFn
+	}
+	type want struct {
+		cd  *fake.Composed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ResolverError": {
+			reason: "Should return an error if a FieldPathResolver cannot be created for the source",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.name"),
+					ToFieldPath:   pointer.String("objectMeta.name"),
+				},
+				fn: func(_ runtime.Object) (FieldPathResolver, error) { return nil, errBoom },
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errBoom,
+			},
+		},
+		"GetValueError": {
+			reason: "Should return an error if the source field path cannot be resolved",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.name"),
+					ToFieldPath:   pointer.String("objectMeta.name"),
+				},
+				fn: func(_ runtime.Object) (FieldPathResolver, error) {
+					return fieldPathResolverFn(func(_ string) (any, error) { return nil, errBoom }), nil
+				},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errBoom,
+			},
+		},
+		"OptionalNotFound": {
+			reason: "Should not patch if an optional source field path is not found",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.name"),
+					ToFieldPath:   pointer.String("objectMeta.name"),
+				},
+				fn: func(_ runtime.Object) (FieldPathResolver, error) {
+					return fieldPathResolverFn(func(_ string) (any, error) { return nil, notFoundError{errBoom} }), nil
+				},
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"CombineResolved": {
+			reason: "Should combine the values returned by the FieldPathResolver",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "spec.a"},
+							{FromFieldPath: "spec.b"},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.name"),
+				},
+				fn: func(_ runtime.Object) (FieldPathResolver, error) {
+					return fieldPathResolverFn(func(path string) (any, error) { return path, nil }), nil
+				},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "spec.a-spec.b"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := &fake.Composed{}
+			err := Apply(tc.args.patch, &fake.Composite{}, cd, WithFieldPathResolver(tc.args.fn))
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// A typedComposite is a typed composite resource that knows its
+// GroupVersionKind, but doesn't serialize its type metadata.
+type typedComposite struct {
+	fake.Composite
+	gvk schema.GroupVersionKind
+}
+
+func (c *typedComposite) GetObjectKind() schema.ObjectKind {
+	apiVersion, kind := c.gvk.ToAPIVersionAndKind()
+	return &metav1.TypeMeta{APIVersion: apiVersion, Kind: kind}
+}
+
+func TestApplyFromCompositeTypeMeta(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XDatabase"}
+	patches := []v1.Patch{
+		{
+			FromFieldPath: pointer.String("apiVersion"),
+			ToFieldPath:   pointer.String("objectMeta.labels[example.org/composite-api-version]"),
+		},
+		{
+			FromFieldPath: pointer.String("kind"),
+			ToFieldPath:   pointer.String("objectMeta.labels[example.org/composite-kind]"),
+		},
+	}
+
+	type want struct {
+		cd  *fake.Composed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		cp     resource.Composite
+		want   want
+	}{
+		"Unstructured": {
+			reason: "The apiVersion and kind of an unstructured composite should be patched.",
+			cp: func() resource.Composite {
+				cp := composite.New(composite.WithGroupVersionKind(gvk))
+				cp.SetName("cp")
+				return cp
+			}(),
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"example.org/composite-api-version": "example.org/v1",
+					"example.org/composite-kind":        "XDatabase",
+				}}},
+			},
+		},
+		"Typed": {
+			reason: "The apiVersion and kind of a typed composite that doesn't serialize its type metadata should be patched.",
+			cp:     &typedComposite{gvk: gvk},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"example.org/composite-api-version": "example.org/v1",
+					"example.org/composite-kind":        "XDatabase",
+				}}},
+			},
+		},
+		"TypedUnknownKind": {
+			reason: "Optional patches from the apiVersion and kind of a composite whose kind is unknown should not be applied.",
+			cp:     &fake.Composite{},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := &fake.Composed{}
+			var err error
+			for _, p := range patches {
+				if err = Apply(p, tc.cp, cd); err != nil {
+					break
+				}
+			}
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyFromCompositeResourceRefs(t *testing.T) {
+	required := v1.FromFieldPathPolicyRequired
+	cp := composite.New()
+	cp.SetResourceReferences([]corev1.ObjectReference{
+		{APIVersion: "example.org/v1", Kind: "Network", Name: "net"},
+		{APIVersion: "example.org/v1", Kind: "Bucket", Name: "bucket", Namespace: "storage"},
+	})
+
+	type want struct {
+		cd  *fake.Composed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		patch  v1.Patch
+		want   want
+	}{
+		"ByIndex": {
+			reason: "Should patch the name of the resource reference at an index.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("spec.resourceRefs[0].name"),
+				ToFieldPath:   pointer.String("objectMeta.labels[example.org/network]"),
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"example.org/network": "net"}}},
+			},
+		},
+		"ByKind": {
+			reason: "Should patch the namespace of the first resource reference of a kind.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("spec.resourceRefs[kind=Bucket].namespace"),
+				ToFieldPath:   pointer.String("objectMeta.namespace"),
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Namespace: "storage"}},
+			},
+		},
+		"OptionalNoMatch": {
+			reason: "Should not patch if no resource reference is of an optional kind.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("spec.resourceRefs[kind=Queue].name"),
+				ToFieldPath:   pointer.String("objectMeta.name"),
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"RequiredNoMatch": {
+			reason: "Should return an error if no resource reference is of a required kind.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("spec.resourceRefs[kind=Queue].name"),
+				ToFieldPath:   pointer.String("objectMeta.name"),
+				Policy:        &v1.PatchPolicy{FromFieldPath: &required},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: notFoundError{errors.Errorf(errFmtFieldPathFilterNoMatch, "spec.resourceRefs", "kind", "Queue")},
+			},
+		},
+		"NotAnArray": {
+			reason: "Should return an error if a filter is applied to a field that is not an array.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("spec[kind=Bucket].name"),
+				ToFieldPath:   pointer.String("objectMeta.name"),
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtFieldPathFilterNotArray, "spec"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := &fake.Composed{}
+			err := Apply(tc.patch, cp, cd)
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolvePatch(t *testing.T) {
+	upper := v1.Transform{
+		Type: v1.TransformTypeString,
+		String: &v1.StringTransform{
+			Type:    v1.StringTransformTypeConvert,
+			Convert: &[]v1.StringConversionType{v1.StringConversionTypeToUpper}[0],
+		},
+	}
+	secrets := ConnectionSecretResolverFn(func(key string) ([]byte, bool, error) {
+		return []byte("s3cr3t"), key == "password", nil
+	})
+
+	type args struct {
+		patch v1.Patch
+		o     []ApplyOption
+	}
+	type want struct {
+		out any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FromCompositeFieldPath": {
+			reason: "The transformed value of the composite's field should be returned.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("objectMeta.name"),
+					ToFieldPath:   pointer.String("objectMeta.labels[example.org/name]"),
+					Transforms:    []v1.Transform{upper},
+				},
+			},
+			want: want{
+				out: "CP",
+			},
+		},
+		"ToCompositeFieldPath": {
+			reason: "The value of the composed resource's field should be returned.",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.name"),
+				},
+			},
+			want: want{
+				out: "cd",
+			},
+		},
+		"CombineFromComposite": {
+			reason: "The combined value of the composite's fields should be returned.",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Strategy: v1.CombineStrategyString,
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.name"},
+							{FromFieldPath: "objectMeta.namespace"},
+						},
+						String: &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels[example.org/name]"),
+				},
+			},
+			want: want{
+				out: "cp-ns",
+			},
+		},
+		"FromConnectionSecretKey": {
+			reason: "The value of the connection secret key should be returned.",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("password"),
+					ToFieldPath:         pointer.String("objectMeta.annotations[example.org/password]"),
+				},
+				o: []ApplyOption{WithConnectionSecretResolver(secrets)},
+			},
+			want: want{
+				out: "s3cr3t",
+			},
+		},
+		"OptionalFieldPathNotFound": {
+			reason: "A patch whose optional fromFieldPath doesn't exist should be skipped.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("objectMeta.labels[nope]"),
+				},
+			},
+			want: want{
+				err: ErrPatchSkipped,
+			},
+		},
+		"Filtered": {
+			reason: "A patch that is filtered by the supplied options should be skipped.",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.name"),
+				},
+				o: []ApplyOption{OnlyPatchTypes(v1.PatchTypeToCompositeFieldPath)},
+			},
+			want: want{
+				err: ErrPatchSkipped,
+			},
+		},
+		"InvalidPatch": {
+			reason: "An error should be returned if the patch can't be applied.",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeFromCompositeFieldPath,
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtRequiredField, "FromFieldPath", v1.PatchTypeFromCompositeFieldPath),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cp := &fake.Composite{ObjectMeta: metav1.ObjectMeta{Name: "cp", Namespace: "ns"}}
+			cd := &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}}
+			out, err := ResolvePatch(tc.args.patch, cp, cd, tc.args.o...)
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("\n%s\nResolvePatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolvePatch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(&fake.Composite{ObjectMeta: metav1.ObjectMeta{Name: "cp", Namespace: "ns"}}, cp); diff != "" {
+				t.Errorf("\n%s\nResolvePatch(...): composite resource should not be patched: -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(&fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}}, cd); diff != "" {
+				t.Errorf("\n%s\nResolvePatch(...): composed resource should not be patched: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyWithResult(t *testing.T) {
+	type args struct {
+		patch v1.Patch
+		o     []ApplyOption
+	}
+	type want struct {
+		r   PatchResult
+		cd  *fake.Composed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Applied": {
+			reason: "A patch that changes the composed resource should be applied.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("objectMeta.name"),
+					ToFieldPath:   pointer.String("objectMeta.labels[example.org/name]"),
+				},
+			},
+			want: want{
+				r:  PatchResultApplied,
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd", Labels: map[string]string{"example.org/name": "cp", "example.org/ns": "ns"}}},
+			},
+		},
+		"NoChange": {
+			reason: "A patch that writes the existing value shouldn't change the composed resource.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("objectMeta.namespace"),
+					ToFieldPath:   pointer.String("objectMeta.labels[example.org/ns]"),
+				},
+			},
+			want: want{
+				r:  PatchResultNoChange,
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd", Labels: map[string]string{"example.org/ns": "ns"}}},
+			},
+		},
+		"SkippedOptional": {
+			reason: "A patch whose optional fromFieldPath doesn't exist should be skipped.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("objectMeta.labels[nope]"),
+					ToFieldPath:   pointer.String("objectMeta.labels[example.org/ns]"),
+				},
+			},
+			want: want{
+				r:  PatchResultSkippedOptional,
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd", Labels: map[string]string{"example.org/ns": "ns"}}},
+			},
+		},
+		"InvalidPatch": {
+			reason: "An error should be returned if the patch can't be applied.",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeFromCompositeFieldPath,
+				},
+			},
+			want: want{
+				cd:  &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd", Labels: map[string]string{"example.org/ns": "ns"}}},
+				err: errors.Errorf(errFmtRequiredField, "FromFieldPath", v1.PatchTypeFromCompositeFieldPath),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cp := &fake.Composite{ObjectMeta: metav1.ObjectMeta{Name: "cp", Namespace: "ns"}}
+			cd := &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd", Labels: map[string]string{"example.org/ns": "ns"}}}
+			r, err := ApplyWithResult(tc.args.patch, cp, cd, tc.args.o...)
+			if diff := cmp.Diff(tc.want.r, r); diff != "" {
+				t.Errorf("\n%s\nApplyWithResult(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApplyWithResult(...): -want composed, +got composed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApplyWithResult(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyExpectedType(t *testing.T) {
+	typ := func(t v1.TransformIOType) *v1.TransformIOType { return &t }
+	toInt := v1.Transform{Type: v1.TransformTypeConvert, Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeInt64}}
+
+	type args struct {
+		patch v1.Patch
+		cp    *composite.Unstructured
+	}
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ExpectedType": {
+			reason: "Should patch a value of the expected type.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.replicas"),
+					ToFieldPath:   pointer.String("spec.replicas"),
+					Transforms:    []v1.Transform{toInt},
+					ExpectedType:  typ(v1.TransformIOTypeInt),
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"spec": map[string]any{"replicas": "3"},
+				}}},
+			},
+			want: want{
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"spec":       map[string]any{"replicas": int64(3)},
+				}}},
+			},
+		},
+		"IntegerAsFloat": {
+			reason: "Should patch an integer if a float64 is expected.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.ratio"),
+					ToFieldPath:   pointer.String("spec.ratio"),
+					ExpectedType:  typ(v1.TransformIOTypeFloat64),
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"spec": map[string]any{"ratio": int64(1)},
+				}}},
+			},
+			want: want{
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"spec":       map[string]any{"ratio": int64(1)},
+				}}},
+			},
+		},
+		"UnexpectedType": {
+			reason: "Should return an error rather than patch a value of an unexpected type.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.replicas"),
+					ToFieldPath:   pointer.String("spec.replicas"),
+					ExpectedType:  typ(v1.TransformIOTypeInt),
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"spec": map[string]any{"replicas": "3"},
+				}}},
+			},
+			want: want{
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+				}}},
+				err: errors.Errorf(errFmtUnexpectedOutputType, v1.TransformIOTypeInt, v1.TransformIOTypeString),
+			},
+		},
+		"UnexpectedCombinedType": {
+			reason: "Should return an error rather than patch a combined value of an unexpected type.",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{{FromFieldPath: "spec.replicas"}},
+						Strategy:  v1.CombineStrategyString,
+						String:    &v1.StringCombine{Format: "%s"},
+					},
+					ToFieldPath:  pointer.String("spec.replicas"),
+					ExpectedType: typ(v1.TransformIOTypeInt64),
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"spec": map[string]any{"replicas": "3"},
+				}}},
+			},
+			want: want{
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+				}}},
+				err: errors.Errorf(errFmtUnexpectedOutputType, v1.TransformIOTypeInt64, v1.TransformIOTypeString),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Composed",
+			}}}
+			err := ApplyToObjects(tc.args.patch, tc.args.cp, cd)
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApplyToObjects(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApplyToObjects(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyToFieldPaths(t *testing.T) {
+	cp := func(spec map[string]any) *composite.Unstructured {
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{"spec": spec}}}
+	}
+	cd := func(fields map[string]any) *composed.Unstructured {
+		o := map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Composed",
+		}
+		for k, v := range fields {
+			o[k] = v
+		}
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: o}}
+	}
+	toUpper := v1.Transform{Type: v1.TransformTypeString, String: &v1.StringTransform{Type: v1.StringTransformTypeConvert, Convert: func() *v1.StringConversionType { c := v1.StringConversionTypeToUpper; return &c }()}}
+
+	type args struct {
+		patch v1.Patch
+		cp    *composite.Unstructured
+	}
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ToFieldPathAndToFieldPaths": {
+			reason: "Should write the transformed value to the ToFieldPath and to each of the ToFieldPaths.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPath:   pointer.String("spec.forProvider.region"),
+					ToFieldPaths:  []string{"metadata.labels[example.org/region]", "spec.location"},
+					Transforms:    []v1.Transform{toUpper},
+				},
+				cp: cp(map[string]any{"region": "us-east-1"}),
+			},
+			want: want{
+				cd: cd(map[string]any{
+					"metadata": map[string]any{"labels": map[string]any{"example.org/region": "US-EAST-1"}},
+					"spec": map[string]any{
+						"forProvider": map[string]any{"region": "US-EAST-1"},
+						"location":    "US-EAST-1",
+					},
+				}),
+			},
+		},
+		"OnlyToFieldPaths": {
+			reason: "Should write only to the ToFieldPaths, not to the FromFieldPath, if no ToFieldPath is set.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPaths:  []string{"spec.a", "spec.b"},
+				},
+				cp: cp(map[string]any{"region": "us-east-1"}),
+			},
+			want: want{
+				cd: cd(map[string]any{
+					"spec": map[string]any{"a": "us-east-1", "b": "us-east-1"},
+				}),
+			},
+		},
+		"OptionalSourceMissing": {
+			reason: "Should write to none of the ToFieldPaths if an optional FromFieldPath doesn't exist.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPaths:  []string{"spec.a", "spec.b"},
+				},
+				cp: cp(map[string]any{}),
+			},
+			want: want{
+				cd: cd(nil),
+			},
+		},
+		"IndependentWrites": {
+			reason: "Should write to the other ToFieldPaths if writing to one of them fails, and return an error.",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPaths:  []string{"spec.items[*].region", "spec.region"},
+				},
+				cp: cp(map[string]any{"region": "us-east-1"}),
+			},
+			want: want{
+				cd: cd(map[string]any{
+					"spec": map[string]any{"region": "us-east-1"},
+				}),
+				err: utilerrors.NewAggregate([]error{
+					errors.Wrapf(errors.Errorf(errFmtExpandingArrayFieldPaths, "spec.items[*].region"), errFmtPatchToFieldPath, "spec.items[*].region"),
+				}),
+			},
+		},
+		"CombineToFieldPaths": {
+			reason: "Should write a combined value to each of the ToFieldPaths.",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{{FromFieldPath: "spec.name"}, {FromFieldPath: "spec.region"}},
+						Strategy:  v1.CombineStrategyString,
+						String:    &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPaths: []string{"spec.a", "spec.b"},
+				},
+				cp: cp(map[string]any{"name": "db", "region": "us-east-1"}),
+			},
+			want: want{
+				cd: cd(map[string]any{
+					"spec": map[string]any{"a": "db-us-east-1", "b": "db-us-east-1"},
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cd(nil)
+			err := ApplyToObjects(tc.args.patch, tc.args.cp, got)
+			if diff := cmp.Diff(tc.want.cd, got); diff != "" {
+				t.Errorf("\n%s\nApplyToObjects(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApplyToObjects(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyAnnotationKeyFieldPaths(t *testing.T) {
+	cp := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]any{
+				"example.org/my.key": "dotted",
+				"example.org/a=b":    "equals",
+			},
+		},
+		"spec": map[string]any{"region": "us-east-1"},
+	}}}
+	cd := func(annotations map[string]any) *composed.Unstructured {
+		o := map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Composed",
+		}
+		if annotations != nil {
+			o["metadata"] = map[string]any{"annotations": annotations}
+		}
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: o}}
+	}
+
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		patch  v1.Patch
+		want   want
+	}{
+		"BracketedKey": {
+			reason: "Should read from and write to keys containing dots and slashes enclosed in brackets.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("metadata.annotations[example.org/my.key]"),
+				ToFieldPath:   pointer.String("metadata.annotations[example.org/your.key]"),
+			},
+			want: want{
+				cd: cd(map[string]any{"example.org/your.key": "dotted"}),
+			},
+		},
+		"SingleQuotedKey": {
+			reason: "Should read from and write to keys containing dots and slashes enclosed in single quotes.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("metadata.annotations['example.org/my.key']"),
+				ToFieldPath:   pointer.String("metadata.annotations['example.org/your.key']"),
+			},
+			want: want{
+				cd: cd(map[string]any{"example.org/your.key": "dotted"}),
+			},
+		},
+		"DoubleQuotedKey": {
+			reason: "Should read from and write to keys containing dots and slashes enclosed in double quotes.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String(`metadata.annotations["example.org/my.key"]`),
+				ToFieldPath:   pointer.String(`metadata.annotations["example.org/your.key"]`),
+			},
+			want: want{
+				cd: cd(map[string]any{"example.org/your.key": "dotted"}),
+			},
+		},
+		"QuotedKeyWithEquals": {
+			reason: "Should treat a quoted key containing an equals sign as a key, not as an array filter.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("metadata.annotations['example.org/a=b']"),
+				ToFieldPath:   pointer.String("metadata.annotations['example.org/c=d']"),
+			},
+			want: want{
+				cd: cd(map[string]any{"example.org/c=d": "equals"}),
+			},
+		},
+		"TemplatedQuotedKey": {
+			reason: "Should resolve a template within a quoted key containing dots.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("metadata.annotations['example.org/my.key']"),
+				ToFieldPath:   pointer.String("metadata.annotations['example.org/{{ spec.region }}.zone']"),
+			},
+			want: want{
+				cd: cd(map[string]any{"example.org/us-east-1.zone": "dotted"}),
+			},
+		},
+		"UnescapedKey": {
+			reason: "Should treat the dots of a key that isn't enclosed in brackets as separators, and so skip an optional patch.",
+			patch: v1.Patch{
+				FromFieldPath: pointer.String("metadata.annotations.example.org/my.key"),
+				ToFieldPath:   pointer.String("metadata.annotations[example.org/your.key]"),
+			},
+			want: want{
+				cd: cd(nil),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cd(nil)
+			err := ApplyToObjects(tc.patch, cp, got)
+			if diff := cmp.Diff(tc.want.cd, got); diff != "" {
+				t.Errorf("\n%s\nApplyToObjects(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApplyToObjects(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyFromComposedFieldPathPatch(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	bucket := func() *fake.Composed {
+		return &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cool-bucket"}}
+	}
+	cds := []ComposedResourceState{
+		{ComposedResource: ComposedResource{ResourceName: "bucket"}, Resource: bucket()},
+		{ComposedResource: ComposedResource{ResourceName: "broken"}, Resource: bucket(), TemplateRenderErr: errBoom},
+	}
+	required := v1.FromFieldPathPolicyRequired
+
+	type args struct {
+		patch v1.Patch
+		cds   []ComposedResourceState
+	}
+	type want struct {
+		cd  *fake.Composed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ByName": {
+			reason: "Should patch from the composed resource with the selected name",
+			args: args{
+				patch: v1.Patch{
+					Type:                 v1.PatchTypeFromComposedFieldPath,
+					FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("bucket")},
+					FromFieldPath:        pointer.String("objectMeta.name"),
+					ToFieldPath:          pointer.String("objectMeta.labels[bucket]"),
+				},
+				cds: cds,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"bucket": "cool-bucket"}}},
+			},
+		},
+		"ByIndex": {
+			reason: "Should patch from the composed resource at the selected index",
+			args: args{
+				patch: v1.Patch{
+					Type:                 v1.PatchTypeFromComposedFieldPath,
+					FromComposedResource: &v1.ComposedResourceSelector{Index: pointer.Int(0)},
+					FromFieldPath:        pointer.String("objectMeta.name"),
+					ToFieldPath:          pointer.String("objectMeta.labels[bucket]"),
+				},
+				cds: cds,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"bucket": "cool-bucket"}}},
+			},
+		},
+		"OptionalMissing": {
+			reason: "Should not patch if an optional source composed resource does not exist",
+			args: args{
+				patch: v1.Patch{
+					Type:                 v1.PatchTypeFromComposedFieldPath,
+					FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("database")},
+					FromFieldPath:        pointer.String("objectMeta.name"),
+				},
+				cds: cds,
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"OptionalMissingDefault": {
+			reason: "Should patch the fromFieldPathDefault if an optional source composed resource does not exist",
+			args: args{
+				patch: v1.Patch{
+					Type:                 v1.PatchTypeFromComposedFieldPath,
+					FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("database")},
+					FromFieldPath:        pointer.String("objectMeta.name"),
+					ToFieldPath:          pointer.String("objectMeta.labels[database]"),
+					Policy:               &v1.PatchPolicy{FromFieldPathDefault: &extv1.JSON{Raw: []byte(`"pending"`)}},
+				},
+				cds: cds,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"database": "pending"}}},
+			},
+		},
+		"OptionalNotRenderedTransformedDefault": {
+			reason: "Should transform the fromFieldPathDefault if a source composed resource that could not be rendered is optional and its policy says so",
+			args: args{
+				patch: v1.Patch{
+					Type:                 v1.PatchTypeFromComposedFieldPath,
+					FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("broken")},
+					FromFieldPath:        pointer.String("objectMeta.name"),
+					ToFieldPath:          pointer.String("objectMeta.labels[bucket]"),
+					Transforms: []v1.Transform{{
+						Type:   v1.TransformTypeString,
+						String: &v1.StringTransform{Type: v1.StringTransformTypeFormat, Format: pointer.String("bucket-%s")},
+					}},
+					Policy: &v1.PatchPolicy{
+						FromFieldPathDefault:          &extv1.JSON{Raw: []byte(`"pending"`)},
+						TransformFromFieldPathDefault: pointer.Bool(true),
+					},
+				},
+				cds: cds,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"bucket": "bucket-pending"}}},
+			},
+		},
+		"RequiredMissing": {
+			reason: "Should return an error if a required source composed resource does not exist",
+			args: args{
+				patch: v1.Patch{
+					Type:                 v1.PatchTypeFromComposedFieldPath,
+					FromComposedResource: &v1.ComposedResourceSelector{Index: pointer.Int(2)},
+					FromFieldPath:        pointer.String("objectMeta.name"),
+					Policy:               &v1.PatchPolicy{FromFieldPath: &required},
+				},
+				cds: cds,
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtComposedResourceIdxNotFound, 2),
+			},
+		},
+		"RequiredNotRendered": {
+			reason: "Should treat a source composed resource that could not be rendered as missing",
+			args: args{
+				patch: v1.Patch{
+					Type:                 v1.PatchTypeFromComposedFieldPath,
+					FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("broken")},
+					FromFieldPath:        pointer.String("objectMeta.name"),
+					Policy:               &v1.PatchPolicy{FromFieldPath: &required},
+				},
+				cds: cds,
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtComposedResourceNotFound, "broken"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := &fake.Composed{}
+			err := Apply(tc.args.patch, &fake.Composite{}, cd, WithComposedResources(tc.args.cds))
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyFromControllerConfigPatch(t *testing.T) {
+	cfg := fieldpath.Pave(map[string]any{
+		"defaults": map[string]any{
+			"region": "us-west-2",
+		},
+	})
+	required := v1.FromFieldPathPolicyRequired
+
+	errNotFound := func(p *fieldpath.Paved, path string) error {
+		_, err := p.GetValue(path)
+		return err
+	}
+
+	type args struct {
+		patch v1.Patch
+		cfg   ControllerConfig
+	}
+	type want struct {
+		cd  *fake.Composed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Patched": {
+			reason: "Should patch from the supplied controller configuration",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromControllerConfig,
+					FromFieldPath: pointer.String("defaults.region"),
+					ToFieldPath:   pointer.String("objectMeta.labels[region]"),
+				},
+				cfg: cfg,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"region": "us-west-2"}}},
+			},
+		},
+		"OptionalMissing": {
+			reason: "Should not patch if an optional value does not exist",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromControllerConfig,
+					FromFieldPath: pointer.String("defaults.zone"),
+					ToFieldPath:   pointer.String("objectMeta.labels[zone]"),
+				},
+				cfg: cfg,
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"RequiredMissing": {
+			reason: "Should return an error if a required value does not exist",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromControllerConfig,
+					FromFieldPath: pointer.String("defaults.zone"),
+					ToFieldPath:   pointer.String("objectMeta.labels[zone]"),
+					Policy:        &v1.PatchPolicy{FromFieldPath: &required},
+				},
+				cfg: cfg,
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errNotFound(cfg, "defaults.zone"),
+			},
+		},
+		"OptionalNoControllerConfig": {
+			reason: "Should not patch if no controller configuration was supplied",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromControllerConfig,
+					FromFieldPath: pointer.String("defaults.region"),
+					ToFieldPath:   pointer.String("objectMeta.labels[region]"),
+				},
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"RequiredNoControllerConfig": {
+			reason: "Should return an error if a required value is read without a controller configuration",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromControllerConfig,
+					FromFieldPath: pointer.String("defaults.region"),
+					ToFieldPath:   pointer.String("objectMeta.labels[region]"),
+					Policy:        &v1.PatchPolicy{FromFieldPath: &required},
+				},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errNotFound(fieldpath.Pave(map[string]any{}), "defaults.region"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := &fake.Composed{}
+			err := Apply(tc.args.patch, &fake.Composite{}, cd, WithControllerConfig(tc.args.cfg))
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewControllerConfig(t *testing.T) {
+	type args struct {
+		values map[string]string
+	}
+	type want struct {
+		cfg ControllerConfig
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoValues": {
+			reason: "Should return an empty configuration if there are no values",
+			want: want{
+				cfg: fieldpath.Pave(map[string]any{}),
+			},
+		},
+		"Values": {
+			reason: "Should expose each value at the field path of its key",
+			args: args{
+				values: map[string]string{
+					"region":   "us-west-2",
+					"equation": "a=b",
+					"zone":     "",
+				},
+			},
+			want: want{
+				cfg: fieldpath.Pave(map[string]any{
+					"region":   "us-west-2",
+					"equation": "a=b",
+					"zone":     "",
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := NewControllerConfig(tc.args.values)
+			if diff := cmp.Diff(tc.want.cfg, cfg, cmp.AllowUnexported(fieldpath.Paved{})); diff != "" {
+				t.Errorf("\n%s\nNewControllerConfig(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyFromConnectionSecretKeyPatch(t *testing.T) {
+	secrets := ConnectionSecretResolverFn(func(key string) ([]byte, bool, error) {
+		v, ok := map[string][]byte{"tls.crt": []byte("s3cr3t")}[key]
+		return v, ok, nil
+	})
+	required := v1.FromFieldPathPolicyRequired
+	errBoom := errors.New("boom")
+
+	type args struct {
+		patch   v1.Patch
+		cp      *fake.Composite
+		cd      *fake.Composed
+		secrets ConnectionSecretResolver
+	}
+	type want struct {
+		cd  *fake.Composed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Patched": {
+			reason: "Should patch from the supplied connection secret",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+					ToFieldPath:         pointer.String("objectMeta.annotations[cert]"),
+				},
+				secrets: secrets,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"cert": "s3cr3t"}}},
+			},
+		},
+		"WildcardToFieldPath": {
+			reason: "Should patch every field path a wildcard toFieldPath expands to",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+					ToFieldPath:         pointer.String("objectMeta.ownerReferences[*].name"),
+				},
+				cd:      &fake.Composed{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Name: "a"}, {Name: "b"}}}},
+				secrets: secrets,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Name: "s3cr3t"}, {Name: "s3cr3t"}}}},
+			},
+		},
+		"TemplatedToFieldPaths": {
+			reason: "Should resolve templated key segments of each toFieldPath using the composite resource",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+					ToFieldPath:         pointer.String("objectMeta.annotations[cert-{{ objectMeta.labels.region }}]"),
+					ToFieldPaths:        []string{"objectMeta.labels[cert]"},
+				},
+				cp:      &fake.Composite{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"region": "us-east-1"}}},
+				secrets: secrets,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"cert-us-east-1": "s3cr3t"},
+					Labels:      map[string]string{"cert": "s3cr3t"},
+				}},
+			},
+		},
+		"MissingToFieldPath": {
+			reason: "Should return an error if the patch has no toFieldPath",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+				},
+				secrets: secrets,
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtRequiredField, "ToFieldPath", v1.PatchTypeFromConnectionSecretKey),
+			},
+		},
+		"OptionalMissing": {
+			reason: "Should not patch if an optional key does not exist",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.key"),
+					ToFieldPath:         pointer.String("objectMeta.annotations[key]"),
+				},
+				secrets: secrets,
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"RequiredMissing": {
+			reason: "Should return an error if a required key does not exist",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.key"),
+					ToFieldPath:         pointer.String("objectMeta.annotations[key]"),
+					Policy:              &v1.PatchPolicy{FromFieldPath: &required},
+				},
+				secrets: secrets,
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtConnectionSecretKeyNotFound, "tls.key"),
+			},
+		},
+		"RequiredNoResolver": {
+			reason: "Should return an error if a required key is read without a resolver",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+					ToFieldPath:         pointer.String("objectMeta.annotations[cert]"),
+					Policy:              &v1.PatchPolicy{FromFieldPath: &required},
+				},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtConnectionSecretKeyNotFound, "tls.crt"),
+			},
+		},
+		"ResolveError": {
+			reason: "Should return an error if the key can't be resolved",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+					ToFieldPath:         pointer.String("objectMeta.annotations[cert]"),
+				},
+				secrets: ConnectionSecretResolverFn(func(_ string) ([]byte, bool, error) { return nil, false, errBoom }),
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Wrapf(errBoom, errFmtResolveConnectionSecretKey, "tls.crt"),
+			},
+		},
+		"TransformErrorOmitsValue": {
+			reason: "Should return an error that doesn't include the value if a transform fails",
+			args: args{
+				patch: v1.Patch{
+					Type:                v1.PatchTypeFromConnectionSecretKey,
+					ConnectionSecretKey: pointer.String("tls.crt"),
+					ToFieldPath:         pointer.String("objectMeta.annotations[cert]"),
+					Transforms: []v1.Transform{{
+						Type: v1.TransformTypeMap,
+						Map:  &v1.MapTransform{Pairs: map[string]extv1.JSON{}},
+					}},
+				},
+				secrets: secrets,
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtTransformConnectionSecretKey, "tls.crt"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cp, cd := tc.args.cp, tc.args.cd
+			if cp == nil {
+				cp = &fake.Composite{}
+			}
+			if cd == nil {
+				cd = &fake.Composed{}
+			}
+			err := Apply(tc.args.patch, cp, cd, WithConnectionSecretResolver(tc.args.secrets))
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyFromComposedConnectionSecretKeyPatch(t *testing.T) {
+	cds := []ComposedResourceState{
+		{
+			ComposedResource:  ComposedResource{ResourceName: "database"},
+			Resource:          &fake.Composed{},
+			ConnectionDetails: map[string][]byte{"endpoint": []byte("db.example.org")},
+		},
+		{
+			ComposedResource:  ComposedResource{ResourceName: "cache"},
+			TemplateRenderErr: errors.New("boom"),
+			ConnectionDetails: map[string][]byte{"endpoint": []byte("cache.example.org")},
+		},
+	}
+	patch := func(name, key string) v1.Patch {
+		return v1.Patch{
+			Type:                 v1.PatchTypeFromComposedConnectionSecretKey,
+			FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String(name)},
+			ConnectionSecretKey:  pointer.String(key),
+			ToFieldPath:          pointer.String("objectMeta.annotations[endpoint]"),
+		}
+	}
+	required := func(p v1.Patch) v1.Patch {
+		r := v1.FromFieldPathPolicyRequired
+		p.Policy = &v1.PatchPolicy{FromFieldPath: &r}
+		return p
+	}
+	errBoom := errors.New("boom")
+
+	type args struct {
+		patch v1.Patch
+		o     []ApplyOption
+	}
+	type want struct {
+		cd  *fake.Composed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"PatchedFromComposedResources": {
+			reason: "Should patch from the connection details of the supplied composed resources",
+			args: args{
+				patch: patch("database", "endpoint"),
+				o:     []ApplyOption{WithComposedResources(cds)},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"endpoint": "db.example.org"}}},
+			},
+		},
+		"PatchedFromResolver": {
+			reason: "Should patch from the supplied resolver in preference to the supplied composed resources",
+			args: args{
+				patch: patch("database", "endpoint"),
+				o: []ApplyOption{
+					WithComposedResources(cds),
+					WithComposedConnectionSecretResolver(ComposedConnectionSecretResolverFn(func(name, key string) ([]byte, bool, error) {
+						return []byte(name + "/" + key), true, nil
+					})),
+				},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"endpoint": "database/endpoint"}}},
+			},
+		},
+		"MissingName": {
+			reason: "Should return an error if the patch doesn't select a composed resource by name",
+			args: args{
+				patch: v1.Patch{
+					Type:                 v1.PatchTypeFromComposedConnectionSecretKey,
+					FromComposedResource: &v1.ComposedResourceSelector{Index: pointer.Int(0)},
+					ConnectionSecretKey:  pointer.String("endpoint"),
+					ToFieldPath:          pointer.String("objectMeta.annotations[endpoint]"),
+				},
+				o: []ApplyOption{WithComposedResources(cds)},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtRequiredField, "FromComposedResource.Name", v1.PatchTypeFromComposedConnectionSecretKey),
+			},
+		},
+		"OptionalMissingKey": {
+			reason: "Should not patch if an optional key does not exist",
+			args: args{
+				patch: patch("database", "password"),
+				o:     []ApplyOption{WithComposedResources(cds)},
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"OptionalUnrenderedResource": {
+			reason: "Should not patch from a composed resource that could not be rendered",
+			args: args{
+				patch: patch("cache", "endpoint"),
+				o:     []ApplyOption{WithComposedResources(cds)},
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"RequiredMissingResource": {
+			reason: "Should return an error if a required key is read from a composed resource that does not exist",
+			args: args{
+				patch: required(patch("queue", "endpoint")),
+				o:     []ApplyOption{WithComposedResources(cds)},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtComposedConnectionSecretKeyNotFound, "endpoint", "queue"),
+			},
+		},
+		"ResolveError": {
+			reason: "Should return an error if the key can't be resolved",
+			args: args{
+				patch: patch("database", "endpoint"),
+				o: []ApplyOption{WithComposedConnectionSecretResolver(ComposedConnectionSecretResolverFn(func(_, _ string) ([]byte, bool, error) {
+					return nil, false, errBoom
+				}))},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Wrapf(errBoom, errFmtResolveComposedConnectionSecretKey, "endpoint", "database"),
+			},
+		},
+		"TransformErrorOmitsValue": {
+			reason: "Should return an error that doesn't include the value if a transform fails",
+			args: args{
+				patch: func() v1.Patch {
+					p := patch("database", "endpoint")
+					p.Transforms = []v1.Transform{{Type: v1.TransformTypeMap, Map: &v1.MapTransform{Pairs: map[string]extv1.JSON{}}}}
+					return p
+				}(),
+				o: []ApplyOption{WithComposedResources(cds)},
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Errorf(errFmtTransformConnectionSecretKey, "endpoint"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := &fake.Composed{}
+			err := Apply(tc.args.patch, &fake.Composite{}, cd, tc.args.o...)
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyToCompositeFieldPathPatchStatus(t *testing.T) {
+	urlFormat := v1.Transform{
+		Type:   v1.TransformTypeString,
+		String: &v1.StringTransform{Type: v1.StringTransformTypeFormat, Format: pointer.String("https://%s")},
+	}
+	required := v1.FromFieldPathPolicyRequired
+	parse := v1.Transform{Type: v1.TransformTypeJSONParse}
+	selectAddress := v1.Transform{
+		Type:        v1.TransformTypeFieldSelect,
+		FieldSelect: &v1.FieldSelectTransform{FieldPath: "endpoints[0].address"},
+	}
+
+	type args struct {
+		patch v1.Patch
+		cp    *composite.Unstructured
+		cd    *composed.Unstructured
+	}
+	type want struct {
+		cp  *composite.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"StatusToStatus": {
+			reason: "Should patch a status field of the composed resource to a status field of the composite resource",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.id"),
+					ToFieldPath:   pointer.String("status.id"),
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"spec":       map[string]any{"region": "us-west-2"},
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status":     map[string]any{"atProvider": map[string]any{"id": "cool-id"}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"spec":       map[string]any{"region": "us-west-2"},
+					"status":     map[string]any{"id": "cool-id"},
+				}}},
+			},
+		},
+		"StatusToExistingStatus": {
+			reason: "Should preserve existing status fields of the composite resource when patching a status field",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.id"),
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"status":     map[string]any{"atProvider": map[string]any{"arn": "cool-arn"}},
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status":     map[string]any{"atProvider": map[string]any{"id": "cool-id"}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"status":     map[string]any{"atProvider": map[string]any{"arn": "cool-arn", "id": "cool-id"}},
+				}}},
+			},
+		},
+		"MergeConditions": {
+			reason: "Should merge conditions by type, keeping the lastTransitionTime of a condition whose status didn't change",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.conditions"),
+					ToFieldPath:   pointer.String("status.resourceConditions"),
+					Policy:        &v1.PatchPolicy{MergeConditions: pointer.Bool(true)},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"status": map[string]any{"resourceConditions": []any{
+						map[string]any{"type": "Ready", "status": "True", "reason": "Available", "lastTransitionTime": "2023-01-01T00:00:00Z"},
+						map[string]any{"type": "Synced", "status": "True", "lastTransitionTime": "2023-01-01T00:00:00Z"},
+						map[string]any{"type": "Healthy", "status": "True", "lastTransitionTime": "2023-01-01T00:00:00Z"},
+					}},
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status": map[string]any{"conditions": []any{
+						map[string]any{"type": "Ready", "status": "True", "reason": "Serving", "lastTransitionTime": "2023-02-01T00:00:00Z"},
+						map[string]any{"type": "Healthy", "status": "False", "lastTransitionTime": "2023-02-01T00:00:00Z"},
+						map[string]any{"type": "Upgraded", "status": "True", "lastTransitionTime": "2023-02-01T00:00:00Z"},
+					}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"status": map[string]any{"resourceConditions": []any{
+						map[string]any{"type": "Ready", "status": "True", "reason": "Serving", "lastTransitionTime": "2023-01-01T00:00:00Z"},
+						map[string]any{"type": "Synced", "status": "True", "lastTransitionTime": "2023-01-01T00:00:00Z"},
+						map[string]any{"type": "Healthy", "status": "False", "lastTransitionTime": "2023-02-01T00:00:00Z"},
+						map[string]any{"type": "Upgraded", "status": "True", "lastTransitionTime": "2023-02-01T00:00:00Z"},
+					}},
+				}}},
+			},
+		},
+		"MergeConditionsNotConditions": {
+			reason: "Should return an error if the patched value isn't an array of conditions",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.conditions"),
+					ToFieldPath:   pointer.String("status.resourceConditions"),
+					Policy:        &v1.PatchPolicy{MergeConditions: pointer.Bool(true)},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status":     map[string]any{"conditions": []any{map[string]any{"status": "True"}}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				err: errors.Errorf(errFmtMergeConditionsNotCondition, 0),
+			},
+		},
+		"ParsedStatusFieldSelect": {
+			reason: "Should patch a field selected from a JSON status field parsed by a jsonParse transform",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.details"),
+					ToFieldPath:   pointer.String("status.address"),
+					Transforms:    []v1.Transform{parse, selectAddress},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status":     map[string]any{"atProvider": map[string]any{"details": `{"endpoints":[{"address":"db.example.org"}]}`}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"status":     map[string]any{"address": "db.example.org"},
+				}}},
+			},
+		},
+		"ParsedStatusFieldSelectMissing": {
+			reason: "Should skip the patch if an optional fieldSelect transform selects a field that doesn't exist",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.details"),
+					ToFieldPath:   pointer.String("status.address"),
+					Transforms:    []v1.Transform{parse, selectAddress},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status":     map[string]any{"atProvider": map[string]any{"details": `{"endpoints":[]}`}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+			},
+		},
+		"ParsedStatusFieldSelectMissingRequired": {
+			reason: "Should return an error if an optional fieldSelect transform of a required patch selects a field that doesn't exist",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.details"),
+					ToFieldPath:   pointer.String("status.address"),
+					Transforms:    []v1.Transform{parse, selectAddress},
+					Policy:        &v1.PatchPolicy{FromFieldPath: &required},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status":     map[string]any{"atProvider": map[string]any{"details": `{"endpoints":[]}`}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				err: errors.Wrapf(errors.Wrapf(errors.Wrap(errors.New("endpoints[0]: no such element"), errFieldSelectNotFound), errFmtTransformTypeFailed, v1.TransformTypeFieldSelect), errFmtTransformAtIndex, 1),
+			},
+		},
+		"SelectMatchNoMatchRequired": {
+			reason: "Should return an error if an optional selectMatch transform of a required patch matches no element",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.endpoints"),
+					ToFieldPath:   pointer.String("status.address"),
+					Transforms: []v1.Transform{{
+						Type: v1.TransformTypeSelectMatch,
+						SelectMatch: &v1.SelectMatchTransform{
+							FieldPath:   "name",
+							Value:       extv1.JSON{Raw: []byte(`"primary"`)},
+							ReturnField: pointer.String("address"),
+						},
+					}},
+					Policy: &v1.PatchPolicy{FromFieldPath: &required},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status": map[string]any{"atProvider": map[string]any{"endpoints": []any{
+						map[string]any{"name": "replica", "address": "replica.example.org"},
+					}}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				err: errors.Wrapf(errors.Wrapf(errors.New(errSelectMatchNoMatch), errFmtTransformTypeFailed, v1.TransformTypeSelectMatch), errFmtTransformAtIndex, 0),
+			},
+		},
+		"MissingStatusDefault": {
+			reason: "Should patch the default, untransformed, if the status field of a composed resource that was just created doesn't exist yet",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.endpoint"),
+					ToFieldPath:   pointer.String("status.url"),
+					Transforms:    []v1.Transform{urlFormat},
+					Policy:        &v1.PatchPolicy{FromFieldPathDefault: &extv1.JSON{Raw: []byte(`"pending"`)}},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"status":     map[string]any{"url": "pending"},
+				}}},
+			},
+		},
+		"ExistingStatusIgnoresDefault": {
+			reason: "Should patch the transformed value of the status field once it exists, rather than the default",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.endpoint"),
+					ToFieldPath:   pointer.String("status.url"),
+					Transforms:    []v1.Transform{urlFormat},
+					Policy:        &v1.PatchPolicy{FromFieldPathDefault: &extv1.JSON{Raw: []byte(`"pending"`)}},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"status":     map[string]any{"url": "pending"},
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+					"status":     map[string]any{"atProvider": map[string]any{"endpoint": "db.example.org"}},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+					"status":     map[string]any{"url": "https://db.example.org"},
+				}}},
+			},
+		},
+		"RequiredPolicyIgnoresDefault": {
+			reason: "Should return an error rather than patch the default if the status field is required",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.endpoint"),
+					ToFieldPath:   pointer.String("status.url"),
+					Policy: &v1.PatchPolicy{
+						FromFieldPath:        &required,
+						FromFieldPathDefault: &extv1.JSON{Raw: []byte(`"pending"`)},
+					},
+				},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composed",
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XR",
+				}}},
+				err: func() error {
+					_, err := fieldpath.Pave(map[string]any{}).GetValue("status.atProvider.endpoint")
+					return err
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Apply(tc.args.patch, tc.args.cp, tc.args.cd)
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp); diff != "" {
+				t.Errorf("\n%s\nApply(cp): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyCounterPatch(t *testing.T) {
+	optional := v1.FromFieldPathPolicyOptional
+	increment := v1.Transform{
+		Type: v1.TransformTypeMath,
+		Math: &v1.MathTransform{Type: v1.MathTransformTypeAdd, Add: pointer.Int64(1)},
+	}
+	counter := func(typ v1.PatchType) v1.Patch {
+		return v1.Patch{
+			Type:          typ,
+			FromFieldPath: pointer.String("status.counter"),
+			ToFieldPath:   pointer.String("status.counter"),
+			Transforms:    []v1.Transform{increment},
+			Policy: &v1.PatchPolicy{
+				FromFieldPath:                 &optional,
+				FromFieldPathDefault:          &extv1.JSON{Raw: []byte(`0`)},
+				TransformFromFieldPathDefault: pointer.Bool(true),
+			},
+		}
+	}
+	// xr and cd return a composite and composed resource with the supplied
+	// counter, or without a counter if it's negative.
+	xr := func(counter int64) *composite.Unstructured {
+		u := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "XR",
+		}}}
+		if counter >= 0 {
+			u.Object["status"] = map[string]any{"counter": counter}
+		}
+		return u
+	}
+	cd := func(counter int64) *composed.Unstructured {
+		u := &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Composed",
+		}}}
+		if counter >= 0 {
+			u.Object["status"] = map[string]any{"counter": counter}
+		}
+		return u
+	}
+
+	type args struct {
+		patch v1.Patch
+		cp    *composite.Unstructured
+		cd    *composed.Unstructured
+	}
+	type want struct {
+		cp  *composite.Unstructured
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"IncrementToComposed": {
+			reason: "Should patch the composite's counter plus one to the same field path of the composed resource",
+			args: args{
+				patch: counter(v1.PatchTypeFromCompositeFieldPath),
+				cp:    xr(41),
+				cd:    cd(41),
+			},
+			want: want{
+				cp: xr(41),
+				cd: cd(42),
+			},
+		},
+		"IncrementBackToComposite": {
+			reason: "Should read the counter before writing it, when patching back to the composite",
+			args: args{
+				patch: counter(v1.PatchTypeToCompositeFieldPath),
+				cp:    xr(7),
+				cd:    cd(7),
+			},
+			want: want{
+				cp: xr(8),
+				cd: cd(7),
+			},
+		},
+		"MissingCounterDefaultsToZero": {
+			reason: "Should transform the default of zero if the counter doesn't exist yet",
+			args: args{
+				patch: counter(v1.PatchTypeFromCompositeFieldPath),
+				cp:    xr(-1),
+				cd:    cd(-1),
+			},
+			want: want{
+				cp: xr(-1),
+				cd: cd(1),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Apply(tc.args.patch, tc.args.cp, tc.args.cd)
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp); diff != "" {
+				t.Errorf("\n%s\nApply(cp): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyResource(t *testing.T) {
+	required := v1.FromFieldPathPolicyRequired
+	errMissing := func() error {
+		_, err := fieldpath.Pave(map[string]any{"spec": map[string]any{}}).GetValue("spec.missing")
+		return err
+	}()
+
+	from := v1.PatchTypeFromCompositeFieldPath
+	patches := []v1.Patch{
+		{Type: from, FromFieldPath: pointer.String("spec.a")},
+		{Type: from, FromFieldPath: pointer.String("spec.missing"), Policy: &v1.PatchPolicy{FromFieldPath: &required}},
+		{Type: from, FromFieldPath: pointer.String("spec.optional")},
+		{Type: from, FromFieldPath: pointer.String("spec.b")},
+		{Type: v1.PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String("status.c")},
+	}
+	xr := func() *composite.Unstructured {
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "XR",
+			"spec":       map[string]any{"a": "A", "b": "B"},
+		}}}
+	}
+	cd := func(spec map[string]any) *composed.Unstructured {
+		u := &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Composed",
+		}}}
+		if spec != nil {
+			u.Object["spec"] = spec
+		}
+		return u
+	}
+
+	type args struct {
+		apply func(cp resource.Composite, cd resource.Composed, ps []v1.Patch, o ...ApplyOption) error
+		o     []ApplyOption
+	}
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"StopOnError": {
+			reason: "Patches should be applied in order until one returns an error.",
+			args: args{
+				apply: ApplyResource,
+				o:     []ApplyOption{OnlyPatchTypes(v1.PatchTypeFromCompositeFieldPath)},
+			},
+			want: want{
+				cd:  cd(map[string]any{"a": "A"}),
+				err: errors.Wrapf(errMissing, errFmtPatch, 1),
+			},
+		},
+		"Continue": {
+			reason: "Every patch should be applied, and the errors of all patches returned.",
+			args: args{
+				apply: ApplyResourceContinue,
+				o:     []ApplyOption{OnlyPatchTypes(v1.PatchTypeFromCompositeFieldPath)},
+			},
+			want: want{
+				cd:  cd(map[string]any{"a": "A", "b": "B"}),
+				err: utilerrors.NewAggregate([]error{errors.Wrapf(errMissing, errFmtPatch, 1)}),
+			},
+		},
+		"NoErrors": {
+			reason: "Only patches of the supplied types should be applied.",
+			args: args{
+				apply: ApplyResourceContinue,
+				o:     []ApplyOption{OnlyPatchTypes(v1.PatchTypeToCompositeFieldPath)},
+			},
+			want: want{
+				cd: cd(nil),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cd(nil)
+			err := tc.args.apply(xr(), got, patches, tc.args.o...)
+			if diff := cmp.Diff(tc.want.cd, got); diff != "" {
+				t.Errorf("\n%s\nApplyResource(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApplyResource(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyCombineArrayPatch(t *testing.T) {
+	optional := v1.FromFieldPathPolicyOptional
+	required := v1.FromFieldPathPolicyRequired
+
+	xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "XR",
+		"spec": map[string]any{
+			"officeCIDR": "10.0.0.0/16",
+			"vpnCIDR":    "",
+			"homeCIDR":   "192.168.0.0/24",
+		},
+	}}}
+	cd := func(spec map[string]any) *composed.Unstructured {
+		u := &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Composed",
+		}}}
+		if spec != nil {
+			u.Object["spec"] = spec
+		}
+		return u
+	}
+	patch := func(pp *v1.PatchPolicy, vars ...v1.CombineVariable) v1.Patch {
+		return v1.Patch{
+			Type:        v1.PatchTypeCombineFromComposite,
+			Combine:     &v1.Combine{Variables: vars, Strategy: v1.CombineStrategyArray},
+			ToFieldPath: pointer.String("spec.allowedRanges"),
+			Policy:      pp,
+		}
+	}
+
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		patch  v1.Patch
+		want   want
+	}{
+		"SkipMissingAndEmpty": {
+			reason: "Variables that are empty or whose optional field doesn't exist should be skipped.",
+			patch: patch(nil,
+				v1.CombineVariable{FromFieldPath: "spec.officeCIDR"},
+				v1.CombineVariable{FromFieldPath: "spec.vpnCIDR"},
+				v1.CombineVariable{FromFieldPath: "spec.partnerCIDR"},
+				v1.CombineVariable{FromFieldPath: "spec.homeCIDR"},
+			),
+			want: want{
+				cd: cd(map[string]any{"allowedRanges": []any{"10.0.0.0/16", "192.168.0.0/24"}}),
+			},
+		},
+		"RequiredVariableMissing": {
+			reason: "A required variable whose field doesn't exist should return an error.",
+			patch: patch(nil,
+				v1.CombineVariable{FromFieldPath: "spec.officeCIDR"},
+				v1.CombineVariable{FromFieldPath: "spec.partnerCIDR", Policy: &required},
+			),
+			want: want{
+				cd: cd(nil),
+				err: func() error {
+					_, err := fieldpath.Pave(map[string]any{"spec": map[string]any{}}).GetValue("spec.partnerCIDR")
+					return err
+				}(),
+			},
+		},
+		"OptionalVariableOfRequiredPatch": {
+			reason: "An optional variable should be skipped even if the patch requires its field paths.",
+			patch: patch(&v1.PatchPolicy{FromFieldPath: &required},
+				v1.CombineVariable{FromFieldPath: "spec.partnerCIDR", Policy: &optional},
+				v1.CombineVariable{FromFieldPath: "spec.officeCIDR"},
+			),
+			want: want{
+				cd: cd(map[string]any{"allowedRanges": []any{"10.0.0.0/16"}}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cd(nil)
+			err := Apply(tc.patch, &composite.Unstructured{Unstructured: *xr.DeepCopy()}, got)
+			if diff := cmp.Diff(tc.want.cd, got); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyStrategicMergePatch(t *testing.T) {
+	strategic := &v1.PatchPolicy{StrategicMerge: pointer.Bool(true)}
+
+	xr := func(manifest any) *composite.Unstructured {
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "XR",
+			"spec":       map[string]any{"manifest": manifest},
+		}}}
+	}
+	cd := func(manifest map[string]any) *composed.Unstructured {
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "kubernetes.crossplane.io/v1alpha1",
+			"kind":       "Object",
+			"spec":       map[string]any{"forProvider": map[string]any{"manifest": manifest}},
+		}}}
+	}
+	deployment := func(containers ...any) map[string]any {
+		return map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec": map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": containers,
+			}}},
+		}
+	}
+	patch := v1.Patch{
+		Type:          v1.PatchTypeFromCompositeFieldPath,
+		FromFieldPath: pointer.String("spec.manifest"),
+		ToFieldPath:   pointer.String("spec.forProvider.manifest"),
+		Policy:        strategic,
+	}
+
+	type args struct {
+		xr *composite.Unstructured
+		cd *composed.Unstructured
+	}
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"BuiltinKind": {
+			reason: "An object of a built in kind should be merged using its merge keys, e.g. containers by name.",
+			args: args{
+				xr: xr(map[string]any{"spec": map[string]any{"template": map[string]any{"spec": map[string]any{
+					"containers": []any{map[string]any{"name": "app", "image": "app:v2"}},
+				}}}}),
+				cd: cd(deployment(
+					map[string]any{"name": "app", "image": "app:v1"},
+					map[string]any{"name": "proxy", "image": "proxy:v1"},
+				)),
+			},
+			want: want{
+				cd: cd(deployment(
+					map[string]any{"name": "app", "image": "app:v2"},
+					map[string]any{"name": "proxy", "image": "proxy:v1"},
+				)),
+			},
+		},
+		"UnknownKind": {
+			reason: "An object of a kind that isn't built in should be merged like a JSON merge patch.",
+			args: args{
+				xr: xr(map[string]any{"spec": map[string]any{"replicas": int64(3), "paused": nil, "tags": []any{"b"}}}),
+				cd: cd(map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Widget",
+					"spec":       map[string]any{"size": "large", "paused": true, "tags": []any{"a"}},
+				}),
+			},
+			want: want{
+				cd: cd(map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "Widget",
+					"spec":       map[string]any{"size": "large", "replicas": int64(3), "tags": []any{"b"}},
+				}),
+			},
+		},
+		"MissingDestination": {
+			reason: "An object should be patched as is if there is no object at the toFieldPath.",
+			args: args{
+				xr: xr(deployment(map[string]any{"name": "app", "image": "app:v2"})),
+				cd: &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "kubernetes.crossplane.io/v1alpha1",
+					"kind":       "Object",
+				}}},
+			},
+			want: want{
+				cd: cd(deployment(map[string]any{"name": "app", "image": "app:v2"})),
+			},
+		},
+		"NotAnObject": {
+			reason: "A value that isn't an object should return an error.",
+			args: args{
+				xr: xr("nope"),
+				cd: cd(deployment()),
+			},
+			want: want{
+				cd:  cd(deployment()),
+				err: errors.New(errStrategicMergeNonObject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Apply(patch, tc.args.xr, tc.args.cd)
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyEmbeddedJSONPatch(t *testing.T) {
+	xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "XR",
+		"spec": map[string]any{
+			"bucketArn": "arn:aws:s3:::example",
+			"region":    "eu-west-1",
+		},
+	}}}
+	cd := func(policy any) *composed.Unstructured {
+		fp := map[string]any{}
+		if policy != nil {
+			fp["policy"] = policy
+		}
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "iam.aws.upbound.io/v1beta1",
+			"kind":       "Policy",
+			"spec":       map[string]any{"forProvider": fp},
+		}}}
+	}
+	patch := func(ep *v1.EmbeddedJSONPolicy) v1.Patch {
+		return v1.Patch{
+			Type:          v1.PatchTypeFromCompositeFieldPath,
+			FromFieldPath: pointer.String("spec.bucketArn"),
+			ToFieldPath:   pointer.String("spec.forProvider.policy"),
+			Policy:        &v1.PatchPolicy{ToEmbeddedJSON: ep},
+		}
+	}
+	resource := &v1.EmbeddedJSONPolicy{FieldPath: "Statement[0].Resource"}
+	errInvalid := json.Unmarshal([]byte("not json"), &map[string]any{})
+
+	type args struct {
+		patch v1.Patch
+		cd    *composed.Unstructured
+	}
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ExistingObject": {
+			reason: "A field of an existing embedded JSON object should be patched, and other fields kept.",
+			args: args{
+				patch: patch(resource),
+				cd:    cd(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Resource": "*"}]}`),
+			},
+			want: want{
+				cd: cd(`{"Statement":[{"Effect":"Allow","Resource":"arn:aws:s3:::example"}],"Version":"2012-10-17"}`),
+			},
+		},
+		"NotFound": {
+			reason: "A field that doesn't exist should be patched as though it were an empty object.",
+			args: args{
+				patch: patch(resource),
+				cd:    cd(nil),
+			},
+			want: want{
+				cd: cd(`{"Statement":[{"Resource":"arn:aws:s3:::example"}]}`),
+			},
+		},
+		"Combine": {
+			reason: "The output of a combine patch should be patched to the embedded JSON object.",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{{FromFieldPath: "spec.region"}, {FromFieldPath: "spec.bucketArn"}},
+						Strategy:  v1.CombineStrategyString,
+						String:    &v1.StringCombine{Format: "%s/%s"},
+					},
+					ToFieldPath: pointer.String("spec.forProvider.policy"),
+					Policy:      &v1.PatchPolicy{ToEmbeddedJSON: &v1.EmbeddedJSONPolicy{FieldPath: "Id"}},
+				},
+				cd: cd(`{"Version":"2012-10-17"}`),
+			},
+			want: want{
+				cd: cd(`{"Id":"eu-west-1/arn:aws:s3:::example","Version":"2012-10-17"}`),
+			},
+		},
+		"Invalid": {
+			reason: "A string that isn't a JSON object should return an error.",
+			args: args{
+				patch: patch(resource),
+				cd:    cd("not json"),
+			},
+			want: want{
+				cd:  cd("not json"),
+				err: errors.Wrap(errInvalid, errEmbeddedJSONInvalid),
+			},
+		},
+		"InitializeInvalid": {
+			reason: "A string that isn't a JSON object should be replaced with an empty object if the policy initializes invalid strings.",
+			args: args{
+				patch: patch(&v1.EmbeddedJSONPolicy{FieldPath: "Statement[0].Resource", InitializeInvalid: pointer.Bool(true)}),
+				cd:    cd("not json"),
+			},
+			want: want{
+				cd: cd(`{"Statement":[{"Resource":"arn:aws:s3:::example"}]}`),
+			},
+		},
+		"NonString": {
+			reason: "An existing value that isn't a string should return an error.",
+			args: args{
+				patch: patch(resource),
+				cd:    cd(map[string]any{"Version": "2012-10-17"}),
+			},
+			want: want{
+				cd:  cd(map[string]any{"Version": "2012-10-17"}),
+				err: errors.New(errEmbeddedJSONNonString),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Apply(tc.args.patch, xr, tc.args.cd)
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyCopiesComplexValues(t *testing.T) {
+	type args struct {
+		patch  v1.Patch
+		cp     *composite.Unstructured
+		cd     *composed.Unstructured
+		mutate func(cp *composite.Unstructured, cd *composed.Unstructured)
+	}
+	type want struct {
+		cp *composite.Unstructured
+		cd *composed.Unstructured
+	}
+
+	xr := func(spec map[string]any) *composite.Unstructured {
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "XR",
+			"spec":       spec,
+		}}}
+	}
+	cd := func(spec map[string]any) *composed.Unstructured {
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "Composed",
+			"spec":       spec,
+		}}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ToCompositeMap": {
+			reason: "Mutating a map of the composed resource after patching it to the composite resource should not mutate the composite resource",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.tags"),
+				},
+				cp: xr(map[string]any{}),
+				cd: cd(map[string]any{"tags": map[string]any{"team": "a"}}),
+				mutate: func(_ *composite.Unstructured, cd *composed.Unstructured) {
+					cd.Object["spec"].(map[string]any)["tags"].(map[string]any)["team"] = "b"
+				},
+			},
+			want: want{
+				cp: xr(map[string]any{"tags": map[string]any{"team": "a"}}),
+				cd: cd(map[string]any{"tags": map[string]any{"team": "b"}}),
+			},
+		},
+		"FromCompositeSlice": {
+			reason: "Mutating a slice of the composite resource after patching it to the composed resource should not mutate the composed resource",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.zones"),
+				},
+				cp: xr(map[string]any{"zones": []any{map[string]any{"name": "a"}}}),
+				cd: cd(map[string]any{}),
+				mutate: func(cp *composite.Unstructured, _ *composed.Unstructured) {
+					cp.Object["spec"].(map[string]any)["zones"].([]any)[0].(map[string]any)["name"] = "b"
+				},
+			},
+			want: want{
+				cp: xr(map[string]any{"zones": []any{map[string]any{"name": "b"}}}),
+				cd: cd(map[string]any{"zones": []any{map[string]any{"name": "a"}}}),
+			},
+		},
+		"FromCompositeWildcard": {
+			reason: "Values patched to fields expanded from a wildcard should not share maps with each other",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.tags"),
+					ToFieldPath:   pointer.String("spec.rules[*].tags"),
+				},
+				cp: xr(map[string]any{"tags": map[string]any{"team": "a"}}),
+				cd: cd(map[string]any{"rules": []any{
+					map[string]any{"tags": map[string]any{}},
+					map[string]any{"tags": map[string]any{}},
+				}}),
+				mutate: func(_ *composite.Unstructured, cd *composed.Unstructured) {
+					cd.Object["spec"].(map[string]any)["rules"].([]any)[0].(map[string]any)["tags"].(map[string]any)["team"] = "b"
+				},
+			},
+			want: want{
+				cp: xr(map[string]any{"tags": map[string]any{"team": "a"}}),
+				cd: cd(map[string]any{"rules": []any{
+					map[string]any{"tags": map[string]any{"team": "b"}},
+					map[string]any{"tags": map[string]any{"team": "a"}},
+				}}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := Apply(tc.args.patch, tc.args.cp, tc.args.cd); err != nil {
+				t.Fatalf("\n%s\nApply(...): unexpected error: %v", tc.reason, err)
+			}
+			tc.args.mutate(tc.args.cp, tc.args.cd)
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp); diff != "" {
+				t.Errorf("\n%s\nApply(cp): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveToFieldPath(t *testing.T) {
+	src := fieldpath.Pave(map[string]any{
+		"spec": map[string]any{
+			"region":  "us-west-2",
+			"empty":   "",
+			"number":  int64(42),
+			"bracket": "a]b",
+		},
+	})
+	_, errMissing := src.GetValue("spec.missing")
+
+	type args struct {
+		path string
+		src  *fieldpath.Paved
+	}
+	type want struct {
+		path string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoTemplate": {
+			reason: "A ToFieldPath without templates should be returned unchanged",
+			args: args{
+				path: "metadata.annotations[example.org/name]",
+				src:  src,
+			},
+			want: want{
+				path: "metadata.annotations[example.org/name]",
+			},
+		},
+		"Template": {
+			reason: "A templated key segment should be replaced by the value at its field path",
+			args: args{
+				path: "metadata.annotations[example.org/name-{{spec.region}}]",
+				src:  src,
+			},
+			want: want{
+				path: "metadata.annotations[example.org/name-us-west-2]",
+			},
+		},
+		"NotFound": {
+			reason: "A templated key segment whose field path does not exist should return an error",
+			args: args{
+				path: "metadata.annotations[example.org/name-{{ spec.missing }}]",
+				src:  src,
+			},
+			want: want{
+				err: errors.Wrapf(errMissing, errFmtResolveToFieldPathKey, "{{ spec.missing }}"),
+			},
+		},
+		"NotString": {
+			reason: "A templated key segment that does not resolve to a string should return an error",
+			args: args{
+				path: "metadata.annotations[example.org/name-{{ spec.number }}]",
+				src:  src,
+			},
+			want: want{
+				err: errors.Errorf(errFmtToFieldPathKeyNotString, "{{ spec.number }}", int64(42)),
+			},
+		},
+		"Empty": {
+			reason: "A templated key segment that resolves to an empty string should return an error",
+			args: args{
+				path: "metadata.annotations[example.org/name-{{ spec.empty }}]",
+				src:  src,
+			},
+			want: want{
+				err: errors.Errorf(errFmtToFieldPathKeyEmpty, "{{ spec.empty }}"),
+			},
+		},
+		"Invalid": {
+			reason: "A templated key segment that resolves to a value that would change the path structure should return an error",
+			args: args{
+				path: "metadata.annotations[example.org/name-{{ spec.bracket }}]",
+				src:  src,
+			},
+			want: want{
+				err: errors.Errorf(errFmtToFieldPathKeyInvalid, "{{ spec.bracket }}", "a]b"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveToFieldPath(tc.args.path, tc.args.src)
+			if diff := cmp.Diff(tc.want.path, got); diff != "" {
+				t.Errorf("\n%s\nResolveToFieldPath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveToFieldPath(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveTransforms(t *testing.T) {
+	skip := v1.TransformOnErrorPolicySkip
+	toInt := v1.Transform{
+		Type:    v1.TransformTypeConvert,
+		Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeInt64},
+	}
+	toIntOrSkip := *toInt.DeepCopy()
+	toIntOrSkip.OnError = &skip
+	upper := v1.Transform{
+		Type: v1.TransformTypeString,
+		String: &v1.StringTransform{
+			Type:    v1.StringTransformTypeConvert,
+			Convert: &[]v1.StringConversionType{v1.StringConversionTypeToUpper}[0],
+		},
+	}
+	parse := v1.Transform{Type: v1.TransformTypeJSONParse}
+	stringify := v1.Transform{
+		Type: v1.TransformTypeString,
+		String: &v1.StringTransform{
+			Type:    v1.StringTransformTypeConvert,
+			Convert: &[]v1.StringConversionType{v1.StringConversionTypeToJSON}[0],
+		},
+	}
+	numericToInt := *toInt.DeepCopy()
+	numericToInt.When = &v1.TransformCondition{Operator: v1.TransformConditionOperatorRegexp, Regexp: pointer.String("^[0-9]+$")}
+	_, errParse := ResolveConvert(*toInt.Convert, "nope")
+
+	type args struct {
+		patch v1.Patch
+		input any
+	}
+	type want struct {
+		output any
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Fail": {
+			reason: "A transform that returns an error should fail the chain by default",
+			args: args{
+				patch: v1.Patch{Transforms: []v1.Transform{toInt, upper}},
+				input: "nope",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errParse, errFmtTransformTypeFailed, string(v1.TransformTypeConvert)), errFmtTransformAtIndex, 0),
+			},
+		},
+		"TransformSetNotInlined": {
+			reason: "A reference to a transform set that wasn't inlined should fail the chain",
+			args: args{
+				patch: v1.Patch{Transforms: []v1.Transform{{Type: v1.TransformTypeTransformSet, TransformSetName: pointer.String("ts")}}},
+				input: "nope",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtTransformSetNotInlined, "ts"), errFmtTransformAtIndex, 0),
+			},
+		},
+		"Skip": {
+			reason: "A transform that returns an error should pass its input downstream if its OnError policy is skip",
+			args: args{
+				patch: v1.Patch{Transforms: []v1.Transform{toIntOrSkip, upper}},
+				input: "nope",
+			},
+			want: want{
+				output: "NOPE",
+			},
+		},
+		"NoError": {
+			reason: "A skippable transform that succeeds should pass its output downstream",
+			args: args{
+				patch: v1.Patch{Transforms: []v1.Transform{toIntOrSkip}},
+				input: "42",
+			},
+			want: want{
+				output: int64(42),
+			},
+		},
+		"ConditionMet": {
+			reason: "A transform whose condition is met by its input should run",
+			args: args{
+				patch: v1.Patch{Transforms: []v1.Transform{numericToInt}},
+				input: "42",
+			},
+			want: want{
+				output: int64(42),
+			},
+		},
+		"ConditionNotMet": {
+			reason: "A transform whose condition isn't met by its input should pass its input downstream without running",
+			args: args{
+				patch: v1.Patch{Transforms: []v1.Transform{numericToInt, upper}},
+				input: "nope",
+			},
+			want: want{
+				output: "NOPE",
+			},
+		},
+		"ConditionError": {
+			reason: "A transform whose condition can't be evaluated should fail the chain",
+			args: args{
+				patch: v1.Patch{Transforms: []v1.Transform{upper, {Type: v1.TransformTypeLength, When: &v1.TransformCondition{Operator: v1.TransformConditionOperatorEqual}}}},
+				input: "nope",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtTransformConditionMissing, "value", v1.TransformConditionOperatorEqual), errFmtTransformConditionAtIndex, 1),
+			},
+		},
+		"JSONRoundTrip": {
+			reason: "A JSON string parsed by a jsonParse transform should be serialized again by a ToJson string conversion",
+			args: args{
+				patch: v1.Patch{Transforms: []v1.Transform{parse, stringify}},
+				input: `{"endpoint":"db.example.org","port":5432}`,
+			},
+			want: want{
+				output: `{"endpoint":"db.example.org","port":5432}`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveTransforms(tc.args.patch, tc.args.input)
+			if diff := cmp.Diff(tc.want.output, got); diff != "" {
+				t.Errorf("\n%s\nResolveTransforms(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveTransforms(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEvaluateTransformCondition(t *testing.T) {
+	asJSON := func(val any) *extv1.JSON {
+		raw, err := json.Marshal(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &extv1.JSON{Raw: raw}
+	}
+
+	type args struct {
+		c v1.TransformCondition
+		i any
+	}
+	type want struct {
+		met bool
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Equal": {
+			reason: "An input equal to the value should meet an Equal condition.",
+			args: args{
+				c: v1.TransformCondition{Operator: v1.TransformConditionOperatorEqual, Value: asJSON(42)},
+				i: int64(42),
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"NotEqual": {
+			reason: "An input equal to the value should not meet a NotEqual condition.",
+			args: args{
+				c: v1.TransformCondition{Operator: v1.TransformConditionOperatorNotEqual, Value: asJSON("prod")},
+				i: "prod",
+			},
+			want: want{
+				met: false,
+			},
+		},
+		"ContainsSubstring": {
+			reason: "A string input that contains a string value should meet a Contains condition.",
+			args: args{
+				c: v1.TransformCondition{Operator: v1.TransformConditionOperatorContains, Value: asJSON("west")},
+				i: "us-west-2",
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"ContainsElement": {
+			reason: "An array input with an element equal to the value should meet a Contains condition.",
+			args: args{
+				c: v1.TransformCondition{Operator: v1.TransformConditionOperatorContains, Value: asJSON(map[string]any{"a": "b"})},
+				i: []any{"a", map[string]any{"a": "b"}},
+			},
 			want: want{
-				cp: &fake.Composite{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cp",
-						Labels: map[string]string{
-							"Test":        "blah",
-							"destination": "foo-bar",
-						},
-					},
-					ConnectionDetailsLastPublishedTimer: lpt,
-				},
-				cd: &fake.Composed{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "cd",
-						Labels: map[string]string{
-							"source1": "foo",
-							"source2": "bar",
-						}},
-				},
-				err: nil,
+				met: true,
+			},
+		},
+		"ContainsOtherType": {
+			reason: "A number input should not meet a Contains condition.",
+			args: args{
+				c: v1.TransformCondition{Operator: v1.TransformConditionOperatorContains, Value: asJSON("4")},
+				i: int64(42),
+			},
+			want: want{
+				met: false,
+			},
+		},
+		"Regexp": {
+			reason: "A string input that matches the regexp should meet a Regexp condition.",
+			args: args{
+				c: v1.TransformCondition{Operator: v1.TransformConditionOperatorRegexp, Regexp: pointer.String("^[0-9]+$")},
+				i: "42",
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"RegexpNonString": {
+			reason: "A non-string input should not meet a Regexp condition.",
+			args: args{
+				c: v1.TransformCondition{Operator: v1.TransformConditionOperatorRegexp, Regexp: pointer.String(".*")},
+				i: int64(42),
+			},
+			want: want{
+				met: false,
+			},
+		},
+		"MissingValue": {
+			reason: "An Equal condition without a value should return an error.",
+			args: args{
+				c: v1.TransformCondition{Operator: v1.TransformConditionOperatorEqual},
+				i: "42",
+			},
+			want: want{
+				err: errors.Errorf(errFmtTransformConditionMissing, "value", v1.TransformConditionOperatorEqual),
+			},
+		},
+		"UnknownOperator": {
+			reason: "An unknown operator should return an error.",
+			args: args{
+				c: v1.TransformCondition{Operator: "GreaterThan", Value: asJSON(1)},
+				i: int64(42),
+			},
+			want: want{
+				err: errors.Errorf(errFmtTransformConditionOperator, "GreaterThan"),
 			},
 		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			ncp := tc.args.cp.DeepCopyObject().(resource.Composite)
-			err := Apply(tc.args.patch, ncp, tc.args.cd, tc.args.only...)
-
-			if tc.want.cp != nil {
-				if diff := cmp.Diff(tc.want.cp, ncp); diff != "" {
-					t.Errorf("\n%s\nApply(cp): -want, +got:\n%s", tc.reason, diff)
-				}
-			}
-			if tc.want.cd != nil {
-				if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
-					t.Errorf("\n%s\nApply(cd): -want, +got:\n%s", tc.reason, diff)
-				}
+			got, err := EvaluateTransformCondition(tc.args.c, tc.args.i)
+			if diff := cmp.Diff(tc.want.met, got); diff != "" {
+				t.Errorf("\n%s\nEvaluateTransformCondition(...): -want, +got:\n%s", tc.reason, diff)
 			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nApply(err): -want, +got:\n%s", tc.reason, diff)
+				t.Errorf("\n%s\nEvaluateTransformCondition(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
 		})
 	}
@@ -1184,6 +5213,7 @@ func TestComposedTemplates(t *testing.T) {
 	type args struct {
 		pss []v1.PatchSet
 		cts []v1.ComposedTemplate
+		o   []InlineOption
 	}
 
 	type want struct {
@@ -1244,7 +5274,7 @@ func TestComposedTemplates(t *testing.T) {
 				}},
 			},
 			want: want{
-				err: errors.Errorf(errFmtUndefinedPatchSet, "patch-set-1"),
+				err: utilerrors.NewAggregate([]error{&v1.PatchSetReferenceError{ResourceIndex: 0, PatchIndex: 0, Err: v1.UndefinedPatchSetError("patch-set-1")}}),
 			},
 		},
 		"DefinedPatchSets": {
@@ -1367,11 +5397,185 @@ func TestComposedTemplates(t *testing.T) {
 				},
 			},
 		},
+		"ConditionalPatchSets": {
+			reason: "References to PatchSets whose conditions are not met should be dropped.",
+			args: args{
+				pss: []v1.PatchSet{
+					{
+						Name: "dev",
+						Patches: []v1.Patch{{
+							Type:          v1.PatchTypeFromCompositeFieldPath,
+							FromFieldPath: pointer.String("spec.parameters.dev"),
+						}},
+					},
+					{
+						Name: "prod",
+						Patches: []v1.Patch{{
+							Type:          v1.PatchTypeFromCompositeFieldPath,
+							FromFieldPath: pointer.String("spec.parameters.prod"),
+						}},
+					},
+				},
+				cts: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{
+						{
+							Type:         v1.PatchTypePatchSet,
+							PatchSetName: pointer.String("dev"),
+							When: &v1.PatchCondition{
+								Source:    v1.PatchConditionSourceEnvironment,
+								FieldPath: "stage",
+								Equals:    &extv1.JSON{Raw: []byte(`"dev"`)},
+							},
+						},
+						{
+							Type:         v1.PatchTypePatchSet,
+							PatchSetName: pointer.String("prod"),
+							When: &v1.PatchCondition{
+								Source:    v1.PatchConditionSourceEnvironment,
+								FieldPath: "stage",
+								Equals:    &extv1.JSON{Raw: []byte(`"prod"`)},
+							},
+						},
+					},
+				}},
+				o: []InlineOption{WithPatchConditionSources(
+					&fake.Composite{},
+					&env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{"stage": "prod"}}},
+				)},
+			},
+			want: want{
+				ct: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{
+						Type:          v1.PatchTypeFromCompositeFieldPath,
+						FromFieldPath: pointer.String("spec.parameters.prod"),
+					}},
+				}},
+			},
+		},
+		"ConditionalPatchSetsWithoutSources": {
+			reason: "References to PatchSets should be inlined regardless of their conditions if no condition sources are supplied.",
+			args: args{
+				pss: []v1.PatchSet{{
+					Name: "dev",
+					Patches: []v1.Patch{{
+						Type:          v1.PatchTypeFromCompositeFieldPath,
+						FromFieldPath: pointer.String("spec.parameters.dev"),
+					}},
+				}},
+				cts: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{
+						Type:         v1.PatchTypePatchSet,
+						PatchSetName: pointer.String("dev"),
+						When:         &v1.PatchCondition{FieldPath: "spec.stage"},
+					}},
+				}},
+			},
+			want: want{
+				ct: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{
+						Type:          v1.PatchTypeFromCompositeFieldPath,
+						FromFieldPath: pointer.String("spec.parameters.dev"),
+					}},
+				}},
+			},
+		},
+		"ConditionalPatchSetsInvalidCondition": {
+			reason: "We should return an error if a condition can't be evaluated.",
+			args: args{
+				pss: []v1.PatchSet{{Name: "dev"}},
+				cts: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{
+						Type:         v1.PatchTypePatchSet,
+						PatchSetName: pointer.String("dev"),
+						When:         &v1.PatchCondition{Source: "Nope", FieldPath: "spec.stage"},
+					}},
+				}},
+				o: []InlineOption{WithPatchConditionSources(&fake.Composite{}, nil)},
+			},
+			want: want{
+				err: fmt.Errorf("cannot evaluate condition of reference to PatchSet %s: %w", "dev", errors.Errorf(errFmtPatchConditionSource, "Nope")),
+			},
+		},
+		"TransformSet": {
+			reason: "References to a transform set should be replaced by its transforms, including in patches of patch sets",
+			args: args{
+				pss: []v1.PatchSet{{
+					Name: "ps",
+					Patches: []v1.Patch{{
+						FromFieldPath: pointer.String("spec.b"),
+						Transforms:    []v1.Transform{{Type: v1.TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+					}},
+				}},
+				cts: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{
+						{
+							FromFieldPath: pointer.String("spec.a"),
+							Transforms:    []v1.Transform{{Type: v1.TransformTypeTransformSet, TransformSetName: pointer.String("ts")}},
+						},
+						{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("ps")},
+					},
+				}},
+				o: []InlineOption{WithTransformSets([]v1.TransformSet{{Name: "ts", Transforms: []v1.Transform{{Type: v1.TransformTypeLength}}}})},
+			},
+			want: want{
+				ct: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{
+						{FromFieldPath: pointer.String("spec.a"), Transforms: []v1.Transform{{Type: v1.TransformTypeLength}}},
+						{FromFieldPath: pointer.String("spec.b"), Transforms: []v1.Transform{{Type: v1.TransformTypeLength}}},
+					},
+				}},
+			},
+		},
+		"PrioritizedPatches": {
+			reason: "Patches should be sorted by priority, with the patches of a PatchSet inheriting the priority of the patch that includes them unless they specify their own.",
+			args: args{
+				pss: []v1.PatchSet{{
+					Name: "ps",
+					Patches: []v1.Patch{
+						{FromFieldPath: pointer.String("spec.a")},
+						{FromFieldPath: pointer.String("spec.b"), Priority: pointer.Int(-1)},
+					},
+				}},
+				cts: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{
+						{FromFieldPath: pointer.String("spec.c"), Priority: pointer.Int(2)},
+						{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("ps"), Priority: pointer.Int(1)},
+						{FromFieldPath: pointer.String("spec.d")},
+					},
+				}},
+			},
+			want: want{
+				ct: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{
+						{FromFieldPath: pointer.String("spec.b"), Priority: pointer.Int(-1)},
+						{FromFieldPath: pointer.String("spec.d")},
+						{FromFieldPath: pointer.String("spec.a"), Priority: pointer.Int(1)},
+						{FromFieldPath: pointer.String("spec.c"), Priority: pointer.Int(2)},
+					},
+				}},
+			},
+		},
+		"UndefinedTransformSet": {
+			reason: "Should return an error when referring to an undefined TransformSet",
+			args: args{
+				cts: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{
+						FromFieldPath: pointer.String("spec.a"),
+						Transforms:    []v1.Transform{{Type: v1.TransformTypeTransformSet, TransformSetName: pointer.String("nope")}},
+					}},
+				}},
+			},
+			want: want{
+				err: utilerrors.NewAggregate([]error{
+					utilerrors.NewAggregate([]error{&v1.TransformSetReferenceError{ResourceIndex: 0, PatchIndex: 0, TransformIndex: 0, Err: v1.UndefinedTransformSetError("nope")}}),
+				}),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got, err := ComposedTemplates(tc.args.pss, tc.args.cts)
+			got, err := ComposedTemplates(tc.args.pss, tc.args.cts, tc.args.o...)
 
 			if diff := cmp.Diff(tc.want.ct, got); diff != "" {
 				t.Errorf("\n%s\nrs.ComposedTemplates(...): -want, +got:\n%s", tc.reason, diff)
@@ -1382,3 +5586,250 @@ func TestComposedTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluatePatchCondition(t *testing.T) {
+	xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"stage":    "prod",
+			"replicas": 3,
+			"tags":     map[string]any{"team": "a"},
+		},
+	}}}
+
+	type args struct {
+		c   v1.PatchCondition
+		cp  resource.Composite
+		env *env.Environment
+	}
+	type want struct {
+		met bool
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FieldExists": {
+			reason: "A condition without a value should be met if the field exists.",
+			args: args{
+				c:  v1.PatchCondition{FieldPath: "spec.stage"},
+				cp: xr,
+			},
+			want: want{met: true},
+		},
+		"FieldDoesNotExist": {
+			reason: "A condition on a field that doesn't exist should not be met.",
+			args: args{
+				c:  v1.PatchCondition{FieldPath: "spec.region", Equals: &extv1.JSON{Raw: []byte(`"us-west-2"`)}},
+				cp: xr,
+			},
+			want: want{met: false},
+		},
+		"StringEquals": {
+			reason: "A condition should be met if the field equals its value.",
+			args: args{
+				c:  v1.PatchCondition{FieldPath: "spec.stage", Equals: &extv1.JSON{Raw: []byte(`"prod"`)}},
+				cp: xr,
+			},
+			want: want{met: true},
+		},
+		"StringNotEquals": {
+			reason: "A condition should not be met if the field doesn't equal its value.",
+			args: args{
+				c:  v1.PatchCondition{FieldPath: "spec.stage", Equals: &extv1.JSON{Raw: []byte(`"dev"`)}},
+				cp: xr,
+			},
+			want: want{met: false},
+		},
+		"IntegerEquals": {
+			reason: "Numbers should be compared by value, regardless of their Go type.",
+			args: args{
+				c:  v1.PatchCondition{FieldPath: "spec.replicas", Equals: &extv1.JSON{Raw: []byte(`3`)}},
+				cp: xr,
+			},
+			want: want{met: true},
+		},
+		"ObjectEquals": {
+			reason: "Objects should be compared deeply.",
+			args: args{
+				c:  v1.PatchCondition{FieldPath: "spec.tags", Equals: &extv1.JSON{Raw: []byte(`{"team":"a"}`)}},
+				cp: xr,
+			},
+			want: want{met: true},
+		},
+		"Environment": {
+			reason: "A condition should be evaluated against the environment if that is its source.",
+			args: args{
+				c: v1.PatchCondition{
+					Source:    v1.PatchConditionSourceEnvironment,
+					FieldPath: "stage",
+					Equals:    &extv1.JSON{Raw: []byte(`"dev"`)},
+				},
+				cp:  xr,
+				env: &env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{"stage": "dev"}}},
+			},
+			want: want{met: true},
+		},
+		"NilEnvironment": {
+			reason: "A condition on a nil environment should not be met.",
+			args: args{
+				c:  v1.PatchCondition{Source: v1.PatchConditionSourceEnvironment, FieldPath: "stage"},
+				cp: xr,
+			},
+			want: want{met: false},
+		},
+		"InvalidValue": {
+			reason: "We should return an error if the condition's value isn't valid JSON.",
+			args: args{
+				c:  v1.PatchCondition{FieldPath: "spec.stage", Equals: &extv1.JSON{Raw: []byte(`{`)}},
+				cp: xr,
+			},
+			want: want{err: errors.Wrap(errors.New("unexpected end of JSON input"), errPatchConditionValue)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			met, err := EvaluatePatchCondition(tc.args.c, tc.args.cp, tc.args.env)
+			if diff := cmp.Diff(tc.want.met, met); diff != "" {
+				t.Errorf("\n%s\nEvaluatePatchCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nEvaluatePatchCondition(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPatchFieldPaths(t *testing.T) {
+	type args struct {
+		pss []v1.PatchSet
+		tss []v1.TransformSet
+		cts []v1.ComposedTemplate
+		env *v1.EnvironmentConfiguration
+	}
+	type want struct {
+		composite []string
+		composed  []string
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UndefinedPatchSet": {
+			reason: "Should return an error if a referenced PatchSet is not defined",
+			args: args{
+				cts: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("nope")}},
+				}},
+			},
+			want: want{
+				err: utilerrors.NewAggregate([]error{&v1.PatchSetReferenceError{ResourceIndex: 0, PatchIndex: 0, Err: v1.UndefinedPatchSetError("nope")}}),
+			},
+		},
+		"TransformSet": {
+			reason: "Should inline referenced TransformSets before returning the field paths of their patches",
+			args: args{
+				tss: []v1.TransformSet{{
+					Name:       "upper",
+					Transforms: []v1.Transform{{Type: v1.TransformTypeString, String: &v1.StringTransform{Type: v1.StringTransformTypeConvert, Convert: &[]v1.StringConversionType{v1.StringConversionTypeToUpper}[0]}}},
+				}},
+				cts: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{
+						FromFieldPath: pointer.String("spec.name"),
+						ToFieldPath:   pointer.String("spec.forProvider.name"),
+						Transforms:    []v1.Transform{{Type: v1.TransformTypeTransformSet, TransformSetName: pointer.String("upper")}},
+					}},
+				}},
+			},
+			want: want{
+				composite: []string{"spec.name"},
+				composed:  []string{"spec.forProvider.name"},
+			},
+		},
+		"FieldPaths": {
+			reason: "Should return the sorted, deduplicated composite field paths read and composed field paths written",
+			args: args{
+				pss: []v1.PatchSet{{
+					Name: "common",
+					Patches: []v1.Patch{
+						{FromFieldPath: pointer.String("spec.region"), ToFieldPath: pointer.String("spec.forProvider.region")},
+					},
+				}},
+				cts: []v1.ComposedTemplate{
+					{
+						Patches: []v1.Patch{
+							{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("common")},
+							{FromFieldPath: pointer.String("metadata.labels")},
+							{
+								Type: v1.PatchTypeCombineFromComposite,
+								Combine: &v1.Combine{Variables: []v1.CombineVariable{
+									{FromFieldPath: "spec.a"},
+									{FromFieldPath: "spec.b"},
+								}},
+								ToFieldPath: pointer.String("spec.forProvider.ab"),
+							},
+							{Type: v1.PatchTypeFromEnvironmentFieldPath, FromFieldPath: pointer.String("size")},
+							{Type: v1.PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String("status.atProvider.id"), ToFieldPath: pointer.String("status.id")},
+						},
+					},
+					{
+						Name: pointer.String("b"),
+						Patches: []v1.Patch{
+							{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("common")},
+							{
+								Type:                 v1.PatchTypeFromComposedFieldPath,
+								FromComposedResource: &v1.ComposedResourceSelector{Index: pointer.Int(0)},
+								FromFieldPath:        pointer.String("status.atProvider.arn"),
+								ToFieldPath:          pointer.String("spec.forProvider.arn"),
+							},
+							{
+								Type: v1.PatchTypeCombineToComposite,
+								Combine: &v1.Combine{Variables: []v1.CombineVariable{
+									{FromFieldPath: "status.atProvider.host"},
+									{FromFieldPath: "status.atProvider.port"},
+								}},
+								ToFieldPath: pointer.String("status.address"),
+							},
+							{Type: v1.PatchTypeToEnvironmentFieldPath, FromFieldPath: pointer.String("status.atProvider.zone"), ToFieldPath: pointer.String("zone")},
+						},
+					},
+				},
+				env: &v1.EnvironmentConfiguration{
+					Patches: []v1.EnvironmentPatch{
+						{FromFieldPath: pointer.String("spec.env"), ToFieldPath: pointer.String("env")},
+						{Type: v1.PatchTypeCombineFromComposite, Combine: &v1.Combine{Variables: []v1.CombineVariable{{FromFieldPath: "spec.a"}}}},
+						{Type: v1.PatchTypeToCompositeFieldPath, FromFieldPath: pointer.String("tier"), ToFieldPath: pointer.String("status.tier")},
+					},
+				},
+			},
+			want: want{
+				composite: []string{"metadata.labels", "spec.a", "spec.b", "spec.env", "spec.region", "status.address", "status.id", "status.tier"},
+				composed: []string{
+					"metadata.labels", "size", "spec.forProvider.ab", "spec.forProvider.arn", "spec.forProvider.region",
+					"status.atProvider.arn", "status.atProvider.host", "status.atProvider.id", "status.atProvider.port", "status.atProvider.zone",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			composite, composed, err := PatchFieldPaths(tc.args.pss, tc.args.tss, tc.args.cts, tc.args.env)
+			if diff := cmp.Diff(tc.want.composite, composite); diff != "" {
+				t.Errorf("\n%s\nPatchFieldPaths(...): -want composite, +got composite:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.composed, composed); diff != "" {
+				t.Errorf("\n%s\nPatchFieldPaths(...): -want composed, +got composed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPatchFieldPaths(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
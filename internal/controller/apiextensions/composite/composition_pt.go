@@ -19,11 +19,14 @@ package composite
 import (
 	"context"
 	"strconv"
+	"sync"
 
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -57,6 +60,7 @@ const (
 	errInline           = "cannot inline Composition patch sets"
 	errRenderCR         = "cannot render composite resource"
 	errSetControllerRef = "cannot set controller reference"
+	errRenderAbort      = "cannot render composed resources, and the Composition's patch error policy is abort"
 
 	errFmtResourceName = "composed resource %q"
 	errFmtPatch        = "cannot apply the patch at index %d"
@@ -116,6 +120,18 @@ func WithComposedConnectionDetailsExtractor(e ConnectionDetailsExtractor) PTComp
 	}
 }
 
+// WithComposedRenderConcurrency configures a PatchAndTransformComposer to
+// render up to the supplied number of composed resources concurrently.
+// Composed resources are rendered one at a time by default. A Composition with
+// patches to the environment is always rendered one resource at a time,
+// because a composed resource may read from the environment what an earlier
+// resource patched to it.
+func WithComposedRenderConcurrency(n int) PTComposerOption {
+	return func(c *PTComposer) {
+		c.renderConcurrency = n
+	}
+}
+
 type composedResource struct {
 	Renderer
 	managed.ConnectionDetailsFetcher
@@ -133,6 +149,8 @@ type PTComposer struct {
 	composite   Renderer
 	composition CompositionTemplateAssociator
 	composed    composedResource
+
+	renderConcurrency int
 }
 
 // NewPTComposer returns a Composer that composes resources using Patch and
@@ -158,6 +176,7 @@ func NewPTComposer(kube client.Client, o ...PTComposerOption) *PTComposer {
 			ConnectionDetailsFetcher:   NewSecretConnectionDetailsFetcher(kube),
 			ConnectionDetailsExtractor: ConnectionDetailsExtractorFn(ExtractConnectionDetails),
 		},
+		renderConcurrency: 1,
 	}
 
 	for _, fn := range o {
@@ -170,8 +189,19 @@ func NewPTComposer(kube client.Client, o ...PTComposerOption) *PTComposer {
 // Compose resources using the bases, patches, and transforms specified by the
 // supplied Composition.
 func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req CompositionRequest) (CompositionResult, error) { //nolint:gocyclo // Breaking this up doesn't seem worth yet more layers of abstraction.
-	// Inline PatchSets before composing resources.
-	ct, err := ComposedTemplates(req.Revision.Spec.PatchSets, req.Revision.Spec.Resources)
+	// If we have an environment, run all environment patches before composing
+	// resources.
+	if req.Environment != nil && req.Revision.Spec.Environment != nil {
+		for i, p := range req.Revision.Spec.Environment.Patches {
+			if err := ApplyEnvironmentPatch(p, xr, req.Environment); err != nil {
+				return CompositionResult{}, errors.Wrapf(err, errFmtPatchEnvironment, i)
+			}
+		}
+	}
+
+	// Inline PatchSets before composing resources. Conditional PatchSet
+	// references are evaluated against the patched environment.
+	ct, err := ComposedTemplates(req.Revision.Spec.PatchSets, req.Revision.Spec.Resources, WithPatchConditionSources(xr, req.Environment), WithTransformSets(req.Revision.Spec.TransformSets))
 	if err != nil {
 		return CompositionResult{}, errors.Wrap(err, errInline)
 	}
@@ -181,14 +211,14 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 		return CompositionResult{}, errors.Wrap(err, errAssociate)
 	}
 
-	// If we have an environment, run all environment patches before composing
-	// resources.
-	if req.Environment != nil && req.Revision.Spec.Environment != nil {
-		for i, p := range req.Revision.Spec.Environment.Patches {
-			if err := ApplyEnvironmentPatch(p, xr, req.Environment); err != nil {
-				return CompositionResult{}, errors.Wrapf(err, errFmtPatchEnvironment, i)
-			}
-		}
+	// Composed resources are applied after the resources they patch from.
+	ts := make([]v1.ComposedTemplate, len(tas))
+	for i := range tas {
+		ts[i] = tas[i].Template
+	}
+	order, err := v1.ApplyOrder(ts)
+	if err != nil {
+		return CompositionResult{}, err
 	}
 
 	events := make([]event.Event, 0)
@@ -197,28 +227,102 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 	// the expectation that any that we fail to render will subsequently have
 	// their error corrected by manual intervention or propagation of a required
 	// input. Errors are recorded, but not considered fatal to the composition
-	// process.
+	// process unless the Composition's patch error policy is abort.
+	abort := req.Revision.Spec.GetPatchErrorPolicy() == v1.PatchErrorPolicyAbort
 	refs := make([]corev1.ObjectReference, len(tas))
 	cds := make([]ComposedResourceState, len(tas))
+	exists := make([]bool, len(tas))
+	rerrs := make([]error, 0)
+
+	// FromConnectionSecretKey patches read from the connection secret of the
+	// XR, which we fetch the first time a patch reads from it. Composed
+	// resources may be rendered concurrently, so the fetch is guarded.
+	var xc managed.ConnectionDetails
+	var mu sync.Mutex
+	fetched := false
+	secret := ConnectionSecretResolverFn(func(key string) ([]byte, bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !fetched {
+			conn, err := c.composed.FetchConnection(ctx, xr)
+			if err != nil {
+				return nil, false, err
+			}
+			xc, fetched = conn, true
+		}
+		v, ok := xc[key]
+		return v, ok, nil
+	})
+
+	concurrency := c.renderConcurrency
+	if concurrency < 1 || (req.Environment != nil && patchesToEnvironment(ct)) {
+		concurrency = 1
+	}
+
+	// Composed resources only read from the XR and environment while they're
+	// rendered, so each may be rendered concurrently. Each renders into its own
+	// state, and errors are reported in template order below, so the results
+	// don't depend on the order in which rendering completes.
+	oerrs := make([]error, len(tas))
+	g := &errgroup.Group{}
+	g.SetLimit(concurrency)
 	for i := range tas {
-		ta := tas[i]
+		i, ta := i, tas[i] // Pin the range variables before using them in a Goroutine.
+		g.Go(func() error {
+			// If this resource is anonymous its "name" is just its index.
+			name := pointer.StringDeref(ta.Template.Name, strconv.Itoa(i))
+			r := composed.New(composed.FromReference(ta.Reference))
+			if err := c.observe(ctx, r, ta.Template); err != nil {
+				oerrs[i] = errors.Wrapf(err, errFmtResourceName, name)
+				return nil
+			}
+			exists[i] = composedResourceExists(r)
+
+			rerr := c.composed.Render(ctx, xr, r, ta.Template, req.Environment)
+			if rerr == nil {
+				rerr = RenderFromConnectionSecret(xr, r, ta.Template, secret, WithComposedResourceExists(exists[i]))
+			}
 
-		// If this resource is anonymous its "name" is just its index.
-		name := pointer.StringDeref(ta.Template.Name, strconv.Itoa(i))
-		r := composed.New(composed.FromReference(ta.Reference))
+			cds[i] = ComposedResourceState{
+				ComposedResource:  ComposedResource{ResourceName: name},
+				TemplateRenderErr: rerr,
+				Template:          &ta.Template,
+				Resource:          r,
+			}
+			refs[i] = *meta.ReferenceTo(r, r.GetObjectKind().GroupVersionKind())
+			return nil
+		})
+	}
+	_ = g.Wait() // Errors are recorded in oerrs and each resource's state.
 
-		rerr := c.composed.Render(ctx, xr, r, ta.Template, req.Environment)
-		if rerr != nil {
-			events = append(events, event.Warning(reasonCompose, errors.Wrapf(rerr, errFmtResourceName, name)))
+	for i := range cds {
+		if oerrs[i] != nil {
+			return CompositionResult{}, oerrs[i]
+		}
+		if rerr := cds[i].TemplateRenderErr; rerr != nil {
+			rerrs = append(rerrs, errors.Wrapf(rerr, errFmtResourceName, cds[i].ResourceName))
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(rerr, errFmtResourceName, cds[i].ResourceName)))
 		}
+	}
 
-		cds[i] = ComposedResourceState{
-			ComposedResource:  ComposedResource{ResourceName: name},
-			TemplateRenderErr: rerr,
-			Template:          &ta.Template,
-			Resource:          r,
+	// Whether a resource can select the composed resources it requires doesn't
+	// depend on what we apply below, so we check before applying anything.
+	// Resources are checked in apply order, so a resource that requires one
+	// that can't be rendered can't be rendered either.
+	for _, i := range order {
+		if cds[i].TemplateRenderErr != nil {
+			continue
+		}
+		if err := CheckComposedSources(xr, *cds[i].Template, cds, WithComposedResourceExists(exists[i])); err != nil {
+			rerrs = append(rerrs, errors.Wrapf(err, errFmtResourceName, cds[i].ResourceName))
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtResourceName, cds[i].ResourceName)))
+			cds[i].TemplateRenderErr = err
 		}
-		refs[i] = *meta.ReferenceTo(r, r.GetObjectKind().GroupVersionKind())
+	}
+
+	// Don't apply any composed resources if we couldn't render them all.
+	if abort && len(rerrs) > 0 {
+		return CompositionResult{}, errors.Wrap(utilerrors.NewAggregate(rerrs), errRenderAbort)
 	}
 
 	// We persist references to our composed resources before we create
@@ -230,18 +334,52 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 		return CompositionResult{}, errors.Wrap(err, errUpdate)
 	}
 
+	// Connection details are fetched when composed resources are observed
+	// below, after they're applied. FromComposedConnectionSecretKey patches
+	// instead fetch the connection details of the resource they read from.
+	secrets := ComposedConnectionSecretResolverFn(func(name, key string) ([]byte, bool, error) {
+		for j := range cds {
+			if cds[j].ResourceName != name || cds[j].TemplateRenderErr != nil {
+				continue
+			}
+			conn, err := c.composed.FetchConnection(ctx, cds[j].Resource)
+			if err != nil {
+				return nil, false, err
+			}
+			v, ok := conn[key]
+			return v, ok, nil
+		}
+		return nil, false, nil
+	})
+
 	// We apply all of our composed resources before we observe them and update
 	// in the loop below. This ensures that issues observing and processing one
 	// composed resource won't block the application of another.
-	for _, cd := range cds {
+	for _, i := range order {
 		// If we were unable to render the composed resource we should not try
 		// and apply it.
-		if cd.TemplateRenderErr != nil {
+		if cds[i].TemplateRenderErr != nil {
 			continue
 		}
+
+		// Composed resources are applied in apply order, so a resource may
+		// patch from the observed state of any resource it reads from.
+		if err := RenderFromComposed(xr, cds[i].Resource, *cds[i].Template, cds, WithComposedResourceExists(exists[i]), WithComposedConnectionSecretResolver(secrets)); err != nil {
+			// Errors that don't depend on the state of the resources we
+			// applied above were returned before we applied anything. This
+			// one may, so resources before this one have already been
+			// applied, but we can at least avoid applying any after it.
+			if abort {
+				return CompositionResult{}, errors.Wrap(errors.Wrapf(err, errFmtResourceName, cds[i].ResourceName), errRenderAbort)
+			}
+			cds[i].TemplateRenderErr = err
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtResourceName, cds[i].ResourceName)))
+			continue
+		}
+
 		o := []resource.ApplyOption{resource.MustBeControllableBy(xr.GetUID())}
-		o = append(o, mergeOptions(filterPatches(cd.Template.Patches, patchTypesFromXR()...))...)
-		if err := c.client.Apply(ctx, cd.Resource, o...); err != nil {
+		o = append(o, mergeOptions(filterPatches(cds[i].Template.Patches, patchTypesFromXR()...))...)
+		if err := c.client.Apply(ctx, cds[i].Resource, o...); err != nil {
 			return CompositionResult{}, errors.Wrap(err, errApply)
 		}
 	}
@@ -484,13 +622,30 @@ func (c RenderFn) Render(cp resource.Composite, cd resource.Composed, t v1.Compo
 // resource.
 type APIDryRunRenderer struct {
 	client client.Client
+	config ControllerConfig
+}
+
+// An APIDryRunRendererOption configures an APIDryRunRenderer.
+type APIDryRunRendererOption func(r *APIDryRunRenderer)
+
+// WithRendererControllerConfig configures the controller configuration that
+// FromControllerConfig patches read from. Without it FromControllerConfig
+// patches behave as though the controller configuration is empty.
+func WithRendererControllerConfig(c ControllerConfig) APIDryRunRendererOption {
+	return func(r *APIDryRunRenderer) {
+		r.config = c
+	}
 }
 
 // NewAPIDryRunRenderer returns a Renderer of composed resources that may
 // perform a dry-run create against an API server in order to name and validate
 // it.
-func NewAPIDryRunRenderer(c client.Client) *APIDryRunRenderer {
-	return &APIDryRunRenderer{client: c}
+func NewAPIDryRunRenderer(c client.Client, o ...APIDryRunRendererOption) *APIDryRunRenderer {
+	r := &APIDryRunRenderer{client: c}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
 }
 
 // Render the supplied composed resource using the supplied composite resource
@@ -500,6 +655,7 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 	kind := cd.GetObjectKind().GroupVersionKind().Kind
 	name := cd.GetName()
 	namespace := cd.GetNamespace()
+	exists := composedResourceExists(cd)
 
 	if err := json.Unmarshal(t.Base.Raw, cd); err != nil {
 		return errors.Wrap(err, errUnmarshal)
@@ -524,12 +680,16 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 	cd.SetName(name)
 	cd.SetNamespace(namespace)
 
+	deleting := meta.WasDeleted(cp)
 	for i := range t.Patches {
-		if err := Apply(t.Patches[i], cp, cd, patchTypesFromXR()...); err != nil {
+		if err := Apply(t.Patches[i], cp, cd, OnlyPatchTypes(patchTypesFromXR()...), WithComposedResourceExists(exists), WithCompositeDeleting(deleting)); err != nil {
+			return errors.Wrapf(err, errFmtPatch, i)
+		}
+		if err := Apply(t.Patches[i], cp, cd, OnlyPatchTypes(v1.PatchTypeFromControllerConfig), WithControllerConfig(r.config), WithComposedResourceExists(exists), WithCompositeDeleting(deleting)); err != nil {
 			return errors.Wrapf(err, errFmtPatch, i)
 		}
 		if env != nil {
-			if err := ApplyToObjects(t.Patches[i], env, cd, patchTypesFromToEnvironment()...); err != nil {
+			if err := ApplyToObjects(t.Patches[i], env, cd, OnlyPatchTypes(patchTypesFromToEnvironment()...), WithComposedResourceExists(exists), WithCompositeDeleting(deleting)); err != nil {
 				return errors.Wrapf(err, errFmtPatch, i)
 			}
 		}
@@ -570,14 +730,76 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 	return errors.Wrap(r.client.Create(ctx, cd, client.DryRunAll), errName)
 }
 
-// RenderComposite renders the supplied composite resource using the supplied composed
-// resource and template.
-func RenderComposite(_ context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, _ *env.Environment) error {
+// observe gets the observed state of the supplied composed resource if the
+// supplied template has patches that depend on whether it exists. References
+// to composed resources are persisted before the resources are created, so a
+// named composed resource may not exist yet.
+func (c *PTComposer) observe(ctx context.Context, cd resource.Composed, t v1.ComposedTemplate) error {
+	if cd.GetName() == "" || !hasImmutableAfterCreatePatches(t) {
+		return nil
+	}
+	nn := types.NamespacedName{Namespace: cd.GetNamespace(), Name: cd.GetName()}
+	return errors.Wrap(resource.IgnoreNotFound(c.client.Get(ctx, nn, cd)), errGetComposed)
+}
+
+// hasImmutableAfterCreatePatches returns true if any of the supplied template's
+// patches has an ImmutableAfterCreate policy.
+func hasImmutableAfterCreatePatches(t v1.ComposedTemplate) bool {
+	for _, p := range t.Patches {
+		if p.Policy.IsImmutableAfterCreate() {
+			return true
+		}
+	}
+	return false
+}
+
+// composedResourceExists returns true if the supplied composed resource has
+// been observed. A composed resource that has only been named, for example
+// from a reference, may not have been created yet.
+func composedResourceExists(cd resource.Composed) bool {
+	return cd.GetUID() != "" || cd.GetResourceVersion() != ""
+}
+
+// RenderFromComposed renders the supplied composed resource by applying any of
+// the supplied template's patches that read from the other supplied composed
+// resources. Any supplied options are passed to each patch.
+func RenderFromComposed(cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, cds []ComposedResourceState, o ...ApplyOption) error {
+	return ApplyResource(cp, cd, t.Patches, append([]ApplyOption{OnlyPatchTypes(v1.PatchTypeFromComposedFieldPath, v1.PatchTypeFromComposedConnectionSecretKey), WithComposedResources(cds), WithCompositeDeleting(meta.WasDeleted(cp))}, o...)...)
+}
+
+// CheckComposedSources returns an error if any of the supplied template's
+// patches requires a composed resource that can't be selected from the supplied
+// composed resources, for example because it couldn't be rendered. Patches that
+// would be skipped given the supplied options are not checked.
+func CheckComposedSources(cp resource.Composite, t v1.ComposedTemplate, cds []ComposedResourceState, o ...ApplyOption) error {
+	ao := newApplyOptions(append([]ApplyOption{OnlyPatchTypes(v1.PatchTypeFromComposedFieldPath, v1.PatchTypeFromComposedConnectionSecretKey), WithCompositeDeleting(meta.WasDeleted(cp))}, o...)...)
 	for i, p := range t.Patches {
-		if err := Apply(p, cp, cd, patchTypesToXR()...); err != nil {
+		if ao.skip(p) || p.FromComposedResource == nil || p.Policy.GetFromFieldPathPolicy() != v1.FromFieldPathPolicyRequired {
+			continue
+		}
+		s := *p.FromComposedResource
+		if p.Type == v1.PatchTypeFromComposedConnectionSecretKey {
+			// Connection secret keys are only read from resources selected
+			// by name.
+			s = v1.ComposedResourceSelector{Name: s.Name}
+		}
+		if _, err := selectComposedResource(s, cds); err != nil {
 			return errors.Wrapf(err, errFmtPatch, i)
 		}
 	}
-
 	return nil
 }
+
+// RenderFromConnectionSecret renders the supplied composed resource by applying
+// any of the supplied template's patches that read from a connection secret,
+// resolving keys using the supplied resolver. Any supplied options are passed
+// to each patch.
+func RenderFromConnectionSecret(cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, r ConnectionSecretResolver, o ...ApplyOption) error {
+	return ApplyResource(cp, cd, t.Patches, append([]ApplyOption{OnlyPatchTypes(v1.PatchTypeFromConnectionSecretKey), WithConnectionSecretResolver(r), WithCompositeDeleting(meta.WasDeleted(cp))}, o...)...)
+}
+
+// RenderComposite renders the supplied composite resource using the supplied composed
+// resource and template.
+func RenderComposite(_ context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, _ *env.Environment) error {
+	return ApplyResource(cp, cd, t.Patches, OnlyPatchTypes(patchTypesToXR()...), WithCompositeDeleting(meta.WasDeleted(cp)))
+}
@@ -18,6 +18,7 @@ package composite
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -25,18 +26,22 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -62,6 +67,55 @@ func TestPTCompose(t *testing.T) {
 		err error
 	}
 
+	// A template whose only patch should be applied until the composed
+	// resource it renders is created.
+	immutable := func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+		tas := []TemplateAssociation{{
+			Template: v1.ComposedTemplate{
+				Name: pointer.String("cool-resource"),
+				Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"CoolComposed"}`)},
+				Patches: []v1.Patch{{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("metadata.name"),
+					ToFieldPath:   pointer.String("spec.initialName"),
+					Policy:        &v1.PatchPolicy{ImmutableAfterCreate: pointer.Bool(true)},
+				}},
+			},
+			Reference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "CoolComposed", Name: "cool-xr-42"},
+		}}
+		return tas, nil
+	}
+	// The composer's client passes composite and composed resources to the
+	// underlying client as *unstructured.Unstructured.
+	isComposed := func(obj client.Object) bool {
+		return obj.GetObjectKind().GroupVersionKind().Kind == "CoolComposed"
+	}
+	initialName := func(obj client.Object) string {
+		s, _ := fieldpath.Pave(obj.(*kunstructured.Unstructured).Object).GetString("spec.initialName")
+		return s
+	}
+	xr := func() *composite.Unstructured {
+		cp := composite.New()
+		cp.SetName("cool-xr")
+		cp.SetLabels(map[string]string{xcrd.LabelKeyNamePrefixForComposed: "cool-xr"})
+		return cp
+	}
+	immutableOpts := []PTComposerOption{
+		WithTemplateAssociator(CompositionTemplateAssociatorFn(immutable)),
+		WithCompositeRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+			return nil
+		})),
+		WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+			return nil, nil
+		})),
+		WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(cd resource.Composed, conn managed.ConnectionDetails, cfg ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+			return nil, nil
+		})),
+		WithComposedReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, o ConditionedObject, rc ...ReadinessCheck) (ready bool, err error) {
+			return true, nil
+		})),
+	}
+
 	cases := map[string]struct {
 		reason string
 		params params
@@ -87,7 +141,7 @@ func TestPTCompose(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrap(errors.Errorf(errFmtUndefinedPatchSet, "nonexistent-patchset"), errInline),
+				err: errors.Wrap(utilerrors.NewAggregate([]error{&v1.PatchSetReferenceError{ResourceIndex: 0, PatchIndex: 0, Err: v1.UndefinedPatchSetError("nonexistent-patchset")}}), errInline),
 			},
 		},
 		"AssociateTemplatesError": {
@@ -157,6 +211,155 @@ func TestPTCompose(t *testing.T) {
 				},
 			},
 		},
+		"RenderComposedErrorAbort": {
+			reason: "We should return any error encountered while rendering a composed resource, without updating or applying anything, if the patch error policy is abort.",
+			params: params{
+				kube: &test.MockClient{
+					// Neither should be called.
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+					MockGet:    test.NewMockGetFn(errBoom),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{
+							{Template: v1.ComposedTemplate{Name: pointer.String("cool-resource")}},
+							{Template: v1.ComposedTemplate{Name: pointer.String("uncool-resource")}},
+						}
+						return tas, nil
+					})),
+					WithComposedRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						if *t.Name == "cool-resource" {
+							return nil
+						}
+						return errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{},
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							PatchErrorPolicy: func() *v1.PatchErrorPolicy {
+								p := v1.PatchErrorPolicyAbort
+								return &p
+							}(),
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(utilerrors.NewAggregate([]error{errors.Wrapf(errBoom, errFmtResourceName, "uncool-resource")}), errRenderAbort),
+			},
+		},
+		"RequiredComposedResourceNotFoundAbort": {
+			reason: "We should return any error encountered selecting a composed resource that a composed resource requires, without updating or applying anything, if the patch error policy is abort.",
+			params: params{
+				kube: &test.MockClient{
+					// Neither should be called.
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+					MockGet:    test.NewMockGetFn(errBoom),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{
+							{Template: v1.ComposedTemplate{
+								Name: pointer.String("cool-consumer"),
+								Patches: []v1.Patch{{
+									Type:                 v1.PatchTypeFromComposedFieldPath,
+									FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("uncool-producer")},
+									FromFieldPath:        pointer.String("status.id"),
+									ToFieldPath:          pointer.String("spec.producerID"),
+									Policy:               &v1.PatchPolicy{FromFieldPath: func() *v1.FromFieldPathPolicy { p := v1.FromFieldPathPolicyRequired; return &p }()},
+								}},
+							}},
+							{Template: v1.ComposedTemplate{Name: pointer.String("cool-producer")}},
+						}
+						return tas, nil
+					})),
+					WithComposedRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						return nil
+					})),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{},
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							PatchErrorPolicy: func() *v1.PatchErrorPolicy {
+								p := v1.PatchErrorPolicyAbort
+								return &p
+							}(),
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(utilerrors.NewAggregate([]error{errors.Wrapf(errors.Wrapf(errors.Errorf(errFmtComposedResourceNotFound, "uncool-producer"), errFmtPatch, 0), errFmtResourceName, "cool-consumer")}), errRenderAbort),
+			},
+		},
+		"RenderFromComposedErrorAbort": {
+			reason: "We should apply the composed resources a composed resource reads from, but not that resource or any after it, if it can't be rendered from them and the patch error policy is abort.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(nil),
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						if obj.GetName() != "cool-producer-42" {
+							t.Errorf("Patch(...): want only cool-producer-42 to be applied, got %q", obj.GetName())
+						}
+						return nil
+					},
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{
+							{
+								Template: v1.ComposedTemplate{
+									Name: pointer.String("cool-consumer"),
+									Patches: []v1.Patch{{
+										Type:                 v1.PatchTypeFromComposedFieldPath,
+										FromComposedResource: &v1.ComposedResourceSelector{Name: pointer.String("cool-producer")},
+										FromFieldPath:        pointer.String("status.id"),
+										ToFieldPath:          pointer.String("spec.producerID"),
+										Policy:               &v1.PatchPolicy{FromFieldPath: func() *v1.FromFieldPathPolicy { p := v1.FromFieldPathPolicyRequired; return &p }()},
+									}},
+								},
+								Reference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "CoolComposed", Name: "cool-consumer-42"},
+							},
+							{
+								Template:  v1.ComposedTemplate{Name: pointer.String("cool-producer")},
+								Reference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "CoolComposed", Name: "cool-producer-42"},
+							},
+						}
+						return tas, nil
+					})),
+					WithComposedRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						return nil
+					})),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{},
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							PatchErrorPolicy: func() *v1.PatchErrorPolicy {
+								p := v1.PatchErrorPolicyAbort
+								return &p
+							}(),
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errors.Wrapf(func() error {
+					_, err := fieldpath.Pave(map[string]any{}).GetValue("status.id")
+					return err
+				}(), errFmtPatch, 0), errFmtResourceName, "cool-consumer"), errRenderAbort),
+			},
+		},
 		"UpdateCompositeError": {
 			reason: "We should return any error encountered while updating our composite resource with references.",
 			params: params{
@@ -417,6 +620,214 @@ func TestPTCompose(t *testing.T) {
 				err: errors.Wrap(errors.Wrap(errBoom, "cannot get object"), errUpdate),
 			},
 		},
+		"ObserveComposedError": {
+			reason: "We should return any error encountered while getting a referenced composed resource whose template has immutableAfterCreate patches.",
+			params: params{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				o: immutableOpts,
+			},
+			args: args{
+				xr: xr(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errBoom, errGetComposed), errFmtResourceName, "cool-resource"),
+			},
+		},
+		"ImmutableAfterCreateReferencedButNotCreated": {
+			reason: "We should apply immutableAfterCreate patches to a composed resource that we persisted a reference to but failed to create.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if isComposed(obj) {
+							return kerrors.NewNotFound(schema.GroupResource{}, "cool-xr-42")
+						}
+						return nil
+					}),
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						if got := initialName(obj); isComposed(obj) && got != "cool-xr" {
+							t.Errorf("Create(...): want spec.initialName %q, got %q", "cool-xr", got)
+						}
+						return nil
+					},
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				o: immutableOpts,
+			},
+			args: args{
+				xr: xr(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "cool-resource", Ready: true}},
+				},
+			},
+		},
+		"ImmutableAfterCreateCreated": {
+			reason: "We should not apply immutableAfterCreate patches to a composed resource that exists.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if isComposed(obj) {
+							obj.SetUID("cool-uid")
+						}
+						return nil
+					}),
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						if isComposed(obj) && initialName(obj) != "" {
+							t.Errorf("Patch(...): want no spec.initialName, got %q", initialName(obj))
+						}
+						return nil
+					},
+				},
+				o: immutableOpts,
+			},
+			args: args{
+				xr: xr(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "cool-resource", Ready: true}},
+				},
+			},
+		},
+		"FromConnectionSecretKey": {
+			reason: "We should render composed resources from the XR's connection secret.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if isComposed(obj) {
+							return kerrors.NewNotFound(schema.GroupResource{}, "cool-xr-42")
+						}
+						return nil
+					}),
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						if !isComposed(obj) {
+							return nil
+						}
+						if got, _ := fieldpath.Pave(obj.(*kunstructured.Unstructured).Object).GetString("spec.password"); got != "s3cr3t" {
+							t.Errorf("Create(...): want spec.password %q, got %q", "s3cr3t", got)
+						}
+						return nil
+					},
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name: pointer.String("cool-resource"),
+								Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"CoolComposed"}`)},
+								Patches: []v1.Patch{{
+									Type:                v1.PatchTypeFromConnectionSecretKey,
+									ConnectionSecretKey: pointer.String("password"),
+									ToFieldPath:         pointer.String("spec.password"),
+								}},
+							},
+							Reference: corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "CoolComposed", Name: "cool-xr-42"},
+						}}
+						return tas, nil
+					})),
+					WithCompositeRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						return nil
+					})),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return managed.ConnectionDetails{"password": []byte("s3cr3t")}, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(cd resource.Composed, conn managed.ConnectionDetails, cfg ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, o ConditionedObject, rc ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: xr(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "cool-resource", Ready: true}},
+				},
+			},
+		},
+		"RenderComposedErrorsConcurrently": {
+			reason: "We should report errors rendering composed resources concurrently in template order, regardless of the order in which rendering completes.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Apply uses Get and Patch.
+					MockGet:   test.NewMockGetFn(nil),
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := make([]TemplateAssociation, 0, 4)
+						for _, n := range []string{"a", "b", "c", "d"} {
+							tas = append(tas, TemplateAssociation{Template: v1.ComposedTemplate{Name: pointer.String(n)}})
+						}
+						return tas, nil
+					})),
+					WithComposedRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						if *t.Name == "a" || *t.Name == "c" {
+							return nil
+						}
+						return errBoom
+					})),
+					WithComposedRenderConcurrency(4),
+					WithCompositeRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						return nil
+					})),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(cd resource.Composed, conn managed.ConnectionDetails, cfg ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, o ConditionedObject, rc ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{},
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{ResourceName: "a", Ready: true},
+						{ResourceName: "b"},
+						{ResourceName: "c", Ready: true},
+						{ResourceName: "d"},
+					},
+					ConnectionDetails: managed.ConnectionDetails{},
+					Events: []event.Event{
+						event.Warning(reasonCompose, errors.Wrapf(errBoom, errFmtResourceName, "b")),
+						event.Warning(reasonCompose, errors.Wrapf(errBoom, errFmtResourceName, "d")),
+					},
+				},
+			},
+		},
 		"Success": {
 			reason: "We should return the resources we composed, and our derived connection details.",
 			params: params{
@@ -864,3 +1275,63 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkPTCompose(b *testing.B) {
+	xr := composite.New()
+	xr.SetName("cool-xr")
+	xr.SetLabels(map[string]string{xcrd.LabelKeyNamePrefixForComposed: "cool-xr"})
+	_ = fieldpath.Pave(xr.Object).SetValue("spec", map[string]any{
+		"region": "us-west-2",
+		"tags":   map[string]any{"team": "a", "env": "prod"},
+	})
+
+	rev := &v1.CompositionRevision{}
+	for i := 0; i < 200; i++ {
+		rev.Spec.Resources = append(rev.Spec.Resources, v1.ComposedTemplate{
+			Name: pointer.String(fmt.Sprintf("bucket-%d", i)),
+			Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)},
+			Patches: []v1.Patch{
+				{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPath:   pointer.String("spec.forProvider.region"),
+				},
+				{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.tags"),
+					ToFieldPath:   pointer.String("spec.forProvider.tags"),
+				},
+				{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{{FromFieldPath: "spec.region"}, {FromFieldPath: "spec.tags.team"}},
+						Strategy:  v1.CombineStrategyString,
+						String:    &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("spec.forProvider.bucketName"),
+				},
+			},
+		})
+	}
+
+	kube := &test.MockClient{
+		MockGet:    test.NewMockGetFn(nil),
+		MockCreate: test.NewMockCreateFn(nil),
+		MockUpdate: test.NewMockUpdateFn(nil),
+		MockPatch:  test.NewMockPatchFn(nil),
+	}
+	associate := CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+		return AssociateByOrder(ct, nil), nil
+	})
+
+	for _, n := range []int{1, 8} {
+		b.Run(fmt.Sprintf("Concurrency%d", n), func(b *testing.B) {
+			c := NewPTComposer(kube, WithTemplateAssociator(associate), WithComposedRenderConcurrency(n))
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Compose(context.Background(), xr, CompositionRequest{Revision: rev}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/json"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
@@ -44,6 +45,7 @@ import (
 	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
 	fnv1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1alpha1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -73,6 +75,7 @@ const (
 	errFmtApplyCD                  = "cannot apply composed resource %q"
 	errFmtFetchCDConnectionDetails = "cannot fetch connection details for composed resource %q (a %s named %s)"
 	errFmtRenderXR                 = "cannot render composite resource from composed resource %q (a %s named %s)"
+	errFmtCopyObserved             = "cannot copy the observed state of composed resource %q"
 	errFmtRunFn                    = "cannot run function %q"
 	errFmtUnsupportedFnType        = "unsupported function type %q"
 	errFmtParseDesiredCD           = "cannot parse desired composed resource %q from FunctionIO"
@@ -519,34 +522,55 @@ func NewXRCDPatchAndTransformer(composite, composed Renderer) *XRCDPatchAndTrans
 // PatchAndTransform updates the supplied composition state by running all
 // patches and transforms within the CompositionRequest.
 func (pt *XRCDPatchAndTransformer) PatchAndTransform(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
-	// Inline PatchSets before composing resources.
-	ct, err := ComposedTemplates(req.Revision.Spec.PatchSets, req.Revision.Spec.Resources)
+	// If we have an environment, run all environment patches before composing
+	// resources.
+	if err := applyEnvironmentPatches(req.Revision.Spec.Environment, s.Composite, req.Environment); err != nil {
+		return err
+	}
+
+	// Inline PatchSets before composing resources. Conditional PatchSet
+	// references are evaluated against the patched environment.
+	ct, err := ComposedTemplates(req.Revision.Spec.PatchSets, req.Revision.Spec.Resources, WithPatchConditionSources(s.Composite, req.Environment), WithTransformSets(req.Revision.Spec.TransformSets))
 	if err != nil {
 		return errors.Wrap(err, errInline)
 	}
 
-	// If we have an environment, run all environment patches before composing
-	// resources.
-	if req.Environment != nil && req.Revision.Spec.Environment != nil {
-		for i, p := range req.Revision.Spec.Environment.Patches {
-			if err := ApplyEnvironmentPatch(p, s.Composite, req.Environment); err != nil {
-				return errors.Wrapf(err, errFmtPatchEnvironment, i)
-			}
-		}
+	// Templates are rendered in the order in which their composed resources
+	// may be applied, such that each is rendered after the templates it reads
+	// from.
+	order, err := v1.ApplyOrder(ct)
+	if err != nil {
+		return err
 	}
 
+	// Patches from composed resources read from their observed state, which
+	// rendering overwrites, so we take a copy of it first.
+	observed, err := observedComposedResources(ct, s.ComposedResources)
+	if err != nil {
+		return err
+	}
+
+	// FromConnectionSecretKey patches read from the connection secret of the
+	// XR, which was fetched before composition began.
+	secret := ConnectionSecretData(s.ConnectionDetails)
+
 	// Render composite and composed resources using any P&T resource templates.
 	// Note that we require templates to be named; a CompositionValidator should
-	// enforce this.
-	for i := range ct {
+	// enforce this. Unlike the PTComposer we always render one resource at a
+	// time, because rendering the XR from a composed resource may change what
+	// the next composed resource renders from the XR.
+	rerrs := make([]error, 0)
+	for _, i := range order {
 		t := ct[i]
 
 		var r resource.Composed = composed.New()
+		created := false
 
 		// Templates must be named. This is a requirement to use Composition
 		// Functions and thus this Composer implementation.
 		if cd, exists := s.ComposedResources[*t.Name]; exists {
 			r = cd.Resource
+			created = true
 
 			// Typically we'll patch from composed resource status to the XR so
 			// we only want to render (i.e. patch) the XR from composed
@@ -558,13 +582,14 @@ func (pt *XRCDPatchAndTransformer) PatchAndTransform(ctx context.Context, req Co
 			}
 		}
 
-		rerr := pt.composed.Render(ctx, s.Composite, r, t, req.Environment)
+		rerr := pt.renderComposed(ctx, s.Composite, r, t, req.Environment, secret, observed, created)
 		if rerr != nil {
 			// Failures to patch from XR->composed aren't terminal. It could be
 			// that other resources need to patch the XR in order for the fields
 			// this render wants to patch from to exist. Rather than returning
 			// this error we just set Rendered = false in our state and return
 			// a Warning event describing what happened.
+			rerrs = append(rerrs, errors.Wrapf(rerr, errFmtResourceName, *t.Name))
 			s.Events = append(s.Events, event.Warning(reasonCompose, errors.Wrapf(rerr, errFmtResourceName, *t.Name)))
 		}
 
@@ -575,9 +600,71 @@ func (pt *XRCDPatchAndTransformer) PatchAndTransform(ctx context.Context, req Co
 			TemplateRenderErr: rerr,
 		})
 	}
+
+	// Nothing has been applied yet, so we can stop composition before any
+	// composed resource is applied.
+	return renderAbortError(req.Revision, rerrs)
+}
+
+// renderAbortError returns an error aggregating the supplied render errors if
+// the patch error policy of the supplied revision is to abort, or nil if it
+// isn't or there are no errors.
+func renderAbortError(rev *v1.CompositionRevision, rerrs []error) error {
+	if rev.Spec.GetPatchErrorPolicy() != v1.PatchErrorPolicyAbort || len(rerrs) == 0 {
+		return nil
+	}
+	return errors.Wrap(utilerrors.NewAggregate(rerrs), errRenderAbort)
+}
+
+// renderComposed renders the supplied composed resource from the composite
+// resource and environment, then from the composite resource's connection
+// secret, then from the observed state of other composed resources.
+func (pt *XRCDPatchAndTransformer) renderComposed(ctx context.Context, xr resource.Composite, cd resource.Composed, t v1.ComposedTemplate, e *env.Environment, secret ConnectionSecretResolver, observed []ComposedResourceState, exists bool) error {
+	if err := pt.composed.Render(ctx, xr, cd, t, e); err != nil {
+		return err
+	}
+	if err := RenderFromConnectionSecret(xr, cd, t, secret, WithComposedResourceExists(exists)); err != nil {
+		return err
+	}
+	return RenderFromComposed(xr, cd, t, observed, WithComposedResourceExists(exists))
+}
+
+// applyEnvironmentPatches applies the patches of the supplied environment
+// configuration, in order, between the supplied composite resource and
+// environment. It does nothing if either the configuration or the environment
+// is nil.
+func applyEnvironmentPatches(ec *v1.EnvironmentConfiguration, xr resource.Composite, e *env.Environment) error {
+	if ec == nil || e == nil {
+		return nil
+	}
+	for i, p := range ec.Patches {
+		if err := ApplyEnvironmentPatch(p, xr, e); err != nil {
+			return errors.Wrapf(err, errFmtPatchEnvironment, i)
+		}
+	}
 	return nil
 }
 
+// observedComposedResources returns a copy of the observed state of the
+// composed resource of each of the supplied templates. The state of a template
+// whose composed resource doesn't exist yet has no resource.
+func observedComposedResources(ct []v1.ComposedTemplate, cds ComposedResourceStates) ([]ComposedResourceState, error) {
+	observed := make([]ComposedResourceState, len(ct))
+	for i := range ct {
+		observed[i] = ComposedResourceState{ComposedResource: ComposedResource{ResourceName: *ct[i].Name}}
+		cd, exists := cds[observed[i].ResourceName]
+		if !exists || cd.Resource == nil {
+			continue
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cd.Resource)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtCopyObserved, observed[i].ResourceName)
+		}
+		observed[i].Resource = &composed.Unstructured{Unstructured: kunstructured.Unstructured{Object: u}}
+	}
+	return observed, nil
+}
+
 // FunctionIODesired builds the initial desired state for a FunctionIO from the XR
 // and any existing or impending composed resources. This reflects the observed
 // state of the world plus the initial desired state as built up by any P&T
@@ -36,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -862,9 +863,11 @@ func TestPatchAndTransform(t *testing.T) {
 						},
 					},
 				},
+				s: &PTFCompositionState{},
 			},
 			want: want{
-				err: errors.Wrap(errors.Errorf(errFmtUndefinedPatchSet, "nonexistent-patchset"), errInline),
+				s:   &PTFCompositionState{},
+				err: errors.Wrap(utilerrors.NewAggregate([]error{&v1.PatchSetReferenceError{ResourceIndex: 0, PatchIndex: 0, Err: v1.UndefinedPatchSetError("nonexistent-patchset")}}), errInline),
 			},
 		},
 		// TODO(negz): Test handling of ApplyEnvironmentPatch errors.
@@ -983,6 +986,115 @@ func TestPatchAndTransform(t *testing.T) {
 				},
 			},
 		},
+		"ComposedRenderErrorAbort": {
+			reason: "We should return any error encountered while rendering a composed resource if the patch error policy is abort.",
+			params: params{
+				composed: RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+					return errBoom
+				}),
+			},
+			args: args{
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							PatchErrorPolicy: func() *v1.PatchErrorPolicy {
+								p := v1.PatchErrorPolicyAbort
+								return &p
+							}(),
+							Resources: []v1.ComposedTemplate{
+								{Name: pointer.String("cool-resource")},
+								{Name: pointer.String("uncool-resource")},
+							},
+						},
+					},
+				},
+				s: &PTFCompositionState{ComposedResources: ComposedResourceStates{}},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					ComposedResources: ComposedResourceStates{
+						"cool-resource": ComposedResourceState{
+							ComposedResource:  ComposedResource{ResourceName: "cool-resource"},
+							Resource:          composed.New(),
+							Template:          &v1.ComposedTemplate{Name: pointer.String("cool-resource")},
+							TemplateRenderErr: errBoom,
+						},
+						"uncool-resource": ComposedResourceState{
+							ComposedResource:  ComposedResource{ResourceName: "uncool-resource"},
+							Resource:          composed.New(),
+							Template:          &v1.ComposedTemplate{Name: pointer.String("uncool-resource")},
+							TemplateRenderErr: errBoom,
+						},
+					},
+					Events: []event.Event{
+						event.Warning(reasonCompose, errors.Wrapf(errBoom, errFmtResourceName, "cool-resource")),
+						event.Warning(reasonCompose, errors.Wrapf(errBoom, errFmtResourceName, "uncool-resource")),
+					},
+				},
+				err: errors.Wrap(utilerrors.NewAggregate([]error{
+					errors.Wrapf(errBoom, errFmtResourceName, "cool-resource"),
+					errors.Wrapf(errBoom, errFmtResourceName, "uncool-resource"),
+				}), errRenderAbort),
+			},
+		},
+		"FromConnectionSecretKey": {
+			reason: "We should render composed resources from the XR's connection secret.",
+			params: params{
+				composed: RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+					cd.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "CoolComposed"})
+					return nil
+				}),
+			},
+			args: args{
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{{
+								Name: pointer.String("cool-resource"),
+								Patches: []v1.Patch{{
+									Type:                v1.PatchTypeFromConnectionSecretKey,
+									ConnectionSecretKey: pointer.String("password"),
+									ToFieldPath:         pointer.String("spec.password"),
+								}},
+							}},
+						},
+					},
+				},
+				s: &PTFCompositionState{
+					Composite:         composite.New(),
+					ConnectionDetails: managed.ConnectionDetails{"password": []byte("s3cr3t")},
+					ComposedResources: ComposedResourceStates{},
+				},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					Composite:         composite.New(),
+					ConnectionDetails: managed.ConnectionDetails{"password": []byte("s3cr3t")},
+					ComposedResources: ComposedResourceStates{
+						"cool-resource": ComposedResourceState{
+							ComposedResource: ComposedResource{ResourceName: "cool-resource"},
+							Resource: func() *composed.Unstructured {
+								r := composed.New()
+								r.Object = map[string]any{
+									"apiVersion": "example.org/v1",
+									"kind":       "CoolComposed",
+									"spec":       map[string]any{"password": "s3cr3t"},
+								}
+								return r
+							}(),
+							Template: &v1.ComposedTemplate{
+								Name: pointer.String("cool-resource"),
+								Patches: []v1.Patch{{
+									Type:                v1.PatchTypeFromConnectionSecretKey,
+									ConnectionSecretKey: pointer.String("password"),
+									ToFieldPath:         pointer.String("spec.password"),
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -17,23 +17,40 @@ limitations under the License.
 package composite
 
 import (
+	"bytes"
 	"crypto/sha1" //nolint:gosec // Not used for secure hashing
 	"crypto/sha256"
 	"crypto/sha512"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/Masterminds/semver"
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/pointer"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 )
@@ -41,10 +58,14 @@ import (
 const (
 	errMathTransformTypeFailed = "type %s is not supported for math transform type"
 	errMathInputNonNumber      = "input is required to be a number for math transformer"
+	errMathModuloByZero        = "cannot compute modulo by zero for math transformer"
 
 	errFmtRequiredField                 = "%s is required by type %s"
 	errFmtConvertInputTypeNotSupported  = "invalid input type %T"
 	errFmtConvertFormatPairNotSupported = "conversion from %s to %s is not supported with format %s"
+	errFmtConvertOverflow               = "value %v overflows %s"
+	errFmtConvertInvalidIPv4            = "%q is not a valid IPv4 address"
+	errFmtConvertIPv4OutOfRange         = "value %d is not within the range of IPv4 addresses"
 	errFmtTransformAtIndex              = "transform at index %d returned error"
 	errFmtTypeNotSupported              = "transform type %s is not supported"
 	errFmtTransformConfigMissing        = "given transform type %s requires configuration"
@@ -61,18 +82,111 @@ const (
 	errFmtMatchInputTypeInvalid   = "unsupported input type '%s'"
 	errMatchRegexpCompile         = "cannot compile regexp"
 
+	errRangeInputNonNumber     = "input is required to be a number for range transformer"
+	errRangeNoBuckets          = "range transform requires at least one bucket"
+	errFmtRangeNoBucket        = "input %v exceeds the max of every bucket and no fallback value is specified"
+	errFmtRangeParseValue      = "cannot parse value of bucket at index %d"
+	errRangeParseFallbackValue = "cannot parse fallback value"
+
+	errCIDRMatchInputNonString     = "input is required to be a string for cidrMatch transformer"
+	errCIDRMatchNoBlocks           = "cidrMatch transform requires at least one block"
+	errFmtCIDRMatchInputNonIP      = "input %q is not an IP address"
+	errFmtCIDRMatchParseCIDR       = "cannot parse CIDR of block at index %d"
+	errFmtCIDRMatchNoBlock         = "input %s is not in any block and no fallback value is specified"
+	errFmtCIDRMatchParseValue      = "cannot parse value of block at index %d"
+	errCIDRMatchParseFallbackValue = "cannot parse fallback value"
+
+	errPEMInputNonString      = "input is required to be a string for pem transformer"
+	errPEMDecode              = "input is not PEM-encoded"
+	errFmtPEMBlockType        = "PEM block is of type %q, not %q"
+	errPEMParseCertificate    = "cannot parse certificate"
+	errFmtPEMAttributeUnknown = "unknown pem transform attribute %q"
+
+	errConditionStatusInputNonString = "input is required to be a string for conditionStatus transformer"
+	errFmtConditionStatusNoValue     = "no value is specified for condition status %q and no default is specified"
+	errConditionStatusParseValue     = "cannot parse value"
+
+	errValueNotAllowed        = "value is not allowed"
+	errFmtAllowlistInput      = "input %s"
+	errAllowlistMarshalInput  = "cannot marshal input to JSON"
+	errFmtAllowlistParseValue = "cannot parse value at index %d"
+	errAllowlistNoValues      = "allowlist transform requires at least one value"
+
+	errFieldSelectInputNonObject = "input is required to be an object for fieldSelect transformer"
+	errFieldSelectNotFound       = "cannot find selected field"
+
+	errSelectMatchInputNonArray  = "input is required to be an array for selectMatch transformer"
+	errSelectMatchParseValue     = "cannot parse value"
+	errSelectMatchNoMatch        = "no element matches"
+	errSelectMatchReturnNotFound = "cannot find returnField of matching element"
+	errFmtSelectMatchElement     = "cannot match element at index %d"
+
+	errLabelSelectorInputNonObject    = "input is required to be an object for labelSelector transformer"
+	errFmtLabelSelectorLabelNonString = "value of label %q is not a string"
+	errLabelSelectorInvalidLabels     = "invalid labels"
+	errLabelSelectorDecode            = "cannot decode label selector"
+	errLabelSelectorInvalid           = "invalid label selector"
+	errLabelSelectorToLabels          = "cannot convert label selector to labels"
+	errFmtLabelSelectorModeUnknown    = "unknown labelSelector transform mode %q"
+
+	errCaseInputNonString = "input is required to be a string for case transformer"
+
+	errSemverInputNonString = "input is required to be a string for semver transformer"
+	errFmtSemverInvalid     = "%q is not a semantic version"
+
+	errDedupInputNonArray       = "input is required to be an array for dedup transformer"
+	errFmtDedupElement          = "cannot dedup element at index %d"
+	errFmtDedupElementNonScalar = "element at index %d is not a scalar; set a keyFieldPath to dedup objects"
+	errFmtDedupElementNonObject = "element at index %d is required to be an object for a dedup transform with a keyFieldPath"
+	errFmtDedupElementNoKey     = "element at index %d has no value at keyFieldPath %s"
+
+	errFmtTransformSetNotInlined = "transformSet %q must be inlined before it is resolved"
+
+	errValidateFormatInputNonString = "input is required to be a string for validateFormat transformer"
+	errFmtValidateFormatInvalid     = "%q is not a valid %s"
+	errFmtValidateFormatUnknown     = "unknown validateFormat transform format %q"
+
+	errAggregateInputNonArray        = "input is required to be an array for aggregate transformer"
+	errFmtAggregateElementNonNumber  = "element at index %d is required to be a number for aggregate transformer"
+	errFmtAggregateTransformTypeFail = "type %s is not supported for aggregate transform type"
+	errAggregateEmpty                = "cannot aggregate an empty array"
+
+	errTernaryInputNonBool = "input is required to be a boolean for ternary transformer"
+	errFmtTernaryParse     = "cannot parse %t value"
+
+	errTruncateInputNonString = "input is required to be a string for truncate transformer"
+
+	errStableSuffixInputNonString = "input is required to be a string for stableSuffix transformer"
+
+	errLengthInputNonArray = "input is required to be an array for length transformer"
+
+	errRelativeTimeInputNonNumber = "input is required to be a number for relativeTime transformer"
+
+	errNumberFormatInputNonNumber = "input is required to be a number for numberFormat transformer"
+
+	errJSONParseInputNonString = "input is required to be a string for jsonParse transformer"
+	errJSONParse               = "cannot parse input as JSON"
+
 	errStringTransformTypeFailed        = "type %s is not supported for string transform type"
 	errStringTransformTypeFormat        = "string transform of type %s fmt is not set"
 	errStringTransformTypeConvert       = "string transform of type %s convert is not set"
 	errStringTransformTypeTrim          = "string transform of type %s trim is not set"
 	errStringTransformTypeRegexp        = "string transform of type %s regexp is not set"
+	errStringTransformTypeReplace       = "string transform of type %s replace is not set"
+	errStringTransformReplaceOldEmpty   = "string transform of type %s requires a non-empty string to replace"
+	errStringTransformTypeSlice         = "string transform of type %s slice is not set"
+	errStringTransformTypeEscape        = "string transform of type %s escape is not set"
+	errFmtStringEscapeContext           = "unknown escape context %q"
 	errStringTransformTypeRegexpFailed  = "could not compile regexp"
 	errStringTransformTypeRegexpNoMatch = "regexp %q had no matches for group %d"
 	errStringConvertTypeFailed          = "type %s is not supported for string convert"
 
 	errDecodeString = "string is not valid base64"
+	errURLDecode    = "string is not valid URL encoding"
 	errMarshalJSON  = "cannot marshal to JSON"
 	errHash         = "cannot generate hash"
+
+	errFmtDNS1123Empty = "cannot normalize %q to a DNS-1123 label"
 )
 
 // Resolve the supplied Transform.
@@ -90,6 +204,10 @@ func Resolve(t v1.Transform, input any) (any, error) { //nolint:gocyclo // This
 		if t.Map == nil {
 			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
 		}
+		if t.MapOptions.IsCaseInsensitive() {
+			out, err = ResolveMapCaseInsensitive(*t.Map, input)
+			break
+		}
 		out, err = ResolveMap(*t.Map, input)
 	case v1.TransformTypeMatch:
 		if t.Match == nil {
@@ -106,23 +224,117 @@ func Resolve(t v1.Transform, input any) (any, error) { //nolint:gocyclo // This
 			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
 		}
 		out, err = ResolveConvert(*t.Convert, input)
+	case v1.TransformTypeRange:
+		if t.Range == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveRange(*t.Range, input)
+	case v1.TransformTypeAggregate:
+		if t.Aggregate == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveAggregate(*t.Aggregate, input)
+	case v1.TransformTypeTernary:
+		if t.Ternary == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveTernary(*t.Ternary, input)
+	case v1.TransformTypeTruncate:
+		if t.Truncate == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveTruncate(*t.Truncate, input)
+	case v1.TransformTypeLength:
+		out, err = ResolveLength(input)
+	case v1.TransformTypeJSONParse:
+		out, err = ResolveJSONParse(input)
+	case v1.TransformTypeRelativeTime:
+		out, err = ResolveRelativeTime(input, now())
+	case v1.TransformTypeNumberFormat:
+		if t.NumberFormat == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveNumberFormat(*t.NumberFormat, input)
+	case v1.TransformTypeCIDRMatch:
+		if t.CIDRMatch == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveCIDRMatch(*t.CIDRMatch, input)
+	case v1.TransformTypePEM:
+		if t.PEM == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolvePEM(*t.PEM, input)
+	case v1.TransformTypeAllowlist:
+		if t.Allowlist == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveAllowlist(*t.Allowlist, input)
+	case v1.TransformTypeConditionStatus:
+		if t.ConditionStatus == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveConditionStatus(*t.ConditionStatus, input)
+	case v1.TransformTypeFieldSelect:
+		if t.FieldSelect == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveFieldSelect(*t.FieldSelect, input)
+	case v1.TransformTypeValidateFormat:
+		if t.ValidateFormat == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveValidateFormat(*t.ValidateFormat, input)
+	case v1.TransformTypeStableSuffix:
+		if t.StableSuffix == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveStableSuffix(*t.StableSuffix, input)
+	case v1.TransformTypeSelectMatch:
+		if t.SelectMatch == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveSelectMatch(*t.SelectMatch, input)
+	case v1.TransformTypeLabelSelector:
+		if t.LabelSelector == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveLabelSelector(*t.LabelSelector, input)
+	case v1.TransformTypeCase:
+		if t.Case == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveCase(*t.Case, input)
+	case v1.TransformTypeSemver:
+		if t.Semver == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveSemver(*t.Semver, input)
+	case v1.TransformTypeDedup:
+		// A dedup transform's configuration is optional.
+		d := v1.DedupTransform{}
+		if t.Dedup != nil {
+			d = *t.Dedup
+		}
+		out, err = ResolveDedup(d, input)
+	case v1.TransformTypeTransformSet:
+		return nil, errors.Errorf(errFmtTransformSetNotInlined, pointer.StringDeref(t.TransformSetName, ""))
 	default:
 		return nil, errors.Errorf(errFmtTypeNotSupported, string(t.Type))
 	}
 
-	return out, errors.Wrapf(err, errFmtTransformTypeFailed, string(t.Type))
+	if err != nil {
+		// Wrapping only errors avoids allocating the arguments of Wrapf.
+		return out, errors.Wrapf(err, errFmtTransformTypeFailed, string(t.Type))
+	}
+	return out, nil
 }
 
 // ResolveMath resolves a Math transform.
 func ResolveMath(t v1.MathTransform, input any) (any, error) {
-	inputInt := int64(0)
-	switch i := input.(type) {
-	case int64:
-		inputInt = i
-	case int:
-		inputInt = int64(i)
-	default:
-		return nil, errors.New(errMathInputNonNumber)
+	inputInt, err := mathInput(input)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := t.Validate(); err != nil {
@@ -135,12 +347,33 @@ func ResolveMath(t v1.MathTransform, input any) (any, error) {
 		return mathClampMax(inputInt, *t.ClampMax), nil
 	case v1.MathTransformTypeClampMin:
 		return mathClampMin(inputInt, *t.ClampMin), nil
+	case v1.MathTransformTypeModulo:
+		if *t.Modulo == 0 {
+			return nil, errors.New(errMathModuloByZero)
+		}
+		return inputInt % *t.Modulo, nil
+	case v1.MathTransformTypeAdd:
+		return inputInt + *t.Add, nil
+	case v1.MathTransformTypeIdentity:
+		return input, nil
 	default:
 		return nil, errors.Errorf(errMathTransformTypeFailed, string(t.Type))
 
 	}
 }
 
+// mathInput returns the supplied integer input as an int64.
+func mathInput(input any) (int64, error) {
+	switch i := input.(type) {
+	case int64:
+		return i, nil
+	case int:
+		return int64(i), nil
+	default:
+		return 0, errors.New(errMathInputNonNumber)
+	}
+}
+
 func mathClampMin(input int64, min int64) int64 {
 	if input < min {
 		return min
@@ -155,6 +388,814 @@ func mathClampMax(input int64, max int64) int64 {
 	return input
 }
 
+// ResolveRange resolves a Range transform.
+func ResolveRange(t v1.RangeTransform, input any) (any, error) {
+	var in float64
+	switch i := input.(type) {
+	case int64:
+		in = float64(i)
+	case int:
+		in = float64(i)
+	case float64:
+		in = i
+	default:
+		return nil, errors.New(errRangeInputNonNumber)
+	}
+
+	if len(t.Buckets) == 0 {
+		return nil, errors.New(errRangeNoBuckets)
+	}
+
+	for i, b := range t.Buckets {
+		if in > float64(b.Max) {
+			continue
+		}
+		out, err := unmarshalJSON(b.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRangeParseValue, i)
+		}
+		return out, nil
+	}
+
+	if len(t.FallbackValue.Raw) == 0 {
+		return nil, errors.Errorf(errFmtRangeNoBucket, input)
+	}
+	out, err := unmarshalJSON(t.FallbackValue)
+	if err != nil {
+		return nil, errors.Wrap(err, errRangeParseFallbackValue)
+	}
+	return out, nil
+}
+
+// ResolveCIDRMatch resolves a CIDRMatch transform.
+func ResolveCIDRMatch(t v1.CIDRMatchTransform, input any) (any, error) {
+	s, ok := input.(string)
+	if !ok {
+		return nil, errors.New(errCIDRMatchInputNonString)
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.Errorf(errFmtCIDRMatchInputNonIP, s)
+	}
+
+	if len(t.Blocks) == 0 {
+		return nil, errors.New(errCIDRMatchNoBlocks)
+	}
+
+	for i, b := range t.Blocks {
+		_, n, err := net.ParseCIDR(b.CIDR)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtCIDRMatchParseCIDR, i)
+		}
+		if !n.Contains(ip) {
+			continue
+		}
+		out, err := unmarshalJSON(b.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtCIDRMatchParseValue, i)
+		}
+		return out, nil
+	}
+
+	if len(t.FallbackValue.Raw) == 0 {
+		return nil, errors.Errorf(errFmtCIDRMatchNoBlock, s)
+	}
+	out, err := unmarshalJSON(t.FallbackValue)
+	if err != nil {
+		return nil, errors.Wrap(err, errCIDRMatchParseFallbackValue)
+	}
+	return out, nil
+}
+
+// ResolveFieldSelect resolves a FieldSelect transform. If the field path
+// doesn't exist and the transform's policy is optional the returned error
+// satisfies fieldpath.IsNotFound, which optional patches use to skip
+// themselves.
+func ResolveFieldSelect(t v1.FieldSelectTransform, input any) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	in, ok := input.(map[string]any)
+	if !ok {
+		return nil, errors.New(errFieldSelectInputNonObject)
+	}
+	return selectField(fieldpath.Pave(in), t.FieldPath, t.GetPolicy(), errFieldSelectNotFound)
+}
+
+// ResolveSelectMatch resolves a SelectMatch transform. Elements of the input
+// that aren't objects never match. If no element matches, or the returnField
+// of the matching element doesn't exist, and the transform's policy is
+// optional the returned error satisfies fieldpath.IsNotFound, which optional
+// patches use to skip themselves.
+func ResolveSelectMatch(t v1.SelectMatchTransform, input any) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	in, ok := input.([]any)
+	if !ok {
+		return nil, errors.New(errSelectMatchInputNonArray)
+	}
+	want, err := canonicalJSON(t.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, errSelectMatchParseValue)
+	}
+
+	for i, e := range in {
+		p, match, err := selectMatches(t, want, i, e)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+		if t.ReturnField == nil {
+			return e, nil
+		}
+		return selectField(p, *t.ReturnField, t.GetPolicy(), errSelectMatchReturnNotFound)
+	}
+
+	if t.GetPolicy() == v1.FromFieldPathPolicyRequired {
+		return nil, errors.New(errSelectMatchNoMatch)
+	}
+	return nil, notFoundError{errors.New(errSelectMatchNoMatch)}
+}
+
+// selectMatches returns whether the supplied element, at the supplied index of
+// the input of a SelectMatch transform, matches the supplied canonical JSON
+// value, and the paved element if it's an object.
+func selectMatches(t v1.SelectMatchTransform, want []byte, i int, e any) (*fieldpath.Paved, bool, error) {
+	o, ok := e.(map[string]any)
+	if !ok {
+		return nil, false, nil
+	}
+	p := fieldpath.Pave(o)
+	v, err := p.GetValue(t.FieldPath)
+	if fieldpath.IsNotFound(err) {
+		return p, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrapf(err, errFmtSelectMatchElement, i)
+	}
+	got, err := json.Marshal(v)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, errFmtSelectMatchElement, i)
+	}
+	return p, string(got) == string(want), nil
+}
+
+// selectField returns the value at the supplied field path of the supplied
+// object. If it doesn't exist the returned error, wrapped with the supplied
+// message, satisfies fieldpath.IsNotFound only if the supplied policy is
+// optional.
+func selectField(p *fieldpath.Paved, path string, policy v1.FromFieldPathPolicy, msg string) (any, error) {
+	out, err := p.GetValue(path)
+	switch {
+	case fieldpath.IsNotFound(err) && policy == v1.FromFieldPathPolicyRequired:
+		// Drop the not found cause so that the patch fails rather than
+		// being skipped.
+		return nil, errors.Wrap(errors.New(err.Error()), msg)
+	case fieldpath.IsNotFound(err):
+		return nil, errors.Wrap(err, msg)
+	case err != nil:
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResolveLabelSelector resolves a LabelSelector transform. In ToSelector mode
+// it returns a label selector whose matchLabels are its input labels. In
+// FromSelector mode it returns the labels matched by its input label selector.
+// Malformed labels or label selectors return an error.
+func ResolveLabelSelector(t v1.LabelSelectorTransform, input any) (any, error) {
+	in, ok := input.(map[string]any)
+	if !ok {
+		return nil, errors.New(errLabelSelectorInputNonObject)
+	}
+
+	switch t.Mode {
+	case v1.LabelSelectorTransformModeToSelector:
+		return labelsToSelector(in)
+	case v1.LabelSelectorTransformModeFromSelector:
+		return selectorToLabels(in)
+	}
+	return nil, errors.Errorf(errFmtLabelSelectorModeUnknown, t.Mode)
+}
+
+// labelsToSelector returns a label selector whose matchLabels are the supplied
+// labels.
+func labelsToSelector(in map[string]any) (any, error) {
+	labels := make(map[string]string, len(in))
+	matchLabels := make(map[string]any, len(in))
+	for k, v := range in {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf(errFmtLabelSelectorLabelNonString, k)
+		}
+		labels[k] = s
+		matchLabels[k] = s
+	}
+	if errs := metav1validation.ValidateLabels(labels, nil); len(errs) > 0 {
+		return nil, errors.Wrap(errs.ToAggregate(), errLabelSelectorInvalidLabels)
+	}
+	return map[string]any{"matchLabels": matchLabels}, nil
+}
+
+// selectorToLabels returns the labels matched by the supplied label selector.
+func selectorToLabels(in map[string]any) (any, error) {
+	ls, err := decodeLabelSelector(in)
+	if err != nil {
+		return nil, errors.Wrap(err, errLabelSelectorDecode)
+	}
+	if errs := metav1validation.ValidateLabelSelector(ls, metav1validation.LabelSelectorValidationOptions{}, nil); len(errs) > 0 {
+		return nil, errors.Wrap(errs.ToAggregate(), errLabelSelectorInvalid)
+	}
+	labels, err := metav1.LabelSelectorAsMap(ls)
+	if err != nil {
+		return nil, errors.Wrap(err, errLabelSelectorToLabels)
+	}
+	out := make(map[string]any, len(labels))
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out, nil
+}
+
+// decodeLabelSelector decodes the supplied object into a label selector,
+// returning an error if the object has fields a label selector doesn't.
+func decodeLabelSelector(in map[string]any) (*metav1.LabelSelector, error) {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	ls := &metav1.LabelSelector{}
+	err = d.Decode(ls)
+	return ls, err
+}
+
+// A notFoundError satisfies fieldpath.IsNotFound, so that a transform may
+// skip the patch it belongs to like a field path that doesn't exist.
+type notFoundError struct {
+	error
+}
+
+func (e notFoundError) IsNotFound() bool {
+	return true
+}
+
+// ResolveValidateFormat resolves a ValidateFormat transform. It returns the
+// input, normalized, if it is of the transform's format.
+func ResolveValidateFormat(t v1.ValidateFormatTransform, input any) (any, error) {
+	s, ok := input.(string)
+	if !ok {
+		return nil, errors.New(errValidateFormatInputNonString)
+	}
+	s = strings.TrimSpace(s)
+
+	switch t.Format {
+	case v1.ValidateFormatHostname:
+		h := strings.TrimSuffix(strings.ToLower(s), ".")
+		if !isHostname(h) {
+			return nil, errors.Errorf(errFmtValidateFormatInvalid, s, t.Format)
+		}
+		return h, nil
+	case v1.ValidateFormatEmail:
+		// Only a bare address is accepted, so for example a display name or
+		// a quoted local part is rejected.
+		a, err := mail.ParseAddress(s)
+		if err != nil || a.Address != s {
+			return nil, errors.Errorf(errFmtValidateFormatInvalid, s, t.Format)
+		}
+		i := strings.LastIndex(s, "@")
+		local, domain := s[:i], strings.ToLower(s[i+1:])
+		if !isHostname(domain) {
+			return nil, errors.Errorf(errFmtValidateFormatInvalid, s, t.Format)
+		}
+		return local + "@" + domain, nil
+	}
+	return nil, errors.Errorf(errFmtValidateFormatUnknown, t.Format)
+}
+
+// isHostname returns true if the supplied lower case string is an RFC 1123
+// hostname, i.e. dot separated labels of at most 63 letters, digits, and
+// hyphens that don't start or end with a hyphen.
+func isHostname(s string) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, l := range strings.Split(s, ".") {
+		if !isHostnameLabel(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHostnameLabel returns true if the supplied lower case string is a label of
+// an RFC 1123 hostname.
+func isHostnameLabel(l string) bool {
+	if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+		return false
+	}
+	for _, r := range l {
+		if !isHostnameRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHostnameRune returns true if the supplied rune is a lower case letter, a
+// digit, or a hyphen.
+func isHostnameRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-'
+}
+
+// ResolveConditionStatus resolves a ConditionStatus transform. The input may be
+// a string, or a string type such as a corev1.ConditionStatus.
+func ResolveConditionStatus(t v1.ConditionStatusTransform, input any) (any, error) {
+	v := reflect.ValueOf(input)
+	if v.Kind() != reflect.String {
+		return nil, errors.New(errConditionStatusInputNonString)
+	}
+	s := v.String()
+
+	var val *extv1.JSON
+	switch corev1.ConditionStatus(s) {
+	case corev1.ConditionTrue:
+		val = t.True
+	case corev1.ConditionFalse:
+		val = t.False
+	case corev1.ConditionUnknown:
+		val = t.Unknown
+	}
+	if val == nil {
+		val = t.Default
+	}
+	if val == nil {
+		return nil, errors.Errorf(errFmtConditionStatusNoValue, s)
+	}
+
+	out, err := unmarshalJSON(*val)
+	if err != nil {
+		return nil, errors.Wrap(err, errConditionStatusParseValue)
+	}
+	return out, nil
+}
+
+// ResolveAllowlist resolves an Allowlist transform. The input is returned
+// unchanged if its JSON encoding is equal to that of one of the permitted
+// values.
+func ResolveAllowlist(t v1.AllowlistTransform, input any) (any, error) {
+	if len(t.Values) == 0 {
+		return nil, errors.New(errAllowlistNoValues)
+	}
+	in, err := json.Marshal(input)
+	if err != nil {
+		return nil, errors.Wrap(err, errAllowlistMarshalInput)
+	}
+	for i, v := range t.Values {
+		allowed, err := canonicalJSON(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtAllowlistParseValue, i)
+		}
+		if string(in) == string(allowed) {
+			return input, nil
+		}
+	}
+	return nil, errors.Wrapf(errors.New(errValueNotAllowed), errFmtAllowlistInput, in)
+}
+
+// canonicalJSON returns the supplied JSON value as json.Marshal would encode
+// it once decoded, so that it may be compared to an encoded input. Plain
+// strings and literals are already in this form.
+func canonicalJSON(v extv1.JSON) ([]byte, error) {
+	switch {
+	case isPlainJSONString(v.Raw), string(v.Raw) == "true", string(v.Raw) == "false", string(v.Raw) == "null":
+		return v.Raw, nil
+	}
+	val, err := unmarshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(val)
+}
+
+// ResolvePEM resolves a PEM transform.
+func ResolvePEM(t v1.PEMTransform, input any) (any, error) {
+	c, err := parsePEMCertificate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.Attribute {
+	case v1.PEMTransformAttributeCommonName:
+		return c.Subject.CommonName, nil
+	case v1.PEMTransformAttributeIssuerCommonName:
+		return c.Issuer.CommonName, nil
+	case v1.PEMTransformAttributeSerialNumber:
+		return c.SerialNumber.String(), nil
+	case v1.PEMTransformAttributeNotBefore:
+		return c.NotBefore.UTC().Format(time.RFC3339), nil
+	case v1.PEMTransformAttributeNotAfter:
+		return c.NotAfter.UTC().Format(time.RFC3339), nil
+	case v1.PEMTransformAttributeDNSNames:
+		out := make([]any, len(c.DNSNames))
+		for i, n := range c.DNSNames {
+			out[i] = n
+		}
+		return out, nil
+	}
+	return nil, errors.Errorf(errFmtPEMAttributeUnknown, t.Attribute)
+}
+
+// parsePEMCertificate parses the supplied input as a PEM encoded certificate.
+func parsePEMCertificate(input any) (*x509.Certificate, error) {
+	s, ok := input.(string)
+	if !ok {
+		return nil, errors.New(errPEMInputNonString)
+	}
+	b, _ := pem.Decode([]byte(s))
+	if b == nil {
+		return nil, errors.New(errPEMDecode)
+	}
+	if b.Type != "CERTIFICATE" {
+		return nil, errors.Errorf(errFmtPEMBlockType, b.Type, "CERTIFICATE")
+	}
+	c, err := x509.ParseCertificate(b.Bytes)
+	return c, errors.Wrap(err, errPEMParseCertificate)
+}
+
+// ResolveAggregate resolves an Aggregate transform. The result is an int64 if
+// every element of the input is an integer, and a float64 otherwise.
+func ResolveAggregate(t v1.AggregateTransform, input any) (any, error) {
+	in, ok := input.([]any)
+	if !ok {
+		return nil, errors.New(errAggregateInputNonArray)
+	}
+
+	ints := make([]int64, 0, len(in))
+	floats := make([]float64, 0, len(in))
+	for i, e := range in {
+		switch n := e.(type) {
+		case int64:
+			ints = append(ints, n)
+			floats = append(floats, float64(n))
+		case int:
+			ints = append(ints, int64(n))
+			floats = append(floats, float64(n))
+		case float64:
+			floats = append(floats, n)
+		default:
+			return nil, errors.Errorf(errFmtAggregateElementNonNumber, i)
+		}
+	}
+
+	if t.GetType() == v1.AggregateTransformTypeCount {
+		return int64(len(in)), nil
+	}
+	if len(ints) == len(in) {
+		out, err := aggregate(t.GetType(), ints)
+		if err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	out, err := aggregate(t.GetType(), floats)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func aggregate[T int64 | float64](t v1.AggregateTransformType, in []T) (T, error) {
+	var out T
+	switch t { //nolint:exhaustive // Counts don't depend on the type of the input, and are handled by ResolveAggregate.
+	case v1.AggregateTransformTypeSum:
+		for _, n := range in {
+			out += n
+		}
+	case v1.AggregateTransformTypeMax:
+		return extremum(in, func(a, b T) bool { return a > b })
+	case v1.AggregateTransformTypeMin:
+		return extremum(in, func(a, b T) bool { return a < b })
+	default:
+		return out, errors.Errorf(errFmtAggregateTransformTypeFail, t)
+	}
+	return out, nil
+}
+
+// extremum returns the element of the supplied non-empty array that is better
+// than every other element.
+func extremum[T int64 | float64](in []T, better func(a, b T) bool) (T, error) {
+	var out T
+	if len(in) == 0 {
+		return out, errors.New(errAggregateEmpty)
+	}
+	out = in[0]
+	for _, n := range in[1:] {
+		if better(n, out) {
+			out = n
+		}
+	}
+	return out, nil
+}
+
+// ResolveTernary resolves a Ternary transform.
+func ResolveTernary(t v1.TernaryTransform, input any) (any, error) {
+	in, ok := input.(bool)
+	if !ok {
+		return nil, errors.New(errTernaryInputNonBool)
+	}
+
+	v := t.False
+	if in {
+		v = t.True
+	}
+
+	out, err := unmarshalJSON(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtTernaryParse, in)
+	}
+	return out, nil
+}
+
+// ResolveLength resolves a Length transform. It returns the number of elements
+// in the supplied array as an int64.
+func ResolveLength(input any) (any, error) {
+	in, ok := input.([]any)
+	if !ok {
+		return nil, errors.New(errLengthInputNonArray)
+	}
+	return int64(len(in)), nil
+}
+
+// now returns the current time, as used by relativeTime transforms. Tests may
+// replace it to resolve relativeTime transforms deterministically.
+var now = time.Now
+
+// ResolveRelativeTime resolves a RelativeTime transform. It returns the supplied
+// time plus the number of seconds input, in UTC, as an RFC3339 timestamp.
+// Callers typically supply the current time, so the output changes every time
+// the transform is resolved.
+func ResolveRelativeTime(input any, now time.Time) (any, error) {
+	var d time.Duration
+	switch i := input.(type) {
+	case int64:
+		d = time.Duration(i) * time.Second
+	case int:
+		d = time.Duration(i) * time.Second
+	case float64:
+		d = time.Duration(i * float64(time.Second))
+	default:
+		return nil, errors.New(errRelativeTimeInputNonNumber)
+	}
+	return now.Add(d).UTC().Format(time.RFC3339), nil
+}
+
+// ResolveJSONParse resolves a JSONParse transform. It returns the value encoded
+// by the supplied JSON string, for example an object or an array. A string
+// transform with the ToJson conversion does the inverse.
+func ResolveJSONParse(input any) (any, error) {
+	in, ok := input.(string)
+	if !ok {
+		return nil, errors.New(errJSONParseInputNonString)
+	}
+	var out any
+	if err := json.Unmarshal([]byte(in), &out); err != nil {
+		return nil, errors.Wrap(err, errJSONParse)
+	}
+	return out, nil
+}
+
+// ResolveNumberFormat resolves a NumberFormat transform.
+func ResolveNumberFormat(t v1.NumberFormatTransform, input any) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	var s string
+	switch n := input.(type) {
+	case int64:
+		s = formatInt(n, t.DecimalPlaces)
+	case int:
+		s = formatInt(int64(n), t.DecimalPlaces)
+	case float64:
+		s = strconv.FormatFloat(n, 'f', pointer.IntDeref(t.DecimalPlaces, -1), 64)
+	default:
+		return nil, errors.New(errNumberFormatInputNonNumber)
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, fraction, _ := strings.Cut(s, ".")
+	if t.ThousandsSeparator != nil {
+		integer = groupThousands(integer, *t.ThousandsSeparator)
+	}
+	if fraction == "" {
+		return sign + integer, nil
+	}
+	return sign + integer + t.GetDecimalSeparator() + fraction, nil
+}
+
+// formatInt formats the supplied integer with the supplied number of decimal
+// places, if any. Integers aren't formatted as floats, which can't represent
+// every int64.
+func formatInt(n int64, places *int) string {
+	s := strconv.FormatInt(n, 10)
+	if p := pointer.IntDeref(places, 0); p > 0 {
+		s += "." + strings.Repeat("0", p)
+	}
+	return s
+}
+
+// groupThousands inserts the supplied separator between each group of three
+// of the supplied digits, counting from the right.
+func groupThousands(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	b := strings.Builder{}
+	first := len(digits) % 3
+	if first == 0 {
+		first = 3
+	}
+	b.WriteString(digits[:first])
+	for i := first; i < len(digits); i += 3 {
+		b.WriteString(sep)
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// ResolveTruncate resolves a Truncate transform.
+func ResolveTruncate(t v1.TruncateTransform, input any) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	in, ok := input.(string)
+	if !ok {
+		return nil, errors.New(errTruncateInputNonString)
+	}
+
+	// Lengths are in characters, so that we never split a multi-byte
+	// character.
+	r := []rune(in)
+	if len(r) <= t.MaxLength {
+		return in, nil
+	}
+	if !t.HashSuffix {
+		return string(r[:t.MaxLength]), nil
+	}
+
+	h := sha256.Sum256([]byte(in))
+	suffix := "-" + hex.EncodeToString(h[:])[:v1.TruncateHashLength]
+	return string(r[:t.MaxLength-len(suffix)]) + suffix, nil
+}
+
+// ResolveSemver resolves a Semver transform.
+func ResolveSemver(t v1.SemverTransform, input any) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	in, ok := input.(string)
+	if !ok {
+		return nil, errors.New(errSemverInputNonString)
+	}
+	v, err := semver.NewVersion(in)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtSemverInvalid, in)
+	}
+
+	switch t.Operation { //nolint:exhaustive // Validate guarantees the remaining operation is normalize.
+	case v1.SemverTransformOperationMajor:
+		return v.Major(), nil
+	case v1.SemverTransformOperationMinor:
+		return v.Minor(), nil
+	case v1.SemverTransformOperationPatch:
+		return v.Patch(), nil
+	}
+	return v.String(), nil
+}
+
+// ResolveDedup resolves a Dedup transform. The first of each set of duplicate
+// elements is kept, in its original position relative to the other kept
+// elements.
+func ResolveDedup(t v1.DedupTransform, input any) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	in, ok := input.([]any)
+	if !ok {
+		return nil, errors.New(errDedupInputNonArray)
+	}
+
+	seen := make(map[string]bool, len(in))
+	out := make([]any, 0, len(in))
+	for i, e := range in {
+		k, err := dedupKey(t, e, i)
+		if err != nil {
+			return nil, err
+		}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// dedupKey returns the JSON encoding of the value that identifies the supplied
+// element, at the supplied index, of the input of a dedup transform.
+func dedupKey(t v1.DedupTransform, e any, i int) (string, error) {
+	v := e
+	if t.KeyFieldPath == nil {
+		switch e.(type) {
+		case map[string]any, []any:
+			return "", errors.Errorf(errFmtDedupElementNonScalar, i)
+		}
+	} else {
+		o, ok := e.(map[string]any)
+		if !ok {
+			return "", errors.Errorf(errFmtDedupElementNonObject, i)
+		}
+		var err error
+		v, err = fieldpath.Pave(o).GetValue(*t.KeyFieldPath)
+		if fieldpath.IsNotFound(err) {
+			// Don't return the not found error, which would skip rather
+			// than fail the patch.
+			return "", errors.Errorf(errFmtDedupElementNoKey, i, *t.KeyFieldPath)
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, errFmtDedupElement, i)
+		}
+	}
+	k, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtDedupElement, i)
+	}
+	return string(k), nil
+}
+
+// ResolveCase resolves a Case transform. See v1.CaseTransform for how the input
+// is split into words.
+func ResolveCase(t v1.CaseTransform, input any) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	in, ok := input.(string)
+	if !ok {
+		return nil, errors.New(errCaseInputNonString)
+	}
+
+	words := strings.FieldsFunc(in, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '_' || r == '-'
+	})
+	for i := range words {
+		words[i] = strings.ToLower(words[i])
+	}
+
+	switch t.To { //nolint:exhaustive // Validate guarantees the remaining type is camel.
+	case v1.CaseTransformTypeKebab:
+		return strings.Join(words, "-"), nil
+	case v1.CaseTransformTypeSnake:
+		return strings.Join(words, "_"), nil
+	}
+	for i := 1; i < len(words); i++ {
+		r := []rune(words[i])
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, ""), nil
+}
+
+// ResolveStableSuffix resolves a StableSuffix transform. The characters of the
+// output are drawn from the transform's charset by a PRNG that is seeded by a
+// hash of the input, so the same input always returns the same output. The
+// output is not cryptographically random.
+func ResolveStableSuffix(t v1.StableSuffixTransform, input any) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	in, ok := input.(string)
+	if !ok {
+		return nil, errors.New(errStableSuffixInputNonString)
+	}
+
+	h := sha256.Sum256([]byte(in))
+	rng := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(h[:8])))) //nolint:gosec // The output is intentionally deterministic.
+	charset := []rune(t.GetCharset())
+	out := make([]rune, t.Length)
+	for i := range out {
+		out[i] = charset[rng.Intn(len(charset))]
+	}
+	return string(out), nil
+}
+
 // ResolveMap resolves a Map transform.
 func ResolveMap(t v1.MapTransform, input any) (any, error) {
 	switch i := input.(type) {
@@ -163,8 +1204,8 @@ func ResolveMap(t v1.MapTransform, input any) (any, error) {
 		if !ok {
 			return nil, errors.Errorf(errFmtMapNotFound, i)
 		}
-		var val interface{}
-		if err := json.Unmarshal(p.Raw, &val); err != nil {
+		val, err := decodeJSON(p.Raw)
+		if err != nil {
 			return nil, errors.Wrapf(err, errFmtMapInvalidJSON, i)
 		}
 		return val, nil
@@ -173,16 +1214,36 @@ func ResolveMap(t v1.MapTransform, input any) (any, error) {
 	}
 }
 
+// ResolveMapCaseInsensitive resolves a Map transform, matching the input
+// against its keys regardless of case. A key that matches the input exactly
+// is preferred.
+func ResolveMapCaseInsensitive(t v1.MapTransform, input any) (any, error) {
+	i, ok := input.(string)
+	if !ok {
+		return ResolveMap(t, input)
+	}
+	if _, ok := t.Pairs[i]; ok {
+		return ResolveMap(t, i)
+	}
+	l := strings.ToLower(i)
+	for k := range t.Pairs {
+		if strings.ToLower(k) == l {
+			return ResolveMap(t, k)
+		}
+	}
+	return nil, errors.Errorf(errFmtMapNotFound, i)
+}
+
 // ResolveMatch resolves a Match transform.
 func ResolveMatch(t v1.MatchTransform, input any) (any, error) {
-	var output any
 	for i, p := range t.Patterns {
 		matches, err := Matches(p, input)
 		if err != nil {
 			return nil, errors.Wrapf(err, errFmtMatchPattern, i)
 		}
 		if matches {
-			if err := unmarshalJSON(p.Result, &output); err != nil {
+			output, err := unmarshalJSON(p.Result)
+			if err != nil {
 				return nil, errors.Wrapf(err, errFmtMatchParseResult, i)
 			}
 			return output, nil
@@ -199,7 +1260,8 @@ func ResolveMatch(t v1.MatchTransform, input any) (any, error) {
 	}
 
 	// Use fallback value if no pattern matches (or if there are no patterns)
-	if err := unmarshalJSON(t.FallbackValue, &output); err != nil {
+	output, err := unmarshalJSON(t.FallbackValue)
+	if err != nil {
 		return nil, errors.Wrap(err, errMatchParseFallbackValue)
 	}
 	return output, nil
@@ -231,7 +1293,7 @@ func matchesRegexp(p v1.MatchTransformPattern, input any) (bool, error) {
 	if p.Regexp == nil {
 		return false, errors.Errorf(errFmtRequiredField, "regexp", v1.MatchTransformPatternTypeRegexp)
 	}
-	re, err := regexp.Compile(*p.Regexp)
+	re, err := compiledRegexps.Compile(*p.Regexp)
 	if err != nil {
 		return false, errors.Wrap(err, errMatchRegexpCompile)
 	}
@@ -247,15 +1309,63 @@ func matchesRegexp(p v1.MatchTransformPattern, input any) (bool, error) {
 
 // unmarshalJSON is a small utility function that returns nil if j contains no
 // data. json.Unmarshal seems to not be able to handle this.
-func unmarshalJSON(j extv1.JSON, output *any) error {
+func unmarshalJSON(j extv1.JSON) (any, error) {
 	if len(j.Raw) == 0 {
-		return nil
+		return nil, nil
+	}
+	return decodeJSON(j.Raw)
+}
+
+// decodeJSON works like json.Unmarshal, but decodes plain JSON strings and
+// the literals true, false, and null without allocating a decoder. Most of
+// the values transforms output are one of these.
+func decodeJSON(raw []byte) (any, error) {
+	switch {
+	case isPlainJSONString(raw):
+		return string(raw[1 : len(raw)-1]), nil
+	case string(raw) == "true":
+		return true, nil
+	case string(raw) == "false":
+		return false, nil
+	case string(raw) == "null":
+		return nil, nil
 	}
-	return json.Unmarshal(j.Raw, output)
+	var out any
+	err := json.Unmarshal(raw, &out)
+	return out, err
 }
 
-// ResolveString resolves a String transform.
+// isPlainJSONString returns true if the supplied JSON is a string of printable
+// ASCII characters that needn't be escaped. Such a string decodes to its
+// unquoted characters, and json.Marshal encodes it back to exactly the same
+// bytes.
+func isPlainJSONString(raw []byte) bool {
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return false
+	}
+	for _, c := range raw[1 : len(raw)-1] {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+		switch c {
+		case '"', '\\', '<', '>', '&':
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveString resolves a String transform. The result of the transform is
+// wrapped in its prefix and suffix, if any.
 func ResolveString(t v1.StringTransform, input any) (string, error) {
+	out, err := resolveString(t, input)
+	if err != nil {
+		return out, err
+	}
+	return pointer.StringDeref(t.Prefix, "") + out + pointer.StringDeref(t.Suffix, ""), nil
+}
+
+func resolveString(t v1.StringTransform, input any) (string, error) { //nolint:gocyclo // just a switch
 	switch t.Type {
 	case v1.StringTransformTypeFormat:
 		if t.Format == nil {
@@ -277,12 +1387,83 @@ func ResolveString(t v1.StringTransform, input any) (string, error) {
 			return "", errors.Errorf(errStringTransformTypeRegexp, string(t.Type))
 		}
 		return stringRegexpTransform(input, *t.Regexp)
+	case v1.StringTransformTypeReplace:
+		if t.Replace == nil {
+			return "", errors.Errorf(errStringTransformTypeReplace, string(t.Type))
+		}
+		if t.Replace.Old == "" {
+			return "", errors.Errorf(errStringTransformReplaceOldEmpty, string(t.Type))
+		}
+		return strings.Replace(fmt.Sprintf("%v", input), t.Replace.Old, t.Replace.New, pointer.IntDeref(t.Replace.Count, -1)), nil
+	case v1.StringTransformTypeSlice:
+		if t.Slice == nil {
+			return "", errors.Errorf(errStringTransformTypeSlice, string(t.Type))
+		}
+		return stringSliceTransform(input, *t.Slice), nil
+	case v1.StringTransformTypeEscape:
+		if t.Escape == nil {
+			return "", errors.Errorf(errStringTransformTypeEscape, string(t.Type))
+		}
+		return stringEscapeTransform(input, t.Escape.Context)
 	default:
 		return "", errors.Errorf(errStringTransformTypeFailed, string(t.Type))
 	}
 }
 
-func stringConvertTransform(t *v1.StringConversionType, input any) (string, error) {
+func stringSliceTransform(input any, s v1.StringTransformSlice) string {
+	// Slice characters rather than bytes, so that a multi-byte character is
+	// never split.
+	r := []rune(fmt.Sprintf("%v", input))
+	start := sliceIndex(s.Start, len(r))
+	end := sliceIndex(pointer.IntDeref(s.End, len(r)), len(r))
+	if start >= end {
+		return ""
+	}
+	return string(r[start:end])
+}
+
+func stringEscapeTransform(input any, c v1.StringEscapeContext) (string, error) {
+	str := fmt.Sprintf("%v", input)
+	switch c {
+	case v1.StringEscapeContextJSON:
+		// Encode the input as a JSON string, then strip its quotes and the
+		// trailing newline added by the encoder. HTML characters such as <
+		// don't need to be escaped in a JSON string.
+		b := &strings.Builder{}
+		e := json.NewEncoder(b)
+		e.SetEscapeHTML(false)
+		if err := e.Encode(str); err != nil {
+			return "", errors.Wrap(err, errMarshalJSON)
+		}
+		q := strings.TrimSuffix(b.String(), "\n")
+		return q[1 : len(q)-1], nil
+	case v1.StringEscapeContextShell:
+		// Single quotes preserve every character but a single quote, which
+		// must end the quoted string, be escaped, and start a new one.
+		return "'" + strings.ReplaceAll(str, "'", `'\''`) + "'", nil
+	case v1.StringEscapeContextURL:
+		return url.QueryEscape(str), nil
+	default:
+		return "", errors.Errorf(errFmtStringEscapeContext, c)
+	}
+}
+
+// sliceIndex resolves a possibly negative index into a sequence of the
+// supplied length, clamping it to the bounds of the sequence.
+func sliceIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+func stringConvertTransform(t *v1.StringConversionType, input any) (string, error) { //nolint:gocyclo // This is a long but simple/same-y switch.
 	str := fmt.Sprintf("%v", input)
 	switch *t {
 	case v1.StringConversionTypeToUpper:
@@ -306,11 +1487,38 @@ func stringConvertTransform(t *v1.StringConversionType, input any) (string, erro
 	case v1.StringConversionTypeToSHA512:
 		hash, err := stringGenerateHash(input, sha512.Sum512)
 		return hex.EncodeToString(hash[:]), errors.Wrap(err, errHash)
+	case v1.StringConversionTypeURLEncode:
+		return url.QueryEscape(str), nil
+	case v1.StringConversionTypeURLDecode:
+		s, err := url.QueryUnescape(str)
+		return s, errors.Wrap(err, errURLDecode)
+	case v1.StringConversionTypeDNS1123:
+		return stringDNS1123Transform(str)
 	default:
 		return "", errors.Errorf(errStringConvertTypeFailed, *t)
 	}
 }
 
+// stringDNS1123Transform normalizes the supplied string to a DNS-1123 label,
+// as validated by Kubernetes. It returns an error if the string has no
+// alphanumeric characters from which to form a label.
+func stringDNS1123Transform(str string) (string, error) {
+	out := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(str))
+	if len(out) > validation.DNS1123LabelMaxLength {
+		out = out[:validation.DNS1123LabelMaxLength]
+	}
+	out = strings.Trim(out, "-")
+	if out == "" {
+		return "", errors.Errorf(errFmtDNS1123Empty, str)
+	}
+	return out, nil
+}
+
 func stringGenerateHash[THash any](input any, hashFunc func([]byte) THash) (THash, error) {
 	inputJSON, err := json.Marshal(input)
 	if err != nil {
@@ -332,7 +1540,7 @@ func stringTrimTransform(input any, t v1.StringTransformType, trim string) strin
 }
 
 func stringRegexpTransform(input any, r v1.StringTransformRegexp) (string, error) {
-	re, err := regexp.Compile(r.Match)
+	re, err := compiledRegexps.Compile(r.Match)
 	if err != nil {
 		return "", errors.Wrap(err, errStringTransformTypeRegexpFailed)
 	}
@@ -355,7 +1563,18 @@ func ResolveConvert(t v1.ConvertTransform, input any) (any, error) {
 		return nil, err
 	}
 
-	from := v1.TransformIOType(reflect.TypeOf(input).String())
+	if s, ok := input.(string); ok && s == "" && t.EmptyAsZero {
+		return convertZeroValue(t.ToType), nil
+	}
+
+	if isIntType(t.ToType) {
+		var err error
+		if input, err = normalizeIntInput(input); err != nil {
+			return nil, err
+		}
+	}
+
+	from := transformIOType(input)
 	if !from.IsValid() {
 		return nil, errors.Errorf(errFmtConvertInputTypeNotSupported, input)
 	}
@@ -366,6 +1585,76 @@ func ResolveConvert(t v1.ConvertTransform, input any) (any, error) {
 	return f(input)
 }
 
+// transformIOType returns the name of the type of the supplied input as a
+// TransformIOType, which is only valid if a transform supports it. The names
+// of the supported types are returned without reflection.
+func transformIOType(input any) v1.TransformIOType {
+	switch input.(type) {
+	case string:
+		return v1.TransformIOTypeString
+	case bool:
+		return v1.TransformIOTypeBool
+	case int:
+		return v1.TransformIOTypeInt
+	case int64:
+		return v1.TransformIOTypeInt64
+	case int32:
+		return v1.TransformIOTypeInt32
+	case int16:
+		return v1.TransformIOTypeInt16
+	case float64:
+		return v1.TransformIOTypeFloat64
+	}
+	return v1.TransformIOType(reflect.TypeOf(input).String())
+}
+
+// isIntType returns true if the supplied type is an integer type.
+func isIntType(t v1.TransformIOType) bool {
+	switch t { //nolint:exhaustive // Only integer types are of interest.
+	case v1.TransformIOTypeInt, v1.TransformIOTypeInt64, v1.TransformIOTypeInt32, v1.TransformIOTypeInt16:
+		return true
+	}
+	return false
+}
+
+// normalizeIntInput normalizes input of any numeric or string kind to an
+// int64, float64, or string, such that it can be converted to an integer
+// type regardless of how a provider happens to represent it. Input of other
+// kinds is returned unchanged.
+func normalizeIntInput(input any) (any, error) {
+	v := reflect.ValueOf(input)
+	switch v.Kind() { //nolint:exhaustive // Other kinds are returned unchanged.
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return nil, errors.Errorf(errFmtConvertOverflow, input, v1.TransformIOTypeInt64)
+		}
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	}
+	return input, nil
+}
+
+// convertZeroValue returns the zero value of the supplied type, as output by
+// a conversion to that type.
+func convertZeroValue(to v1.TransformIOType) any {
+	switch to {
+	case v1.TransformIOTypeInt, v1.TransformIOTypeInt64, v1.TransformIOTypeInt32, v1.TransformIOTypeInt16:
+		return int64(0)
+	case v1.TransformIOTypeFloat64:
+		return float64(0)
+	case v1.TransformIOTypeBool:
+		return false
+	case v1.TransformIOTypeString:
+		return ""
+	}
+	return nil
+}
+
 type conversionPair struct {
 	from   v1.TransformIOType
 	to     v1.TransformIOType
@@ -383,10 +1672,20 @@ func GetConversionFunc(t *v1.ConvertTransform, from v1.TransformIOType) (func(an
 	if from == v1.TransformIOTypeInt {
 		from = v1.TransformIOTypeInt64
 	}
+	if to == v1.TransformIOTypeInt32 || to == v1.TransformIOTypeInt16 {
+		p := conversionPair{from: from, to: to, format: t.GetFormat()}
+		if from == v1.TransformIOTypeInt64 {
+			// An int64 is converted to an int64 regardless of format.
+			p.format = v1.ConvertTransformFormatNone
+		}
+		f, ok := narrowConversions[p]
+		if !ok {
+			return nil, errors.Errorf(v1.ErrFmtConvertFormatPairNotSupported, originalFrom, to, t.GetFormat())
+		}
+		return f, nil
+	}
 	if to == from {
-		return func(input any) (any, error) {
-			return input, nil
-		}, nil
+		return convertIdentity, nil
 	}
 	f, ok := conversions[conversionPair{from: from, to: to, format: t.GetFormat()}]
 	if !ok {
@@ -395,13 +1694,67 @@ func GetConversionFunc(t *v1.ConvertTransform, from v1.TransformIOType) (func(an
 	return f, nil
 }
 
+// convertIdentity is the conversion of a type to itself.
+func convertIdentity(input any) (any, error) {
+	return input, nil
+}
+
+// narrowConversions are the conversions to integer types narrower than int64.
+// They're derived from the conversions to int64 up front, rather than for
+// every conversion.
+var narrowConversions = func() map[conversionPair]func(any) (any, error) {
+	m := make(map[conversionPair]func(any) (any, error))
+	for _, to := range []v1.TransformIOType{v1.TransformIOTypeInt32, v1.TransformIOTypeInt16} {
+		m[conversionPair{from: v1.TransformIOTypeInt64, to: to, format: v1.ConvertTransformFormatNone}] = convertWithinRange(convertIdentity, to)
+		for p, f := range conversions {
+			if p.to == v1.TransformIOTypeInt64 {
+				m[conversionPair{from: p.from, to: to, format: p.format}] = convertWithinRange(f, to)
+			}
+		}
+	}
+	return m
+}()
+
+// convertWithinRange wraps the supplied conversion to int64 such that it
+// returns an error if the converted value doesn't fit within the supplied
+// narrower integer type. The converted value is still returned as an int64.
+func convertWithinRange(f func(any) (any, error), to v1.TransformIOType) func(any) (any, error) {
+	lo, hi := int64(math.MinInt32), int64(math.MaxInt32)
+	if to == v1.TransformIOTypeInt16 {
+		lo, hi = math.MinInt16, math.MaxInt16
+	}
+	return func(input any) (any, error) {
+		// Floats are checked before they're converted, because converting a
+		// float that doesn't fit within an int64 is undefined.
+		if v, ok := input.(float64); ok && (v < float64(lo) || v > float64(hi)) {
+			return nil, errors.Errorf(errFmtConvertOverflow, input, to)
+		}
+		out, err := f(input)
+		if err != nil {
+			return nil, err
+		}
+		if v := out.(int64); v < lo || v > hi {
+			return nil, errors.Errorf(errFmtConvertOverflow, input, to)
+		}
+		return out, nil
+	}
+}
+
 // The unparam linter is complaining that these functions always return a nil
 // error, but we need this to be the case given some other functions in the map
 // may return an error.
 var conversions = map[conversionPair]func(any) (any, error){
 	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeInt64, format: v1.ConvertTransformFormatNone}: func(i any) (any, error) {
-
-		return strconv.ParseInt(i.(string), 10, 64)
+		v, err := strconv.ParseInt(i.(string), 10, 64)
+		if err == nil {
+			return v, nil
+		}
+		// Fall back to parsing a float, which is truncated just like a
+		// float input would be.
+		if f, ferr := strconv.ParseFloat(i.(string), 64); ferr == nil && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f), nil
+		}
+		return v, err
 	},
 	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeBool, format: v1.ConvertTransformFormatNone}: func(i any) (any, error) {
 		return strconv.ParseBool(i.(string))
@@ -417,6 +1770,24 @@ var conversions = map[conversionPair]func(any) (any, error){
 		return q.AsApproximateFloat64(), nil
 	},
 
+	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeInt64, format: v1.ConvertTransformFormatIPv4}: func(i any) (any, error) {
+		ip, err := netip.ParseAddr(i.(string))
+		if err != nil || !ip.Is4() {
+			return nil, errors.Errorf(errFmtConvertInvalidIPv4, i)
+		}
+		b := ip.As4()
+		return int64(binary.BigEndian.Uint32(b[:])), nil
+	},
+	{from: v1.TransformIOTypeInt64, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatIPv4}: func(i any) (any, error) {
+		v := i.(int64)
+		if v < 0 || v > math.MaxUint32 {
+			return nil, errors.Errorf(errFmtConvertIPv4OutOfRange, v)
+		}
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(v))
+		return netip.AddrFrom4(b).String(), nil
+	},
+
 	{from: v1.TransformIOTypeInt64, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatNone}: func(i any) (any, error) { //nolint:unparam // See note above.
 		return strconv.FormatInt(i.(int64), 10), nil
 	},
@@ -17,17 +17,34 @@ limitations under the License.
 package composite
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Masterminds/semver"
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -135,6 +152,81 @@ func TestMapResolve(t *testing.T) {
 	}
 }
 
+func TestMapCaseInsensitiveResolve(t *testing.T) {
+	pairs := map[string]extv1.JSON{
+		"us-east-1": {Raw: []byte(`"virginia"`)},
+		"EU-WEST-1": {Raw: []byte(`"ireland"`)},
+	}
+
+	type args struct {
+		t v1.MapTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"ExactMatch": {
+			reason: "An input that matches a key exactly should return its value.",
+			args: args{
+				t: v1.MapTransform{Pairs: pairs},
+				i: "us-east-1",
+			},
+			want: want{
+				o: "virginia",
+			},
+		},
+		"DifferentCase": {
+			reason: "An input that differs from a key only by case should return its value.",
+			args: args{
+				t: v1.MapTransform{Pairs: pairs},
+				i: "eu-West-1",
+			},
+			want: want{
+				o: "ireland",
+			},
+		},
+		"KeyNotFound": {
+			reason: "An input that doesn't match any key regardless of case should return an error.",
+			args: args{
+				t: v1.MapTransform{Pairs: pairs},
+				i: "ap-south-1",
+			},
+			want: want{
+				err: errors.Errorf(errFmtMapNotFound, "ap-south-1"),
+			},
+		},
+		"NonStringInput": {
+			reason: "A non-string input should return an error.",
+			args: args{
+				t: v1.MapTransform{Pairs: pairs},
+				i: 5,
+			},
+			want: want{
+				err: errors.Errorf(errFmtMapTypeNotSupported, "int"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveMapCaseInsensitive(tc.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\nResolveMapCaseInsensitive(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveMapCaseInsensitive(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestMatchResolve(t *testing.T) {
 	asJSON := func(val interface{}) extv1.JSON {
 		raw, err := json.Marshal(val)
@@ -457,15 +549,23 @@ func TestMatchResolve(t *testing.T) {
 	}
 }
 
-func TestMathResolve(t *testing.T) {
-	two := int64(2)
+func TestRangeResolve(t *testing.T) {
+	asJSON := func(val any) extv1.JSON {
+		raw, err := json.Marshal(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return extv1.JSON{Raw: raw}
+	}
+
+	buckets := []v1.RangeTransformBucket{
+		{Max: 10, Value: asJSON("small")},
+		{Max: 50, Value: asJSON("medium")},
+	}
 
 	type args struct {
-		mathType   v1.MathTransformType
-		multiplier *int64
-		clampMin   *int64
-		clampMax   *int64
-		i          any
+		t v1.RangeTransform
+		i any
 	}
 	type want struct {
 		o   any
@@ -476,158 +576,204 @@ func TestMathResolve(t *testing.T) {
 		args
 		want
 	}{
-		"InvalidType": {
+		"ErrNonNumberInput": {
 			args: args{
-				mathType: "bad",
-				i:        25,
+				t: v1.RangeTransform{Buckets: buckets},
+				i: "ten",
 			},
 			want: want{
-				err: &field.Error{
-					Type:  field.ErrorTypeInvalid,
-					Field: "type",
-				},
+				err: errors.New(errRangeInputNonNumber),
 			},
 		},
-		"NonNumberInput": {
+		"ErrNoBuckets": {
 			args: args{
-				mathType:   v1.MathTransformTypeMultiply,
-				multiplier: &two,
-				i:          "ola",
+				t: v1.RangeTransform{},
+				i: 10,
 			},
 			want: want{
-				err: errors.New(errMathInputNonNumber),
+				err: errors.New(errRangeNoBuckets),
 			},
 		},
-		"MultiplyNoConfig": {
+		"FirstBucketInclusive": {
 			args: args{
-				mathType: v1.MathTransformTypeMultiply,
-				i:        25,
+				t: v1.RangeTransform{Buckets: buckets},
+				i: int64(10),
 			},
 			want: want{
-				err: &field.Error{
-					Type:  field.ErrorTypeRequired,
-					Field: "multiply",
-				},
+				o: "small",
 			},
 		},
-		"MultiplySuccess": {
+		"SecondBucketFloat": {
 			args: args{
-				mathType:   v1.MathTransformTypeMultiply,
-				multiplier: &two,
-				i:          3,
+				t: v1.RangeTransform{Buckets: buckets},
+				i: 10.5,
 			},
 			want: want{
-				o: 3 * two,
+				o: "medium",
 			},
 		},
-		"MultiplySuccessInt64": {
+		"OrderPreserved": {
 			args: args{
-				mathType:   v1.MathTransformTypeMultiply,
-				multiplier: &two,
-				i:          int64(3),
+				t: v1.RangeTransform{Buckets: []v1.RangeTransformBucket{
+					{Max: 50, Value: asJSON("medium")},
+					{Max: 10, Value: asJSON("small")},
+				}},
+				i: 5,
 			},
 			want: want{
-				o: 3 * two,
+				o: "medium",
 			},
 		},
-		"ClampMinSuccess": {
+		"Fallback": {
 			args: args{
-				mathType: v1.MathTransformTypeClampMin,
-				clampMin: &two,
-				i:        1,
+				t: v1.RangeTransform{Buckets: buckets, FallbackValue: asJSON("large")},
+				i: 51,
 			},
 			want: want{
-				o: int64(2),
+				o: "large",
 			},
 		},
-		"ClampMinSuccessNoChange": {
+		"ErrNoFallback": {
 			args: args{
-				mathType: v1.MathTransformTypeClampMin,
-				clampMin: &two,
-				i:        3,
+				t: v1.RangeTransform{Buckets: buckets},
+				i: 51,
 			},
 			want: want{
-				o: int64(3),
+				err: errors.Errorf(errFmtRangeNoBucket, 51),
 			},
 		},
-		"ClampMinSuccessInt64": {
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveRange(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCIDRMatchResolve(t *testing.T) {
+	asJSON := func(val any) extv1.JSON {
+		raw, err := json.Marshal(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return extv1.JSON{Raw: raw}
+	}
+
+	blocks := []v1.CIDRMatchTransformBlock{
+		{CIDR: "10.0.0.0/16", Value: asJSON("us-east-1")},
+		{CIDR: "10.0.0.0/8", Value: asJSON("internal")},
+		{CIDR: "fd00::/8", Value: asJSON("ula")},
+	}
+
+	type args struct {
+		t v1.CIDRMatchTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonStringInput": {
 			args: args{
-				mathType: v1.MathTransformTypeClampMin,
-				clampMin: &two,
-				i:        int64(1),
+				t: v1.CIDRMatchTransform{Blocks: blocks},
+				i: 10,
 			},
 			want: want{
-				o: int64(2),
+				err: errors.New(errCIDRMatchInputNonString),
 			},
 		},
-		"ClampMinNoConfig": {
+		"ErrNonIPInput": {
 			args: args{
-				mathType: v1.MathTransformTypeClampMin,
-				i:        25,
+				t: v1.CIDRMatchTransform{Blocks: blocks},
+				i: "10.0.0",
 			},
 			want: want{
-				err: &field.Error{
-					Type:  field.ErrorTypeRequired,
-					Field: "clampMin",
-				},
+				err: errors.Errorf(errFmtCIDRMatchInputNonIP, "10.0.0"),
 			},
 		},
-		"ClampMaxSuccess": {
+		"ErrNoBlocks": {
 			args: args{
-				mathType: v1.MathTransformTypeClampMax,
-				clampMax: &two,
-				i:        3,
+				t: v1.CIDRMatchTransform{},
+				i: "10.0.0.1",
 			},
 			want: want{
-				o: int64(2),
+				err: errors.New(errCIDRMatchNoBlocks),
 			},
 		},
-		"ClampMaxSuccessNoChange": {
+		"ErrInvalidCIDR": {
 			args: args{
-				mathType: v1.MathTransformTypeClampMax,
-				clampMax: &two,
-				i:        1,
+				t: v1.CIDRMatchTransform{Blocks: []v1.CIDRMatchTransformBlock{{CIDR: "10.0.0.0"}}},
+				i: "10.0.0.1",
 			},
 			want: want{
-				o: int64(1),
+				err: errors.Wrapf(&net.ParseError{Type: "CIDR address", Text: "10.0.0.0"}, errFmtCIDRMatchParseCIDR, 0),
 			},
 		},
-		"ClampMaxSuccessInt64": {
+		"FirstContainingBlock": {
 			args: args{
-				mathType: v1.MathTransformTypeClampMax,
-				clampMax: &two,
-				i:        int64(3),
+				t: v1.CIDRMatchTransform{Blocks: blocks},
+				i: "10.0.1.1",
 			},
 			want: want{
-				o: int64(2),
+				o: "us-east-1",
 			},
 		},
-		"ClampMaxNoConfig": {
+		"OrderPreserved": {
 			args: args{
-				mathType: v1.MathTransformTypeClampMax,
-				i:        25,
+				t: v1.CIDRMatchTransform{Blocks: blocks},
+				i: "10.1.0.1",
 			},
 			want: want{
-				err: &field.Error{
-					Type:  field.ErrorTypeRequired,
-					Field: "clampMax",
-				},
+				o: "internal",
+			},
+		},
+		"IPv6": {
+			args: args{
+				t: v1.CIDRMatchTransform{Blocks: blocks},
+				i: "fd12:3456::1",
+			},
+			want: want{
+				o: "ula",
+			},
+		},
+		"Fallback": {
+			args: args{
+				t: v1.CIDRMatchTransform{Blocks: blocks, FallbackValue: asJSON("external")},
+				i: "192.168.0.1",
+			},
+			want: want{
+				o: "external",
+			},
+		},
+		"ErrNoFallback": {
+			args: args{
+				t: v1.CIDRMatchTransform{Blocks: blocks},
+				i: "192.168.0.1",
+			},
+			want: want{
+				err: errors.Errorf(errFmtCIDRMatchNoBlock, "192.168.0.1"),
 			},
 		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			tr := v1.MathTransform{Type: tc.mathType, Multiply: tc.multiplier, ClampMin: tc.clampMin, ClampMax: tc.clampMax}
-			got, err := ResolveMath(tr, tc.i)
+			got, err := ResolveCIDRMatch(tc.args.t, tc.i)
 
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
 			}
-			fieldErr := &field.Error{}
-			if err != nil && errors.As(err, &fieldErr) {
-				fieldErr.Detail = ""
-				fieldErr.BadValue = nil
-			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
 			}
@@ -635,354 +781,472 @@ func TestMathResolve(t *testing.T) {
 	}
 }
 
-func TestStringResolve(t *testing.T) {
+func TestPEMResolve(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "db.example.org"},
+		DNSNames:     []string{"db.example.org", "db"},
+		NotBefore:    time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
 
 	type args struct {
-		stype   v1.StringTransformType
-		fmts    *string
-		convert *v1.StringConversionType
-		trim    *string
-		regexp  *v1.StringTransformRegexp
-		i       any
+		attr v1.PEMTransformAttribute
+		i    any
 	}
 	type want struct {
-		o   string
+		o   any
 		err error
 	}
-	sFmt := "verycool%s"
-	iFmt := "the largest %d"
-
-	upper := v1.StringConversionTypeToUpper
-	lower := v1.StringConversionTypeToLower
-	tobase64 := v1.StringConversionTypeToBase64
-	frombase64 := v1.StringConversionTypeFromBase64
-	toJSON := v1.StringConversionTypeToJSON
-	wrongConvertType := v1.StringConversionType("Something")
-	toSha1 := v1.StringConversionTypeToSHA1
-	toSha256 := v1.StringConversionTypeToSHA256
-	toSha512 := v1.StringConversionTypeToSHA512
-
-	prefix := "https://"
-	suffix := "-test"
 
 	cases := map[string]struct {
 		args
 		want
 	}{
-		"NotSupportedType": {
+		"ErrNonStringInput": {
 			args: args{
-				stype: "Something",
-				i:     "value",
+				attr: v1.PEMTransformAttributeCommonName,
+				i:    42,
 			},
 			want: want{
-				err: errors.Errorf(errStringTransformTypeFailed, "Something"),
+				err: errors.New(errPEMInputNonString),
 			},
 		},
-		"FmtFailed": {
+		"ErrNotPEM": {
 			args: args{
-				stype: v1.StringTransformTypeFormat,
-				i:     "value",
+				attr: v1.PEMTransformAttributeCommonName,
+				i:    "not a certificate",
 			},
 			want: want{
-				err: errors.Errorf(errStringTransformTypeFormat, string(v1.StringTransformTypeFormat)),
+				err: errors.New(errPEMDecode),
 			},
 		},
-		"FmtString": {
+		"ErrNotCertificate": {
 			args: args{
-				stype: v1.StringTransformTypeFormat,
-				fmts:  &sFmt,
-				i:     "thing",
+				attr: v1.PEMTransformAttributeCommonName,
+				i:    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("secret")})),
 			},
 			want: want{
-				o: "verycoolthing",
+				err: errors.Errorf(errFmtPEMBlockType, "PRIVATE KEY", "CERTIFICATE"),
 			},
 		},
-		"FmtInteger": {
+		"ErrUnknownAttribute": {
 			args: args{
-				stype: v1.StringTransformTypeFormat,
-				fmts:  &iFmt,
-				i:     8,
+				attr: "Nope",
+				i:    cert,
 			},
 			want: want{
-				o: "the largest 8",
+				err: errors.Errorf(errFmtPEMAttributeUnknown, "Nope"),
 			},
 		},
-		"ConvertNotSet": {
+		"CommonName": {
 			args: args{
-				stype: v1.StringTransformTypeConvert,
-				i:     "crossplane",
+				attr: v1.PEMTransformAttributeCommonName,
+				i:    cert,
 			},
 			want: want{
-				err: errors.Errorf(errStringTransformTypeConvert, string(v1.StringTransformTypeConvert)),
+				o: "db.example.org",
 			},
 		},
-		"ConvertTypFailed": {
+		"IssuerCommonName": {
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &wrongConvertType,
-				i:       "crossplane",
+				attr: v1.PEMTransformAttributeIssuerCommonName,
+				i:    cert,
 			},
 			want: want{
-				err: errors.Errorf(errStringConvertTypeFailed, wrongConvertType),
+				o: "db.example.org",
 			},
 		},
-		"ConvertToUpper": {
+		"SerialNumber": {
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &upper,
-				i:       "crossplane",
+				attr: v1.PEMTransformAttributeSerialNumber,
+				i:    cert,
 			},
 			want: want{
-				o: "CROSSPLANE",
+				o: "42",
 			},
 		},
-		"ConvertToLower": {
+		"NotBefore": {
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &lower,
-				i:       "CrossPlane",
+				attr: v1.PEMTransformAttributeNotBefore,
+				i:    cert,
 			},
 			want: want{
-				o: "crossplane",
+				o: "2023-01-01T00:00:00Z",
 			},
 		},
-		"ConvertToBase64": {
+		"NotAfter": {
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &tobase64,
-				i:       "CrossPlane",
+				attr: v1.PEMTransformAttributeNotAfter,
+				i:    cert,
 			},
 			want: want{
-				o: "Q3Jvc3NQbGFuZQ==",
+				o: "2024-01-01T00:00:00Z",
 			},
 		},
-		"ConvertFromBase64": {
+		"DNSNames": {
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &frombase64,
-				i:       "Q3Jvc3NQbGFuZQ==",
+				attr: v1.PEMTransformAttributeDNSNames,
+				i:    cert,
 			},
 			want: want{
-				o: "CrossPlane",
+				o: []any{"db.example.org", "db"},
 			},
 		},
-		"ConvertFromBase64Error": {
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolvePEM(v1.PEMTransform{Attribute: tc.attr}, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConditionStatusResolve(t *testing.T) {
+	health := v1.ConditionStatusTransform{
+		True:    &extv1.JSON{Raw: []byte(`1`)},
+		False:   &extv1.JSON{Raw: []byte(`0`)},
+		Default: &extv1.JSON{Raw: []byte(`-1`)},
+	}
+
+	type args struct {
+		t v1.ConditionStatusTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"True": {
+			reason: "A True status should return the true value.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &frombase64,
-				i:       "ThisStringIsNotBase64",
+				t: health,
+				i: "True",
 			},
 			want: want{
-				o:   "N\x18\xacJ\xda\xe2\x9e\x02,6\x8bAjǺ",
-				err: errors.Wrap(errors.New("illegal base64 data at input byte 20"), errDecodeString),
+				o: float64(1),
 			},
 		},
-		"ConvertToSha1": {
+		"CoreConditionStatus": {
+			reason: "A corev1.ConditionStatus input should be treated like a string.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &toSha1,
-				i:       "Crossplane",
+				t: health,
+				i: corev1.ConditionFalse,
 			},
 			want: want{
-				o: "f9fd1da3c0cc298643ff098a0c59febf1d8b7b84",
+				o: float64(0),
 			},
 		},
-		"ConvertToSha1Error": {
+		"MetaConditionStatus": {
+			reason: "A metav1.ConditionStatus input should be treated like a string.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &toSha1,
-				i:       func() {},
+				t: health,
+				i: metav1.ConditionTrue,
 			},
 			want: want{
-				o:   "0000000000000000000000000000000000000000",
-				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
+				o: float64(1),
 			},
 		},
-		"ConvertToSha256": {
+		"MissingStatusUsesDefault": {
+			reason: "A status with no value should return the default.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &toSha256,
-				i:       "Crossplane",
+				t: health,
+				i: "Unknown",
 			},
 			want: want{
-				o: "e84ae541a0725d73154ee76b7ac3fec4b007dd01ed701d506cd7e7a45bb48935",
+				o: float64(-1),
 			},
 		},
-		"ConvertToSha256Error": {
+		"UnknownInputUsesDefault": {
+			reason: "An input that isn't a known status should return the default.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &toSha256,
-				i:       func() {},
+				t: health,
+				i: "Maybe",
 			},
 			want: want{
-				o:   "0000000000000000000000000000000000000000000000000000000000000000",
-				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
+				o: float64(-1),
 			},
 		},
-		"ConvertToSha512": {
+		"NoValueOrDefault": {
+			reason: "A status with no value and no default should return an error.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &toSha512,
-				i:       "Crossplane",
+				t: v1.ConditionStatusTransform{True: &extv1.JSON{Raw: []byte(`"healthy"`)}},
+				i: "False",
 			},
 			want: want{
-				o: "b48622a3f487b8cb7748b356c9531cf54d9125c1456689c115744821f3dafd59c8c7d4dc5627c4a1e4082c67ee9f4528365a644a01a0c46d6dd0a6d979c8f51f",
+				err: errors.Errorf(errFmtConditionStatusNoValue, "False"),
 			},
 		},
-		"ConvertToSha512Error": {
+		"NonStringInput": {
+			reason: "A non-string input should return an error.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &toSha512,
-				i:       func() {},
+				t: health,
+				i: true,
 			},
 			want: want{
-				o:   "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
-				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
+				err: errors.New(errConditionStatusInputNonString),
 			},
 		},
-		"TrimPrefix": {
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveConditionStatus(tc.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\nResolveConditionStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveConditionStatus(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFieldSelectResolve(t *testing.T) {
+	required := v1.FromFieldPathPolicyRequired
+	input := map[string]any{
+		"endpoints": []any{map[string]any{"address": "db.example.org", "port": float64(5432)}},
+	}
+	_, errNotFound := fieldpath.Pave(input).GetValue("endpoints[1].address")
+
+	type args struct {
+		t v1.FieldSelectTransform
+		i any
+	}
+	type want struct {
+		o        any
+		err      error
+		notFound bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Scalar": {
+			reason: "The value at the field path should be returned.",
 			args: args{
-				stype: v1.StringTransformTypeTrimPrefix,
-				trim:  &prefix,
-				i:     "https://crossplane.io",
+				t: v1.FieldSelectTransform{FieldPath: "endpoints[0].port"},
+				i: input,
 			},
 			want: want{
-				o: "crossplane.io",
+				o: float64(5432),
 			},
 		},
-		"TrimSuffix": {
+		"Object": {
+			reason: "An object at the field path should be returned.",
 			args: args{
-				stype: v1.StringTransformTypeTrimSuffix,
-				trim:  &suffix,
-				i:     "my-string-test",
+				t: v1.FieldSelectTransform{FieldPath: "endpoints[0]"},
+				i: input,
 			},
 			want: want{
-				o: "my-string",
+				o: map[string]any{"address": "db.example.org", "port": float64(5432)},
 			},
 		},
-		"TrimPrefixWithoutMatch": {
+		"OptionalNotFound": {
+			reason: "A field path that doesn't exist should return an error that satisfies fieldpath.IsNotFound if the policy is optional.",
 			args: args{
-				stype: v1.StringTransformTypeTrimPrefix,
-				trim:  &prefix,
-				i:     "crossplane.io",
+				t: v1.FieldSelectTransform{FieldPath: "endpoints[1].address"},
+				i: input,
 			},
 			want: want{
-				o: "crossplane.io",
+				err:      errors.Wrap(errNotFound, errFieldSelectNotFound),
+				notFound: true,
 			},
 		},
-		"TrimSuffixWithoutMatch": {
+		"RequiredNotFound": {
+			reason: "A field path that doesn't exist should return an error that doesn't satisfy fieldpath.IsNotFound if the policy is required.",
 			args: args{
-				stype: v1.StringTransformTypeTrimSuffix,
-				trim:  &suffix,
-				i:     "my-string",
+				t: v1.FieldSelectTransform{FieldPath: "endpoints[1].address", Policy: &required},
+				i: input,
 			},
 			want: want{
-				o: "my-string",
+				err: errors.Wrap(errors.New(errNotFound.Error()), errFieldSelectNotFound),
 			},
 		},
-		"RegexpNotCompiling": {
+		"NonObjectInput": {
+			reason: "A non-object input should return an error.",
 			args: args{
-				stype: v1.StringTransformTypeRegexp,
-				regexp: &v1.StringTransformRegexp{
-					Match: "[a-z",
-				},
-				i: "my-string",
+				t: v1.FieldSelectTransform{FieldPath: "address"},
+				i: `{"address": "db.example.org"}`,
 			},
 			want: want{
-				err: errors.Wrap(errors.New("error parsing regexp: missing closing ]: `[a-z`"), errStringTransformTypeRegexpFailed),
+				err: errors.New(errFieldSelectInputNonObject),
 			},
 		},
-		"RegexpSimpleMatch": {
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveFieldSelect(tc.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\nResolveFieldSelect(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveFieldSelect(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.notFound, fieldpath.IsNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nfieldpath.IsNotFound(ResolveFieldSelect(...)): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSelectMatchResolve(t *testing.T) {
+	required := v1.FromFieldPathPolicyRequired
+	input := []any{
+		"not-an-object",
+		map[string]any{"type": "InternalIP", "address": "10.0.0.1"},
+		map[string]any{"type": "ExternalIP", "address": "203.0.113.1"},
+		map[string]any{"type": "ExternalIP", "address": "203.0.113.2"},
+		map[string]any{"type": "Hostname"},
+	}
+	_, errNotFound := fieldpath.Pave(map[string]any{"type": "Hostname"}).GetValue("address")
+
+	type args struct {
+		t v1.SelectMatchTransform
+		i any
+	}
+	type want struct {
+		o        any
+		err      error
+		notFound bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Element": {
+			reason: "The first matching element should be returned.",
 			args: args{
-				stype: v1.StringTransformTypeRegexp,
-				regexp: &v1.StringTransformRegexp{
-					Match: "[0-9]",
-				},
-				i: "my-1-string",
+				t: v1.SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"ExternalIP"`)}},
+				i: input,
 			},
 			want: want{
-				o: "1",
+				o: map[string]any{"type": "ExternalIP", "address": "203.0.113.1"},
 			},
 		},
-		"RegexpCaptureGroup": {
+		"ReturnField": {
+			reason: "The returnField of the first matching element should be returned.",
 			args: args{
-				stype: v1.StringTransformTypeRegexp,
-				regexp: &v1.StringTransformRegexp{
-					Match: "my-([0-9]+)-string",
-					Group: pointer.Int(1),
-				},
-				i: "my-1-string",
+				t: v1.SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"InternalIP"`)}, ReturnField: pointer.String("address")},
+				i: input,
 			},
 			want: want{
-				o: "1",
+				o: "10.0.0.1",
 			},
 		},
-		"RegexpNoSuchCaptureGroup": {
+		"NumberValue": {
+			reason: "A number should match an equal number regardless of its Go type.",
 			args: args{
-				stype: v1.StringTransformTypeRegexp,
-				regexp: &v1.StringTransformRegexp{
-					Match: "my-([0-9]+)-string",
-					Group: pointer.Int(2),
-				},
-				i: "my-1-string",
+				t: v1.SelectMatchTransform{FieldPath: "port", Value: extv1.JSON{Raw: []byte(`5432`)}, ReturnField: pointer.String("name")},
+				i: []any{map[string]any{"name": "http", "port": int64(80)}, map[string]any{"name": "postgres", "port": int64(5432)}},
 			},
 			want: want{
-				err: errors.Errorf(errStringTransformTypeRegexpNoMatch, "my-([0-9]+)-string", 2),
+				o: "postgres",
 			},
 		},
-		"ConvertToJSONSuccess": {
+		"OptionalNoMatch": {
+			reason: "No matching element should return an error that satisfies fieldpath.IsNotFound if the policy is optional.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &toJSON,
-				i: map[string]any{
-					"foo": "bar",
-				},
+				t: v1.SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"InternalDNS"`)}},
+				i: input,
 			},
 			want: want{
-				o: "{\"foo\":\"bar\"}",
+				err:      notFoundError{errors.New(errSelectMatchNoMatch)},
+				notFound: true,
 			},
 		},
-		"ConvertToJSONFail": {
+		"RequiredNoMatch": {
+			reason: "No matching element should return an error that doesn't satisfy fieldpath.IsNotFound if the policy is required.",
 			args: args{
-				stype:   v1.StringTransformTypeConvert,
-				convert: &toJSON,
-				i: map[string]any{
-					"foo": func() {},
-				},
+				t: v1.SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"InternalDNS"`)}, Policy: &required},
+				i: input,
 			},
 			want: want{
-				o:   "",
-				err: errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON),
+				err: errors.New(errSelectMatchNoMatch),
+			},
+		},
+		"OptionalReturnFieldNotFound": {
+			reason: "A returnField that doesn't exist should return an error that satisfies fieldpath.IsNotFound if the policy is optional.",
+			args: args{
+				t: v1.SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"Hostname"`)}, ReturnField: pointer.String("address")},
+				i: input,
+			},
+			want: want{
+				err:      errors.Wrap(errNotFound, errSelectMatchReturnNotFound),
+				notFound: true,
+			},
+		},
+		"RequiredReturnFieldNotFound": {
+			reason: "A returnField that doesn't exist should return an error that doesn't satisfy fieldpath.IsNotFound if the policy is required.",
+			args: args{
+				t: v1.SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"Hostname"`)}, ReturnField: pointer.String("address"), Policy: &required},
+				i: input,
+			},
+			want: want{
+				err: errors.Wrap(errors.New(errNotFound.Error()), errSelectMatchReturnNotFound),
+			},
+		},
+		"NonArrayInput": {
+			reason: "A non-array input should return an error.",
+			args: args{
+				t: v1.SelectMatchTransform{FieldPath: "type", Value: extv1.JSON{Raw: []byte(`"ExternalIP"`)}},
+				i: map[string]any{"type": "ExternalIP"},
+			},
+			want: want{
+				err: errors.New(errSelectMatchInputNonArray),
 			},
 		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-
-			tr := v1.StringTransform{Type: tc.stype,
-				Format:  tc.fmts,
-				Convert: tc.convert,
-				Trim:    tc.trim,
-				Regexp:  tc.regexp,
-			}
-
-			got, err := ResolveString(tr, tc.i)
+			got, err := ResolveSelectMatch(tc.t, tc.i)
 
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
-				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+				t.Errorf("\n%s\nResolveSelectMatch(...): -want, +got:\n%s", tc.reason, diff)
 			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+				t.Errorf("\n%s\nResolveSelectMatch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.notFound, fieldpath.IsNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nfieldpath.IsNotFound(ResolveSelectMatch(...)): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
 
-func TestConvertResolve(t *testing.T) {
+func TestLabelSelectorResolve(t *testing.T) {
+	toSelector := v1.LabelSelectorTransform{Mode: v1.LabelSelectorTransformModeToSelector}
+	fromSelector := v1.LabelSelectorTransform{Mode: v1.LabelSelectorTransformModeFromSelector}
+
 	type args struct {
-		to     v1.TransformIOType
-		format *v1.ConvertTransformFormat
-		i      any
+		t v1.LabelSelectorTransform
+		i any
 	}
 	type want struct {
 		o   any
@@ -990,219 +1254,3065 @@ func TestConvertResolve(t *testing.T) {
 	}
 
 	cases := map[string]struct {
+		reason string
 		args
 		want
 	}{
-		"StringToBool": {
+		"ToSelector": {
+			reason: "A map of labels should be converted to a selector whose matchLabels are the labels.",
 			args: args{
-				i:  "true",
-				to: v1.TransformIOTypeBool,
+				t: toSelector,
+				i: map[string]any{"app": "db", "example.org/tier": "backend"},
 			},
 			want: want{
-				o: true,
+				o: map[string]any{"matchLabels": map[string]any{"app": "db", "example.org/tier": "backend"}},
 			},
 		},
-		"StringToFloat64": {
+		"ToSelectorNonStringLabel": {
+			reason: "A label whose value isn't a string should return an error.",
 			args: args{
-				i:  "1000",
-				to: v1.TransformIOTypeFloat64,
+				t: toSelector,
+				i: map[string]any{"replicas": float64(3)},
 			},
 			want: want{
-				o: 1000.0,
+				err: errors.Errorf(errFmtLabelSelectorLabelNonString, "replicas"),
 			},
 		},
-		"StringToQuantityFloat64": {
+		"ToSelectorInvalidLabel": {
+			reason: "A label with an invalid key should return an error.",
 			args: args{
-				i:      "1000m",
-				to:     v1.TransformIOTypeFloat64,
-				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatQuantity))),
+				t: toSelector,
+				i: map[string]any{"not a key": "db"},
 			},
 			want: want{
-				o: 1.0,
+				err: errors.Wrap(metav1validation.ValidateLabels(map[string]string{"not a key": "db"}, nil).ToAggregate(), errLabelSelectorInvalidLabels),
 			},
 		},
-		"StringToQuantityFloat64InvalidFormat": {
+		"FromSelector": {
+			reason: "A selector's matchLabels and single valued In requirements should be converted to a map of labels.",
 			args: args{
-				i:      "1000 blabla",
-				to:     v1.TransformIOTypeFloat64,
-				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatQuantity))),
+				t: fromSelector,
+				i: map[string]any{
+					"matchLabels":      map[string]any{"app": "db"},
+					"matchExpressions": []any{map[string]any{"key": "tier", "operator": "In", "values": []any{"backend"}}},
+				},
 			},
 			want: want{
-				err: resource.ErrFormatWrong,
+				o: map[string]any{"app": "db", "tier": "backend"},
 			},
 		},
-		"SameTypeNoOp": {
+		"FromSelectorUnknownField": {
+			reason: "A selector with a field a label selector doesn't have should return an error.",
 			args: args{
-				i:  true,
-				to: v1.TransformIOTypeBool,
+				t: fromSelector,
+				i: map[string]any{"matchLabel": map[string]any{"app": "db"}},
 			},
 			want: want{
-				o: true,
+				err: errors.Wrap(errors.New(`json: unknown field "matchLabel"`), errLabelSelectorDecode),
 			},
 		},
-		"IntAliasToInt64": {
+		"FromSelectorInvalidOperator": {
+			reason: "A selector with a requirement that can't be converted to a label should return an error.",
 			args: args{
-				i:  int64(1),
-				to: v1.TransformIOTypeInt,
+				t: fromSelector,
+				i: map[string]any{
+					"matchExpressions": []any{map[string]any{"key": "tier", "operator": "Exists"}},
+				},
 			},
 			want: want{
-				o: int64(1),
+				err: errors.Wrap(errors.New(`operator "Exists" cannot be converted into the old label selector format`), errLabelSelectorToLabels),
 			},
 		},
-		"InputTypeNotSupported": {
+		"NonObjectInput": {
+			reason: "A non-object input should return an error.",
 			args: args{
-				i:  []int{64},
-				to: v1.TransformIOTypeString,
+				t: toSelector,
+				i: "app=db",
 			},
 			want: want{
-				err: errors.Errorf(errFmtConvertInputTypeNotSupported, []int{}),
+				err: errors.New(errLabelSelectorInputNonObject),
 			},
 		},
-		"ConversionPairFormatNotSupported": {
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveLabelSelector(tc.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\nResolveLabelSelector(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveLabelSelector(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateFormatResolve(t *testing.T) {
+	type args struct {
+		t v1.ValidateFormatTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Hostname": {
+			reason: "A hostname should be returned in lower case without a trailing dot.",
 			args: args{
-				i:      100,
-				to:     v1.TransformIOTypeString,
-				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatQuantity))),
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatHostname},
+				i: " DB-1.Example.org. ",
 			},
 			want: want{
-				err: errors.Errorf(errFmtConvertFormatPairNotSupported, "int", "string", string(v1.ConvertTransformFormatQuantity)),
+				o: "db-1.example.org",
 			},
 		},
-		"ConversionPairNotSupported": {
+		"HostnameInvalidCharacter": {
+			reason: "A hostname with a character that isn't a letter, digit, or hyphen should be invalid.",
 			args: args{
-				i:  "[64]",
-				to: "[]int",
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatHostname},
+				i: "db_1.example.org",
 			},
 			want: want{
-				err: &field.Error{
-					Type:     field.ErrorTypeInvalid,
-					Field:    "toType",
-					BadValue: v1.TransformIOType("[]int"),
-					Detail:   "invalid type",
-				},
+				err: errors.Errorf(errFmtValidateFormatInvalid, "db_1.example.org", v1.ValidateFormatHostname),
 			},
 		},
-		"ConversionPairSupportedFloat64Int64": {
+		"HostnameLabelHyphen": {
+			reason: "A hostname with a label that starts with a hyphen should be invalid.",
 			args: args{
-				i:  float64(1.1),
-				to: v1.TransformIOTypeInt64,
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatHostname},
+				i: "-db.example.org",
 			},
 			want: want{
-				o: int64(1),
+				err: errors.Errorf(errFmtValidateFormatInvalid, "-db.example.org", v1.ValidateFormatHostname),
+			},
+		},
+		"HostnameEmptyLabel": {
+			reason: "A hostname with an empty label should be invalid.",
+			args: args{
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatHostname},
+				i: "db..example.org",
+			},
+			want: want{
+				err: errors.Errorf(errFmtValidateFormatInvalid, "db..example.org", v1.ValidateFormatHostname),
+			},
+		},
+		"Email": {
+			reason: "An email address should be returned with its domain in lower case.",
+			args: args{
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatEmail},
+				i: "Ops.Team@Example.org",
+			},
+			want: want{
+				o: "Ops.Team@example.org",
+			},
+		},
+		"EmailDisplayName": {
+			reason: "An email address with a display name should be invalid.",
+			args: args{
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatEmail},
+				i: "Ops <ops@example.org>",
+			},
+			want: want{
+				err: errors.Errorf(errFmtValidateFormatInvalid, "Ops <ops@example.org>", v1.ValidateFormatEmail),
+			},
+		},
+		"EmailInvalidDomain": {
+			reason: "An email address whose domain isn't a hostname should be invalid.",
+			args: args{
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatEmail},
+				i: "ops@example_org",
+			},
+			want: want{
+				err: errors.Errorf(errFmtValidateFormatInvalid, "ops@example_org", v1.ValidateFormatEmail),
+			},
+		},
+		"EmailMissingAt": {
+			reason: "A string that isn't an email address should be invalid.",
+			args: args{
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatEmail},
+				i: "ops.example.org",
+			},
+			want: want{
+				err: errors.Errorf(errFmtValidateFormatInvalid, "ops.example.org", v1.ValidateFormatEmail),
+			},
+		},
+		"NonStringInput": {
+			reason: "A non-string input should return an error.",
+			args: args{
+				t: v1.ValidateFormatTransform{Format: v1.ValidateFormatHostname},
+				i: 42,
+			},
+			want: want{
+				err: errors.New(errValidateFormatInputNonString),
+			},
+		},
+		"UnknownFormat": {
+			reason: "An unknown format should return an error.",
+			args: args{
+				t: v1.ValidateFormatTransform{Format: "uuid"},
+				i: "db.example.org",
+			},
+			want: want{
+				err: errors.Errorf(errFmtValidateFormatUnknown, "uuid"),
 			},
 		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			tr := v1.ConvertTransform{ToType: tc.args.to, Format: tc.format}
-			got, err := ResolveConvert(tr, tc.i)
+			got, err := ResolveValidateFormat(tc.t, tc.i)
 
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
-				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+				t.Errorf("\n%s\nResolveValidateFormat(...): -want, +got:\n%s", tc.reason, diff)
 			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+				t.Errorf("\n%s\nResolveValidateFormat(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
 
-func TestConvertTransformGetConversionFunc(t *testing.T) {
+func TestStableSuffixResolve(t *testing.T) {
 	type args struct {
-		ct   *v1.ConvertTransform
-		from v1.TransformIOType
+		t v1.StableSuffixTransform
+		i any
 	}
 	type want struct {
+		o   any
 		err error
 	}
+
 	cases := map[string]struct {
 		reason string
-		args   args
-		want   want
+		args
+		want
 	}{
-		"IntToString": {
-			reason: "Int to String should be valid",
+		"DefaultCharset": {
+			reason: "A suffix of lower case letters and digits should be derived from the input.",
 			args: args{
-				ct: &v1.ConvertTransform{
-					ToType: v1.TransformIOTypeString,
-				},
-				from: v1.TransformIOTypeInt,
+				t: v1.StableSuffixTransform{Length: 5},
+				i: "my-claim",
+			},
+			want: want{
+				o: "3c5ek",
 			},
 		},
-		"IntToInt": {
-			reason: "Int to Int should be valid",
+		"DifferentInput": {
+			reason: "A different input should return a different suffix.",
 			args: args{
-				ct: &v1.ConvertTransform{
-					ToType: v1.TransformIOTypeInt,
-				},
-				from: v1.TransformIOTypeInt,
+				t: v1.StableSuffixTransform{Length: 5},
+				i: "my-other-claim",
+			},
+			want: want{
+				o: "5d3ac",
 			},
 		},
-		"IntToInt64": {
-			reason: "Int to Int64 should be valid",
+		"Charset": {
+			reason: "A suffix should only contain characters of the supplied charset.",
 			args: args{
-				ct: &v1.ConvertTransform{
-					ToType: v1.TransformIOTypeInt,
-				},
-				from: v1.TransformIOTypeInt64,
+				t: v1.StableSuffixTransform{Length: 8, Charset: pointer.String("AB")},
+				i: "my-claim",
+			},
+			want: want{
+				o: "BABAAAAA",
 			},
 		},
-		"Int64ToInt": {
-			reason: "Int64 to Int should be valid",
+		"InvalidLength": {
+			reason: "A length of zero should return an error.",
 			args: args{
-				ct: &v1.ConvertTransform{
-					ToType: v1.TransformIOTypeInt64,
-				},
-				from: v1.TransformIOTypeInt,
+				t: v1.StableSuffixTransform{Length: 0},
+				i: "my-claim",
+			},
+			want: want{
+				err: field.Invalid(field.NewPath("length"), 0, fmt.Sprintf("length must be between 1 and %d", v1.MaxStableSuffixLength)),
 			},
 		},
-		"IntToFloat": {
-			reason: "Int to Float should be valid",
+		"NonStringInput": {
+			reason: "A non-string input should return an error.",
 			args: args{
-				ct: &v1.ConvertTransform{
-					ToType: v1.TransformIOTypeInt,
-				},
-				from: v1.TransformIOTypeFloat64,
+				t: v1.StableSuffixTransform{Length: 5},
+				i: 42,
+			},
+			want: want{
+				err: errors.New(errStableSuffixInputNonString),
 			},
 		},
-		"IntToBool": {
-			reason: "Int to Bool should be valid",
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveStableSuffix(tc.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\nResolveStableSuffix(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveStableSuffix(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAllowlistResolve(t *testing.T) {
+	values := []extv1.JSON{
+		{Raw: []byte(`"prod"`)},
+		{Raw: []byte(`3`)},
+		{Raw: []byte(`{"tier": "gold", "region": "us"}`)},
+	}
+
+	type args struct {
+		t v1.AllowlistTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"AllowedString": {
+			reason: "A permitted string should be passed through unchanged.",
 			args: args{
-				ct: &v1.ConvertTransform{
-					ToType: v1.TransformIOTypeInt,
-				},
-				from: v1.TransformIOTypeBool,
+				t: v1.AllowlistTransform{Values: values},
+				i: "prod",
+			},
+			want: want{
+				o: "prod",
 			},
 		},
-		"StringToIntInvalidFormat": {
-			reason: "String to Int with invalid format should be invalid",
+		"AllowedNumber": {
+			reason: "A permitted number should be passed through unchanged, regardless of its Go type.",
 			args: args{
-				ct: &v1.ConvertTransform{
-					ToType: v1.TransformIOTypeInt,
-					Format: &[]v1.ConvertTransformFormat{"wrong"}[0],
-				},
-				from: v1.TransformIOTypeString,
+				t: v1.AllowlistTransform{Values: values},
+				i: int64(3),
 			},
 			want: want{
-				err: fmt.Errorf("conversion from string to int64 is not supported with format wrong"),
+				o: int64(3),
 			},
 		},
-		"IntToIntInvalidFormat": {
-			reason: "Int to Int, invalid format ignored because it is the same type",
+		"AllowedObject": {
+			reason: "A permitted object should be passed through unchanged, regardless of the order of its keys.",
 			args: args{
-				ct: &v1.ConvertTransform{
-					ToType: v1.TransformIOTypeInt,
-					Format: &[]v1.ConvertTransformFormat{"wrong"}[0],
-				},
-				from: v1.TransformIOTypeInt,
+				t: v1.AllowlistTransform{Values: values},
+				i: map[string]any{"region": "us", "tier": "gold"},
+			},
+			want: want{
+				o: map[string]any{"region": "us", "tier": "gold"},
+			},
+		},
+		"NotAllowed": {
+			reason: "A value that isn't permitted should return an error.",
+			args: args{
+				t: v1.AllowlistTransform{Values: values},
+				i: "dev",
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errValueNotAllowed), errFmtAllowlistInput, `"dev"`),
+			},
+		},
+		"NotAllowedDifferentType": {
+			reason: "A value that is only permitted as a different JSON type should return an error.",
+			args: args{
+				t: v1.AllowlistTransform{Values: values},
+				i: "3",
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errValueNotAllowed), errFmtAllowlistInput, `"3"`),
+			},
+		},
+		"NoValues": {
+			reason: "An allowlist with no values should return an error.",
+			args: args{
+				i: "prod",
+			},
+			want: want{
+				err: errors.New(errAllowlistNoValues),
+			},
+		},
+		"InvalidValue": {
+			reason: "A permitted value that isn't valid JSON should return an error.",
+			args: args{
+				t: v1.AllowlistTransform{Values: []extv1.JSON{{Raw: []byte(`prod`)}}},
+				i: "prod",
+			},
+			want: want{
+				err: errors.Wrapf(json.Unmarshal([]byte(`prod`), new(any)), errFmtAllowlistParseValue, 0),
 			},
 		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			_, err := GetConversionFunc(tc.args.ct, tc.args.from)
+			got, err := ResolveAllowlist(tc.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\nResolveAllowlist(...): -want, +got:\n%s", tc.reason, diff)
+			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("%s\nGetConversionFunc(...): -want, +got:\n%s", tc.reason, diff)
+				t.Errorf("\n%s\nResolveAllowlist(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAggregateResolve(t *testing.T) {
+	type args struct {
+		t v1.AggregateTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonArrayInput": {
+			args: args{
+				t: v1.AggregateTransform{},
+				i: int64(10),
+			},
+			want: want{
+				err: errors.New(errAggregateInputNonArray),
+			},
+		},
+		"ErrNonNumberElement": {
+			args: args{
+				t: v1.AggregateTransform{},
+				i: []any{int64(1), "two"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtAggregateElementNonNumber, 1),
+			},
+		},
+		"SumDefault": {
+			args: args{
+				t: v1.AggregateTransform{},
+				i: []any{int64(1), int64(2), int64(3)},
+			},
+			want: want{
+				o: int64(6),
+			},
+		},
+		"SumFloat": {
+			args: args{
+				t: v1.AggregateTransform{Type: v1.AggregateTransformTypeSum},
+				i: []any{int64(1), 2.5},
+			},
+			want: want{
+				o: 3.5,
+			},
+		},
+		"SumEmpty": {
+			args: args{
+				t: v1.AggregateTransform{Type: v1.AggregateTransformTypeSum},
+				i: []any{},
+			},
+			want: want{
+				o: int64(0),
+			},
+		},
+		"Max": {
+			args: args{
+				t: v1.AggregateTransform{Type: v1.AggregateTransformTypeMax},
+				i: []any{int64(3), int64(7), int64(-1)},
+			},
+			want: want{
+				o: int64(7),
+			},
+		},
+		"Min": {
+			args: args{
+				t: v1.AggregateTransform{Type: v1.AggregateTransformTypeMin},
+				i: []any{int64(3), 1.5, int64(7)},
+			},
+			want: want{
+				o: 1.5,
+			},
+		},
+		"ErrMaxEmpty": {
+			args: args{
+				t: v1.AggregateTransform{Type: v1.AggregateTransformTypeMax},
+				i: []any{},
+			},
+			want: want{
+				err: errors.New(errAggregateEmpty),
+			},
+		},
+		"Count": {
+			args: args{
+				t: v1.AggregateTransform{Type: v1.AggregateTransformTypeCount},
+				i: []any{1.5, 2.5},
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"ErrUnknownType": {
+			args: args{
+				t: v1.AggregateTransform{Type: "Median"},
+				i: []any{int64(1)},
+			},
+			want: want{
+				err: errors.Errorf(errFmtAggregateTransformTypeFail, "Median"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveAggregate(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTernaryResolve(t *testing.T) {
+	type args struct {
+		t v1.TernaryTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	tt := v1.TernaryTransform{
+		True:  extv1.JSON{Raw: []byte(`"enabled"`)},
+		False: extv1.JSON{Raw: []byte(`"disabled"`)},
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonBoolInput": {
+			args: args{
+				t: tt,
+				i: "true",
+			},
+			want: want{
+				err: errors.New(errTernaryInputNonBool),
+			},
+		},
+		"True": {
+			args: args{
+				t: tt,
+				i: true,
+			},
+			want: want{
+				o: "enabled",
+			},
+		},
+		"False": {
+			args: args{
+				t: tt,
+				i: false,
+			},
+			want: want{
+				o: "disabled",
+			},
+		},
+		"ObjectValue": {
+			args: args{
+				t: v1.TernaryTransform{
+					True:  extv1.JSON{Raw: []byte(`{"tier":"premium"}`)},
+					False: extv1.JSON{Raw: []byte(`null`)},
+				},
+				i: true,
+			},
+			want: want{
+				o: map[string]any{"tier": "premium"},
+			},
+		},
+		"ErrInvalidJSON": {
+			args: args{
+				t: v1.TernaryTransform{
+					True:  extv1.JSON{Raw: []byte(`{`)},
+					False: extv1.JSON{Raw: []byte(`null`)},
+				},
+				i: true,
+			},
+			want: want{
+				err: errors.Wrapf(json.Unmarshal([]byte(`{`), new(any)), errFmtTernaryParse, true),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveTernary(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTruncateResolve(t *testing.T) {
+	type args struct {
+		t v1.TruncateTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonStringInput": {
+			args: args{
+				t: v1.TruncateTransform{MaxLength: 4},
+				i: 12345,
+			},
+			want: want{
+				err: errors.New(errTruncateInputNonString),
+			},
+		},
+		"ErrInvalid": {
+			args: args{
+				t: v1.TruncateTransform{MaxLength: 4, HashSuffix: true},
+				i: "my-very-long-resource-name",
+			},
+			want: want{
+				err: &field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "maxLength",
+					BadValue: 4,
+					Detail:   "maxLength must be at least 10 to fit a hash suffix",
+				},
+			},
+		},
+		"WithinLimit": {
+			args: args{
+				t: v1.TruncateTransform{MaxLength: 26, HashSuffix: true},
+				i: "my-very-long-resource-name",
+			},
+			want: want{
+				o: "my-very-long-resource-name",
+			},
+		},
+		"Truncated": {
+			args: args{
+				t: v1.TruncateTransform{MaxLength: 12},
+				i: "my-very-long-resource-name",
+			},
+			want: want{
+				o: "my-very-long",
+			},
+		},
+		"TruncatedMultiByte": {
+			args: args{
+				t: v1.TruncateTransform{MaxLength: 3},
+				i: "héllo",
+			},
+			want: want{
+				o: "hél",
+			},
+		},
+		"TruncatedWithHashSuffix": {
+			args: args{
+				t: v1.TruncateTransform{MaxLength: 20, HashSuffix: true},
+				i: "my-very-long-resource-name",
+			},
+			want: want{
+				o: "my-very-lon-7c9761f3",
+			},
+		},
+		"TruncatedWithDifferentHashSuffix": {
+			args: args{
+				t: v1.TruncateTransform{MaxLength: 20, HashSuffix: true},
+				i: "my-very-long-resource-other",
+			},
+			want: want{
+				o: "my-very-lon-44350344",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveTruncate(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCaseResolve(t *testing.T) {
+	type args struct {
+		t v1.CaseTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonStringInput": {
+			args: args{
+				t: v1.CaseTransform{To: v1.CaseTransformTypeKebab},
+				i: 42,
+			},
+			want: want{
+				err: errors.New(errCaseInputNonString),
+			},
+		},
+		"ErrUnknownType": {
+			args: args{
+				t: v1.CaseTransform{To: "pascal"},
+				i: "my bucket",
+			},
+			want: want{
+				err: field.Invalid(field.NewPath("to"), v1.CaseTransformType("pascal"), "unknown case transform type"),
+			},
+		},
+		"Kebab": {
+			args: args{
+				t: v1.CaseTransform{To: v1.CaseTransformTypeKebab},
+				i: "My Storage_Bucket",
+			},
+			want: want{
+				o: "my-storage-bucket",
+			},
+		},
+		"Snake": {
+			args: args{
+				t: v1.CaseTransform{To: v1.CaseTransformTypeSnake},
+				i: "my-storage bucket",
+			},
+			want: want{
+				o: "my_storage_bucket",
+			},
+		},
+		"Camel": {
+			args: args{
+				t: v1.CaseTransform{To: v1.CaseTransformTypeCamel},
+				i: "My_storage-BUCKET name",
+			},
+			want: want{
+				o: "myStorageBucketName",
+			},
+		},
+		"MixedRepeatedDelimiters": {
+			args: args{
+				t: v1.CaseTransform{To: v1.CaseTransformTypeSnake},
+				i: "  -my__storage - \tbucket_ ",
+			},
+			want: want{
+				o: "my_storage_bucket",
+			},
+		},
+		"NoSplitOnCase": {
+			args: args{
+				t: v1.CaseTransform{To: v1.CaseTransformTypeKebab},
+				i: "myBucket name",
+			},
+			want: want{
+				o: "mybucket-name",
+			},
+		},
+		"CamelMultiByte": {
+			args: args{
+				t: v1.CaseTransform{To: v1.CaseTransformTypeCamel},
+				i: "straße élan",
+			},
+			want: want{
+				o: "straßeÉlan",
+			},
+		},
+		"OnlyDelimiters": {
+			args: args{
+				t: v1.CaseTransform{To: v1.CaseTransformTypeCamel},
+				i: " _-",
+			},
+			want: want{
+				o: "",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveCase(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSemverResolve(t *testing.T) {
+	type args struct {
+		t v1.SemverTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonStringInput": {
+			args: args{
+				t: v1.SemverTransform{Operation: v1.SemverTransformOperationNormalize},
+				i: 1,
+			},
+			want: want{
+				err: errors.New(errSemverInputNonString),
+			},
+		},
+		"ErrUnknownOperation": {
+			args: args{
+				t: v1.SemverTransform{Operation: "bump"},
+				i: "1.2.3",
+			},
+			want: want{
+				err: field.Invalid(field.NewPath("operation"), v1.SemverTransformOperation("bump"), "unknown semver transform operation"),
+			},
+		},
+		"ErrInvalidVersion": {
+			args: args{
+				t: v1.SemverTransform{Operation: v1.SemverTransformOperationNormalize},
+				i: "latest",
+			},
+			want: want{
+				err: errors.Wrapf(semver.ErrInvalidSemVer, errFmtSemverInvalid, "latest"),
+			},
+		},
+		"Normalize": {
+			args: args{
+				t: v1.SemverTransform{Operation: v1.SemverTransformOperationNormalize},
+				i: "v1.2.3",
+			},
+			want: want{
+				o: "1.2.3",
+			},
+		},
+		"NormalizePartial": {
+			args: args{
+				t: v1.SemverTransform{Operation: v1.SemverTransformOperationNormalize},
+				i: "v1.2",
+			},
+			want: want{
+				o: "1.2.0",
+			},
+		},
+		"NormalizePrereleaseAndMetadata": {
+			args: args{
+				t: v1.SemverTransform{Operation: v1.SemverTransformOperationNormalize},
+				i: "v1.2.3-rc.1+build.5",
+			},
+			want: want{
+				o: "1.2.3-rc.1+build.5",
+			},
+		},
+		"Major": {
+			args: args{
+				t: v1.SemverTransform{Operation: v1.SemverTransformOperationMajor},
+				i: "v10.20.30",
+			},
+			want: want{
+				o: int64(10),
+			},
+		},
+		"Minor": {
+			args: args{
+				t: v1.SemverTransform{Operation: v1.SemverTransformOperationMinor},
+				i: "10.20.30-alpha",
+			},
+			want: want{
+				o: int64(20),
+			},
+		},
+		"Patch": {
+			args: args{
+				t: v1.SemverTransform{Operation: v1.SemverTransformOperationPatch},
+				i: "10.20.30",
+			},
+			want: want{
+				o: int64(30),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveSemver(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDedupResolve(t *testing.T) {
+	type args struct {
+		t v1.DedupTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonArrayInput": {
+			args: args{
+				i: "a,b,a",
+			},
+			want: want{
+				err: errors.New(errDedupInputNonArray),
+			},
+		},
+		"ErrInvalidKeyFieldPath": {
+			args: args{
+				t: v1.DedupTransform{KeyFieldPath: pointer.String("")},
+				i: []any{},
+			},
+			want: want{
+				err: field.Required(field.NewPath("keyFieldPath"), "keyFieldPath must not be empty if specified"),
+			},
+		},
+		"Scalars": {
+			args: args{
+				i: []any{"b", "a", "b", int64(3), "3", float64(3), true, nil, "a", true, nil},
+			},
+			want: want{
+				o: []any{"b", "a", int64(3), "3", true, nil},
+			},
+		},
+		"Empty": {
+			args: args{
+				i: []any{},
+			},
+			want: want{
+				o: []any{},
+			},
+		},
+		"ErrObjectWithoutKey": {
+			args: args{
+				i: []any{"a", map[string]any{"name": "a"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtDedupElementNonScalar, 1),
+			},
+		},
+		"ErrArrayWithoutKey": {
+			args: args{
+				i: []any{[]any{"a"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtDedupElementNonScalar, 0),
+			},
+		},
+		"ObjectsByKey": {
+			args: args{
+				t: v1.DedupTransform{KeyFieldPath: pointer.String("metadata.name")},
+				i: []any{
+					map[string]any{"metadata": map[string]any{"name": "b"}, "value": int64(1)},
+					map[string]any{"metadata": map[string]any{"name": "a"}, "value": int64(2)},
+					map[string]any{"metadata": map[string]any{"name": "b"}, "value": int64(3)},
+				},
+			},
+			want: want{
+				o: []any{
+					map[string]any{"metadata": map[string]any{"name": "b"}, "value": int64(1)},
+					map[string]any{"metadata": map[string]any{"name": "a"}, "value": int64(2)},
+				},
+			},
+		},
+		"ErrScalarWithKey": {
+			args: args{
+				t: v1.DedupTransform{KeyFieldPath: pointer.String("name")},
+				i: []any{map[string]any{"name": "a"}, "a"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtDedupElementNonObject, 1),
+			},
+		},
+		"ErrObjectMissingKey": {
+			args: args{
+				t: v1.DedupTransform{KeyFieldPath: pointer.String("name")},
+				i: []any{map[string]any{"name": "a"}, map[string]any{"id": "a"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtDedupElementNoKey, 1, "name"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveDedup(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMathResolve(t *testing.T) {
+	two := int64(2)
+
+	type args struct {
+		mathType   v1.MathTransformType
+		multiplier *int64
+		clampMin   *int64
+		clampMax   *int64
+		modulo     *int64
+		add        *int64
+		i          any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ModuloSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				modulo:   pointer.Int64(3),
+				i:        7,
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"ModuloNegativeInput": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				modulo:   pointer.Int64(3),
+				i:        int64(-7),
+			},
+			want: want{
+				o: int64(-1),
+			},
+		},
+		"ModuloNegativeDivisor": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				modulo:   pointer.Int64(-3),
+				i:        int64(7),
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"ModuloByZero": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				modulo:   pointer.Int64(0),
+				i:        int64(7),
+			},
+			want: want{
+				err: errors.New(errMathModuloByZero),
+			},
+		},
+		"Add": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				add:      &two,
+				i:        int64(40),
+			},
+			want: want{
+				o: int64(42),
+			},
+		},
+		"AddMissing": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				i:        int64(40),
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "add",
+				},
+			},
+		},
+		"ModuloMissing": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				i:        int64(7),
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "modulo",
+				},
+			},
+		},
+		"InvalidType": {
+			args: args{
+				mathType: "bad",
+				i:        25,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "type",
+				},
+			},
+		},
+		"NonNumberInput": {
+			args: args{
+				mathType:   v1.MathTransformTypeMultiply,
+				multiplier: &two,
+				i:          "ola",
+			},
+			want: want{
+				err: errors.New(errMathInputNonNumber),
+			},
+		},
+		"MultiplyNoConfig": {
+			args: args{
+				mathType: v1.MathTransformTypeMultiply,
+				i:        25,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "multiply",
+				},
+			},
+		},
+		"MultiplySuccess": {
+			args: args{
+				mathType:   v1.MathTransformTypeMultiply,
+				multiplier: &two,
+				i:          3,
+			},
+			want: want{
+				o: 3 * two,
+			},
+		},
+		"MultiplySuccessInt64": {
+			args: args{
+				mathType:   v1.MathTransformTypeMultiply,
+				multiplier: &two,
+				i:          int64(3),
+			},
+			want: want{
+				o: 3 * two,
+			},
+		},
+		"MultiplyIdentity": {
+			args: args{
+				mathType:   v1.MathTransformTypeMultiply,
+				multiplier: pointer.Int64(1),
+				i:          int64(3),
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+		"IdentitySuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeIdentity,
+				i:        3,
+			},
+			want: want{
+				o: 3,
+			},
+		},
+		"IdentityNonNumberInput": {
+			args: args{
+				mathType: v1.MathTransformTypeIdentity,
+				i:        "ola",
+			},
+			want: want{
+				err: errors.New(errMathInputNonNumber),
+			},
+		},
+		"ClampMinSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMin,
+				clampMin: &two,
+				i:        1,
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"ClampMinSuccessNoChange": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMin,
+				clampMin: &two,
+				i:        3,
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+		"ClampMinSuccessInt64": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMin,
+				clampMin: &two,
+				i:        int64(1),
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"ClampMinNoConfig": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMin,
+				i:        25,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "clampMin",
+				},
+			},
+		},
+		"ClampMaxSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMax,
+				clampMax: &two,
+				i:        3,
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"ClampMaxSuccessNoChange": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMax,
+				clampMax: &two,
+				i:        1,
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"ClampMaxSuccessInt64": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMax,
+				clampMax: &two,
+				i:        int64(3),
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"ClampMaxNoConfig": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMax,
+				i:        25,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "clampMax",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := v1.MathTransform{Type: tc.mathType, Multiply: tc.multiplier, ClampMin: tc.clampMin, ClampMax: tc.clampMax, Modulo: tc.modulo, Add: tc.add}
+			got, err := ResolveMath(tr, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			fieldErr := &field.Error{}
+			if err != nil && errors.As(err, &fieldErr) {
+				fieldErr.Detail = ""
+				fieldErr.BadValue = nil
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStringResolve(t *testing.T) {
+
+	type args struct {
+		stype   v1.StringTransformType
+		fmts    *string
+		convert *v1.StringConversionType
+		trim    *string
+		regexp  *v1.StringTransformRegexp
+		replace *v1.StringTransformReplace
+		slice   *v1.StringTransformSlice
+		escape  *v1.StringTransformEscape
+		prefix  *string
+		suffix  *string
+		i       any
+	}
+	type want struct {
+		o   string
+		err error
+	}
+	sFmt := "verycool%s"
+	iFmt := "the largest %d"
+
+	upper := v1.StringConversionTypeToUpper
+	lower := v1.StringConversionTypeToLower
+	tobase64 := v1.StringConversionTypeToBase64
+	frombase64 := v1.StringConversionTypeFromBase64
+	toJSON := v1.StringConversionTypeToJSON
+	wrongConvertType := v1.StringConversionType("Something")
+	toSha1 := v1.StringConversionTypeToSHA1
+	toSha256 := v1.StringConversionTypeToSHA256
+	toSha512 := v1.StringConversionTypeToSHA512
+	urlEncode := v1.StringConversionTypeURLEncode
+	urlDecode := v1.StringConversionTypeURLDecode
+	dns1123 := v1.StringConversionTypeDNS1123
+
+	prefix := "https://"
+	suffix := "-test"
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"NotSupportedType": {
+			args: args{
+				stype: "Something",
+				i:     "value",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeFailed, "Something"),
+			},
+		},
+		"FmtFailed": {
+			args: args{
+				stype: v1.StringTransformTypeFormat,
+				i:     "value",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeFormat, string(v1.StringTransformTypeFormat)),
+			},
+		},
+		"FmtString": {
+			args: args{
+				stype: v1.StringTransformTypeFormat,
+				fmts:  &sFmt,
+				i:     "thing",
+			},
+			want: want{
+				o: "verycoolthing",
+			},
+		},
+		"FmtInteger": {
+			args: args{
+				stype: v1.StringTransformTypeFormat,
+				fmts:  &iFmt,
+				i:     8,
+			},
+			want: want{
+				o: "the largest 8",
+			},
+		},
+		"ConvertNotSet": {
+			args: args{
+				stype: v1.StringTransformTypeConvert,
+				i:     "crossplane",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeConvert, string(v1.StringTransformTypeConvert)),
+			},
+		},
+		"ConvertTypFailed": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &wrongConvertType,
+				i:       "crossplane",
+			},
+			want: want{
+				err: errors.Errorf(errStringConvertTypeFailed, wrongConvertType),
+			},
+		},
+		"ConvertToUpper": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &upper,
+				i:       "crossplane",
+			},
+			want: want{
+				o: "CROSSPLANE",
+			},
+		},
+		"ConvertToLower": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &lower,
+				i:       "CrossPlane",
+			},
+			want: want{
+				o: "crossplane",
+			},
+		},
+		"ConvertToBase64": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &tobase64,
+				i:       "CrossPlane",
+			},
+			want: want{
+				o: "Q3Jvc3NQbGFuZQ==",
+			},
+		},
+		"ConvertFromBase64": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &frombase64,
+				i:       "Q3Jvc3NQbGFuZQ==",
+			},
+			want: want{
+				o: "CrossPlane",
+			},
+		},
+		"ConvertFromBase64Error": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &frombase64,
+				i:       "ThisStringIsNotBase64",
+			},
+			want: want{
+				o:   "N\x18\xacJ\xda\xe2\x9e\x02,6\x8bAjǺ",
+				err: errors.Wrap(errors.New("illegal base64 data at input byte 20"), errDecodeString),
+			},
+		},
+		"ConvertURLEncode": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &urlEncode,
+				i:       "cross plane/io?a=b&c",
+			},
+			want: want{
+				o: "cross+plane%2Fio%3Fa%3Db%26c",
+			},
+		},
+		"ConvertURLDecode": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &urlDecode,
+				i:       "cross+plane%2Fio%3Fa%3Db%26c",
+			},
+			want: want{
+				o: "cross plane/io?a=b&c",
+			},
+		},
+		"ConvertURLDecodeError": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &urlDecode,
+				i:       "cross%zzplane",
+			},
+			want: want{
+				err: errors.Wrap(url.EscapeError("%zz"), errURLDecode),
+			},
+		},
+		"ConvertDNS1123": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &dns1123,
+				i:       "-My_Cool.App (Prod)-",
+			},
+			want: want{
+				o: "my-cool-app--prod",
+			},
+		},
+		"ConvertDNS1123Truncate": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &dns1123,
+				i:       strings.Repeat("a", 62) + "_b",
+			},
+			want: want{
+				o: strings.Repeat("a", 62),
+			},
+		},
+		"ConvertDNS1123NonASCII": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &dns1123,
+				i:       "Zürich",
+			},
+			want: want{
+				o: "z-rich",
+			},
+		},
+		"ConvertDNS1123Empty": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &dns1123,
+				i:       "_.-",
+			},
+			want: want{
+				err: errors.Errorf(errFmtDNS1123Empty, "_.-"),
+			},
+		},
+		"ConvertToSha1": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toSha1,
+				i:       "Crossplane",
+			},
+			want: want{
+				o: "f9fd1da3c0cc298643ff098a0c59febf1d8b7b84",
+			},
+		},
+		"ConvertToSha1Error": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toSha1,
+				i:       func() {},
+			},
+			want: want{
+				o:   "0000000000000000000000000000000000000000",
+				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
+			},
+		},
+		"ConvertToSha256": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toSha256,
+				i:       "Crossplane",
+			},
+			want: want{
+				o: "e84ae541a0725d73154ee76b7ac3fec4b007dd01ed701d506cd7e7a45bb48935",
+			},
+		},
+		"ConvertToSha256Error": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toSha256,
+				i:       func() {},
+			},
+			want: want{
+				o:   "0000000000000000000000000000000000000000000000000000000000000000",
+				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
+			},
+		},
+		"ConvertToSha512": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toSha512,
+				i:       "Crossplane",
+			},
+			want: want{
+				o: "b48622a3f487b8cb7748b356c9531cf54d9125c1456689c115744821f3dafd59c8c7d4dc5627c4a1e4082c67ee9f4528365a644a01a0c46d6dd0a6d979c8f51f",
+			},
+		},
+		"ConvertToSha512Error": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toSha512,
+				i:       func() {},
+			},
+			want: want{
+				o:   "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
+			},
+		},
+		"TrimPrefix": {
+			args: args{
+				stype: v1.StringTransformTypeTrimPrefix,
+				trim:  &prefix,
+				i:     "https://crossplane.io",
+			},
+			want: want{
+				o: "crossplane.io",
+			},
+		},
+		"TrimSuffix": {
+			args: args{
+				stype: v1.StringTransformTypeTrimSuffix,
+				trim:  &suffix,
+				i:     "my-string-test",
+			},
+			want: want{
+				o: "my-string",
+			},
+		},
+		"TrimPrefixWithoutMatch": {
+			args: args{
+				stype: v1.StringTransformTypeTrimPrefix,
+				trim:  &prefix,
+				i:     "crossplane.io",
+			},
+			want: want{
+				o: "crossplane.io",
+			},
+		},
+		"TrimSuffixWithoutMatch": {
+			args: args{
+				stype: v1.StringTransformTypeTrimSuffix,
+				trim:  &suffix,
+				i:     "my-string",
+			},
+			want: want{
+				o: "my-string",
+			},
+		},
+		"RegexpNotCompiling": {
+			args: args{
+				stype: v1.StringTransformTypeRegexp,
+				regexp: &v1.StringTransformRegexp{
+					Match: "[a-z",
+				},
+				i: "my-string",
+			},
+			want: want{
+				err: errors.Wrap(errors.New("error parsing regexp: missing closing ]: `[a-z`"), errStringTransformTypeRegexpFailed),
+			},
+		},
+		"RegexpSimpleMatch": {
+			args: args{
+				stype: v1.StringTransformTypeRegexp,
+				regexp: &v1.StringTransformRegexp{
+					Match: "[0-9]",
+				},
+				i: "my-1-string",
+			},
+			want: want{
+				o: "1",
+			},
+		},
+		"RegexpCaptureGroup": {
+			args: args{
+				stype: v1.StringTransformTypeRegexp,
+				regexp: &v1.StringTransformRegexp{
+					Match: "my-([0-9]+)-string",
+					Group: pointer.Int(1),
+				},
+				i: "my-1-string",
+			},
+			want: want{
+				o: "1",
+			},
+		},
+		"RegexpNoSuchCaptureGroup": {
+			args: args{
+				stype: v1.StringTransformTypeRegexp,
+				regexp: &v1.StringTransformRegexp{
+					Match: "my-([0-9]+)-string",
+					Group: pointer.Int(2),
+				},
+				i: "my-1-string",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeRegexpNoMatch, "my-([0-9]+)-string", 2),
+			},
+		},
+		"ReplaceNotSet": {
+			args: args{
+				stype: v1.StringTransformTypeReplace,
+				i:     "my-string",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeReplace, v1.StringTransformTypeReplace),
+			},
+		},
+		"ReplaceOldEmpty": {
+			args: args{
+				stype:   v1.StringTransformTypeReplace,
+				replace: &v1.StringTransformReplace{New: "x"},
+				i:       "my-string",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformReplaceOldEmpty, v1.StringTransformTypeReplace),
+			},
+		},
+		"ReplaceAll": {
+			args: args{
+				stype:   v1.StringTransformTypeReplace,
+				replace: &v1.StringTransformReplace{Old: "-", New: "_"},
+				i:       "my-long-string",
+			},
+			want: want{
+				o: "my_long_string",
+			},
+		},
+		"ReplaceCount": {
+			args: args{
+				stype:   v1.StringTransformTypeReplace,
+				replace: &v1.StringTransformReplace{Old: "-", New: "_", Count: pointer.Int(1)},
+				i:       "my-long-string",
+			},
+			want: want{
+				o: "my_long-string",
+			},
+		},
+		"ReplaceWithEmptyString": {
+			args: args{
+				stype:   v1.StringTransformTypeReplace,
+				replace: &v1.StringTransformReplace{Old: "-"},
+				i:       "my-long-string",
+			},
+			want: want{
+				o: "mylongstring",
+			},
+		},
+		"ReplaceNoMatch": {
+			args: args{
+				stype:   v1.StringTransformTypeReplace,
+				replace: &v1.StringTransformReplace{Old: "?", New: "_"},
+				i:       "my-long-string",
+			},
+			want: want{
+				o: "my-long-string",
+			},
+		},
+		"SliceNotSet": {
+			args: args{
+				stype: v1.StringTransformTypeSlice,
+				i:     "my-long-string",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeSlice, v1.StringTransformTypeSlice),
+			},
+		},
+		"SlicePrefix": {
+			args: args{
+				stype: v1.StringTransformTypeSlice,
+				slice: &v1.StringTransformSlice{End: pointer.Int(8)},
+				i:     "2cf24dba5fb0a30e26e83b2ac5b9e29e",
+			},
+			want: want{
+				o: "2cf24dba",
+			},
+		},
+		"SliceRange": {
+			args: args{
+				stype: v1.StringTransformTypeSlice,
+				slice: &v1.StringTransformSlice{Start: 3, End: pointer.Int(7)},
+				i:     "my-long-string",
+			},
+			want: want{
+				o: "long",
+			},
+		},
+		"SliceNegativeIndices": {
+			args: args{
+				stype: v1.StringTransformTypeSlice,
+				slice: &v1.StringTransformSlice{Start: -6, End: pointer.Int(-1)},
+				i:     "my-long-string",
+			},
+			want: want{
+				o: "strin",
+			},
+		},
+		"SliceToEnd": {
+			args: args{
+				stype: v1.StringTransformTypeSlice,
+				slice: &v1.StringTransformSlice{Start: -6},
+				i:     "my-long-string",
+			},
+			want: want{
+				o: "string",
+			},
+		},
+		"SliceOutOfRange": {
+			args: args{
+				stype: v1.StringTransformTypeSlice,
+				slice: &v1.StringTransformSlice{Start: -100, End: pointer.Int(100)},
+				i:     "my-long-string",
+			},
+			want: want{
+				o: "my-long-string",
+			},
+		},
+		"SliceStartAfterEnd": {
+			args: args{
+				stype: v1.StringTransformTypeSlice,
+				slice: &v1.StringTransformSlice{Start: 7, End: pointer.Int(3)},
+				i:     "my-long-string",
+			},
+			want: want{
+				o: "",
+			},
+		},
+		"SliceMultiByte": {
+			args: args{
+				stype: v1.StringTransformTypeSlice,
+				slice: &v1.StringTransformSlice{End: pointer.Int(2)},
+				i:     "日本語",
+			},
+			want: want{
+				o: "日本",
+			},
+		},
+		"EscapeNotSet": {
+			args: args{
+				stype: v1.StringTransformTypeEscape,
+				i:     "value",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeEscape, v1.StringTransformTypeEscape),
+			},
+		},
+		"EscapeJSON": {
+			args: args{
+				stype:  v1.StringTransformTypeEscape,
+				escape: &v1.StringTransformEscape{Context: v1.StringEscapeContextJSON},
+				i:      "say \"hi\"\n<b>\\o/</b>",
+			},
+			want: want{
+				o: `say \"hi\"\n<b>\\o/</b>`,
+			},
+		},
+		"EscapeShell": {
+			args: args{
+				stype:  v1.StringTransformTypeEscape,
+				escape: &v1.StringTransformEscape{Context: v1.StringEscapeContextShell},
+				i:      "it's $HOME; rm -rf /",
+			},
+			want: want{
+				o: `'it'\''s $HOME; rm -rf /'`,
+			},
+		},
+		"EscapeURL": {
+			args: args{
+				stype:  v1.StringTransformTypeEscape,
+				escape: &v1.StringTransformEscape{Context: v1.StringEscapeContextURL},
+				i:      "a b&c=d/e",
+			},
+			want: want{
+				o: "a+b%26c%3Dd%2Fe",
+			},
+		},
+		"EscapeUnknownContext": {
+			args: args{
+				stype:  v1.StringTransformTypeEscape,
+				escape: &v1.StringTransformEscape{Context: "sql"},
+				i:      "value",
+			},
+			want: want{
+				err: errors.Errorf(errFmtStringEscapeContext, "sql"),
+			},
+		},
+		"ConvertToJSONSuccess": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toJSON,
+				i: map[string]any{
+					"foo": "bar",
+				},
+			},
+			want: want{
+				o: "{\"foo\":\"bar\"}",
+			},
+		},
+		"ConvertToJSONFail": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toJSON,
+				i: map[string]any{
+					"foo": func() {},
+				},
+			},
+			want: want{
+				o:   "",
+				err: errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON),
+			},
+		},
+		"FormatPrefixSuffix": {
+			args: args{
+				stype:  v1.StringTransformTypeFormat,
+				fmts:   &sFmt,
+				prefix: pointer.String("<"),
+				suffix: pointer.String(">"),
+				i:      "thing",
+			},
+			want: want{
+				o: "<verycoolthing>",
+			},
+		},
+		"ConvertPrefixSuffix": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &lower,
+				prefix:  pointer.String("Pre-"),
+				suffix:  pointer.String("-Post"),
+				i:       "CRoSSPlaNe",
+			},
+			want: want{
+				o: "Pre-crossplane-Post",
+			},
+		},
+		"EmptyPrefixSuffix": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &upper,
+				prefix:  pointer.String(""),
+				suffix:  pointer.String(""),
+				i:       "crossplane",
+			},
+			want: want{
+				o: "CROSSPLANE",
+			},
+		},
+		"PrefixSuffixOnError": {
+			args: args{
+				stype:   v1.StringTransformTypeConvert,
+				convert: &toJSON,
+				prefix:  pointer.String("<"),
+				suffix:  pointer.String(">"),
+				i:       func() {},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+
+			tr := v1.StringTransform{Type: tc.stype,
+				Format:  tc.fmts,
+				Convert: tc.convert,
+				Trim:    tc.trim,
+				Regexp:  tc.regexp,
+				Replace: tc.replace,
+				Slice:   tc.slice,
+				Escape:  tc.escape,
+				Prefix:  tc.prefix,
+				Suffix:  tc.suffix,
+			}
+
+			got, err := ResolveString(tr, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertResolve(t *testing.T) {
+	type args struct {
+		to          v1.TransformIOType
+		format      *v1.ConvertTransformFormat
+		emptyAsZero bool
+		i           any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"StringToBool": {
+			args: args{
+				i:  "true",
+				to: v1.TransformIOTypeBool,
+			},
+			want: want{
+				o: true,
+			},
+		},
+		"StringToFloat64": {
+			args: args{
+				i:  "1000",
+				to: v1.TransformIOTypeFloat64,
+			},
+			want: want{
+				o: 1000.0,
+			},
+		},
+		"StringToQuantityFloat64": {
+			args: args{
+				i:      "1000m",
+				to:     v1.TransformIOTypeFloat64,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatQuantity))),
+			},
+			want: want{
+				o: 1.0,
+			},
+		},
+		"StringToQuantityFloat64InvalidFormat": {
+			args: args{
+				i:      "1000 blabla",
+				to:     v1.TransformIOTypeFloat64,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatQuantity))),
+			},
+			want: want{
+				err: resource.ErrFormatWrong,
+			},
+		},
+		"StringToIPv4Int64": {
+			args: args{
+				i:      "10.0.1.2",
+				to:     v1.TransformIOTypeInt64,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatIPv4))),
+			},
+			want: want{
+				o: int64(167772418),
+			},
+		},
+		"StringToIPv4Int64Max": {
+			args: args{
+				i:      "255.255.255.255",
+				to:     v1.TransformIOTypeInt,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatIPv4))),
+			},
+			want: want{
+				o: int64(math.MaxUint32),
+			},
+		},
+		"StringToIPv4Int64Invalid": {
+			args: args{
+				i:      "10.0.1",
+				to:     v1.TransformIOTypeInt64,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatIPv4))),
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertInvalidIPv4, "10.0.1"),
+			},
+		},
+		"StringToIPv4Int64IPv6": {
+			args: args{
+				i:      "2001:db8::1",
+				to:     v1.TransformIOTypeInt64,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatIPv4))),
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertInvalidIPv4, "2001:db8::1"),
+			},
+		},
+		"IPv4Int64ToString": {
+			args: args{
+				i:      int64(167772418),
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatIPv4))),
+			},
+			want: want{
+				o: "10.0.1.2",
+			},
+		},
+		"IPv4Int64ToStringZero": {
+			args: args{
+				i:      int64(0),
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatIPv4))),
+			},
+			want: want{
+				o: "0.0.0.0",
+			},
+		},
+		"IPv4Int64ToStringOutOfRange": {
+			args: args{
+				i:      int64(math.MaxUint32 + 1),
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatIPv4))),
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertIPv4OutOfRange, int64(math.MaxUint32+1)),
+			},
+		},
+		"SameTypeNoOp": {
+			args: args{
+				i:  true,
+				to: v1.TransformIOTypeBool,
+			},
+			want: want{
+				o: true,
+			},
+		},
+		"IntAliasToInt64": {
+			args: args{
+				i:  int64(1),
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"IntToInt": {
+			args: args{
+				i:  100,
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				o: int64(100),
+			},
+		},
+		"Int32ToInt": {
+			args: args{
+				i:  int32(100),
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				o: int64(100),
+			},
+		},
+		"Uint8ToInt16": {
+			args: args{
+				i:  uint8(100),
+				to: v1.TransformIOTypeInt16,
+			},
+			want: want{
+				o: int64(100),
+			},
+		},
+		"Uint64OverflowsInt": {
+			args: args{
+				i:  uint64(math.MaxUint64),
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertOverflow, uint64(math.MaxUint64), v1.TransformIOTypeInt64),
+			},
+		},
+		"Float32ToInt": {
+			args: args{
+				i:  float32(2.5),
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"FloatStringToInt": {
+			args: args{
+				i:  "2.5",
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"InvalidStringToInt": {
+			args: args{
+				i:  "ten",
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				o:   int64(0),
+				err: &strconv.NumError{Func: "ParseInt", Num: "ten", Err: strconv.ErrSyntax},
+			},
+		},
+		"InputTypeNotSupportedToInt": {
+			args: args{
+				i:  []int{64},
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertInputTypeNotSupported, []int{}),
+			},
+		},
+		"InputTypeNotSupported": {
+			args: args{
+				i:  []int{64},
+				to: v1.TransformIOTypeString,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertInputTypeNotSupported, []int{}),
+			},
+		},
+		"ConversionPairFormatNotSupported": {
+			args: args{
+				i:      100,
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatQuantity))),
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertFormatPairNotSupported, "int", "string", string(v1.ConvertTransformFormatQuantity)),
+			},
+		},
+		"ConversionPairNotSupported": {
+			args: args{
+				i:  "[64]",
+				to: "[]int",
+			},
+			want: want{
+				err: &field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "toType",
+					BadValue: v1.TransformIOType("[]int"),
+					Detail:   "invalid type",
+				},
+			},
+		},
+		"ConversionPairSupportedFloat64Int64": {
+			args: args{
+				i:  float64(1.1),
+				to: v1.TransformIOTypeInt64,
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"StringToInt32": {
+			args: args{
+				i:  "-2147483648",
+				to: v1.TransformIOTypeInt32,
+			},
+			want: want{
+				o: int64(math.MinInt32),
+			},
+		},
+		"StringToInt32Overflow": {
+			args: args{
+				i:  "2147483648",
+				to: v1.TransformIOTypeInt32,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertOverflow, "2147483648", v1.TransformIOTypeInt32),
+			},
+		},
+		"Int64ToInt32": {
+			args: args{
+				i:  int64(math.MaxInt32),
+				to: v1.TransformIOTypeInt32,
+			},
+			want: want{
+				o: int64(math.MaxInt32),
+			},
+		},
+		"Int64ToInt32Overflow": {
+			args: args{
+				i:  int64(math.MaxInt32 + 1),
+				to: v1.TransformIOTypeInt32,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertOverflow, int64(math.MaxInt32+1), v1.TransformIOTypeInt32),
+			},
+		},
+		"Int64ToInt16Overflow": {
+			args: args{
+				i:  int64(math.MinInt16 - 1),
+				to: v1.TransformIOTypeInt16,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertOverflow, int64(math.MinInt16-1), v1.TransformIOTypeInt16),
+			},
+		},
+		"Float64ToInt16": {
+			args: args{
+				i:  float64(100.5),
+				to: v1.TransformIOTypeInt16,
+			},
+			want: want{
+				o: int64(100),
+			},
+		},
+		"Float64ToInt32Overflow": {
+			args: args{
+				i:  float64(1e20),
+				to: v1.TransformIOTypeInt32,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertOverflow, float64(1e20), v1.TransformIOTypeInt32),
+			},
+		},
+		"BoolToInt32": {
+			args: args{
+				i:  true,
+				to: v1.TransformIOTypeInt32,
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"EmptyStringToInt": {
+			args: args{
+				i:  "",
+				to: v1.TransformIOTypeInt,
+			},
+			want: want{
+				o:   int64(0),
+				err: &strconv.NumError{Func: "ParseInt", Num: "", Err: strconv.ErrSyntax},
+			},
+		},
+		"EmptyStringToIntEmptyAsZero": {
+			args: args{
+				i:           "",
+				to:          v1.TransformIOTypeInt,
+				emptyAsZero: true,
+			},
+			want: want{
+				o: int64(0),
+			},
+		},
+		"EmptyStringToInt32EmptyAsZero": {
+			args: args{
+				i:           "",
+				to:          v1.TransformIOTypeInt32,
+				emptyAsZero: true,
+			},
+			want: want{
+				o: int64(0),
+			},
+		},
+		"EmptyStringToFloat64EmptyAsZero": {
+			args: args{
+				i:           "",
+				to:          v1.TransformIOTypeFloat64,
+				emptyAsZero: true,
+			},
+			want: want{
+				o: float64(0),
+			},
+		},
+		"EmptyStringToBoolEmptyAsZero": {
+			args: args{
+				i:           "",
+				to:          v1.TransformIOTypeBool,
+				emptyAsZero: true,
+			},
+			want: want{
+				o: false,
+			},
+		},
+		"NonEmptyStringToIntEmptyAsZero": {
+			args: args{
+				i:           "42",
+				to:          v1.TransformIOTypeInt,
+				emptyAsZero: true,
+			},
+			want: want{
+				o: int64(42),
+			},
+		},
+		"InputTypeNotSupportedEmptyAsZero": {
+			args: args{
+				i:           []int{},
+				to:          v1.TransformIOTypeString,
+				emptyAsZero: true,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertInputTypeNotSupported, []int{}),
+			},
+		},
+		"ConversionPairFormatNotSupportedInt32": {
+			args: args{
+				i:      "1000m",
+				to:     v1.TransformIOTypeInt32,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatQuantity))),
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertFormatPairNotSupported, "string", "int32", string(v1.ConvertTransformFormatQuantity)),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := v1.ConvertTransform{ToType: tc.args.to, Format: tc.format, EmptyAsZero: tc.emptyAsZero}
+			got, err := ResolveConvert(tr, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertTransformGetConversionFunc(t *testing.T) {
+	type args struct {
+		ct   *v1.ConvertTransform
+		from v1.TransformIOType
+	}
+	type want struct {
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"IntToString": {
+			reason: "Int to String should be valid",
+			args: args{
+				ct: &v1.ConvertTransform{
+					ToType: v1.TransformIOTypeString,
+				},
+				from: v1.TransformIOTypeInt,
+			},
+		},
+		"IntToInt": {
+			reason: "Int to Int should be valid",
+			args: args{
+				ct: &v1.ConvertTransform{
+					ToType: v1.TransformIOTypeInt,
+				},
+				from: v1.TransformIOTypeInt,
+			},
+		},
+		"IntToInt64": {
+			reason: "Int to Int64 should be valid",
+			args: args{
+				ct: &v1.ConvertTransform{
+					ToType: v1.TransformIOTypeInt,
+				},
+				from: v1.TransformIOTypeInt64,
+			},
+		},
+		"Int64ToInt": {
+			reason: "Int64 to Int should be valid",
+			args: args{
+				ct: &v1.ConvertTransform{
+					ToType: v1.TransformIOTypeInt64,
+				},
+				from: v1.TransformIOTypeInt,
+			},
+		},
+		"IntToFloat": {
+			reason: "Int to Float should be valid",
+			args: args{
+				ct: &v1.ConvertTransform{
+					ToType: v1.TransformIOTypeInt,
+				},
+				from: v1.TransformIOTypeFloat64,
+			},
+		},
+		"IntToBool": {
+			reason: "Int to Bool should be valid",
+			args: args{
+				ct: &v1.ConvertTransform{
+					ToType: v1.TransformIOTypeInt,
+				},
+				from: v1.TransformIOTypeBool,
+			},
+		},
+		"StringToIntInvalidFormat": {
+			reason: "String to Int with invalid format should be invalid",
+			args: args{
+				ct: &v1.ConvertTransform{
+					ToType: v1.TransformIOTypeInt,
+					Format: &[]v1.ConvertTransformFormat{"wrong"}[0],
+				},
+				from: v1.TransformIOTypeString,
+			},
+			want: want{
+				err: fmt.Errorf("conversion from string to int64 is not supported with format wrong"),
+			},
+		},
+		"IntToIntInvalidFormat": {
+			reason: "Int to Int, invalid format ignored because it is the same type",
+			args: args{
+				ct: &v1.ConvertTransform{
+					ToType: v1.TransformIOTypeInt,
+					Format: &[]v1.ConvertTransformFormat{"wrong"}[0],
+				},
+				from: v1.TransformIOTypeInt,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := GetConversionFunc(tc.args.ct, tc.args.from)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nGetConversionFunc(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLengthResolve(t *testing.T) {
+	type args struct {
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonArrayInput": {
+			args: args{
+				i: "us-east-1a",
+			},
+			want: want{
+				err: errors.New(errLengthInputNonArray),
+			},
+		},
+		"Empty": {
+			args: args{
+				i: []any{},
+			},
+			want: want{
+				o: int64(0),
+			},
+		},
+		"Strings": {
+			args: args{
+				i: []any{"us-east-1a", "us-east-1b", "us-east-1c"},
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+		"Mixed": {
+			args: args{
+				i: []any{"a", int64(1), map[string]any{"b": true}},
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveLength(tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRelativeTimeResolve(t *testing.T) {
+	at := time.Date(2023, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	type args struct {
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonNumberInput": {
+			args: args{
+				i: "3600",
+			},
+			want: want{
+				err: errors.New(errRelativeTimeInputNonNumber),
+			},
+		},
+		"Seconds": {
+			args: args{
+				i: int64(3600),
+			},
+			want: want{
+				o: "2023-06-01T11:00:00Z",
+			},
+		},
+		"NegativeSeconds": {
+			args: args{
+				i: -86400,
+			},
+			want: want{
+				o: "2023-05-31T10:00:00Z",
+			},
+		},
+		"FractionalSeconds": {
+			args: args{
+				i: float64(90.5),
+			},
+			want: want{
+				o: "2023-06-01T10:01:30Z",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveRelativeTime(tc.i, at)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Now", func(t *testing.T) {
+		defer func(fn func() time.Time) { now = fn }(now)
+		now = func() time.Time { return at }
+
+		got, err := Resolve(v1.Transform{Type: v1.TransformTypeRelativeTime}, int64(60))
+		if diff := cmp.Diff("2023-06-01T10:01:00Z", got); diff != "" {
+			t.Errorf("Resolve(...): -want, +got:\n%s", diff)
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("Resolve(...): -want error, +got error:\n%s", diff)
+		}
+	})
+}
+
+func TestNumberFormatResolve(t *testing.T) {
+	type args struct {
+		t v1.NumberFormatTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonNumberInput": {
+			args: args{
+				t: v1.NumberFormatTransform{},
+				i: "1234",
+			},
+			want: want{
+				err: errors.New(errNumberFormatInputNonNumber),
+			},
+		},
+		"ErrInvalid": {
+			args: args{
+				t: v1.NumberFormatTransform{ThousandsSeparator: pointer.String(".")},
+				i: int64(1234),
+			},
+			want: want{
+				err: &field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "thousandsSeparator",
+					BadValue: ".",
+					Detail:   "thousandsSeparator must differ from decimalSeparator",
+				},
+			},
+		},
+		"Int64": {
+			args: args{
+				t: v1.NumberFormatTransform{ThousandsSeparator: pointer.String(",")},
+				i: int64(1234567),
+			},
+			want: want{
+				o: "1,234,567",
+			},
+		},
+		"IntSmall": {
+			args: args{
+				t: v1.NumberFormatTransform{ThousandsSeparator: pointer.String(",")},
+				i: 123,
+			},
+			want: want{
+				o: "123",
+			},
+		},
+		"NegativeInt64WithDecimalPlaces": {
+			args: args{
+				t: v1.NumberFormatTransform{ThousandsSeparator: pointer.String(","), DecimalPlaces: pointer.Int(2)},
+				i: int64(-123456),
+			},
+			want: want{
+				o: "-123,456.00",
+			},
+		},
+		"LargeInt64": {
+			args: args{
+				t: v1.NumberFormatTransform{ThousandsSeparator: pointer.String(",")},
+				i: int64(math.MaxInt64),
+			},
+			want: want{
+				o: "9,223,372,036,854,775,807",
+			},
+		},
+		"Float64": {
+			args: args{
+				t: v1.NumberFormatTransform{ThousandsSeparator: pointer.String(",")},
+				i: 1234.5,
+			},
+			want: want{
+				o: "1,234.5",
+			},
+		},
+		"Float64Rounded": {
+			args: args{
+				t: v1.NumberFormatTransform{ThousandsSeparator: pointer.String(","), DecimalPlaces: pointer.Int(2)},
+				i: 9876543.219,
+			},
+			want: want{
+				o: "9,876,543.22",
+			},
+		},
+		"Float64NoDecimalPlaces": {
+			args: args{
+				t: v1.NumberFormatTransform{DecimalPlaces: pointer.Int(0)},
+				i: 1234.5678,
+			},
+			want: want{
+				o: "1235",
+			},
+		},
+		"CustomSeparators": {
+			args: args{
+				t: v1.NumberFormatTransform{
+					ThousandsSeparator: pointer.String("."),
+					DecimalSeparator:   pointer.String(","),
+					DecimalPlaces:      pointer.Int(1),
+				},
+				i: 1234567.89,
+			},
+			want: want{
+				o: "1.234.567,9",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveNumberFormat(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJSONParseResolve(t *testing.T) {
+	type args struct {
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"ErrNonStringInput": {
+			args: args{
+				i: int64(1),
+			},
+			want: want{
+				err: errors.New(errJSONParseInputNonString),
+			},
+		},
+		"ErrInvalidJSON": {
+			args: args{
+				i: `{"endpoint":`,
+			},
+			want: want{
+				err: errors.Wrap(json.Unmarshal([]byte(`{"endpoint":`), new(any)), errJSONParse),
+			},
+		},
+		"Object": {
+			args: args{
+				i: `{"endpoint":"db.example.org","port":5432,"tls":true}`,
+			},
+			want: want{
+				o: map[string]any{"endpoint": "db.example.org", "port": float64(5432), "tls": true},
+			},
+		},
+		"Array": {
+			args: args{
+				i: `["a","b"]`,
+			},
+			want: want{
+				o: []any{"a", "b"},
+			},
+		},
+		"String": {
+			args: args{
+				i: `"cool"`,
+			},
+			want: want{
+				o: "cool",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveJSONParse(tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	// decodeJSON and canonicalJSON must behave exactly like json.Unmarshal
+	// and json.Marshal, regardless of whether they take their fast path.
+	cases := []string{
+		`"us-west-2"`,
+		`""`,
+		`"with \"escaped\" quotes"`,
+		`"caf\u00e9"`,
+		`"café"`,
+		`"<b>&amp;</b>"`,
+		`"tab\there"`,
+		`"unterminated`,
+		`true`,
+		`false`,
+		`null`,
+		`42`,
+		`4.2e1`,
+		`{"cpu":2}`,
+		`["a", "b"]`,
+		` "padded" `,
+		`nope`,
+	}
+
+	for _, raw := range cases {
+		t.Run(raw, func(t *testing.T) {
+			var want any
+			wantErr := json.Unmarshal([]byte(raw), &want)
+
+			got, err := decodeJSON([]byte(raw))
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("decodeJSON(%s): -want, +got:\n%s", raw, diff)
+			}
+			if diff := cmp.Diff(wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("decodeJSON(%s): -want error, +got error:\n%s", raw, diff)
+			}
+			if wantErr != nil {
+				return
+			}
+
+			wantCanonical, _ := json.Marshal(want)
+			gotCanonical, err := canonicalJSON(extv1.JSON{Raw: []byte(raw)})
+			if err != nil {
+				t.Fatalf("canonicalJSON(%s): %v", raw, err)
+			}
+			if diff := cmp.Diff(string(wantCanonical), string(gotCanonical)); diff != "" {
+				t.Errorf("canonicalJSON(%s): -want, +got:\n%s", raw, diff)
+			}
+		})
+	}
+}
+
+func BenchmarkResolve(b *testing.B) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		b.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "db.example.org"},
+		NotBefore:    time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	j := func(s string) extv1.JSON { return extv1.JSON{Raw: []byte(s)} }
+	jp := func(s string) *extv1.JSON { return &extv1.JSON{Raw: []byte(s)} }
+
+	cases := map[string]struct {
+		t     v1.Transform
+		input any
+	}{
+		"Math": {
+			t:     v1.Transform{Type: v1.TransformTypeMath, Math: &v1.MathTransform{Multiply: pointer.Int64(2)}},
+			input: int64(21),
+		},
+		"Map": {
+			t:     v1.Transform{Type: v1.TransformTypeMap, Map: &v1.MapTransform{Pairs: map[string]extv1.JSON{"us-west": j(`"us-west-2"`), "eu-west": j(`"eu-west-1"`)}}},
+			input: "eu-west",
+		},
+		"MapObject": {
+			t:     v1.Transform{Type: v1.TransformTypeMap, Map: &v1.MapTransform{Pairs: map[string]extv1.JSON{"small": j(`{"cpu":2,"memory":"4Gi"}`)}}},
+			input: "small",
+		},
+		"Match": {
+			t: v1.Transform{Type: v1.TransformTypeMatch, Match: &v1.MatchTransform{Patterns: []v1.MatchTransformPattern{
+				{Type: v1.MatchTransformPatternTypeLiteral, Literal: pointer.String("us-west"), Result: j(`"us-west-2"`)},
+				{Type: v1.MatchTransformPatternTypeRegexp, Regexp: pointer.String("^eu-"), Result: j(`"eu-west-1"`)},
+			}}},
+			input: "eu-central",
+		},
+		"StringFormat": {
+			t:     v1.Transform{Type: v1.TransformTypeString, String: &v1.StringTransform{Type: v1.StringTransformTypeFormat, Format: pointer.String("db-%s")}},
+			input: "example",
+		},
+		"StringConvert": {
+			t:     v1.Transform{Type: v1.TransformTypeString, String: &v1.StringTransform{Type: v1.StringTransformTypeConvert, Convert: func() *v1.StringConversionType { t := v1.StringConversionTypeToUpper; return &t }()}},
+			input: "example",
+		},
+		"StringRegexp": {
+			t:     v1.Transform{Type: v1.TransformTypeString, String: &v1.StringTransform{Type: v1.StringTransformTypeRegexp, Regexp: &v1.StringTransformRegexp{Match: "^db-(.+)$", Group: pointer.Int(1)}}},
+			input: "db-example",
+		},
+		"ConvertStringToInt64": {
+			t:     v1.Transform{Type: v1.TransformTypeConvert, Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeInt64}},
+			input: "42",
+		},
+		"ConvertInt64ToString": {
+			t:     v1.Transform{Type: v1.TransformTypeConvert, Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeString}},
+			input: int64(42),
+		},
+		"ConvertFloat64ToInt32": {
+			t:     v1.Transform{Type: v1.TransformTypeConvert, Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeInt32}},
+			input: float64(42),
+		},
+		"ConvertQuantityToFloat64": {
+			t:     v1.Transform{Type: v1.TransformTypeConvert, Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeFloat64, Format: func() *v1.ConvertTransformFormat { f := v1.ConvertTransformFormatQuantity; return &f }()}},
+			input: "250m",
+		},
+		"ConvertIdentity": {
+			t:     v1.Transform{Type: v1.TransformTypeConvert, Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeString}},
+			input: "example",
+		},
+		"Range": {
+			t:     v1.Transform{Type: v1.TransformTypeRange, Range: &v1.RangeTransform{Buckets: []v1.RangeTransformBucket{{Max: 10, Value: j(`"small"`)}, {Max: 100, Value: j(`"large"`)}}}},
+			input: int64(42),
+		},
+		"Aggregate": {
+			t:     v1.Transform{Type: v1.TransformTypeAggregate, Aggregate: &v1.AggregateTransform{Type: v1.AggregateTransformTypeSum}},
+			input: []any{int64(1), int64(2), int64(3)},
+		},
+		"Ternary": {
+			t:     v1.Transform{Type: v1.TransformTypeTernary, Ternary: &v1.TernaryTransform{True: j(`"enabled"`), False: j(`"disabled"`)}},
+			input: true,
+		},
+		"Truncate": {
+			t:     v1.Transform{Type: v1.TransformTypeTruncate, Truncate: &v1.TruncateTransform{MaxLength: 8}},
+			input: "a-rather-long-resource-name",
+		},
+		"Length": {
+			t:     v1.Transform{Type: v1.TransformTypeLength},
+			input: []any{"a", "b", "c"},
+		},
+		"RelativeTime": {
+			t:     v1.Transform{Type: v1.TransformTypeRelativeTime},
+			input: int64(3600),
+		},
+		"Case": {
+			t:     v1.Transform{Type: v1.TransformTypeCase, Case: &v1.CaseTransform{To: v1.CaseTransformTypeCamel}},
+			input: "my storage_bucket",
+		},
+		"Semver": {
+			t:     v1.Transform{Type: v1.TransformTypeSemver, Semver: &v1.SemverTransform{Operation: v1.SemverTransformOperationNormalize}},
+			input: "v1.2.3-rc.1",
+		},
+		"Dedup": {
+			t:     v1.Transform{Type: v1.TransformTypeDedup},
+			input: []any{"a", "b", "a", "c", "b"},
+		},
+		"JSONParse": {
+			t:     v1.Transform{Type: v1.TransformTypeJSONParse},
+			input: `{"cpu":2,"memory":"4Gi"}`,
+		},
+		"NumberFormat": {
+			t:     v1.Transform{Type: v1.TransformTypeNumberFormat, NumberFormat: &v1.NumberFormatTransform{ThousandsSeparator: pointer.String(",")}},
+			input: int64(1234567),
+		},
+		"CIDRMatch": {
+			t:     v1.Transform{Type: v1.TransformTypeCIDRMatch, CIDRMatch: &v1.CIDRMatchTransform{Blocks: []v1.CIDRMatchTransformBlock{{CIDR: "10.0.0.0/8", Value: j(`"private"`)}}}},
+			input: "10.1.2.3",
+		},
+		"PEM": {
+			t:     v1.Transform{Type: v1.TransformTypePEM, PEM: &v1.PEMTransform{Attribute: v1.PEMTransformAttributeCommonName}},
+			input: cert,
+		},
+		"Allowlist": {
+			t:     v1.Transform{Type: v1.TransformTypeAllowlist, Allowlist: &v1.AllowlistTransform{Values: []extv1.JSON{j(`"us-west-2"`), j(`"eu-west-1"`)}}},
+			input: "eu-west-1",
+		},
+		"ConditionStatus": {
+			t:     v1.Transform{Type: v1.TransformTypeConditionStatus, ConditionStatus: &v1.ConditionStatusTransform{True: jp(`"ready"`), Default: jp(`"pending"`)}},
+			input: "True",
+		},
+		"FieldSelect": {
+			t:     v1.Transform{Type: v1.TransformTypeFieldSelect, FieldSelect: &v1.FieldSelectTransform{FieldPath: "endpoint.address"}},
+			input: map[string]any{"endpoint": map[string]any{"address": "db.example.org"}},
+		},
+		"ValidateFormat": {
+			t:     v1.Transform{Type: v1.TransformTypeValidateFormat, ValidateFormat: &v1.ValidateFormatTransform{Format: v1.ValidateFormatHostname}},
+			input: "db.example.org",
+		},
+		"StableSuffix": {
+			t:     v1.Transform{Type: v1.TransformTypeStableSuffix, StableSuffix: &v1.StableSuffixTransform{Length: 5}},
+			input: "example",
+		},
+		"SelectMatch": {
+			t:     v1.Transform{Type: v1.TransformTypeSelectMatch, SelectMatch: &v1.SelectMatchTransform{FieldPath: "type", Value: j(`"ExternalIP"`), ReturnField: pointer.String("address")}},
+			input: []any{map[string]any{"type": "InternalIP", "address": "10.0.0.1"}, map[string]any{"type": "ExternalIP", "address": "203.0.113.1"}},
+		},
+		"LabelSelector": {
+			t:     v1.Transform{Type: v1.TransformTypeLabelSelector, LabelSelector: &v1.LabelSelectorTransform{Mode: v1.LabelSelectorTransformModeToSelector}},
+			input: map[string]any{"app": "db", "example.org/tier": "backend"},
+		},
+	}
+
+	for name, tc := range cases {
+		b.Run(name, func(b *testing.B) {
+			if _, err := Resolve(tc.t, tc.input); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = Resolve(tc.t, tc.input)
 			}
 		})
 	}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+type equateOptions struct {
+	inline bool
+}
+
+// An EquateOption configures how EquateComposition compares Compositions.
+type EquateOption func(o *equateOptions)
+
+// EquateInlinedPatchSets configures EquateComposition to compare each
+// CompositionSpec with its patch sets and transform sets inlined, such that a
+// spec that references a patch set is equal to one that contains the patches of
+// that set. Specs with references that can't be inlined are compared as-is.
+func EquateInlinedPatchSets() EquateOption {
+	return func(o *equateOptions) {
+		o.inline = true
+	}
+}
+
+// EquateComposition returns a cmp.Option that compares Composition types by
+// their effect, rather than by their literal representation. Patches and
+// transforms are compared with their defaults applied, so for example a patch
+// with no type is equal to a FromCompositeFieldPath patch, and a patch with no
+// toFieldPath is equal to one that patches to its fromFieldPath. Nil and empty
+// slices and maps are considered equal.
+func EquateComposition(o ...EquateOption) cmp.Option {
+	eo := &equateOptions{}
+	for _, fn := range o {
+		fn(eo)
+	}
+
+	opts := cmp.Options{
+		cmpopts.EquateEmpty(),
+		cmp.Transformer("DefaultPatch", func(p v1.Patch) v1.Patch {
+			out := p.DeepCopy()
+			out.Default()
+			out.Type = out.GetType()
+			return *out
+		}),
+		cmp.Transformer("DefaultTransform", func(t v1.Transform) v1.Transform {
+			out := t.DeepCopy()
+			policy := out.GetOnError()
+			out.OnError = &policy
+			return *out
+		}),
+	}
+	if eo.inline {
+		opts = append(opts, cmp.Transformer("InlinePatchSets", func(cs v1.CompositionSpec) v1.CompositionSpec {
+			ct, err := cs.InlinedResources()
+			if err != nil {
+				return cs
+			}
+			out := cs.DeepCopy()
+			out.PatchSets = nil
+			out.TransformSets = nil
+			out.Resources = ct
+			return *out
+		}))
+	}
+	return opts
+}
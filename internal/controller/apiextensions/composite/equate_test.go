@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestEquateComposition(t *testing.T) {
+	fail := v1.TransformOnErrorPolicyFail
+
+	type args struct {
+		a any
+		b any
+		o []EquateOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"DefaultedPatches": {
+			reason: "Patches should be equal to their defaulted equivalents.",
+			args: args{
+				a: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{FromFieldPath: pointer.String("spec.a")}},
+				}},
+				b: []v1.ComposedTemplate{{
+					Patches: []v1.Patch{{
+						Type:          v1.PatchTypeFromCompositeFieldPath,
+						FromFieldPath: pointer.String("spec.a"),
+						ToFieldPath:   pointer.String("spec.a"),
+					}},
+				}},
+			},
+			want: true,
+		},
+		"DefaultedTransforms": {
+			reason: "Transforms should be equal to their defaulted equivalents.",
+			args: args{
+				a: v1.Patch{Transforms: []v1.Transform{{Type: v1.TransformTypeLength}}},
+				b: v1.Patch{Transforms: []v1.Transform{{Type: v1.TransformTypeLength, OnError: &fail}}},
+			},
+			want: true,
+		},
+		"EmptySlices": {
+			reason: "Nil and empty slices should be equal.",
+			args: args{
+				a: []v1.ComposedTemplate{{Patches: []v1.Patch{}}},
+				b: []v1.ComposedTemplate{{}},
+			},
+			want: true,
+		},
+		"DifferentPatches": {
+			reason: "Patches that patch different field paths should not be equal.",
+			args: args{
+				a: v1.Patch{FromFieldPath: pointer.String("spec.a")},
+				b: v1.Patch{FromFieldPath: pointer.String("spec.a"), ToFieldPath: pointer.String("spec.b")},
+			},
+			want: false,
+		},
+		"TransformOrder": {
+			reason: "Transforms in a different order should not be equal.",
+			args: args{
+				a: v1.Patch{Transforms: []v1.Transform{{Type: v1.TransformTypeLength}, {Type: v1.TransformTypeTernary}}},
+				b: v1.Patch{Transforms: []v1.Transform{{Type: v1.TransformTypeTernary}, {Type: v1.TransformTypeLength}}},
+			},
+			want: false,
+		},
+		"PatchSetsNotInlined": {
+			reason: "A spec that references a patch set should not equal one with the set inlined by default.",
+			args: args{
+				a: v1.CompositionSpec{
+					PatchSets: []v1.PatchSet{{Name: "ps", Patches: []v1.Patch{{FromFieldPath: pointer.String("spec.a")}}}},
+					Resources: []v1.ComposedTemplate{{Patches: []v1.Patch{{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("ps")}}}},
+				},
+				b: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{Patches: []v1.Patch{{FromFieldPath: pointer.String("spec.a")}}}},
+				},
+			},
+			want: false,
+		},
+		"PatchSetsInlined": {
+			reason: "A spec that references a patch set should equal one with the set inlined when configured.",
+			args: args{
+				a: v1.CompositionSpec{
+					PatchSets: []v1.PatchSet{{Name: "ps", Patches: []v1.Patch{{FromFieldPath: pointer.String("spec.a")}}}},
+					Resources: []v1.ComposedTemplate{{Patches: []v1.Patch{{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("ps")}}}},
+				},
+				b: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{Patches: []v1.Patch{{FromFieldPath: pointer.String("spec.a")}}}},
+				},
+				o: []EquateOption{EquateInlinedPatchSets()},
+			},
+			want: true,
+		},
+		"UndefinedPatchSetInlined": {
+			reason: "A spec that references an undefined patch set should be compared as-is.",
+			args: args{
+				a: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{Patches: []v1.Patch{{Type: v1.PatchTypePatchSet, PatchSetName: pointer.String("nope")}}}},
+				},
+				b: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{}},
+				},
+				o: []EquateOption{EquateInlinedPatchSets()},
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := cmp.Equal(tc.args.a, tc.args.b, EquateComposition(tc.args.o...))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncmp.Equal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -75,10 +75,12 @@ func withMergeOptions(fieldPath string, mergeOptions *xpv1.MergeOptions) resourc
 func mergeOptions(pas []v1.Patch) []resource.ApplyOption {
 	opts := make([]resource.ApplyOption, 0, len(pas))
 	for _, p := range pas {
-		if p.Policy == nil || p.ToFieldPath == nil {
+		if p.Policy == nil {
 			continue
 		}
-		opts = append(opts, withMergeOptions(*p.ToFieldPath, p.Policy.MergeOptions))
+		for _, path := range p.GetToFieldPaths() {
+			opts = append(opts, withMergeOptions(path, p.Policy.MergeOptions))
+		}
 	}
 	return opts
 }
@@ -86,7 +88,9 @@ func mergeOptions(pas []v1.Patch) []resource.ApplyOption {
 // patchFieldValueToObject applies the value to the "to" object at the given
 // path with the given merge options, returning any errors as they occur.
 // If no merge options is supplied, then destination field is replaced
-// with the given value.
+// with the given value. The value is written as a copy (the fieldpath package
+// round-trips it through JSON), so the "to" object never shares maps or slices
+// with the object the value was read from.
 func patchFieldValueToObject(fieldPath string, value any, to runtime.Object, mo *xpv1.MergeOptions) error {
 	paved, err := fieldpath.PaveObject(to)
 	if err != nil {
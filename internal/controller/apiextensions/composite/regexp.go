@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+)
+
+// regexpCacheSize is the maximum number of compiled regular expressions that
+// are cached for use by transforms.
+const regexpCacheSize = 1024
+
+// compiledRegexps caches the regular expressions compiled by transforms, which
+// would otherwise be compiled every time a composite resource is reconciled.
+var compiledRegexps = newRegexpCache(regexpCacheSize)
+
+// A regexpCache caches compiled regular expressions by pattern. When full it
+// evicts the least recently used regular expression. It is safe for concurrent
+// use, as are the regular expressions it returns.
+type regexpCache struct {
+	mu      sync.Mutex
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type regexpCacheEntry struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func newRegexpCache(size int) *regexpCache {
+	return &regexpCache{size: size, lru: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Compile returns the compiled regular expression for the supplied pattern,
+// compiling it if it isn't cached. Patterns that don't compile aren't cached.
+func (c *regexpCache) Compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := c.get(pattern); ok {
+		return re, nil
+	}
+
+	// We don't hold the lock while compiling. Another goroutine may compile
+	// the same pattern concurrently, in which case the first to finish wins.
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[pattern]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*regexpCacheEntry).re, nil
+	}
+	c.entries[pattern] = c.lru.PushFront(&regexpCacheEntry{pattern: pattern, re: re})
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*regexpCacheEntry).pattern)
+	}
+	return re, nil
+}
+
+func (c *regexpCache) get(pattern string) (*regexp.Regexp, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[pattern]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*regexpCacheEntry).re, true
+}
+
+// Len returns the number of cached regular expressions.
+func (c *regexpCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestRegexpCache(t *testing.T) {
+	type want struct {
+		cached []string
+		err    bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		size     int
+		patterns []string
+		want     want
+	}{
+		"CachesPatterns": {
+			reason:   "Each distinct pattern should be cached once.",
+			size:     2,
+			patterns: []string{"a", "b", "a"},
+			want: want{
+				cached: []string{"a", "b"},
+			},
+		},
+		"EvictsLeastRecentlyUsed": {
+			reason:   "The least recently used pattern should be evicted when the cache is full.",
+			size:     2,
+			patterns: []string{"a", "b", "a", "c"},
+			want: want{
+				cached: []string{"a", "c"},
+			},
+		},
+		"DoesNotCacheInvalidPatterns": {
+			reason:   "Patterns that don't compile should return an error and not be cached.",
+			size:     2,
+			patterns: []string{"a", "[a-z"},
+			want: want{
+				cached: []string{"a"},
+				err:    true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newRegexpCache(tc.size)
+			var err error
+			for _, p := range tc.patterns {
+				if _, perr := c.Compile(p); perr != nil {
+					err = perr
+				}
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nCompile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(len(tc.want.cached), c.Len()); diff != "" {
+				t.Errorf("\n%s\nLen(): -want, +got:\n%s", tc.reason, diff)
+			}
+			for _, p := range tc.want.cached {
+				if _, ok := c.get(p); !ok {
+					t.Errorf("\n%s\nget(%q): pattern should be cached", tc.reason, p)
+				}
+			}
+		})
+	}
+}
+
+func TestRegexpCacheConcurrent(t *testing.T) {
+	c := newRegexpCache(4)
+	patterns := []string{"a+", "b+", "c+", "d+", "e+", "f+"}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				p := patterns[j%len(patterns)]
+				re, err := c.Compile(p)
+				if err != nil {
+					t.Errorf("Compile(%q): %v", p, err)
+					return
+				}
+				if re.String() != p {
+					t.Errorf("Compile(%q): got regexp %q", p, re.String())
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.Len() > 4 {
+		t.Errorf("Len(): cache should hold at most 4 patterns, got %d", c.Len())
+	}
+}
+
+func BenchmarkStringRegexpResolve(b *testing.B) {
+	r := v1.StringTransformRegexp{Match: `^arn:aws:iam::([0-9]{12}):role/(.+)$`}
+	input := "arn:aws:iam::123456789012:role/example"
+
+	b.Run("Uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			re := regexp.MustCompile(r.Match)
+			_ = re.FindStringSubmatch(input)
+		}
+	})
+	b.Run("Cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := stringRegexpTransform(input, r); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controller contains options specific to apiextensions controllers.
+package controller
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+)
+
+// Options specific to apiextensions controllers.
+type Options struct {
+	controller.Options
+
+	// ControllerConfig values that FromControllerConfig patches may read,
+	// keyed by the field path they are exposed at.
+	ControllerConfig map[string]string
+
+	// ComposedRenderConcurrency is the maximum number of composed resources
+	// of a composite resource that are rendered concurrently.
+	ComposedRenderConcurrency int
+}
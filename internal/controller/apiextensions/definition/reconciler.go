@@ -50,6 +50,7 @@ import (
 	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
@@ -110,7 +111,7 @@ func (fn CRDRenderFn) Render(d *v1.CompositeResourceDefinition) (*extv1.CustomRe
 
 // Setup adds a controller that reconciles CompositeResourceDefinitions by
 // defining a composite resource and starting a controller to reconcile it.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
+func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 	name := "defined/" + strings.ToLower(v1.CompositeResourceDefinitionGroupKind)
 
 	r := NewReconciler(mgr,
@@ -145,7 +146,7 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 
 // WithOptions lets the Reconciler know which options to pass to new composite
 // resource controllers.
-func WithOptions(o controller.Options) ReconcilerOption {
+func WithOptions(o apiextensionscontroller.Options) ReconcilerOption {
 	return func(r *Reconciler) {
 		r.options = o
 	}
@@ -210,7 +211,7 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
 
-		options: controller.DefaultOptions(),
+		options: apiextensionscontroller.Options{Options: controller.DefaultOptions()},
 	}
 
 	for _, f := range opts {
@@ -229,7 +230,7 @@ type Reconciler struct {
 	log    logging.Logger
 	record event.Recorder
 
-	options controller.Options
+	options apiextensionscontroller.Options
 }
 
 // Reconcile a CompositeResourceDefinition by defining a new kind of composite
@@ -425,7 +426,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 // CompositeReconcilerOptions builds the options for a composite resource
 // reconciler. The options vary based on the supplied feature flags.
-func CompositeReconcilerOptions(co controller.Options, d *v1.CompositeResourceDefinition, c client.Client, l logging.Logger, e event.Recorder) []composite.ReconcilerOption {
+func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.CompositeResourceDefinition, c client.Client, l logging.Logger, e event.Recorder) []composite.ReconcilerOption {
 	// The default set of reconciler options when no feature flags are enabled.
 	o := []composite.ReconcilerOption{
 		composite.WithConnectionPublishers(composite.NewAPIFilteredSecretPublisher(c, d.GetConnectionSecretKeys())),
@@ -450,6 +451,19 @@ func CompositeReconcilerOptions(co controller.Options, d *v1.CompositeResourceDe
 			composite.WithEnvironmentFetcher(environment.NewAPIEnvironmentFetcher(c)))
 	}
 
+	// Composed resources are rendered using the controller's configuration,
+	// which FromControllerConfig patches read from. The renderer is passed
+	// composed resources, which the unstructured client knows how to handle.
+	r := composite.NewAPIDryRunRenderer(unstructured.NewClient(c),
+		composite.WithRendererControllerConfig(composite.NewControllerConfig(co.ControllerConfig)))
+
+	// The composite reconciler's default PTComposer renders composed resources
+	// without the controller's configuration, so we always specify one.
+	o = append(o, composite.WithComposer(composite.NewPTComposer(c,
+		composite.WithComposedRenderer(r),
+		composite.WithComposedRenderConcurrency(co.ComposedRenderConcurrency),
+	)))
+
 	// If external secret stores aren't enabled we just fetch connection details
 	// from Kubernetes secrets.
 	var fetcher managed.ConnectionDetailsFetcher = composite.NewSecretConnectionDetailsFetcher(c)
@@ -483,7 +497,11 @@ func CompositeReconcilerOptions(co controller.Options, d *v1.CompositeResourceDe
 		o = append(o,
 			composite.WithConnectionPublishers(pc...),
 			composite.WithConfigurator(cc),
-			composite.WithComposer(composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher))))
+			composite.WithComposer(composite.NewPTComposer(c,
+				composite.WithComposedConnectionDetailsFetcher(fetcher),
+				composite.WithComposedRenderer(r),
+				composite.WithComposedRenderConcurrency(co.ComposedRenderConcurrency),
+			)))
 	}
 
 	// If Composition Functions are enabled we want to try to use the
@@ -500,8 +518,13 @@ func CompositeReconcilerOptions(co controller.Options, d *v1.CompositeResourceDe
 			composite.NewPTFComposer(c,
 				composite.WithComposedResourceGetter(composite.NewExistingComposedResourceGetter(c, fetcher)),
 				composite.WithCompositeConnectionDetailsFetcher(fetcher),
+				composite.WithPatchAndTransformer(composite.NewXRCDPatchAndTransformer(composite.RendererFn(composite.RenderComposite), r)),
+			),
+			composite.NewPTComposer(c,
+				composite.WithComposedConnectionDetailsFetcher(fetcher),
+				composite.WithComposedRenderer(r),
+				composite.WithComposedRenderConcurrency(co.ComposedRenderConcurrency),
 			),
-			composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher)),
 			composite.FallBackForAnonymousTemplates(c),
 		)
 
@@ -43,6 +43,7 @@ const (
 	errFmtIndexAccessWrongType = "trying to access a '%s' by index"
 	errFmtFieldAccessWrongType = "trying to access a field '%s' of object, but schema says parent is of type: '%v'"
 	errUnableToParse           = "cannot parse base"
+	errUnknownFieldPath        = "field path is not defined by the schema"
 )
 
 // validatePatchesWithSchemas validates the patches of a composition against the resources schemas.
@@ -181,6 +182,15 @@ func (v *Validator) validatePatchWithSchemaInternal(ctx patchValidationCtx) *fie
 		v1.PatchTypeCombineToEnvironment:
 		// TODO(phisco): implement validation for environment related patches
 		return nil
+	case v1.PatchTypeNoop:
+		return nil
+	case v1.PatchTypeFromComposedFieldPath, v1.PatchTypeFromComposedConnectionSecretKey:
+		// The schema of the source composed resource isn't available here.
+		return nil
+	case v1.PatchTypeFromControllerConfig, v1.PatchTypeFromConnectionSecretKey:
+		// The controller's configuration and connection secrets have no
+		// schema.
+		return nil
 	}
 	if validationErr != nil {
 		return validationErr
@@ -188,6 +198,68 @@ func (v *Validator) validatePatchWithSchemaInternal(ctx patchValidationCtx) *fie
 	return validateIOTypesWithTransforms(ctx.patch.Transforms, fromType, toType)
 }
 
+// ValidatePatchFieldPaths validates that the field paths the supplied patch
+// reads from and writes to are defined by the supplied schemas of the
+// composite and composed resources. Unlike an optional patch whose field path
+// doesn't exist, which silently does nothing, a field path that isn't defined
+// by the schema is returned as an error. Only patches between the composite
+// and composed resources are validated; PatchSets must be inlined first. A nil
+// schema accepts any field path.
+func ValidatePatchFieldPaths(p v1.Patch, composite, composed *apiextensions.JSONSchemaProps) *field.Error {
+	var from, to *apiextensions.JSONSchemaProps
+	switch p.GetType() { //nolint:exhaustive // Only patches between the composite and composed resources are validated.
+	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeCombineFromComposite:
+		from, to = composite, composed
+	case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite:
+		from, to = composed, composite
+	default:
+		return nil
+	}
+
+	if err := validateFromFieldPaths(p, from); err != nil {
+		return err
+	}
+	return validateToFieldPaths(p, to)
+}
+
+// validateFromFieldPaths validates that the field paths the supplied patch, or
+// the variables of its combine, read from are defined by the supplied schema.
+func validateFromFieldPaths(p v1.Patch, from *apiextensions.JSONSchemaProps) *field.Error {
+	if p.Combine == nil {
+		if _, err := validateFieldPath(from, p.GetFromFieldPath()); err != nil {
+			return field.Invalid(field.NewPath("fromFieldPath"), p.GetFromFieldPath(), errors.Wrap(err, errUnknownFieldPath).Error())
+		}
+		return nil
+	}
+	for i, v := range p.Combine.Variables {
+		if _, err := validateFieldPath(from, v.FromFieldPath); err != nil {
+			return field.Invalid(field.NewPath("combine", "variables").Index(i).Child("fromFieldPath"), v.FromFieldPath, errors.Wrap(err, errUnknownFieldPath).Error())
+		}
+	}
+	return nil
+}
+
+// validateToFieldPaths validates that the field paths the supplied patch writes
+// to are defined by the supplied schema.
+func validateToFieldPaths(p v1.Patch, to *apiextensions.JSONSchemaProps) *field.Error {
+	toFieldPath := p.GetToFieldPath()
+	// A patch patches the same field path it reads from by default.
+	if p.Combine == nil && p.ToFieldPath == nil && len(p.ToFieldPaths) == 0 {
+		toFieldPath = p.GetFromFieldPath()
+	}
+	if p.ToFieldPath != nil || len(p.ToFieldPaths) == 0 {
+		if _, err := validateFieldPath(to, toFieldPath); err != nil {
+			return field.Invalid(field.NewPath("toFieldPath"), toFieldPath, errors.Wrap(err, errUnknownFieldPath).Error())
+		}
+	}
+	for i, path := range p.ToFieldPaths {
+		if _, err := validateFieldPath(to, path); err != nil {
+			return field.Invalid(field.NewPath("toFieldPaths").Index(i), path, errors.Wrap(err, errUnknownFieldPath).Error())
+		}
+	}
+	return nil
+}
+
 // validateCombineFromCompositePathPatch validates Combine Patch types, by going through and validating the fromField
 // path variables, checking if the right combine strategy is set and validating transforms.
 func validateCombineFromCompositePathPatch(patch v1.Patch, from, to *apiextensions.JSONSchemaProps) (fromType, toType xpschema.KnownJSONType, err *field.Error) {
@@ -196,33 +268,74 @@ func validateCombineFromCompositePathPatch(patch v1.Patch, from, to *apiextensio
 	if toFieldPathErr != nil {
 		return "", "", field.Invalid(field.NewPath("toFieldPath"), toFieldPath, toFieldPathErr.Error())
 	}
+	if err := validateCombineVariables(patch.Combine.Variables, from); err != nil {
+		return "", "", err
+	}
+
+	fromType, err = combineOutputType(patch.Combine)
+	if err != nil {
+		return "", "", err
+	}
+
+	// TODO(lsviben): check if we could validate the patch combine format, worth looking at https://cs.opensource.google/go/x/tools/+/refs/tags/v0.7.0:go/analysis/passes/printf/printf.go;l=1025
+
+	return fromType, toType, nil
+}
+
+// validateCombineVariables validates that the field path each of the supplied
+// combine variables reads from is defined by the supplied schema, and that
+// the variable's transforms accept the type of that field path.
+func validateCombineVariables(vars []v1.CombineVariable, from *apiextensions.JSONSchemaProps) *field.Error {
 	errs := field.ErrorList{}
-	for _, variable := range patch.Combine.Variables {
+	for i, variable := range vars {
 		fromFieldPath := variable.FromFieldPath
-		_, err := validateFieldPath(from, fromFieldPath)
+		variableType, err := validateFieldPath(from, fromFieldPath)
 		if err != nil {
 			errs = append(errs, field.Invalid(field.NewPath("fromFieldPath"), fromFieldPath, err.Error()))
 			continue
 		}
+		if _, err := validateTransformsChainIOTypes(variable.Transforms, variableType); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("variables").Index(i)))
+		}
 	}
 
 	if len(errs) > 0 {
-		return "", "", field.Invalid(field.NewPath("combine"), patch.Combine.Variables, errs.ToAggregate().Error())
+		return field.Invalid(field.NewPath("combine"), vars, errs.ToAggregate().Error())
 	}
+	return nil
+}
 
-	switch patch.Combine.Strategy {
+// combineOutputType returns the type of the value the supplied combine
+// outputs, or an empty type if it isn't known. It returns an error if the
+// combine's strategy isn't supported or lacks its configuration.
+func combineOutputType(c *v1.Combine) (xpschema.KnownJSONType, *field.Error) {
+	switch c.Strategy {
 	case v1.CombineStrategyString:
-		if patch.Combine.String == nil {
-			return "", "", field.Required(field.NewPath("combine", "string"), "string combine strategy requires configuration")
+		if c.String == nil {
+			return "", field.Required(field.NewPath("combine", "string"), "string combine strategy requires configuration")
+		}
+		return xpschema.KnownJSONTypeString, nil
+	case v1.CombineStrategyCoalesce:
+		// The output of a coalesce is any of its variables, or its default,
+		// whose types we don't check.
+		return "", nil
+	case v1.CombineStrategyPercentDiff:
+		return xpschema.KnownJSONTypeNumber, nil
+	case v1.CombineStrategyMath:
+		if c.Math == nil {
+			return "", field.Required(field.NewPath("combine", "math"), "math combine strategy requires configuration")
+		}
+		return xpschema.KnownJSONTypeNumber, nil
+	case v1.CombineStrategyArray:
+		return xpschema.KnownJSONTypeArray, nil
+	case v1.CombineStrategySet:
+		if c.Set == nil {
+			return "", field.Required(field.NewPath("combine", "set"), "set combine strategy requires configuration")
 		}
-		fromType = xpschema.KnownJSONTypeString
+		return xpschema.KnownJSONTypeArray, nil
 	default:
-		return "", "", field.Invalid(field.NewPath("combine", "strategy"), patch.Combine.Strategy, "combine strategy is not supported")
+		return "", field.Invalid(field.NewPath("combine", "strategy"), c.Strategy, "combine strategy is not supported")
 	}
-
-	// TODO(lsviben): check if we could validate the patch combine format, worth looking at https://cs.opensource.google/go/x/tools/+/refs/tags/v0.7.0:go/analysis/passes/printf/printf.go;l=1025
-
-	return fromType, toType, nil
 }
 
 // validateFromCompositeFieldPathPatch validates a patch of type FromCompositeFieldPath.
@@ -265,7 +378,9 @@ func validateIOTypesWithTransforms(transforms []v1.Transform, fromType, toType x
 
 func validateTransformsChainIOTypes(transforms []v1.Transform, fromType xpschema.KnownJSONType) (outputType v1.TransformIOType, fErr *field.Error) {
 	inputType, err := xpschema.FromKnownJSONType(fromType)
-	if err != nil && fromType != "" {
+	// Arrays have no TransformIOType, but may be input to an aggregate
+	// transform. We treat their type as unknown.
+	if err != nil && fromType != "" && fromType != xpschema.KnownJSONTypeArray {
 		return "", field.InternalError(field.NewPath("transforms"), fErr)
 	}
 	for i, transform := range transforms {
@@ -398,34 +513,12 @@ func validateFieldPathSegmentIndex(parent *apiextensions.JSONSchemaProps, segmen
 }
 
 // IsValidInputForTransform validates the supplied Transform type, taking into consideration also the input type.
-//
-//nolint:gocyclo // This is a long but simple/same-y switch.
 func IsValidInputForTransform(t *v1.Transform, fromType v1.TransformIOType) error {
-	switch t.Type {
-	case v1.TransformTypeMath:
-		if fromType != v1.TransformIOTypeInt && fromType != v1.TransformIOTypeInt64 && fromType != v1.TransformIOTypeFloat64 {
-			return errors.Errorf("math transform can only be used with numeric types, got %s", fromType)
-		}
-	case v1.TransformTypeMap:
-		if fromType != v1.TransformIOTypeString {
-			return errors.Errorf("map transform can only be used with string types, got %s", fromType)
-		}
-	case v1.TransformTypeMatch:
-		if fromType != v1.TransformIOTypeString {
-			return errors.Errorf("match transform can only be used with string input types, got %s", fromType)
-		}
-	case v1.TransformTypeString:
-		if fromType != v1.TransformIOTypeString {
-			return errors.Errorf("string transform can only be used with string input types, got %s", fromType)
-		}
-	case v1.TransformTypeConvert:
-		if _, err := composite.GetConversionFunc(t.Convert, fromType); err != nil {
-			return err
-		}
-	default:
-		return errors.Errorf("unknown transform type %s", t.Type)
+	if t.Type == v1.TransformTypeConvert {
+		_, err := composite.GetConversionFunc(t.Convert, fromType)
+		return err
 	}
-	return nil
+	return t.ValidateInput(fromType)
 }
 
 // GetBaseObject returns the base object of the composed template.
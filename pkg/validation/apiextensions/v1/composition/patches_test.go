@@ -294,6 +294,60 @@ func TestValidateTransforms(t *testing.T) {
 				toType:   "",
 			},
 		},
+		"AcceptAggregateTransformsFromArray": {
+			reason: "Should accept an aggregate transform counting an array into an integer",
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type:      v1.TransformTypeAggregate,
+						Aggregate: &v1.AggregateTransform{Type: v1.AggregateTransformTypeCount},
+					},
+				},
+				fromType: "array",
+				toType:   "integer",
+			},
+		},
+		"RejectAggregateTransformsFromScalar": {
+			reason: "Should reject an aggregate transform whose input is not an array",
+			want: want{err: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "transforms[0]",
+			}},
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type:      v1.TransformTypeAggregate,
+						Aggregate: &v1.AggregateTransform{},
+					},
+				},
+				fromType: "integer",
+				toType:   "integer",
+			},
+		},
+		"AcceptLengthTransformsFromArray": {
+			reason: "Should accept a length transform counting an array into an integer",
+			args: args{
+				transforms: []v1.Transform{
+					{Type: v1.TransformTypeLength},
+				},
+				fromType: "array",
+				toType:   "integer",
+			},
+		},
+		"RejectLengthTransformsFromScalar": {
+			reason: "Should reject a length transform whose input is not an array",
+			want: want{err: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "transforms[0]",
+			}},
+			args: args{
+				transforms: []v1.Transform{
+					{Type: v1.TransformTypeLength},
+				},
+				fromType: "string",
+				toType:   "integer",
+			},
+		},
 		"RejectNoInputTypeWrongOutputTypeForTransforms": {
 			reason: "Should return an error if there is no type spec for the input, but output is specified and transforms are wrong",
 			want: want{err: &field.Error{
@@ -459,6 +513,177 @@ func TestValidateFieldPath(t *testing.T) {
 	}
 }
 
+func TestValidatePatchFieldPaths(t *testing.T) {
+	composite := &apiextensions.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensions.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]apiextensions.JSONSchemaProps{
+					"region": {Type: "string"},
+					"size":   {Type: "string"},
+				},
+			},
+			"status": {
+				Type: "object",
+				Properties: map[string]apiextensions.JSONSchemaProps{
+					"address": {Type: "string"},
+				},
+			},
+		},
+	}
+	composed := &apiextensions.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensions.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]apiextensions.JSONSchemaProps{
+					"forProvider": {
+						Type: "object",
+						Properties: map[string]apiextensions.JSONSchemaProps{
+							"region": {Type: "string"},
+							"name":   {Type: "string"},
+						},
+					},
+				},
+			},
+			"status": {
+				Type: "object",
+				Properties: map[string]apiextensions.JSONSchemaProps{
+					"atProvider": {
+						Type:                   "object",
+						XPreserveUnknownFields: pointer.Bool(true),
+					},
+				},
+			},
+		},
+	}
+
+	type args struct {
+		patch v1.Patch
+	}
+	type want struct {
+		err *field.Error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FromCompositeFieldPath": {
+			reason: "Should accept a patch whose field paths are defined by the schemas",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPath:   pointer.String("spec.forProvider.region"),
+				},
+			},
+		},
+		"UnknownFromFieldPath": {
+			reason: "Should reject a patch whose fromFieldPath isn't defined by the composite's schema",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.regoin"),
+					ToFieldPath:   pointer.String("spec.forProvider.region"),
+				},
+			},
+			want: want{
+				err: field.Invalid(field.NewPath("fromFieldPath"), "spec.regoin", xperrors.Wrap(xperrors.Errorf(errFmtFieldInvalid, "regoin"), errUnknownFieldPath).Error()),
+			},
+		},
+		"UnknownDefaultToFieldPath": {
+			reason: "Should reject a patch without a toFieldPath whose fromFieldPath isn't defined by the composed resource's schema",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.region"),
+				},
+			},
+			want: want{
+				err: field.Invalid(field.NewPath("toFieldPath"), "spec.region", xperrors.Wrap(xperrors.Errorf(errFmtFieldInvalid, "region"), errUnknownFieldPath).Error()),
+			},
+		},
+		"ToFieldPaths": {
+			reason: "Should accept a patch without a toFieldPath whose toFieldPaths are defined by the composed resource's schema",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPaths:  []string{"spec.forProvider.region", "spec.forProvider.name"},
+				},
+			},
+		},
+		"UnknownToFieldPaths": {
+			reason: "Should reject a patch with a toFieldPaths entry that isn't defined by the composed resource's schema",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: pointer.String("spec.region"),
+					ToFieldPath:   pointer.String("spec.forProvider.region"),
+					ToFieldPaths:  []string{"spec.forProvider.name", "spec.forProvider.nmae"},
+				},
+			},
+			want: want{
+				err: field.Invalid(field.NewPath("toFieldPaths").Index(1), "spec.forProvider.nmae", xperrors.Wrap(xperrors.Errorf(errFmtFieldInvalid, "nmae"), errUnknownFieldPath).Error()),
+			},
+		},
+		"ToCompositeFieldPath": {
+			reason: "Should accept a patch from a field that the composed resource's schema preserves",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.address"),
+					ToFieldPath:   pointer.String("status.address"),
+				},
+			},
+		},
+		"UnknownToCompositeFieldPath": {
+			reason: "Should reject a patch whose toFieldPath isn't defined by the composite's schema",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.address"),
+					ToFieldPath:   pointer.String("status.adress"),
+				},
+			},
+			want: want{
+				err: field.Invalid(field.NewPath("toFieldPath"), "status.adress", xperrors.Wrap(xperrors.Errorf(errFmtFieldInvalid, "adress"), errUnknownFieldPath).Error()),
+			},
+		},
+		"UnknownCombineVariable": {
+			reason: "Should reject a combine patch whose variable isn't defined by the composite's schema",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Strategy:  v1.CombineStrategyString,
+						Variables: []v1.CombineVariable{{FromFieldPath: "spec.region"}, {FromFieldPath: "spec.sise"}},
+						String:    &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("spec.forProvider.name"),
+				},
+			},
+			want: want{
+				err: field.Invalid(field.NewPath("combine", "variables").Index(1).Child("fromFieldPath"), "spec.sise", xperrors.Wrap(xperrors.Errorf(errFmtFieldInvalid, "sise"), errUnknownFieldPath).Error()),
+			},
+		},
+		"EnvironmentPatch": {
+			reason: "Should ignore a patch that isn't between the composite and composed resources",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromEnvironmentFieldPath,
+					FromFieldPath: pointer.String("nope"),
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePatchFieldPaths(tc.args.patch, composite, composed)
+			if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Errorf("\n%s\nValidatePatchFieldPaths(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestValidateFieldPathSegmentIndex(t *testing.T) {
 	type args struct {
 		parent  *apiextensions.JSONSchemaProps
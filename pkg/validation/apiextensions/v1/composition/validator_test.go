@@ -272,6 +272,30 @@ func TestValidatorValidate(t *testing.T) {
 				})),
 			},
 		},
+		"AcceptStrictPatchWithCoalesceCombinePatch": {
+			reason: "Should accept a Composition with a coalesce combine patch, if validation mode is strict and all CRDs are found",
+			want: want{
+				errs: nil,
+			},
+			args: args{
+				gkToCRDs: defaultGKToCRDs(),
+				comp: buildDefaultComposition(t, v1.CompositionValidationModeStrict, nil, withPatches(0, v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{
+								FromFieldPath: "spec.someField",
+							},
+							{
+								FromFieldPath: "spec.someField",
+							},
+						},
+						Strategy: v1.CombineStrategyCoalesce,
+					},
+					ToFieldPath: pointer.String("spec.someOtherField"),
+				})),
+			},
+		},
 		"AcceptEnvironmentConfigPatchUnsupported": {
 			reason: "Should accept Composition using an EnvironmentConfig related PatchType, if all CRDs are found",
 			want: want{
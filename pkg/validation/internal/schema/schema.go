@@ -62,7 +62,7 @@ func FromTransformIOType(c v1.TransformIOType) KnownJSONType {
 		return KnownJSONTypeString
 	case v1.TransformIOTypeBool:
 		return KnownJSONTypeBoolean
-	case v1.TransformIOTypeInt, v1.TransformIOTypeInt64:
+	case v1.TransformIOTypeInt, v1.TransformIOTypeInt64, v1.TransformIOTypeInt32, v1.TransformIOTypeInt16:
 		return KnownJSONTypeInteger
 	case v1.TransformIOTypeFloat64:
 		return KnownJSONTypeNumber